@@ -1,26 +1,60 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	abuseModule "go_boilerplate/internal/modules/abuse"
+	auditModule "go_boilerplate/internal/modules/audit"
 	authModule "go_boilerplate/internal/modules/auth"
 	"go_boilerplate/internal/modules/auth/dto"
-	oauthModule "go_boilerplate/internal/modules/oauth"
+	emailModule "go_boilerplate/internal/modules/email"
 	oauthdto "go_boilerplate/internal/modules/oauth/dto"
 	roleModule "go_boilerplate/internal/modules/role"
+	tenantModule "go_boilerplate/internal/modules/tenant"
 	userModule "go_boilerplate/internal/modules/user"
 
 	// [MODULE_IMPORT_MARKER]
+	"go_boilerplate/internal/routes"
+	"go_boilerplate/internal/shared/apiversion"
+	"go_boilerplate/internal/shared/apperror"
+	"go_boilerplate/internal/shared/buildinfo"
+	"go_boilerplate/internal/shared/cache"
+	"go_boilerplate/internal/shared/casbinauth"
 	"go_boilerplate/internal/shared/config"
 	"go_boilerplate/internal/shared/database"
+	"go_boilerplate/internal/shared/database/pool"
+	"go_boilerplate/internal/shared/database/replica"
+	"go_boilerplate/internal/shared/database/retention"
+	"go_boilerplate/internal/shared/database/seed"
+	"go_boilerplate/internal/shared/database/timeout"
+	"go_boilerplate/internal/shared/events"
+	"go_boilerplate/internal/shared/health"
+	"go_boilerplate/internal/shared/i18n"
+	"go_boilerplate/internal/shared/jobs"
+	"go_boilerplate/internal/shared/lock"
 	"go_boilerplate/internal/shared/middleware"
+	"go_boilerplate/internal/shared/observability"
+	"go_boilerplate/internal/shared/observability/anomaly"
+	"go_boilerplate/internal/shared/permission"
+	"go_boilerplate/internal/shared/server"
+	"go_boilerplate/internal/shared/session"
 	"go_boilerplate/internal/shared/utils"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/expvar"
+	"github.com/gofiber/fiber/v2/middleware/pprof"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 
 	_ "go_boilerplate/docs"
@@ -48,72 +82,210 @@ import (
 // @description Type "Bearer" followed by a space and then your token.
 
 func main() {
+	printConfig := flag.Bool("print-config", false, "Print the effective configuration (secrets redacted) and exit")
+	flag.Parse()
+
 	// 1. Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		fmt.Printf("Failed to load configuration: %v\n", err)
 		os.Exit(1)
 	}
+	if *printConfig {
+		printEffectiveConfig(cfg.Report())
+		return
+	}
 
 	// 2. Initialize logger
 	logger := utils.InitLogger(cfg)
-	logger.Info("Starting Go Boilerplate API...")
+	logs := utils.NewLogLevels(logger) // Module loggers, whose levels can be changed at runtime
+	logger.Infof("Starting Go Boilerplate API %s...", buildinfo.Get())
+	logConfigReport(logger, cfg)
+	cfg.OnReload(func(old, new config.HotConfig) {
+		logs.SetLevel(utils.ParseLogLevel(new.LogLevel))
+	})
+
+	if err := i18n.SetDefault(cfg.I18n.DefaultLocale); err != nil {
+		logger.Fatalf("Invalid DEFAULT_LOCALE: %v", err)
+	}
+
+	// OpenTelemetry tracing: spans of requests, services, queries, Redis and outbound HTTP calls are
+	// exported over OTLP; without it they are no-ops
+	shutdownTracing := func(context.Context) error { return nil }
+	if cfg.Tracing.Enabled {
+		shutdownTracing, err = observability.InitTracing(context.Background(), cfg, logger)
+		if err != nil {
+			logger.Fatalf("Failed to initialize tracing: %v", err)
+		}
+		logger.Infof("✓ Tracing enabled (%s, %.0f%% of new traces sampled)", cfg.Tracing.Exporter, cfg.Tracing.SampleRatio*100)
+	}
 
 	// 3. Initialize database
-	db, err := database.InitDB(cfg)
+	db, err := database.InitDB(cfg, logger)
 	if err != nil {
 		logger.Fatalf("Failed to connect to database: %v", err)
 	}
 	logger.Info("Database connected successfully")
 
-	// 4. Initialize Redis
-	redisClient, err := database.InitRedis(cfg, logger)
-	if err != nil {
-		logger.Warnf("Failed to connect to Redis: %v", err)
-	} else {
+	// 4. Initialize Redis: it is optional, so the API starts degraded when Redis is down and the
+	// monitor reconnects once it answers again
+	var redisClient *redis.Client
+	var redisMonitor *database.RedisMonitor
+	if cfg.Redis.Enabled {
+		redisClient = database.NewRedisClient(cfg)
 		defer redisClient.Close()
+		redisMonitor = database.NewRedisMonitor(redisClient, logger)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := redisMonitor.Check(ctx); err != nil {
+			logger.Warnf("Failed to connect to Redis, starting degraded and retrying every %s: %v", cfg.Redis.CheckInterval, err)
+		} else {
+			logger.Info("✓ Connected to Redis")
+		}
+		cancel()
+	} else {
+		logger.Info("Redis disabled (REDIS_ENABLED=false)")
 	}
 
 	// 5. Run database migrations
 
-	// Step 1: Rename tables (drop old tables) - ONLY IN DEVELOPMENT
-	if cfg.Server.IsDevelopment() {
+	// MIGRATION_MODE picks what manages the schema: GORM AutoMigrate (auto, the development
+	// default), the versioned SQL migrations in db/migrations (sql, the default elsewhere) or both
+	migrationMode := cfg.Database.MigrationMode
+	logger.Infof("Migration mode: %s", migrationMode)
+
+	// Step 1: Versioned SQL migrations embedded in the binary (same as `cmd/migrate -up`)
+	if migrationMode != config.MigrationModeAuto {
+		if cfg.Database.MigrateOnStart {
+			if err := database.MigrateUp(cfg, logger); err != nil {
+				logger.Fatalf("Failed to run SQL migrations: %v", err)
+			}
+		}
+		// Refuse to serve against a schema older than the code
+		if err := database.CheckMigrations(cfg); err != nil {
+			logger.Fatalf("Database schema is not up to date: %v", err)
+		}
+	}
+
+	// Step 2: Rename tables (drop old tables) - ONLY IN DEVELOPMENT, with AutoMigrate
+	if cfg.Server.IsDevelopment() && migrationMode != config.MigrationModeSQL {
 		logger.Info("Running in development mode - dropping old tables...")
 		if err := database.RenameTables(db, logger); err != nil {
 			logger.Warnf("Failed to rename tables: %v", err)
 		}
 	}
 
-	// Step 2: AutoMigrate models with new table names
-	// This should only run in development. In production, use manual migrations (golang-migrate).
-	if cfg.Server.IsDevelopment() {
+	// Step 3: AutoMigrate models with new table names
+	// Running it on top of the SQL migrations (both) lets the schema drift from db/migrations
+	if migrationMode != config.MigrationModeSQL {
 		migrationModels := []any{
 			&roleModule.Role{},
 			&userModule.User{},
+			&userModule.DataExport{},
+			&userModule.UserPreference{},
+			&userModule.UserPermission{},
 			&dto.Session{},
 			&oauthdto.OAuthAccount{},
+			&abuseModule.AbuseReport{},
+			&casbinauth.CasbinRule{},
+			&auditModule.AuditEvent{},
+			&auditModule.AuditLog{},
+			&tenantModule.Tenant{},
+			&emailModule.EmailMessage{},
 			// [MODULE_MIGRATION_MARKER]
 		}
 
 		if err := database.AutoMigrate(db, migrationModels, logger); err != nil {
 			logger.Fatalf("Failed to run migrations: %v", err)
 		}
+
+		if err := database.MigrateUserRoles(db, logger); err != nil {
+			logger.Fatalf("Failed to migrate user roles: %v", err)
+		}
 	} else {
-		logger.Info("Running in production mode - skipping AutoMigrate")
+		logger.Info("MIGRATION_MODE is sql - skipping AutoMigrate")
 	}
 
-	// Step 3: Seed initial roles
+	// Step 4: Seed roles, the SuperAdmin user and, in development, demo users (see cmd/seed)
+	// Startup reads what it just seeded, so it queries the primary rather than the read replicas
+	seedCtx := replica.WithPrimary(context.Background())
 	roleRepo := roleModule.NewRoleRepository(db)
-	roleService := roleModule.NewRoleService(roleRepo)
-	if err := roleService.SeedInitialRoles(); err != nil {
-		logger.Warnf("Failed to seed initial roles: %v", err)
-	} else {
-		logger.Info("✓ Initial roles seeded successfully")
+	locker := lock.New(redisClient)
+	if err := seed.Run(seedCtx, db, database.Seeders(cfg, logger), seed.Options{Env: cfg.Server.Mode, Locker: locker}, logger); err != nil {
+		logger.Warnf("Failed to seed database: %v", err)
+	}
+	// Seeding and migrations bypass the services, so drop responses cached by a previous deployment
+	dropSharedCaches(context.Background(), cfg, redisClient)
+	if redisMonitor != nil {
+		// Invalidations made during an outage never reached Redis
+		redisMonitor.OnRecover(func(ctx context.Context) {
+			dropSharedCaches(ctx, cfg, redisClient)
+		})
+	}
+
+	// New users get DEFAULT_ROLE_SLUG; refuse to start rather than fail every registration
+	if defaultRole, err := roleRepo.FindBySlug(seedCtx, cfg.RBAC.DefaultRoleSlug); err != nil || defaultRole == nil {
+		logger.Fatalf("Default role %q (DEFAULT_ROLE_SLUG) not found: %v", cfg.RBAC.DefaultRoleSlug, err)
+	}
+
+	// Optional Casbin backend: permission checks are answered from m_casbin_rules instead of token claims.
+	// An empty policy is seeded from the current role permissions, inheritance and per-user overrides
+	if cfg.RBAC.Backend == "casbin" {
+		enforcer, err := casbinauth.NewEnforcer(db, cfg.RBAC)
+		if err != nil {
+			logger.Fatalf("Failed to initialize Casbin: %v", err)
+		}
+
+		roles, _, err := roleRepo.FindAll(seedCtx, 0, -1, nil)
+		if err != nil {
+			logger.Fatalf("Failed to load roles for Casbin: %v", err)
+		}
+		var overrides []userModule.UserPermission
+		if err := db.Find(&overrides).Error; err != nil {
+			logger.Fatalf("Failed to load permission overrides for Casbin: %v", err)
+		}
+
+		slugs := make(map[uuid.UUID]string, len(roles))
+		for _, r := range roles {
+			slugs[r.ID] = r.Slug
+		}
+		var grants []casbinauth.Grant
+		var inherits [][2]string
+		for _, r := range roles {
+			for _, p := range r.Permissions {
+				grants = append(grants, casbinauth.Grant{Subject: r.Slug, Permission: p})
+			}
+			if r.ParentID != nil && slugs[*r.ParentID] != "" {
+				inherits = append(inherits, [2]string{r.Slug, slugs[*r.ParentID]})
+			}
+		}
+		for _, o := range overrides {
+			grants = append(grants, casbinauth.Grant{
+				Subject:    o.UserID.String(),
+				Permission: o.Permission,
+				Deny:       o.Effect == userModule.PermissionDeny,
+			})
+		}
+
+		seeded, err := enforcer.SeedIfEmpty(grants, inherits)
+		if err != nil {
+			logger.Fatalf("Failed to seed Casbin policy: %v", err)
+		}
+		if seeded {
+			logger.Infof("✓ Casbin policy seeded with %d rules", len(grants)+len(inherits))
+		}
+		middleware.UseAuthorizer(enforcer)
+		logger.Info("✓ Authorization backend: casbin")
+	}
+
+	// Refuse access tokens revoked on logout (Redis only; without it they stay valid until they expire)
+	if redisClient != nil {
+		middleware.UseDenylist(session.NewDenylist(redisClient))
 	}
 
-	// Step 4: Seed SuperAdmin user
-	if err := database.SeedSuperAdmin(db, cfg, logger); err != nil {
-		logger.Warnf("Failed to seed SuperAdmin user: %v", err)
+	// Bound request-time queries by operation class (registered after migrations and seeding)
+	if err := db.Use(timeout.NewPlugin(cfg.Database.Timeouts, observability.NewCounter(redisClient))); err != nil {
+		logger.Fatalf("Failed to register query timeout plugin: %v", err)
 	}
 
 	// 5. Create Fiber app
@@ -121,66 +293,273 @@ func main() {
 		AppName:               "Go Boilerplate API",
 		DisableStartupMessage: false,
 		EnablePrintRoutes:     cfg.Server.IsDevelopment(),
+		BodyLimit:             cfg.Server.BodyLimit,
+		// Behind a load balancer, c.IP() reads PROXY_HEADER, but only from TRUSTED_PROXIES
+		ProxyHeader:             cfg.Proxy.Header,
+		EnableTrustedProxyCheck: cfg.Proxy.Header != "",
+		TrustedProxies:          cfg.Proxy.TrustedProxies,
+		EnableIPValidation:      cfg.Proxy.Header != "",
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			code := fiber.StatusInternalServerError
-			if e, ok := err.(*fiber.Error); ok {
-				code = e.Code
+			message := "Internal server error"
+			cause := err
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				code = fiberErr.Code
+				message = fiberErr.Message
+				cause = nil // Already described by message
+			} else if appErr, ok := apperror.As(err); ok {
+				code = appErr.Status
 			}
 
 			// Log error
 			logger.WithFields(logrus.Fields{
-				"path":    c.Path(),
-				"method":  c.Method(),
-				"status":  code,
-				"error":   err.Error(),
+				"path":   c.Path(),
+				"method": c.Method(),
+				"status": code,
+				"error":  err.Error(),
 			}).Error("Request error")
 
-			return c.Status(code).JSON(fiber.Map{
-				"success": false,
-				"error":   err.Error(),
-			})
+			return utils.ErrorResponse(c, code, message, cause)
 		},
 	})
 
 	// 6. Register global middleware
-	app.Use(middleware.HTTPLogger(logger))
-	app.Use(middleware.CORS(cfg))
+	app.Use(requestid.New())
+	app.Use(middleware.RequestContext())
+	if cfg.Metrics.Enabled {
+		app.Use(middleware.Metrics("/metrics"))
+	}
+	if cfg.Tracing.Enabled {
+		app.Use(middleware.Tracing("/health", "/version", "/metrics", "/swagger", "/debug"))
+	}
+	if cfg.Logger.WideEvents {
+		app.Use(middleware.WideEvent(logger))
+	} else {
+		app.Use(middleware.HTTPLogger(logger, cfg.Logger))
+	}
+	// Recover right after request ID and logging, so a panic in any later middleware or handler is logged as a 500
 	app.Use(recover.New())
+	if cfg.BodyLog.Enabled && cfg.Server.IsProduction() {
+		logger.Warn("BODY_LOG_ENABLED is set in production; request and response bodies are being logged")
+	}
+	app.Use(middleware.BodyLogger(logger, cfg.BodyLog, "/health", "/version", "/metrics", "/swagger", "/debug"))
+	app.Use(middleware.Debug(cfg))
+	app.Use(middleware.Locale(cfg.I18n))
+	app.Use(middleware.ResponseEnvelope(cfg.Server.Envelope))
+	app.Use(middleware.APIVersion(cfg.APIVersion))
+	app.Use(middleware.CORS(cfg))
+	app.Use(middleware.SecurityHeaders(cfg.Security.Headers, "/swagger"))
 
-	// 7. Health check endpoint
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"success": true,
-			"status":  "ok",
-			"message": "API is running",
-		})
-	})
+	// Cap requests in flight so spikes are rejected early instead of piling up on the database pool
+	app.Use(middleware.ConcurrencyLimit(cfg.Concurrency, cfg.Concurrency.MaxInFlight, "/health", "/metrics", "/debug"))
 
-	// Register Swagger route
-	app.Get("/swagger/*", swagger.HandlerDefault)
+	// Connection pools of the primary and the read replicas, for metrics and the stats log line
+	dbPools, err := database.Pools(db, cfg)
+	if err != nil {
+		logger.Fatalf("Failed to get database handle: %v", err)
+	}
 
-	// 8. Register module routes
-	logger.Info("Registering module routes...")
+	// Shed low-priority traffic while requests queue for database connections,
+	// keeping health checks and login/refresh responsive during load spikes
+	var poolMonitor *pool.Monitor
+	if cfg.Pool.Enabled {
+		poolMonitor = pool.NewMonitor(dbPools[0].DB, cfg.Pool, emailModule.NewAdminNotifier(cfg, db, logger), logger)
+		if cfg.Pool.ShedLoad {
+			app.Use(middleware.ShedLoad(poolMonitor, cfg.Pool.Interval, "/health", "/metrics", "/debug", authModule.LoginPath, authModule.RefreshPath))
+		}
+	}
+
+	// Bound every request context; route groups may add a shorter middleware.Timeout
+	app.Use(middleware.Timeout(cfg.Server.RequestTimeout))
+
+	// Resolve the request tenant (header, subdomain or token claim) before anything that reads it
+	tenantResolver := tenantModule.NewResolver(tenantModule.NewTenantRepository(db), cfg.Tenancy.CacheTTL)
+	app.Use(middleware.ResolveTenant(cfg, tenantResolver, "/health", "/version", "/metrics", "/swagger", "/debug"))
+
+	// Record POST/PUT/PATCH/DELETE requests (buffered in memory, flushed by a background job)
+	auditRecorder := auditModule.NewRecorder(auditModule.NewAuditEventRepository(db), cfg.Audit, logger)
+	app.Use(middleware.Audit(auditRecorder, cfg.Audit))
 
-	// Auth routes (register, login, refresh, logout)
-	authModule.RegisterRoutes(app, db, cfg, logger, redisClient)
-	logger.Info("✓ Auth routes registered")
+	// Track last_seen of authenticated users (buffered in Redis, flushed by a background job)
+	activityTracker := userModule.NewActivityTracker(redisClient, userModule.NewUserRepository(db), logger)
+	app.Use(middleware.TrackActivity(activityTracker))
 
-	// User routes (CRUD operations)
-	userModule.RegisterRoutes(app, db, cfg, logger)
-	logger.Info("✓ User routes registered")
+	// 7. Health check endpoints: /health/live for liveness probes, /health/ready (and /health) for
+	// readiness with the status of each dependency
+	checker := health.NewChecker(cfg.Health.Timeout, health.Postgres(db), health.Migrations(db))
+	if cfg.Redis.Enabled {
+		checker.Add(health.Redis(redisClient))
+	}
+	if cfg.Email.Enabled && cfg.Email.Provider == "smtp" && cfg.Health.CheckSMTP {
+		checker.Add(health.SMTP(cfg.Email))
+	}
+	app.Get("/health/live", checker.Live)
+	app.Get("/health/ready", checker.Ready)
+	app.Get("/health", checker.Ready)
+
+	// Build of the running binary: version, commit and build time set with -ldflags (see buildinfo)
+	app.Get("/version", buildinfo.Handler)
+
+	// Prometheus metrics: HTTP requests per route, query durations per table/operation, connection
+	// pool and runtime stats, auth counters
+	if cfg.Metrics.Enabled {
+		for _, source := range dbPools {
+			observability.Registry.MustRegister(collectors.NewDBStatsCollector(source.DB, source.Name))
+		}
+		app.Get("/metrics", observability.MetricsHandler(cfg.Metrics.Token))
+	}
+
+	// Runtime debug endpoints: pprof profiles under /debug/pprof and expvar under /debug/vars, for a
+	// super_admin or DEBUG_TOKEN (e.g. curl -H "X-Debug-Token: ..." .../debug/pprof/profile?seconds=30)
+	if cfg.Debug.EndpointsEnabled {
+		app.Use("/debug", middleware.RequireDebugAccess(cfg), pprof.New(), expvar.New())
+	}
+
+	// Register Swagger route
+	app.Get("/swagger/*", swagger.HandlerDefault)
+
+	// Domain events (EVENT_BUS_DRIVER): modules publish them, subscribers on every instance react
+	bus := events.New(cfg.Events, redisClient, logger)
+	bus.Subscribe("*", func(_ context.Context, event events.Event) error {
+		logger.WithFields(logrus.Fields{"event": event.Name, "event_id": event.ID}).Debug("Domain event received")
+		return nil
+	})
 
-	// Role routes (manage roles - SuperAdmin only)
-	roleModule.RegisterRoutes(app, db, cfg, logger)
-	logger.Info("✓ Role routes registered")
+	// 8. Register module routes
+	routes.Register(app, db, cfg, logs, redisClient, bus)
+	if !apiversion.Registered(cfg.APIVersion.Default) {
+		logger.Fatalf("API_DEFAULT_VERSION %q has no routes (registered: %v)", cfg.APIVersion.Default, apiversion.Versions())
+	}
 
-	// OAuth routes (Google, GitHub)
-	oauthModule.RegisterRoutes(app, db, cfg, logger)
-	logger.Info("✓ OAuth routes registered")
+	// 9. Start background jobs
+	// Exclusive jobs work on shared data and run on one instance per interval
+	scheduler := jobs.NewScheduler(logger, locker)
+	scheduler.Add(jobs.Job{
+		Name:      "flush-last-seen",
+		Interval:  cfg.Activity.LastSeenFlushInterval,
+		Run:       activityTracker.Flush,
+		RunOnStop: true,
+		Exclusive: true,
+	})
+	if cfg.Audit.Enabled {
+		scheduler.Add(jobs.Job{
+			Name:      "flush-audit-events",
+			Interval:  cfg.Audit.FlushInterval,
+			Run:       auditRecorder.Flush,
+			RunOnStop: true,
+		})
+	}
+	if cfg.Anomaly.Enabled {
+		detector := anomaly.NewDetector(redisClient, emailModule.NewAdminNotifier(cfg, db, logger), anomaly.AuthMetrics, cfg.Anomaly, logger)
+		scheduler.Add(jobs.Job{
+			Name:      "auth-anomaly-detection",
+			Interval:  cfg.Anomaly.Interval,
+			Run:       detector.Run,
+			Exclusive: true,
+		})
+	}
+	if poolMonitor != nil {
+		scheduler.Add(jobs.Job{
+			Name:     "db-pool-monitor",
+			Interval: cfg.Pool.Interval,
+			Run:      poolMonitor.Run,
+		})
+	}
+	if cfg.Pool.StatsLogInterval > 0 {
+		scheduler.Add(jobs.Job{
+			Name:     "db-stats-log",
+			Interval: cfg.Pool.StatsLogInterval,
+			Run:      pool.NewReporter(dbPools, logger).Run,
+		})
+	}
+	if redisMonitor != nil {
+		scheduler.Add(jobs.Job{
+			Name:     "redis-monitor",
+			Interval: cfg.Redis.CheckInterval,
+			Run:      redisMonitor.Run,
+		})
+	}
+	if cfg.Secrets.RefreshInterval > 0 {
+		scheduler.Add(jobs.Job{
+			Name:     "refresh-secrets",
+			Interval: cfg.Secrets.RefreshInterval,
+			Run: func(ctx context.Context) error {
+				return refreshSecrets(ctx, logger, cfg)
+			},
+		})
+	}
+	// Soft-deleted rows of these models are permanently deleted after TRASH_RETENTION
+	purger := retention.NewPurger(db, cfg.Trash, logger)
+	if err := purger.Register(&userModule.User{}); err != nil {
+		logger.Fatalf("Failed to register soft-delete retention: %v", err)
+	}
+	scheduler.Add(jobs.Job{
+		Name:      "purge-soft-deleted",
+		Interval:  cfg.Trash.PurgeInterval,
+		Run:       purger.Run,
+		Exclusive: true,
+	})
+	// Expired refresh tokens pile up in t_sessions when sessions live in the database
+	sessionsInDatabase := cfg.Sessions.Driver == session.DriverDatabase || redisClient == nil
+	if sessionsInDatabase && cfg.Sessions.CleanupInterval > 0 {
+		scheduler.Add(jobs.Job{
+			Name:      "purge-expired-sessions",
+			Interval:  cfg.Sessions.CleanupInterval,
+			Run:       session.NewCleanup(db, cfg.Sessions, logger).Run,
+			Exclusive: true,
+		})
+	}
+	if cfg.Email.Enabled {
+		// Every instance delivers queued email; claimed messages are skipped by the others
+		emailQueue := emailModule.NewQueue(db, emailModule.NewSender(cfg.Email, logger), cfg, logger)
+		scheduler.Add(jobs.Job{
+			Name:     "send-queued-email",
+			Interval: cfg.EmailQueue.PollInterval,
+			Run:      emailQueue.Process,
+		})
+		if cfg.EmailQueue.Retention > 0 {
+			scheduler.Add(jobs.Job{
+				Name:      "purge-email-messages",
+				Interval:  time.Hour,
+				Run:       emailQueue.Purge,
+				Exclusive: true,
+			})
+		}
+	}
+	// Audit log entries are deleted once past AUDIT_LOG_RETENTION (or their resource's override)
+	if auditTrail := auditModule.NewTrail(db, cfg.Audit, logger); auditTrail != nil {
+		scheduler.Add(jobs.Job{
+			Name:      "purge-audit-logs",
+			Interval:  time.Hour,
+			Run:       auditTrail.Purge,
+			Exclusive: true,
+		})
+	}
+	// [MODULE_JOB_MARKER]
+	scheduler.Start()
 
-	// [MODULE_ROUTE_MARKER]
+	// Reload hot settings (log level, rate limits, CORS origins, feature flags) on SIGHUP and config file changes
+	reloadCtx, stopReloads := context.WithCancel(context.Background())
+	go func() {
+		hupChan := make(chan os.Signal, 1)
+		signal.Notify(hupChan, syscall.SIGHUP)
+		for {
+			select {
+			case <-hupChan:
+				reloadConfig(logger, cfg, "SIGHUP")
+			case <-reloadCtx.Done():
+				return
+			}
+		}
+	}()
+	if cfg.HotReload.Watch {
+		if err := config.WatchFiles(reloadCtx, func() { reloadConfig(logger, cfg, "config file change") }); err != nil {
+			logger.Warnf("Failed to watch config files: %v", err)
+		}
+	}
 
-	// 9. Graceful shutdown
+	// 10. Graceful shutdown
 	// Handle shutdown signals
 	go func() {
 		sigChan := make(chan os.Signal, 1)
@@ -188,11 +567,25 @@ func main() {
 		<-sigChan
 
 		logger.Info("Shutting down server...")
+		stopReloads()
+
+		// Stop background jobs first so final runs still have a database
+		scheduler.Stop()
 
 		if err := app.Shutdown(); err != nil {
 			logger.Errorf("Error during server shutdown: %v", err)
 		}
 
+		// Let event handlers still running finish
+		_ = bus.Close()
+
+		// Export the spans still buffered
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Errorf("Error flushing traces: %v", err)
+		}
+		cancel()
+
 		// Close database connection
 		if err := database.CloseDB(db); err != nil {
 			logger.Errorf("Error closing database: %v", err)
@@ -201,13 +594,84 @@ func main() {
 		logger.Info("Server shut down gracefully")
 	}()
 
-	// 10. Start server
+	// 11. Start server
 	addr := ":" + cfg.Server.Port
 	logger.Infof("Server starting on %s", addr)
 	logger.Infof("Environment: %s", cfg.Server.Mode)
-	logger.Infof("API Documentation: http://localhost%s/swagger", addr)
+	logger.Infof("API Documentation: %s://localhost%s/swagger", server.Scheme(cfg), addr)
 
-	if err := app.Listen(addr); err != nil {
+	if err := server.Listen(app, cfg, logger); err != nil {
 		logger.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+// dropSharedCaches drops the Redis caches whose invalidations may have been missed: cached
+// responses, users with their roles and permission sets
+func dropSharedCaches(ctx context.Context, cfg *config.Config, redisClient *redis.Client) {
+	_ = cache.NewResponseCache(redisClient, cfg.Cache).Invalidate(ctx, cache.NamespaceRoles, cache.NamespaceUsers)
+	_ = cache.NewNamespace(cache.New(cfg.CacheStore, redisClient), cache.NamespaceUserRoles).Clear(ctx)
+	_ = permission.NewCache(redisClient, cfg.RBAC.PermissionCacheTTL).InvalidateAll(ctx)
+}
+
+// logConfigReport logs where the configuration came from; individual settings (secrets
+// redacted) are logged at debug level
+func logConfigReport(logger *logrus.Logger, cfg *config.Config) {
+	report := cfg.Report()
+	counts := report.BySource()
+	logger.WithFields(logrus.Fields{
+		"mode":          cfg.Server.Mode,
+		"env_file":      report.EnvFile,
+		"config_files":  report.Files,
+		"from_env":      counts[config.SourceEnv],
+		"from_file":     counts[config.SourceFile],
+		"from_defaults": counts[config.SourceDefault],
+	}).Info("Configuration loaded")
+
+	for _, warning := range report.Warnings {
+		logger.Warn(warning)
+	}
+	for _, setting := range report.Settings {
+		logger.WithFields(logrus.Fields{
+			"key":    setting.Key,
+			"value":  setting.Value,
+			"source": setting.Source,
+		}).Debug("Config setting")
+	}
+}
+
+// reloadConfig applies hot settings from a reloaded configuration and warns about changes that
+// need a restart; an invalid configuration is logged and the running settings are kept
+func reloadConfig(logger *logrus.Logger, cfg *config.Config, trigger string) {
+	applied, restartRequired, err := cfg.Reload()
+	if err != nil {
+		logger.WithField("trigger", trigger).Errorf("Config reload failed, keeping the current settings: %v", err)
+		return
+	}
+	logger.WithFields(logrus.Fields{
+		"trigger": trigger,
+		"applied": applied,
+	}).Info("Configuration reloaded")
+	if len(restartRequired) > 0 {
+		logger.WithField("settings", restartRequired).Warn("Changed settings take effect after a restart")
+	}
+}
+
+// refreshSecrets re-fetches the referenced secrets; settings keep the values read at startup, so a
+// rotated secret is reported and takes effect on the next restart
+func refreshSecrets(ctx context.Context, logger *logrus.Logger, cfg *config.Config) error {
+	changed, err := cfg.SecretResolver().Refresh(ctx)
+	for _, ref := range changed {
+		logger.WithField("secret", ref).Warn("Secret rotated; restart to apply the new value")
+	}
+	return err
+}
+
+// printEffectiveConfig prints every setting as KEY=value with its source (secrets redacted)
+func printEffectiveConfig(report config.Report) {
+	for _, setting := range report.Settings {
+		fmt.Printf("%s=%s # %s\n", setting.Key, setting.Value, setting.Source)
+	}
+	for _, warning := range report.Warnings {
+		fmt.Printf("# warning: %s\n", warning)
+	}
+}
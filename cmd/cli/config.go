@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gopkg.in/gomail.v2"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// minJWTSecretLength is the HS256 key size recommended by RFC 7518 (256 bits)
+const minJWTSecretLength = 32
+
+// checkResult is the outcome of one configuration check
+type checkResult struct {
+	Level   string // ok, warning, error or skipped
+	Name    string
+	Message string
+}
+
+// configValidate loads and validates the configuration, checks key material and connectivity to the
+// services it points at, and prints a report; it returns the exit code
+func configValidate(args []string) int {
+	flags := flag.NewFlagSet("config validate", flag.ExitOnError)
+	offline := flags.Bool("offline", false, "Skip the Postgres, Redis and SMTP connectivity checks")
+	timeout := flags.Duration("timeout", 5*time.Second, "Timeout of each connectivity check")
+	strict := flags.Bool("strict", false, "Exit with an error on warnings too")
+	flags.Parse(args)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		var validationErr *config.ValidationError
+		if !errors.As(err, &validationErr) {
+			fmt.Printf("Failed to load configuration: %v\n", err)
+			return 1
+		}
+		results := make([]checkResult, len(validationErr.Problems))
+		for i, problem := range validationErr.Problems {
+			results[i] = checkResult{Level: "error", Name: problem.Key, Message: problem.Message}
+		}
+		printResults(results)
+		return 1
+	}
+
+	var results []checkResult
+	for _, warning := range cfg.Report().Warnings {
+		results = append(results, checkResult{Level: "warning", Name: "config", Message: warning})
+	}
+	results = append(results, checkResult{Level: "ok", Name: "config", Message: fmt.Sprintf("Loaded (%s mode)", cfg.Server.Mode)})
+	results = append(results, checkJWT(cfg), checkTLS(cfg))
+
+	if *offline {
+		results = append(results,
+			checkResult{Level: "skipped", Name: "postgres", Message: "--offline"},
+			checkResult{Level: "skipped", Name: "redis", Message: "--offline"},
+			checkResult{Level: "skipped", Name: "smtp", Message: "--offline"},
+		)
+	} else {
+		results = append(results, checkPostgres(cfg, *timeout)...)
+		results = append(results, checkRedis(cfg, *timeout), checkSMTP(cfg, *timeout))
+	}
+
+	printResults(results)
+	for _, r := range results {
+		if r.Level == "error" || (r.Level == "warning" && *strict) {
+			return 1
+		}
+	}
+	return 0
+}
+
+// checkJWT verifies the JWT secret is long enough and signs and validates a token with it
+func checkJWT(cfg *config.Config) checkResult {
+	const name = "jwt"
+	secret := cfg.JWT.Secret
+
+	if config.IsPlaceholderSecret(secret) {
+		return checkResult{Level: "warning", Name: name, Message: "JWT_SECRET is a sample value; set a random secret before deploying"}
+	}
+	if len(secret) < minJWTSecretLength {
+		level := "warning"
+		if cfg.Server.IsProduction() {
+			level = "error"
+		}
+		return checkResult{Level: level, Name: name, Message: fmt.Sprintf("JWT_SECRET is %d bytes; use at least %d", len(secret), minJWTSecretLength)}
+	}
+
+	manager := utils.NewJWTManager(secret, cfg.JWT.AccessExpiry, cfg.JWT.RefreshExpiry, cfg.JWT.Issuer)
+	token, err := manager.GenerateAccessToken(uuid.New(), "config-check@localhost", nil, nil, "")
+	if err == nil {
+		_, err = manager.ValidateToken(token)
+	}
+	if err != nil {
+		return checkResult{Level: "error", Name: name, Message: fmt.Sprintf("Signing a test token failed: %v", err)}
+	}
+	return checkResult{Level: "ok", Name: name, Message: "Secret signs and validates tokens"}
+}
+
+// checkTLS loads the certificate and key files when HTTPS uses them
+func checkTLS(cfg *config.Config) checkResult {
+	const name = "tls"
+	switch {
+	case !cfg.TLS.Enabled:
+		return checkResult{Level: "skipped", Name: name, Message: "TLS_ENABLED=false"}
+	case cfg.TLS.Autocert:
+		return checkResult{Level: "ok", Name: name, Message: fmt.Sprintf("Certificates from Let's Encrypt for %v", cfg.TLS.AutocertHosts)}
+	}
+
+	if _, err := tls.LoadX509KeyPair(cfg.TLS.CertFile, cfg.TLS.KeyFile); err != nil {
+		return checkResult{Level: "error", Name: name, Message: fmt.Sprintf("Loading TLS_CERT_FILE/TLS_KEY_FILE failed: %v", err)}
+	}
+	return checkResult{Level: "ok", Name: name, Message: "Certificate and key loaded"}
+}
+
+// checkPostgres connects to the database and its read replicas and pings them
+func checkPostgres(cfg *config.Config, timeout time.Duration) []checkResult {
+	results := []checkResult{pingPostgres("postgres", cfg.Database.GetDSN(), fmt.Sprintf("%s:%s", cfg.Database.Host, cfg.Database.Port), cfg.Database.DBName, timeout)}
+	for i, dsn := range cfg.Database.GetReplicaDSNs() {
+		replica := cfg.Database.Replicas[i]
+		results = append(results, pingPostgres("postgres replica "+replica, dsn, replica, cfg.Database.DBName, timeout))
+	}
+	return results
+}
+
+// pingPostgres opens a connection with dsn and pings it
+func pingPostgres(name, dsn, addr, dbName string, timeout time.Duration) checkResult {
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		return checkResult{Level: "error", Name: name, Message: err.Error()}
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return checkResult{Level: "error", Name: name, Message: err.Error()}
+	}
+	defer sqlDB.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return checkResult{Level: "error", Name: name, Message: fmt.Sprintf("%s: %v", addr, err)}
+	}
+	return checkResult{Level: "ok", Name: name, Message: fmt.Sprintf("Connected to %s/%s", addr, dbName)}
+}
+
+// checkRedis pings Redis when it is enabled
+func checkRedis(cfg *config.Config, timeout time.Duration) checkResult {
+	const name = "redis"
+	if !cfg.Redis.Enabled {
+		return checkResult{Level: "skipped", Name: name, Message: "REDIS_ENABLED=false"}
+	}
+
+	addr := fmt.Sprintf("%s:%s", cfg.Redis.Host, cfg.Redis.Port)
+	client := redis.NewClient(&redis.Options{
+		Addr:       addr,
+		Password:   cfg.Redis.Password,
+		DB:         cfg.Redis.DB,
+		MaxRetries: -1,
+	})
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return checkResult{Level: "error", Name: name, Message: fmt.Sprintf("%s: %v", addr, err)}
+	}
+	return checkResult{Level: "ok", Name: name, Message: "Connected to " + addr}
+}
+
+// checkSMTP connects and authenticates to the SMTP server the way the email service does, when
+// email is enabled with the smtp provider
+func checkSMTP(cfg *config.Config, timeout time.Duration) checkResult {
+	const name = "smtp"
+	if !cfg.Email.Enabled {
+		return checkResult{Level: "skipped", Name: name, Message: "EMAIL_ENABLED=false"}
+	}
+	if cfg.Email.Provider != "smtp" {
+		return checkResult{Level: "skipped", Name: name, Message: "EMAIL_PROVIDER=" + cfg.Email.Provider}
+	}
+
+	addr := fmt.Sprintf("%s:%d", cfg.Email.SMTPHost, cfg.Email.SMTPPort)
+	dialer := gomail.NewDialer(cfg.Email.SMTPHost, cfg.Email.SMTPPort, cfg.Email.SMTPUser, cfg.Email.SMTPPassword)
+
+	// gomail always waits up to 10s to connect, so the check enforces --timeout itself
+	done := make(chan error, 1)
+	go func() {
+		conn, err := dialer.Dial()
+		if err == nil {
+			err = conn.Close()
+		}
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return checkResult{Level: "error", Name: name, Message: fmt.Sprintf("%s: %v", addr, err)}
+		}
+		return checkResult{Level: "ok", Name: name, Message: "Connected and authenticated to " + addr}
+	case <-time.After(timeout):
+		return checkResult{Level: "error", Name: name, Message: fmt.Sprintf("%s: no answer within %s", addr, timeout)}
+	}
+}
+
+// printResults prints one line per check and a summary
+func printResults(results []checkResult) {
+	errorCount, warningCount := 0, 0
+	for _, r := range results {
+		switch r.Level {
+		case "error":
+			errorCount++
+		case "warning":
+			warningCount++
+		}
+		fmt.Printf("%-8s %-30s %s\n", strings.ToUpper(r.Level), r.Name, r.Message)
+	}
+	fmt.Printf("\nConfiguration check: %d errors, %d warnings\n", errorCount, warningCount)
+}
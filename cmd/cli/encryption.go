@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	oauthdto "go_boilerplate/internal/modules/oauth/dto"
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/database"
+	"go_boilerplate/internal/shared/database/encryption"
+	"go_boilerplate/internal/shared/utils"
+)
+
+// encryptedModels are the models with encrypted columns; add new ones here so rotation covers them
+var encryptedModels = []any{
+	&oauthdto.OAuthAccount{},
+}
+
+// encryptionRotate re-encrypts every encrypted column with the primary key of ENCRYPTION_KEYS,
+// including plaintext values written before encryption was enabled; it returns the exit code
+func encryptionRotate(args []string) int {
+	flags := flag.NewFlagSet("encryption rotate", flag.ExitOnError)
+	batchSize := flags.Int("batch-size", 500, "Rows read per query")
+	timeout := flags.Duration("timeout", time.Hour, "Give up after this long; rerunning continues where it stopped")
+	flags.Parse(args)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Printf("Failed to load configuration: %v\n", err)
+		return 1
+	}
+	if len(cfg.Encryption.Keys) == 0 {
+		fmt.Println("ENCRYPTION_KEYS is not set; nothing to encrypt with")
+		return 1
+	}
+
+	logger := utils.InitLogger(cfg)
+	db, err := database.InitDB(cfg, logger)
+	if err != nil {
+		fmt.Printf("Failed to connect to database: %v\n", err)
+		return 1
+	}
+	keyring := encryption.Default()
+
+	columns, err := encryption.Columns(db, encryptedModels...)
+	if err != nil {
+		fmt.Printf("Failed to list encrypted columns: %v\n", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	status := 0
+	for _, column := range columns {
+		rotated, err := keyring.Rotate(ctx, db, column, *batchSize)
+		if err != nil {
+			fmt.Printf("%s.%s: %d rows re-encrypted, then failed: %v\n", column.Table, column.Name, rotated, err)
+			status = 1
+			continue
+		}
+		fmt.Printf("%s.%s: %d rows re-encrypted with key %q\n", column.Table, column.Name, rotated, keyring.Primary())
+	}
+	return status
+}
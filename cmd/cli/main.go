@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `Usage: go run ./cmd/cli <command> [flags]
+
+Commands:
+  routes check [--strict]   Boot the route wiring without listening and report duplicate paths,
+                            POST/PUT/PATCH routes without a body validator, handlers missing Swagger
+                            annotations and routes without a role/permission check
+  config validate [--offline] [--timeout 5s] [--strict]
+                            Load and validate the configuration, check the JWT secret and TLS files,
+                            and connect to Postgres, Redis and SMTP; exits 1 on errors (for deploy
+                            pipelines)
+  encryption rotate [--batch-size 500] [--timeout 1h]
+                            Re-encrypt encrypted columns (OAuth tokens) with the first key of
+                            ENCRYPTION_KEYS, including plaintext values written before encryption
+                            was enabled; run it after adding or rotating a key
+`
+
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Print(usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] + " " + os.Args[2] {
+	case "routes check":
+		os.Exit(routesCheck(os.Args[3:]))
+	case "config validate":
+		os.Exit(configValidate(os.Args[3:]))
+	case "encryption rotate":
+		os.Exit(encryptionRotate(os.Args[3:]))
+	default:
+		fmt.Print(usage)
+		os.Exit(2)
+	}
+}
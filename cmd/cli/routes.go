@@ -0,0 +1,412 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"go_boilerplate/internal/routes"
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+const (
+	goModule        = "go_boilerplate"
+	sourceDir       = "internal"
+	swaggerBasePath = "/api/v1" // @BasePath in cmd/api/main.go
+)
+
+// Middleware recognised by the checks, by package-qualified function name
+var (
+	validatorMiddleware  = []string{"middleware.BodyValidator"}
+	authMiddleware       = []string{"middleware.JWTAuth"}
+	permissionMiddleware = []string{
+		"middleware.RequireRole",
+		"middleware.RequirePermission",
+		"middleware.RequirePermissionLive",
+		"authorize.RequireOwnerOr",
+	}
+)
+
+// closureSuffix matches the compiler-generated suffix of closures and method values
+var closureSuffix = regexp.MustCompile(`(\.func\d+)+(\.\d+)*$|-fm$`)
+
+// routeEntry is a registered route with the group/app middleware that runs before it
+type routeEntry struct {
+	Method  string
+	Path    string
+	Chain   []string // Package-qualified middleware names, including group middleware
+	Handler string   // Fully qualified name of the final handler
+}
+
+// handlerDocs holds the doc comments of every function in the source tree
+type handlerDocs struct {
+	funcs   map[string][]string   // Fully qualified name -> doc lines
+	methods map[string][][]string // Package path + method name -> doc lines of every method with that name
+}
+
+// lookup finds the doc comment of a handler
+// Handlers registered through an interface (handler.GetUser on a UserHandler) are reported by the runtime
+// as pkg.UserHandler.GetUser, so they resolve to the package's method of that name with a @Router annotation
+func (d handlerDocs) lookup(name string) ([]string, bool) {
+	if doc, ok := d.funcs[name]; ok {
+		return doc, true
+	}
+
+	dot := strings.LastIndex(name, ".")
+	typeDot := strings.LastIndex(name[:max(dot, 0)], ".")
+	if dot < 0 || typeDot < strings.LastIndex(name, "/") {
+		return nil, false
+	}
+
+	candidates := d.methods[name[:typeDot]+"."+name[dot+1:]]
+	for _, doc := range candidates {
+		if len(annotations(doc, "@Router")) > 0 {
+			return doc, true
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0], true
+	}
+	return nil, false
+}
+
+// finding is a single problem reported by the route check
+type finding struct {
+	Level   string // error or warning
+	Method  string
+	Path    string
+	Message string
+}
+
+// routesCheck boots the route wiring without listening and reports problems; it returns the exit code
+func routesCheck(args []string) int {
+	flags := flag.NewFlagSet("routes check", flag.ExitOnError)
+	strict := flags.Bool("strict", false, "Exit with an error on warnings too")
+	flags.Parse(args)
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Printf("Failed to load configuration: %v\n", err)
+		return 1
+	}
+
+	app, err := bootRoutes(cfg)
+	if err != nil {
+		fmt.Printf("Failed to register routes: %v\n", err)
+		return 1
+	}
+
+	docs, err := parseHandlerDocs(sourceDir)
+	if err != nil {
+		fmt.Printf("Failed to parse handler sources: %v\n", err)
+		return 1
+	}
+
+	entries := collectRoutes(app)
+	findings := checkRoutes(entries, docs)
+	printFindings(len(entries), findings)
+
+	for _, f := range findings {
+		if f.Level == "error" || *strict {
+			return 1
+		}
+	}
+	return 0
+}
+
+// bootRoutes registers every module's routes on a fresh app
+// Database and Redis clients are created without connecting, so no services need to be running
+func bootRoutes(cfg *config.Config) (*fiber.App, error) {
+	logger := utils.InitLogger(cfg)
+	logger.SetLevel(logrus.WarnLevel)
+
+	db, err := gorm.Open(postgres.Open(cfg.Database.GetDSN()), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var redisClient *redis.Client
+	if cfg.Redis.Enabled {
+		redisClient = redis.NewClient(&redis.Options{
+			Addr: fmt.Sprintf("%s:%s", cfg.Redis.Host, cfg.Redis.Port),
+		})
+		defer redisClient.Close()
+	}
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	routes.Register(app, db, cfg, utils.NewLogLevels(logger), redisClient, nil)
+	return app, nil
+}
+
+// collectRoutes resolves, for every route, the middleware chain Fiber runs before its handlers
+func collectRoutes(app *fiber.App) []routeEntry {
+	type indexed struct {
+		index int
+		route fiber.Route
+	}
+
+	// GetRoutes(true) is GetRoutes() without Use() middleware, in the same order
+	all := app.GetRoutes()
+	plain := app.GetRoutes(true)
+
+	var uses, handlers []indexed
+	j := 0
+	for i, r := range all {
+		if j < len(plain) && sameRoute(r, plain[j]) {
+			handlers = append(handlers, indexed{i, r})
+			j++
+			continue
+		}
+		uses = append(uses, indexed{i, r})
+	}
+
+	var entries []routeEntry
+	for _, h := range handlers {
+		// HEAD routes are registered automatically for every GET route
+		if h.route.Method == fiber.MethodHead || len(h.route.Handlers) == 0 {
+			continue
+		}
+
+		entry := routeEntry{Method: h.route.Method, Path: h.route.Path}
+		for _, u := range uses {
+			if u.index < h.index && u.route.Method == h.route.Method && matchesPrefix(u.route.Path, h.route.Path) {
+				for _, mw := range u.route.Handlers {
+					entry.Chain = append(entry.Chain, shortName(funcName(mw)))
+				}
+			}
+		}
+		last := len(h.route.Handlers) - 1
+		for _, mw := range h.route.Handlers[:last] {
+			entry.Chain = append(entry.Chain, shortName(funcName(mw)))
+		}
+		entry.Handler = funcName(h.route.Handlers[last])
+
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// checkRoutes runs the static checks over the collected routes
+func checkRoutes(entries []routeEntry, docs handlerDocs) []finding {
+	var findings []finding
+	report := func(e routeEntry, level, format string, args ...any) {
+		findings = append(findings, finding{Level: level, Method: e.Method, Path: e.Path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	seen := map[string]bool{}
+	for _, e := range entries {
+		key := e.Method + " " + swaggerPath(e.Path)
+		if seen[key] {
+			report(e, "error", "duplicate route: only the first registration is reachable")
+		}
+		seen[key] = true
+
+		switch e.Method {
+		case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch:
+			if !hasAny(e.Chain, validatorMiddleware) {
+				report(e, "warning", "no BodyValidator middleware")
+			}
+		}
+
+		authenticated := hasAny(e.Chain, authMiddleware)
+		if authenticated && !hasAny(e.Chain, permissionMiddleware) {
+			report(e, "warning", "authenticated but no role/permission middleware (checks inside the handler are not visible)")
+		}
+
+		handler := shortName(e.Handler)
+		doc, ok := docs.lookup(e.Handler)
+		if !ok {
+			report(e, "error", "handler %s has no Swagger annotations", handler)
+			continue
+		}
+
+		routers := annotations(doc, "@Router")
+		if len(routers) == 0 {
+			report(e, "error", "handler %s has no @Router annotation", handler)
+		} else if !routerMatches(routers, e) {
+			report(e, "warning", "@Router of %s (%s) does not match the route", handler, strings.Join(routers, "; "))
+		}
+
+		if len(annotations(doc, "@Security")) > 0 && !authenticated {
+			report(e, "error", "handler %s is documented with @Security but the route has no JWTAuth", handler)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Path != findings[j].Path {
+			return findings[i].Path < findings[j].Path
+		}
+		return findings[i].Method < findings[j].Method
+	})
+	return findings
+}
+
+// printFindings prints the report and a summary line
+func printFindings(routeCount int, findings []finding) {
+	errorCount := 0
+	for _, f := range findings {
+		if f.Level == "error" {
+			errorCount++
+		}
+		fmt.Printf("%-8s %-7s %-50s %s\n", strings.ToUpper(f.Level), f.Method, f.Path, f.Message)
+	}
+	fmt.Printf("\nChecked %d routes: %d errors, %d warnings\n", routeCount, errorCount, len(findings)-errorCount)
+}
+
+// parseHandlerDocs collects the doc comments of every function under root
+func parseHandlerDocs(root string) (handlerDocs, error) {
+	docs := handlerDocs{funcs: map[string][]string{}, methods: map[string][][]string{}}
+	fset := token.NewFileSet()
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+
+		pkg := goModule + "/" + filepath.ToSlash(filepath.Dir(path))
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Doc == nil {
+				continue
+			}
+			doc := strings.Split(fn.Doc.Text(), "\n")
+			docs.funcs[pkg+"."+declName(fn)] = doc
+			if fn.Recv != nil {
+				docs.methods[pkg+"."+fn.Name.Name] = append(docs.methods[pkg+"."+fn.Name.Name], doc)
+			}
+		}
+		return nil
+	})
+	return docs, err
+}
+
+// declName returns a function name as the runtime reports it, e.g. (*userHandler).GetUser
+func declName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+
+	switch recv := fn.Recv.List[0].Type.(type) {
+	case *ast.StarExpr:
+		if ident, ok := recv.X.(*ast.Ident); ok {
+			return "(*" + ident.Name + ")." + fn.Name.Name
+		}
+	case *ast.Ident:
+		return recv.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// annotations returns the values of a Swagger annotation in a doc comment
+func annotations(doc []string, name string) []string {
+	var values []string
+	for _, line := range doc {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), name+" "); ok {
+			values = append(values, strings.TrimSpace(rest))
+		}
+	}
+	return values
+}
+
+// routerMatches reports whether any @Router annotation ("/users/{id} [put]") describes the route
+func routerMatches(routers []string, e routeEntry) bool {
+	want := swaggerPath(strings.TrimPrefix(e.Path, swaggerBasePath))
+	for _, router := range routers {
+		fields := strings.Fields(router)
+		if len(fields) != 2 {
+			continue
+		}
+		method := strings.Trim(fields[1], "[]")
+		if strings.EqualFold(method, e.Method) && swaggerPath(fields[0]) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// swaggerPath normalizes a Fiber path to Swagger notation (/users/:id/ -> /users/{id})
+func swaggerPath(path string) string {
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") {
+			segments[i] = "{" + strings.TrimSuffix(strings.TrimPrefix(s, ":"), "?") + "}"
+		}
+	}
+	if normalized := strings.Join(segments, "/"); normalized != "" {
+		return normalized
+	}
+	return "/"
+}
+
+// matchesPrefix reports whether middleware registered with Use(prefix) runs for path
+func matchesPrefix(prefix, path string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// sameRoute reports whether a and b are the same registration
+func sameRoute(a, b fiber.Route) bool {
+	if a.Method != b.Method || a.Path != b.Path || len(a.Handlers) != len(b.Handlers) {
+		return false
+	}
+	for i := range a.Handlers {
+		if reflect.ValueOf(a.Handlers[i]).Pointer() != reflect.ValueOf(b.Handlers[i]).Pointer() {
+			return false
+		}
+	}
+	return true
+}
+
+// funcName returns the fully qualified name of a handler without closure suffixes
+func funcName(h fiber.Handler) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(h).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+	return closureSuffix.ReplaceAllString(fn.Name(), "")
+}
+
+// shortName strips the import path, e.g. go_boilerplate/internal/shared/middleware.JWTAuth -> middleware.JWTAuth
+func shortName(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// hasAny reports whether chain contains any of names
+func hasAny(chain, names []string) bool {
+	for _, c := range chain {
+		for _, n := range names {
+			if c == n {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -2,12 +2,16 @@ package main
 
 import (
 	"bytes"
+	"flag"
 	"fmt"
+	"go/format"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
 	"time"
+
+	"go.yaml.in/yaml/v3"
 )
 
 type Config struct {
@@ -15,13 +19,75 @@ type Config struct {
 	NameUpper   string // Product
 	NamePlural  string // products
 	PackagePath string // go_boilerplate/internal/modules/product/dto
+	WithTrash   bool   // Generate trash endpoints (list trashed, restore, purge) and the retention purge job
 }
 
 const (
-	modulePath = "internal/modules"
-	mainGoPath = "cmd/api/main.go"
+	modulePath          = "internal/modules"
+	mainGoPath          = "cmd/api/main.go"
+	routesGoPath        = "internal/routes/routes.go"
+	modulesManifestPath = "internal/modules/modules.yaml"
+
+	// generatorVersion is recorded in manifests so future tooling (e.g. gen update) knows which templates a module came from
+	generatorVersion = "1.0.0"
 )
 
+// ModuleManifest describes a generated module; written to internal/modules/<name>/module.yaml
+type ModuleManifest struct {
+	Name        string             `yaml:"name"`
+	Version     string             `yaml:"version"`
+	Generator   string             `yaml:"generator"` // Generator version the module was last generated with
+	Table       string             `yaml:"table"`
+	BasePath    string             `yaml:"base_path"`
+	Fields      []ManifestField    `yaml:"fields"`
+	Relations   []ManifestRelation `yaml:"relations"`
+	Flags       ManifestFlags      `yaml:"flags"`
+	Permissions []string           `yaml:"permissions"`
+	DependsOn   []string           `yaml:"depends_on"` // Other modules this one imports
+	Changelog   []ChangelogEntry   `yaml:"changelog"`
+}
+
+// ManifestField describes a model field
+type ManifestField struct {
+	Name   string `yaml:"name"`
+	Type   string `yaml:"type"`
+	Column string `yaml:"column"`
+}
+
+// ManifestRelation describes a relation to another module's model
+type ManifestRelation struct {
+	Name   string `yaml:"name"`
+	Type   string `yaml:"type"` // belongs_to, has_one, has_many, many_to_many
+	Module string `yaml:"module"`
+}
+
+// ManifestFlags records the generator flags a module was generated with
+type ManifestFlags struct {
+	WithTrash bool `yaml:"with_trash"`
+}
+
+// ChangelogEntry records one generation of a module
+type ChangelogEntry struct {
+	Version string   `yaml:"version"`
+	Date    string   `yaml:"date"`
+	Changes []string `yaml:"changes"`
+}
+
+// ModulesManifest lists every module of the project; written to internal/modules/modules.yaml
+type ModulesManifest struct {
+	Modules []ModuleIndexEntry `yaml:"modules"`
+}
+
+// ModuleIndexEntry is one module in the project-wide manifest
+// Hand-written modules have no manifest and version
+type ModuleIndexEntry struct {
+	Name      string `yaml:"name"`
+	Path      string `yaml:"path"`
+	Generated bool   `yaml:"generated"`
+	Version   string `yaml:"version,omitempty"`
+	Manifest  string `yaml:"manifest,omitempty"`
+}
+
 var templates = map[string]string{
 	"model.go": `package {{.Name}}
 
@@ -49,74 +115,119 @@ func ({{.NameUpper}}) TableName() string {
 	"repository.go": `package {{.Name}}
 
 import (
+	"context"
+{{- if .WithTrash}}
+	"time"
+{{- end}}
+
+	"go_boilerplate/internal/shared/database/repository"
+	"go_boilerplate/internal/shared/filter"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type {{.NameUpper}}Repository interface {
-	Create(item *{{.NameUpper}}) error
-	FindByID(id uuid.UUID) (*{{.NameUpper}}, error)
-	FindAll(page, limit int) ([]{{.NameUpper}}, int64, error)
-	Update(item *{{.NameUpper}}) error
-	Delete(id uuid.UUID) error
-}
-
+	Create(ctx context.Context, item *{{.NameUpper}}) error
+	FindByID(ctx context.Context, id uuid.UUID) (*{{.NameUpper}}, error)
+	FindAll(ctx context.Context, offset, limit int, f filter.Filter) ([]{{.NameUpper}}, int64, error)
+	Update(ctx context.Context, item *{{.NameUpper}}) error
+	Delete(ctx context.Context, id uuid.UUID) error
+{{- if .WithTrash}}
+	FindTrashed(ctx context.Context, offset, limit int, f filter.Filter) ([]{{.NameUpper}}, int64, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	Purge(ctx context.Context, id uuid.UUID) error
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+{{- end}}
+}
+
+// {{.Name}}Repository implements {{.NameUpper}}Repository; Create, FindByID, FindAll, Update and Delete
+// come from repository.Repository. Add module-specific queries here.
 type {{.Name}}Repository struct {
+	repository.Repository[{{.NameUpper}}]
 	db *gorm.DB
 }
 
 func New{{.NameUpper}}Repository(db *gorm.DB) {{.NameUpper}}Repository {
-	return &{{.Name}}Repository{db: db}
-}
-
-func (r *{{.Name}}Repository) Create(item *{{.NameUpper}}) error {
-	return r.db.Create(item).Error
+	return &{{.Name}}Repository{Repository: repository.New[{{.NameUpper}}](db), db: db}
 }
+{{- if .WithTrash}}
 
-func (r *{{.Name}}Repository) FindByID(id uuid.UUID) (*{{.NameUpper}}, error) {
-	var item {{.NameUpper}}
-	if err := r.db.First(&item, "id = ?", id).Error; err != nil {
-		return nil, err
-	}
-	return &item, nil
-}
-
-func (r *{{.Name}}Repository) FindAll(page, limit int) ([]{{.NameUpper}}, int64, error) {
+// FindTrashed lists soft-deleted {{.NamePlural}}, most recently deleted first
+func (r *{{.Name}}Repository) FindTrashed(ctx context.Context, offset, limit int, f filter.Filter) ([]{{.NameUpper}}, int64, error) {
 	var items []{{.NameUpper}}
 	var total int64
-	offset := (page - 1) * limit
 
-	if err := r.db.Model(&{{.NameUpper}}{}).Count(&total).Error; err != nil {
+	if err := r.db.WithContext(ctx).Unscoped().Model(&{{.NameUpper}}{}).Where("deleted_at IS NOT NULL").Scopes(f.Scope()).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	if err := r.db.Offset(offset).Limit(limit).Find(&items).Error; err != nil {
+	if err := r.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").Scopes(f.Scope()).
+		Order("deleted_at DESC").Offset(offset).Limit(limit).Find(&items).Error; err != nil {
 		return nil, 0, err
 	}
 	return items, total, nil
 }
 
-func (r *{{.Name}}Repository) Update(item *{{.NameUpper}}) error {
-	return r.db.Save(item).Error
+// Restore clears deleted_at on a trashed {{.Name}}
+func (r *{{.Name}}Repository) Restore(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Unscoped().Model(&{{.NameUpper}}{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge permanently deletes a {{.Name}}; only items already in the trash can be purged
+func (r *{{.Name}}Repository) Purge(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&{{.NameUpper}}{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
-func (r *{{.Name}}Repository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&{{.NameUpper}}{}, "id = ?", id).Error
+// PurgeDeletedBefore permanently deletes every {{.Name}} trashed before cutoff
+func (r *{{.Name}}Repository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).Delete(&{{.NameUpper}}{})
+	return result.RowsAffected, result.Error
 }
+{{- end}}
 `,
 	"service.go": `package {{.Name}}
 
 import (
+	"context"
+{{- if .WithTrash}}
+	"time"
+{{- end}}
+
 	"{{.PackagePath}}"
+	"go_boilerplate/internal/shared/filter"
+
 	"github.com/google/uuid"
 )
 
 type {{.NameUpper}}Service interface {
-	Create(req *dto.Create{{.NameUpper}}Request) (*{{.NameUpper}}, error)
-	GetByID(id uuid.UUID) (*{{.NameUpper}}, error)
-	GetAll(page, limit int) ([]{{.NameUpper}}, int64, error)
-	Update(id uuid.UUID, req *dto.Update{{.NameUpper}}Request) (*{{.NameUpper}}, error)
-	Delete(id uuid.UUID) error
+	Create(ctx context.Context, req *dto.Create{{.NameUpper}}Request) (*{{.NameUpper}}, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*{{.NameUpper}}, error)
+	GetAll(ctx context.Context, page, limit int, f filter.Filter) ([]{{.NameUpper}}, int64, error)
+	Update(ctx context.Context, id uuid.UUID, req *dto.Update{{.NameUpper}}Request) (*{{.NameUpper}}, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+{{- if .WithTrash}}
+	GetTrashed(ctx context.Context, page, limit int, f filter.Filter) ([]{{.NameUpper}}, int64, error)
+	Restore(ctx context.Context, id uuid.UUID) (*{{.NameUpper}}, error)
+	Purge(ctx context.Context, id uuid.UUID) error
+	PurgeExpired(ctx context.Context, cutoff time.Time) (int64, error)
+{{- end}}
 }
 
 type {{.Name}}Service struct {
@@ -127,27 +238,27 @@ func New{{.NameUpper}}Service(repo {{.NameUpper}}Repository) {{.NameUpper}}Servi
 	return &{{.Name}}Service{repo: repo}
 }
 
-func (s *{{.Name}}Service) Create(req *dto.Create{{.NameUpper}}Request) (*{{.NameUpper}}, error) {
+func (s *{{.Name}}Service) Create(ctx context.Context, req *dto.Create{{.NameUpper}}Request) (*{{.NameUpper}}, error) {
 	item := &{{.NameUpper}}{
 		ID:   uuid.New(),
 		Name: req.Name,
 	}
-	if err := s.repo.Create(item); err != nil {
+	if err := s.repo.Create(ctx, item); err != nil {
 		return nil, err
 	}
 	return item, nil
 }
 
-func (s *{{.Name}}Service) GetByID(id uuid.UUID) (*{{.NameUpper}}, error) {
-	return s.repo.FindByID(id)
+func (s *{{.Name}}Service) GetByID(ctx context.Context, id uuid.UUID) (*{{.NameUpper}}, error) {
+	return s.repo.FindByID(ctx, id)
 }
 
-func (s *{{.Name}}Service) GetAll(page, limit int) ([]{{.NameUpper}}, int64, error) {
-	return s.repo.FindAll(page, limit)
+func (s *{{.Name}}Service) GetAll(ctx context.Context, page, limit int, f filter.Filter) ([]{{.NameUpper}}, int64, error) {
+	return s.repo.FindAll(ctx, (page-1)*limit, limit, f)
 }
 
-func (s *{{.Name}}Service) Update(id uuid.UUID, req *dto.Update{{.NameUpper}}Request) (*{{.NameUpper}}, error) {
-	item, err := s.repo.FindByID(id)
+func (s *{{.Name}}Service) Update(ctx context.Context, id uuid.UUID, req *dto.Update{{.NameUpper}}Request) (*{{.NameUpper}}, error) {
+	item, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -156,15 +267,37 @@ func (s *{{.Name}}Service) Update(id uuid.UUID, req *dto.Update{{.NameUpper}}Req
 		item.Name = req.Name
 	}
 
-	if err := s.repo.Update(item); err != nil {
+	if err := s.repo.Update(ctx, item); err != nil {
 		return nil, err
 	}
 	return item, nil
 }
 
-func (s *{{.Name}}Service) Delete(id uuid.UUID) error {
-	return s.repo.Delete(id)
+func (s *{{.Name}}Service) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+{{- if .WithTrash}}
+
+func (s *{{.Name}}Service) GetTrashed(ctx context.Context, page, limit int, f filter.Filter) ([]{{.NameUpper}}, int64, error) {
+	return s.repo.FindTrashed(ctx, (page-1)*limit, limit, f)
+}
+
+func (s *{{.Name}}Service) Restore(ctx context.Context, id uuid.UUID) (*{{.NameUpper}}, error) {
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+func (s *{{.Name}}Service) Purge(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Purge(ctx, id)
+}
+
+// PurgeExpired permanently deletes {{.NamePlural}} that were trashed before cutoff
+func (s *{{.Name}}Service) PurgeExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	return s.repo.PurgeDeletedBefore(ctx, cutoff)
 }
+{{- end}}
 `,
 	"handler.go": `package {{.Name}}
 
@@ -172,12 +305,20 @@ import (
 	"strconv"
 
 	"{{.PackagePath}}"
+	"go_boilerplate/internal/shared/filter"
+	"go_boilerplate/internal/shared/serializer"
 	"go_boilerplate/internal/shared/utils"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
 
+// {{.Name}}Filters are the fields accepted by ?filter[field][op]= on the list endpoint
+var {{.Name}}Filters = filter.Fields{
+	"name":       {Column: "name", Type: filter.String},
+	"created_at": {Column: "created_at", Type: filter.Time},
+}
+
 type {{.NameUpper}}Handler struct {
 	service {{.NameUpper}}Service
 }
@@ -197,7 +338,7 @@ func New{{.NameUpper}}Handler(service {{.NameUpper}}Service) *{{.NameUpper}}Hand
 func (h *{{.NameUpper}}Handler) Create(c *fiber.Ctx) error {
 	req := c.Locals("validatedBody").(*dto.Create{{.NameUpper}}Request)
 
-	item, err := h.service.Create(req)
+	item, err := h.service.Create(c.UserContext(), req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to create {{.Name}}", err)
 	}
@@ -210,6 +351,7 @@ func (h *{{.NameUpper}}Handler) Create(c *fiber.Ctx) error {
 // @Tags {{.NameUpper}}
 // @Produce json
 // @Param id path string true "ID"
+// @Param fields query string false "Comma-separated fields to return (id is always returned)"
 // @Success 200 {object} utils.APIResponse
 // @Router /{{.NamePlural}}/{id} [get]
 func (h *{{.NameUpper}}Handler) Get(c *fiber.Ctx) error {
@@ -218,12 +360,22 @@ func (h *{{.NameUpper}}Handler) Get(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid ID", err)
 	}
 
-	item, err := h.service.GetByID(id)
+	opts, err := serializer.FromQuery(c)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid include", err)
+	}
+
+	item, err := h.service.GetByID(c.UserContext(), id)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusNotFound, "{{.NameUpper}} not found", err)
 	}
 
-	return utils.SuccessResponse(c, fiber.StatusOK, item, "{{.NameUpper}} retrieved successfully")
+	response, err := opts.Apply(item, "")
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve {{.Name}}", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, response, "{{.NameUpper}} retrieved successfully")
 }
 
 // List handles listing all {{.NamePlural}}
@@ -232,23 +384,40 @@ func (h *{{.NameUpper}}Handler) Get(c *fiber.Ctx) error {
 // @Produce json
 // @Param page query int false "Page"
 // @Param limit query int false "Limit"
+// @Param filter[name][like] query string false "Filter by name"
+// @Param fields query string false "Comma-separated fields to return (id is always returned)"
 // @Success 200 {object} utils.APIResponse
 // @Router /{{.NamePlural}} [get]
 func (h *{{.NameUpper}}Handler) List(c *fiber.Ctx) error {
 	page, _ := strconv.Atoi(c.Query("page", "1"))
 	limit, _ := strconv.Atoi(c.Query("limit", "10"))
 
-	items, total, err := h.service.GetAll(page, limit)
+	f, err := filter.FromQuery(c, {{.Name}}Filters)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid filter", err)
+	}
+
+	opts, err := serializer.FromQuery(c)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid include", err)
+	}
+
+	items, total, err := h.service.GetAll(c.UserContext(), page, limit, f)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve {{.NamePlural}}", err)
 	}
 
-	return utils.SuccessResponse(c, fiber.StatusOK, fiber.Map{
+	response, err := opts.Apply(fiber.Map{
 		"items": items,
 		"total": total,
 		"page":  page,
 		"limit": limit,
-	}, "{{.NamePlural}} retrieved successfully")
+	}, "items")
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve {{.NamePlural}}", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, response, "{{.NamePlural}} retrieved successfully")
 }
 
 // Update handles updating a {{.Name}}
@@ -268,7 +437,7 @@ func (h *{{.NameUpper}}Handler) Update(c *fiber.Ctx) error {
 
 	req := c.Locals("validatedBody").(*dto.Update{{.NameUpper}}Request)
 
-	item, err := h.service.Update(id, req)
+	item, err := h.service.Update(c.UserContext(), id, req)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to update {{.Name}}", err)
 	}
@@ -289,17 +458,95 @@ func (h *{{.NameUpper}}Handler) Delete(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid ID", err)
 	}
 
-	if err := h.service.Delete(id); err != nil {
+	if err := h.service.Delete(c.UserContext(), id); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to delete {{.Name}}", err)
 	}
 
 	return utils.SuccessResponse(c, fiber.StatusOK, nil, "{{.NameUpper}} deleted successfully")
 }
+{{- if .WithTrash}}
+
+// ListTrash handles listing soft-deleted {{.NamePlural}}
+// @Summary List trashed {{.NamePlural}}
+// @Tags {{.NameUpper}}
+// @Produce json
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Param filter[name][like] query string false "Filter by name"
+// @Success 200 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /{{.NamePlural}}/trash [get]
+func (h *{{.NameUpper}}Handler) ListTrash(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+
+	f, err := filter.FromQuery(c, {{.Name}}Filters)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid filter", err)
+	}
+
+	items, total, err := h.service.GetTrashed(c.UserContext(), page, limit, f)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve trashed {{.NamePlural}}", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, fiber.Map{
+		"items": items,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	}, "Trashed {{.NamePlural}} retrieved successfully")
+}
+
+// Restore handles restoring a soft-deleted {{.Name}}
+// @Summary Restore {{.Name}}
+// @Tags {{.NameUpper}}
+// @Produce json
+// @Param id path string true "ID"
+// @Success 200 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /{{.NamePlural}}/{id}/restore [post]
+func (h *{{.NameUpper}}Handler) Restore(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid ID", err)
+	}
+
+	item, err := h.service.Restore(c.UserContext(), id)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Trashed {{.Name}} not found", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, item, "{{.NameUpper}} restored successfully")
+}
+
+// Purge handles permanently deleting a trashed {{.Name}}
+// @Summary Purge {{.Name}}
+// @Tags {{.NameUpper}}
+// @Produce json
+// @Param id path string true "ID"
+// @Success 200 {object} utils.APIResponse
+// @Security BearerAuth
+// @Router /{{.NamePlural}}/{id}/purge [delete]
+func (h *{{.NameUpper}}Handler) Purge(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid ID", err)
+	}
+
+	if err := h.service.Purge(c.UserContext(), id); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Trashed {{.Name}} not found", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, nil, "{{.NameUpper}} permanently deleted")
+}
+{{- end}}
 `,
 	"routes.go": `package {{.Name}}
 
 import (
 	"{{.PackagePath}}"
+	"go_boilerplate/internal/shared/apiversion"
 	"go_boilerplate/internal/shared/config"
 	"go_boilerplate/internal/shared/middleware"
 
@@ -313,15 +560,52 @@ func RegisterRoutes(app *fiber.App, db *gorm.DB, cfg *config.Config, logger *log
 	service := New{{.NameUpper}}Service(repo)
 	handler := New{{.NameUpper}}Handler(service)
 
-	api := app.Group("/api/v1/{{.NamePlural}}")
+	api := apiversion.Group(app, "v1").Group("/{{.NamePlural}}")
 	api.Use(middleware.JWTAuth(cfg))
 
 	api.Post("/", middleware.BodyValidator(&dto.Create{{.NameUpper}}Request{}), handler.Create)
 	api.Get("/", handler.List)
+{{- if .WithTrash}}
+
+	// Trash: soft-deleted items can be listed and restored until purged
+	api.Get("/trash", middleware.RequirePermission(cfg, Perm{{.NameUpper}}Restore), handler.ListTrash)
+	api.Post("/:id/restore", middleware.RequirePermission(cfg, Perm{{.NameUpper}}Restore), handler.Restore)
+	api.Delete("/:id/purge", middleware.RequirePermission(cfg, Perm{{.NameUpper}}Purge), handler.Purge)
+
+{{end}}
 	api.Get("/:id", handler.Get)
 	api.Put("/:id", middleware.BodyValidator(&dto.Update{{.NameUpper}}Request{}), handler.Update)
 	api.Delete("/:id", handler.Delete)
 }
+`,
+	"permissions.go": `package {{.Name}}
+
+import "go_boilerplate/internal/shared/permission"
+
+// {{.NameUpper}} permissions
+const (
+	Perm{{.NameUpper}}Create = "{{.NamePlural}}.create"
+	Perm{{.NameUpper}}Read   = "{{.NamePlural}}.read"
+	Perm{{.NameUpper}}Update = "{{.NamePlural}}.update"
+	Perm{{.NameUpper}}Delete = "{{.NamePlural}}.delete"
+{{- if .WithTrash}}
+	Perm{{.NameUpper}}Restore = "{{.NamePlural}}.restore"
+	Perm{{.NameUpper}}Purge   = "{{.NamePlural}}.purge"
+{{- end}}
+)
+
+func init() {
+	permission.Register(
+		permission.Permission{Name: Perm{{.NameUpper}}Create, Description: "Create {{.NamePlural}}"},
+		permission.Permission{Name: Perm{{.NameUpper}}Read, Description: "View {{.NamePlural}}"},
+		permission.Permission{Name: Perm{{.NameUpper}}Update, Description: "Update {{.NamePlural}}"},
+		permission.Permission{Name: Perm{{.NameUpper}}Delete, Description: "Delete {{.NamePlural}}"},
+{{- if .WithTrash}}
+		permission.Permission{Name: Perm{{.NameUpper}}Restore, Description: "View and restore trashed {{.NamePlural}}"},
+		permission.Permission{Name: Perm{{.NameUpper}}Purge, Description: "Permanently delete trashed {{.NamePlural}}"},
+{{- end}}
+	)
+}
 `,
 	"dto/request.go": `package dto
 
@@ -349,13 +633,58 @@ type {{.NameUpper}}Response struct {
 `,
 }
 
+// trashTemplates are only generated with --with-trash
+var trashTemplates = map[string]string{
+	"trash.go": `package {{.Name}}
+
+import (
+	"context"
+	"time"
+
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/jobs"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// TrashPurgeJob permanently deletes {{.NamePlural}} that stayed in the trash longer than TRASH_RETENTION
+func TrashPurgeJob(db *gorm.DB, cfg *config.Config, logger *logrus.Logger) jobs.Job {
+	service := New{{.NameUpper}}Service(New{{.NameUpper}}Repository(db))
+
+	return jobs.Job{
+		Name:      "purge-trashed-{{.NamePlural}}",
+		Interval:  cfg.Trash.PurgeInterval,
+		Exclusive: true,
+		Run: func(ctx context.Context) error {
+			if cfg.Trash.Retention <= 0 {
+				return nil
+			}
+
+			purged, err := service.PurgeExpired(ctx, time.Now().Add(-cfg.Trash.Retention))
+			if err != nil {
+				return err
+			}
+			if purged > 0 {
+				logger.Infof("Purged %d trashed {{.NamePlural}}", purged)
+			}
+			return nil
+		},
+	}
+}
+`,
+}
+
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run cmd/gen/main.go <module-name>")
+	withTrash := flag.Bool("with-trash", false, "generate trash endpoints (list trashed, restore, purge) and a retention purge job")
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		fmt.Println("Usage: go run cmd/gen/main.go [--with-trash] <module-name>")
 		os.Exit(1)
 	}
 
-	name := strings.ToLower(os.Args[1])
+	name := strings.ToLower(flag.Arg(0))
 	nameUpper := strings.Title(name)
 	namePlural := name + "s"
 	if strings.HasSuffix(name, "y") {
@@ -367,6 +696,7 @@ func main() {
 		NameUpper:   nameUpper,
 		NamePlural:  namePlural,
 		PackagePath: "go_boilerplate/internal/modules/" + name + "/dto",
+		WithTrash:   *withTrash,
 	}
 
 	// 1. Create Directories
@@ -377,7 +707,33 @@ func main() {
 	}
 
 	// 2. Generate Files
-	for fileName, tmplStr := range templates {
+	generateFiles(baseDir, templates, config)
+	if config.WithTrash {
+		generateFiles(baseDir, trashTemplates, config)
+	}
+
+	// 3. Auto Inject to main.go (migration, jobs) and the route registry
+	injectToMain(config)
+	injectToRoutes(config)
+
+	// 4. Generate SQL Migrations
+	generateMigrations(config)
+
+	// 5. Write the module manifest and update the project-wide one
+	manifest := writeModuleManifest(baseDir, config)
+	updateModulesManifest(manifest, baseDir)
+
+	fmt.Printf("\n🚀 Module '%s' generated successfully!\n", name)
+	fmt.Println("Next steps:")
+	fmt.Printf("1. Refresh Swagger: make swagger\n")
+	fmt.Printf("2. Sync built-in roles with the new %s.* permissions: preview with GET /api/v1/roles/permission-sync, apply with POST\n", namePlural)
+	if config.WithTrash {
+		fmt.Printf("3. Grant %s.restore and %s.purge to the roles that manage the trash\n", namePlural, namePlural)
+	}
+}
+
+func generateFiles(baseDir string, files map[string]string, config Config) {
+	for fileName, tmplStr := range files {
 		filePath := filepath.Join(baseDir, fileName)
 
 		tmpl, err := template.New(fileName).Parse(tmplStr)
@@ -392,28 +748,61 @@ func main() {
 			continue
 		}
 
-		if err := os.WriteFile(filePath, buf.Bytes(), 0644); err != nil {
+		// Optional template sections leave stray blank lines; gofmt tidies them up
+		source := buf.Bytes()
+		if formatted, err := format.Source(source); err == nil {
+			source = formatted
+		}
+
+		if err := os.WriteFile(filePath, source, 0644); err != nil {
 			fmt.Printf("Error writing file %s: %v\n", filePath, err)
 			continue
 		}
 		fmt.Printf("✓ Created %s\n", filePath)
 	}
+}
 
-	// 3. Auto Inject to main.go
-	injectToMain(config)
-
-	// 4. Generate SQL Migrations
-	generateMigrations(config)
+func injectToMain(config Config) {
+	inject(mainGoPath, func(line string) []string {
+		switch {
+		case strings.Contains(line, "// [MODULE_IMPORT_MARKER]"):
+			return []string{moduleImport(config)}
+		case strings.Contains(line, "// [MODULE_MIGRATION_MARKER]"):
+			return []string{fmt.Sprintf("\t\t\t&%sModule.%s{},", config.Name, config.NameUpper)}
+		case config.WithTrash && strings.Contains(line, "// [MODULE_JOB_MARKER]"):
+			// Trash purge job
+			return []string{fmt.Sprintf("\tscheduler.Add(%sModule.TrashPurgeJob(db, cfg, logger))", config.Name)}
+		}
+		return nil
+	})
+}
+
+func injectToRoutes(config Config) {
+	inject(routesGoPath, func(line string) []string {
+		switch {
+		case strings.Contains(line, "// [MODULE_IMPORT_MARKER]"):
+			return []string{moduleImport(config)}
+		case strings.Contains(line, "// [MODULE_ROUTE_MARKER]"):
+			return []string{
+				fmt.Sprintf("\t// %s routes", config.NameUpper),
+				fmt.Sprintf("\t%sModule.RegisterRoutes(app, db, cfg, logs.Module(%q))", config.Name, config.Name),
+				fmt.Sprintf("\tlogger.Info(\"✓ %s routes registered\")", config.NameUpper),
+				"",
+			}
+		}
+		return nil
+	})
+}
 
-	fmt.Printf("\n🚀 Module '%s' generated successfully!\n", name)
-	fmt.Println("Next steps:")
-	fmt.Printf("1. Refresh Swagger: make swagger\n")
+func moduleImport(config Config) string {
+	return fmt.Sprintf("\t%sModule \"go_boilerplate/internal/modules/%s\"", config.Name, config.Name)
 }
 
-func injectToMain(config Config) {
-	content, err := os.ReadFile(mainGoPath)
+// inject appends the lines returned by linesAfter below each matching marker line of path
+func inject(path string, linesAfter func(line string) []string) {
+	content, err := os.ReadFile(path)
 	if err != nil {
-		fmt.Printf("Error reading main.go: %v\n", err)
+		fmt.Printf("Error reading %s: %v\n", path, err)
 		return
 	}
 
@@ -422,31 +811,130 @@ func injectToMain(config Config) {
 
 	for _, line := range lines {
 		newLines = append(newLines, line)
+		newLines = append(newLines, linesAfter(line)...)
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(newLines, "\n")), 0644); err != nil {
+		fmt.Printf("Error updating %s: %v\n", path, err)
+	} else {
+		fmt.Printf("✓ Auto-injected to %s\n", path)
+	}
+}
+
+// writeModuleManifest writes module.yaml, keeping the changelog of a previous generation and bumping its minor version
+func writeModuleManifest(baseDir string, config Config) ModuleManifest {
+	path := filepath.Join(baseDir, "module.yaml")
+
+	flags := "none"
+	if config.WithTrash {
+		flags = "--with-trash"
+	}
+
+	manifest := ModuleManifest{
+		Name:      config.Name,
+		Version:   "0.1.0",
+		Generator: generatorVersion,
+		Table:     "t_" + config.NamePlural,
+		BasePath:  "/api/v1/" + config.NamePlural,
+		Fields: []ManifestField{
+			{Name: "ID", Type: "uuid.UUID", Column: "id"},
+			{Name: "Name", Type: "string", Column: "name"},
+			{Name: "CreatedAt", Type: "time.Time", Column: "created_at"},
+			{Name: "UpdatedAt", Type: "time.Time", Column: "updated_at"},
+			{Name: "DeletedAt", Type: "gorm.DeletedAt", Column: "deleted_at"},
+		},
+		Relations: []ManifestRelation{},
+		Flags:     ManifestFlags{WithTrash: config.WithTrash},
+		DependsOn: []string{},
+	}
+	for _, action := range []string{"create", "read", "update", "delete"} {
+		manifest.Permissions = append(manifest.Permissions, config.NamePlural+"."+action)
+	}
+	if config.WithTrash {
+		manifest.Permissions = append(manifest.Permissions, config.NamePlural+".restore", config.NamePlural+".purge")
+	}
 
-		// Inject Import
-		if strings.Contains(line, "// [MODULE_IMPORT_MARKER]") {
-			newLines = append(newLines, fmt.Sprintf("\t%sModule \"go_boilerplate/internal/modules/%s\"", config.Name, config.Name))
+	change := fmt.Sprintf("Generated with generator %s (flags: %s)", generatorVersion, flags)
+	if content, err := os.ReadFile(path); err == nil {
+		var previous ModuleManifest
+		if err := yaml.Unmarshal(content, &previous); err == nil {
+			manifest.Version = bumpMinor(previous.Version)
+			manifest.Changelog = previous.Changelog
+			change = fmt.Sprintf("Regenerated with generator %s (flags: %s)", generatorVersion, flags)
 		}
+	}
+	manifest.Changelog = append(manifest.Changelog, ChangelogEntry{
+		Version: manifest.Version,
+		Date:    time.Now().Format("2006-01-02"),
+		Changes: []string{change},
+	})
+
+	if err := writeYAML(path, manifest); err != nil {
+		fmt.Printf("Error writing %s: %v\n", path, err)
+	} else {
+		fmt.Printf("✓ Created %s\n", path)
+	}
+	return manifest
+}
 
-		// Inject Migration
-		if strings.Contains(line, "// [MODULE_MIGRATION_MARKER]") {
-			newLines = append(newLines, fmt.Sprintf("\t\t\t&%sModule.%s{},", config.Name, config.NameUpper))
+// updateModulesManifest adds or refreshes the module in internal/modules/modules.yaml
+func updateModulesManifest(manifest ModuleManifest, baseDir string) {
+	var index ModulesManifest
+	if content, err := os.ReadFile(modulesManifestPath); err == nil {
+		if err := yaml.Unmarshal(content, &index); err != nil {
+			fmt.Printf("Error reading %s: %v\n", modulesManifestPath, err)
+			return
 		}
+	}
+
+	entry := ModuleIndexEntry{
+		Name:      manifest.Name,
+		Path:      filepath.ToSlash(baseDir),
+		Generated: true,
+		Version:   manifest.Version,
+		Manifest:  filepath.ToSlash(filepath.Join(baseDir, "module.yaml")),
+	}
 
-		// Inject Route
-		if strings.Contains(line, "// [MODULE_ROUTE_MARKER]") {
-			newLines = append(newLines, fmt.Sprintf("\t// %s routes", config.NameUpper))
-			newLines = append(newLines, fmt.Sprintf("\t%sModule.RegisterRoutes(app, db, cfg, logger)", config.Name))
-			newLines = append(newLines, fmt.Sprintf("\tlogger.Info(\"✓ %s routes registered\")", config.NameUpper))
-			newLines = append(newLines, "")
+	replaced := false
+	for i, m := range index.Modules {
+		if m.Name == entry.Name {
+			index.Modules[i] = entry
+			replaced = true
 		}
 	}
+	if !replaced {
+		index.Modules = append(index.Modules, entry)
+	}
 
-	if err := os.WriteFile(mainGoPath, []byte(strings.Join(newLines, "\n")), 0644); err != nil {
-		fmt.Printf("Error updating main.go: %v\n", err)
+	if err := writeYAML(modulesManifestPath, index); err != nil {
+		fmt.Printf("Error updating %s: %v\n", modulesManifestPath, err)
 	} else {
-		fmt.Println("✓ Auto-injected to cmd/api/main.go")
+		fmt.Printf("✓ Updated %s\n", modulesManifestPath)
+	}
+}
+
+// bumpMinor increments the minor component of a major.minor.patch version
+func bumpMinor(version string) string {
+	var major, minor, patch int
+	if _, err := fmt.Sscanf(version, "%d.%d.%d", &major, &minor, &patch); err != nil {
+		return "0.1.0"
+	}
+	return fmt.Sprintf("%d.%d.0", major, minor+1)
+}
+
+// writeYAML writes v as YAML with a header noting the file is maintained by the generator
+func writeYAML(path string, v any) error {
+	var buf bytes.Buffer
+	buf.WriteString("# Maintained by cmd/gen\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
 	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
 }
 
 func generateMigrations(config Config) {
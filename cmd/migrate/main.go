@@ -2,14 +2,17 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
+	"os"
+	"text/tabwriter"
+	"time"
 
 	"go_boilerplate/internal/shared/config"
 	"go_boilerplate/internal/shared/database"
+	"go_boilerplate/internal/shared/utils"
 
 	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
 func main() {
@@ -19,48 +22,52 @@ func main() {
 	steps := flag.Int("steps", 0, "Number of steps to migrate (0 for all)")
 	version := flag.Bool("version", false, "Print current migration version")
 	force := flag.Int("force", -1, "Force set version (useful for dirty state)")
+	path := flag.String("path", "", "Read migrations from this directory instead of the ones embedded in the binary")
+	list := flag.Bool("list", false, "List the available migrations")
+	status := flag.Bool("status", false, "Show applied and pending migrations with the time each was applied")
+	gotoVersion := flag.Int("goto", -1, "Migrate up or down to this version")
+	dryRun := flag.Bool("dry-run", false, "Print the SQL -up, -down or -goto would run, without running it")
 
 	flag.Parse()
 
+	// Listing reads the migrations only, no database needed
+	if *list {
+		migrations, err := database.ListMigrations(*path)
+		if err != nil {
+			log.Fatalf("Failed to list migrations: %v", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME")
+		for _, migration := range migrations {
+			fmt.Fprintf(w, "%d\t%s\n", migration.Version, migration.Name)
+		}
+		w.Flush()
+		return
+	}
+
 	// Load config
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	// Connect to database using existing helper or construct DSN manually
-	// We'll use the DSN from config directly
-
-	// Initialize database connection for driver
-	db, err := database.InitDB(cfg)
+	// Initialize database connection for driver (waits for the database to come up)
+	db, err := database.InitDB(cfg, utils.InitLogger(cfg))
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer func() {
-		sqlDB, _ := db.DB()
-		sqlDB.Close()
-	}()
 
 	sqlDB, err := db.DB()
 	if err != nil {
 		log.Fatalf("Failed to get sql.DB: %v", err)
 	}
 
-	driver, err := postgres.WithInstance(sqlDB, &postgres.Config{
-		MigrationsTable: "schema_migrations", // Default table name
-	})
-	if err != nil {
-		log.Fatalf("Failed to create postgres driver: %v", err)
-	}
-
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://db/migrations",
-		"postgres",
-		driver,
-	)
+	// Migrations are embedded in the binary; -path runs them from a directory instead
+	m, err := database.NewMigrator(sqlDB, *path)
 	if err != nil {
 		log.Fatalf("Failed to create migration instance: %v", err)
 	}
+	defer m.Close() // Also closes the database connection
 
 	// Handle force version
 	if *force >= 0 {
@@ -73,11 +80,11 @@ func main() {
 
 	// Handle version check
 	if *version {
-		v, dirty, err := m.Version()
-		if err != nil && err != migrate.ErrNilVersion {
+		v, dirty, ok, err := m.Version()
+		if err != nil {
 			log.Fatalf("Failed to get version: %v", err)
 		}
-		if err == migrate.ErrNilVersion {
+		if !ok {
 			log.Println("No migrations applied")
 		} else {
 			log.Printf("Version: %d, Dirty: %v\n", v, dirty)
@@ -85,6 +92,46 @@ func main() {
 		return
 	}
 
+	// Handle status
+	if *status {
+		printStatus(m)
+		return
+	}
+
+	// Handle dry run: print what -up, -down or -goto would run
+	if *dryRun {
+		var plan []database.Step
+		switch {
+		case *gotoVersion >= 0:
+			plan, err = m.PlanGoto(uint(*gotoVersion))
+		case *up:
+			plan, err = m.PlanUp(*steps)
+		case *down:
+			plan, err = m.PlanDown(*steps)
+		default:
+			log.Fatal("-dry-run needs -up, -down or -goto")
+		}
+		if err != nil {
+			log.Fatalf("Failed to plan migrations: %v", err)
+		}
+		printPlan(plan)
+		return
+	}
+
+	// Handle goto
+	if *gotoVersion >= 0 {
+		if err := m.Goto(uint(*gotoVersion)); err != nil {
+			if err == migrate.ErrNoChange {
+				log.Println("No changes to apply")
+			} else {
+				log.Fatalf("Failed to migrate to version %d: %v", *gotoVersion, err)
+			}
+		} else {
+			log.Printf("Migrated to version %d successfully", *gotoVersion)
+		}
+		return
+	}
+
 	// Handle Up migration
 	if *up {
 		if *steps > 0 {
@@ -140,3 +187,51 @@ func main() {
 	// If no flags are set, print usage
 	flag.Usage()
 }
+
+// printStatus prints every migration with whether and when it was applied
+func printStatus(m *database.Migrator) {
+	migrations, err := m.List()
+	if err != nil {
+		log.Fatalf("Failed to get migration status: %v", err)
+	}
+	current, dirty, _, err := m.Version()
+	if err != nil {
+		log.Fatalf("Failed to get version: %v", err)
+	}
+
+	pending := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, migration := range migrations {
+		state, appliedAt := "pending", "-"
+		if migration.Applied {
+			state = "applied"
+			if dirty && migration.Version == current {
+				state = "dirty"
+			}
+			if migration.AppliedAt != nil {
+				appliedAt = migration.AppliedAt.Local().Format(time.DateTime)
+			}
+		} else {
+			pending++
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", migration.Version, migration.Name, state, appliedAt)
+	}
+	w.Flush()
+	fmt.Printf("\n%d applied, %d pending\n", len(migrations)-pending, pending)
+}
+
+// printPlan prints the SQL of each migration a command would run
+func printPlan(plan []database.Step) {
+	if len(plan) == 0 {
+		log.Println("No changes to apply")
+		return
+	}
+	for _, step := range plan {
+		direction := "down"
+		if step.Up {
+			direction = "up"
+		}
+		fmt.Printf("-- %06d_%s.%s.sql\n%s\n\n", step.Version, step.Name, direction, step.SQL)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"go_boilerplate/internal/shared/cache"
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/database"
+	"go_boilerplate/internal/shared/database/replica"
+	"go_boilerplate/internal/shared/database/seed"
+	"go_boilerplate/internal/shared/lock"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func main() {
+	env := flag.String("env", "", "Run the seeders tagged with this environment (default: SERVER_MODE)")
+	only := flag.String("only", "", "Comma-separated seeders to run (default: all)")
+	list := flag.Bool("list", false, "List the seeders and the environments they run in")
+
+	flag.Parse()
+
+	// Load config
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	logger := utils.InitLogger(cfg)
+	seeders := database.Seeders(cfg, logger)
+
+	if *list {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SEEDER\tENVIRONMENTS")
+		for _, seeder := range seeders {
+			envs := strings.Join(seeder.Envs, ", ")
+			if envs == "" {
+				envs = "all"
+			}
+			fmt.Fprintf(w, "%s\t%s\n", seeder.Name, envs)
+		}
+		w.Flush()
+		return
+	}
+
+	opts := seed.Options{Env: *env}
+	if opts.Env == "" {
+		opts.Env = cfg.Server.Mode
+	}
+	for _, name := range strings.Split(*only, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			opts.Only = append(opts.Only, name)
+		}
+	}
+	if err := seed.Validate(seeders, opts); err != nil {
+		log.Fatal(err)
+	}
+
+	// Initialize database connection (waits for the database to come up)
+	db, err := database.InitDB(cfg, logger)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	// Redis serializes seeding with API instances starting meanwhile and holds the caches to drop
+	var redisClient *redis.Client
+	if cfg.Redis.Enabled {
+		if redisClient, err = database.InitRedis(cfg, logger); err != nil {
+			logger.Warnf("Failed to connect to Redis, seeding unlocked and cached responses may be stale: %v", err)
+		} else {
+			defer redisClient.Close()
+		}
+	}
+	opts.Locker = lock.New(redisClient)
+
+	// Seeders read what earlier ones wrote, so everything goes to the primary
+	ctx := replica.WithPrimary(context.Background())
+	if err := seed.Run(ctx, db, seeders, opts, logger); err != nil {
+		log.Fatalf("Seeding failed: %v", err)
+	}
+
+	// Seeders bypass the services, so drop cached role and user responses and user profiles
+	if redisClient != nil {
+		_ = cache.NewResponseCache(redisClient, cfg.Cache).Invalidate(ctx, cache.NamespaceRoles, cache.NamespaceUsers)
+		_ = cache.NewNamespace(cache.New(cfg.CacheStore, redisClient), cache.NamespaceUserRoles).Clear(ctx)
+	}
+	log.Printf("Seeding completed (%s)", opts.Env)
+}
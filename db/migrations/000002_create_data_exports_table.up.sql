@@ -0,0 +1,17 @@
+-- Create t_data_exports table (GDPR data exports)
+CREATE TABLE IF NOT EXISTS t_data_exports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    file_name VARCHAR(255),
+    archive BYTEA,
+    size BIGINT DEFAULT 0,
+    error TEXT,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_data_exports_user FOREIGN KEY (user_id) REFERENCES m_users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_t_data_exports_user_id ON t_data_exports(user_id);
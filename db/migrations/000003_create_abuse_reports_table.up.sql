@@ -0,0 +1,23 @@
+-- Create t_abuse_reports table
+CREATE TABLE IF NOT EXISTS t_abuse_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reporter_id UUID,
+    reporter_email VARCHAR(255),
+    category VARCHAR(50) NOT NULL,
+    subject VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL,
+    target_url VARCHAR(2048),
+    status VARCHAR(20) NOT NULL DEFAULT 'new',
+    triage_notes TEXT,
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    triaged_by UUID,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_abuse_reports_reporter FOREIGN KEY (reporter_id) REFERENCES m_users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_t_abuse_reports_reporter_id ON t_abuse_reports(reporter_id);
+CREATE INDEX IF NOT EXISTS idx_t_abuse_reports_category ON t_abuse_reports(category);
+CREATE INDEX IF NOT EXISTS idx_t_abuse_reports_status ON t_abuse_reports(status);
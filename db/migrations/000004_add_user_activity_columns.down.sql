@@ -0,0 +1,2 @@
+ALTER TABLE m_users DROP COLUMN IF EXISTS last_seen_at;
+ALTER TABLE m_users DROP COLUMN IF EXISTS last_login_at;
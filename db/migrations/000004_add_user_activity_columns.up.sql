@@ -0,0 +1,2 @@
+ALTER TABLE m_users ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMPTZ;
+ALTER TABLE m_users ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMPTZ;
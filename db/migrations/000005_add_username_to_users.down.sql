@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_m_users_username;
+ALTER TABLE m_users DROP COLUMN IF EXISTS username;
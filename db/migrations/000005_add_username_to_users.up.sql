@@ -0,0 +1,2 @@
+ALTER TABLE m_users ADD COLUMN IF NOT EXISTS username VARCHAR(30);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_m_users_username ON m_users (username);
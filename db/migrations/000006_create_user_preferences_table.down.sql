@@ -0,0 +1 @@
+DROP TABLE IF EXISTS m_user_preferences CASCADE;
@@ -0,0 +1,8 @@
+-- Create m_user_preferences table (one preferences document per user)
+CREATE TABLE IF NOT EXISTS m_user_preferences (
+    user_id UUID PRIMARY KEY,
+    data JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_preferences_user FOREIGN KEY (user_id) REFERENCES m_users(id) ON DELETE CASCADE
+);
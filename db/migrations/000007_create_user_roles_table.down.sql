@@ -0,0 +1,15 @@
+ALTER TABLE m_users ADD COLUMN IF NOT EXISTS role_id UUID;
+
+-- Keep one role per user (lowest role id wins when several were assigned)
+UPDATE m_users u
+SET role_id = ur.role_id
+FROM (
+    SELECT DISTINCT ON (user_id) user_id, role_id
+    FROM m_user_roles
+    ORDER BY user_id, role_id
+) ur
+WHERE ur.user_id = u.id;
+
+ALTER TABLE m_users ADD CONSTRAINT fk_users_role FOREIGN KEY (role_id) REFERENCES m_roles(id);
+
+DROP TABLE IF EXISTS m_user_roles;
@@ -0,0 +1,18 @@
+-- Create m_user_roles join table (users can hold multiple roles)
+CREATE TABLE IF NOT EXISTS m_user_roles (
+    user_id UUID NOT NULL,
+    role_id UUID NOT NULL,
+    PRIMARY KEY (user_id, role_id),
+    CONSTRAINT fk_user_roles_user FOREIGN KEY (user_id) REFERENCES m_users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_roles_role FOREIGN KEY (role_id) REFERENCES m_roles(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_m_user_roles_role_id ON m_user_roles(role_id);
+
+-- Carry over the existing single role assignment
+INSERT INTO m_user_roles (user_id, role_id)
+SELECT id, role_id FROM m_users WHERE role_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE m_users DROP CONSTRAINT IF EXISTS fk_users_role;
+ALTER TABLE m_users DROP COLUMN IF EXISTS role_id;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_m_roles_parent_id;
+ALTER TABLE m_roles DROP CONSTRAINT IF EXISTS fk_roles_parent;
+ALTER TABLE m_roles DROP COLUMN IF EXISTS parent_id;
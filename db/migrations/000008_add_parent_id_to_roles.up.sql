@@ -0,0 +1,7 @@
+-- Roles inherit permissions from their parent role (transitively)
+ALTER TABLE m_roles ADD COLUMN IF NOT EXISTS parent_id UUID;
+
+ALTER TABLE m_roles
+    ADD CONSTRAINT fk_roles_parent FOREIGN KEY (parent_id) REFERENCES m_roles(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_m_roles_parent_id ON m_roles(parent_id);
@@ -0,0 +1 @@
+ALTER TABLE m_roles DROP COLUMN IF EXISTS is_system;
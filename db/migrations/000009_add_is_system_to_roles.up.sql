@@ -0,0 +1,4 @@
+-- Built-in roles cannot be deleted
+ALTER TABLE m_roles ADD COLUMN IF NOT EXISTS is_system BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE m_roles SET is_system = TRUE WHERE slug IN ('super_admin', 'admin', 'user');
@@ -0,0 +1,11 @@
+-- Create m_user_permissions table (per-user grant/deny overrides on top of role permissions)
+CREATE TABLE IF NOT EXISTS m_user_permissions (
+    user_id UUID NOT NULL,
+    permission VARCHAR(100) NOT NULL,
+    effect VARCHAR(10) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, permission),
+    CONSTRAINT chk_user_permissions_effect CHECK (effect IN ('grant', 'deny')),
+    CONSTRAINT fk_user_permissions_user FOREIGN KEY (user_id) REFERENCES m_users(id) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS m_casbin_rules;
@@ -0,0 +1,13 @@
+-- Create m_casbin_rules table (policy store for RBAC_BACKEND=casbin)
+CREATE TABLE IF NOT EXISTS m_casbin_rules (
+    id BIGSERIAL PRIMARY KEY,
+    ptype VARCHAR(100) NOT NULL,
+    v0 VARCHAR(255) NOT NULL DEFAULT '',
+    v1 VARCHAR(255) NOT NULL DEFAULT '',
+    v2 VARCHAR(255) NOT NULL DEFAULT '',
+    v3 VARCHAR(255) NOT NULL DEFAULT '',
+    v4 VARCHAR(255) NOT NULL DEFAULT '',
+    v5 VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_m_casbin_rules_ptype ON m_casbin_rules(ptype);
@@ -0,0 +1,20 @@
+-- Create t_audit_events table (POST/PUT/PATCH/DELETE requests recorded by the audit middleware)
+CREATE TABLE IF NOT EXISTS t_audit_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id UUID,
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(2048) NOT NULL,
+    route VARCHAR(255),
+    status INTEGER NOT NULL,
+    body TEXT,
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    request_id VARCHAR(64),
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_t_audit_events_actor_id ON t_audit_events(actor_id);
+CREATE INDEX IF NOT EXISTS idx_t_audit_events_route ON t_audit_events(route);
+CREATE INDEX IF NOT EXISTS idx_t_audit_events_status ON t_audit_events(status);
+CREATE INDEX IF NOT EXISTS idx_t_audit_events_created_at ON t_audit_events(created_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_t_audit_events_tenant_id;
+ALTER TABLE t_audit_events DROP COLUMN IF EXISTS tenant_id;
+DROP TABLE IF EXISTS m_tenants;
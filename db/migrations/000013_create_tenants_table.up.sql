@@ -0,0 +1,16 @@
+-- Create m_tenants table (tenants resolved from the X-Tenant-ID header, subdomain or token tenant_id claim)
+CREATE TABLE IF NOT EXISTS m_tenants (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    slug VARCHAR(63) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    settings JSONB NOT NULL DEFAULT '{}',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_m_tenants_slug ON m_tenants(slug);
+
+-- Audit events are scoped to the tenant of the request that produced them
+ALTER TABLE t_audit_events ADD COLUMN IF NOT EXISTS tenant_id UUID;
+CREATE INDEX IF NOT EXISTS idx_t_audit_events_tenant_id ON t_audit_events(tenant_id);
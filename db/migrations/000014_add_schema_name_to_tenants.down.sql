@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_m_tenants_schema_name;
+
+ALTER TABLE m_tenants DROP COLUMN IF EXISTS schema_name;
@@ -0,0 +1,4 @@
+-- Tenants with a schema_name keep their tables in that schema (schema-per-tenant); empty keeps them in public
+ALTER TABLE m_tenants ADD COLUMN IF NOT EXISTS schema_name VARCHAR(63) NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_m_tenants_schema_name ON m_tenants(schema_name) WHERE schema_name <> '';
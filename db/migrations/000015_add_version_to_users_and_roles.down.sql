@@ -0,0 +1,3 @@
+ALTER TABLE m_roles DROP COLUMN IF EXISTS version;
+
+ALTER TABLE m_users DROP COLUMN IF EXISTS version;
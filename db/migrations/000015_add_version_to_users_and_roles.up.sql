@@ -0,0 +1,4 @@
+-- Optimistic locking: every update of a user or role checks and increments its version
+ALTER TABLE m_users ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE m_roles ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
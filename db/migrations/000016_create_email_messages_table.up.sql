@@ -0,0 +1,17 @@
+-- Create t_email_messages table (outgoing email queue, delivered by the send-queued-email job)
+CREATE TABLE IF NOT EXISTS t_email_messages (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    recipient VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_t_email_messages_due ON t_email_messages(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_t_email_messages_updated_at ON t_email_messages(updated_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_t_email_messages_created_at;
+DROP INDEX IF EXISTS idx_t_email_messages_recipient;
+
+ALTER TABLE t_email_messages DROP COLUMN IF EXISTS provider_message_id;
+ALTER TABLE t_email_messages DROP COLUMN IF EXISTS provider;
+ALTER TABLE t_email_messages DROP COLUMN IF EXISTS template;
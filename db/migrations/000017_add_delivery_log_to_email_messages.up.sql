@@ -0,0 +1,7 @@
+-- Delivery log: every outbound email keeps its template, provider and provider message ID
+ALTER TABLE t_email_messages ADD COLUMN IF NOT EXISTS template VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE t_email_messages ADD COLUMN IF NOT EXISTS provider VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE t_email_messages ADD COLUMN IF NOT EXISTS provider_message_id VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_t_email_messages_recipient ON t_email_messages(recipient);
+CREATE INDEX IF NOT EXISTS idx_t_email_messages_created_at ON t_email_messages(created_at);
@@ -0,0 +1 @@
+ALTER TABLE t_email_messages DROP COLUMN IF EXISTS attachments;
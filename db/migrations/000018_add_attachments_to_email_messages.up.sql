@@ -0,0 +1,2 @@
+-- Attachments and inline images of outgoing email, with their content (base64 in JSON)
+ALTER TABLE t_email_messages ADD COLUMN IF NOT EXISTS attachments JSONB;
@@ -0,0 +1,4 @@
+CREATE INDEX IF NOT EXISTS idx_t_email_messages_recipient ON t_email_messages(recipient);
+DROP INDEX IF EXISTS idx_t_email_messages_recipient_created_at;
+
+ALTER TABLE t_email_messages DROP COLUMN IF EXISTS content_hash;
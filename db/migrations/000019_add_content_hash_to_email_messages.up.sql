@@ -0,0 +1,6 @@
+-- Identical emails to a recipient share a content hash, so the queue can suppress duplicates
+ALTER TABLE t_email_messages ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Recent emails per recipient, for the hourly limit; it also serves lookups by recipient
+CREATE INDEX IF NOT EXISTS idx_t_email_messages_recipient_created_at ON t_email_messages(recipient, created_at);
+DROP INDEX IF EXISTS idx_t_email_messages_recipient;
@@ -0,0 +1,19 @@
+-- Create t_audit_logs table (changes recorded by the user, role and auth services through audit.Trail)
+CREATE TABLE IF NOT EXISTS t_audit_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id UUID,
+    tenant_id UUID,
+    action VARCHAR(100) NOT NULL,
+    resource_type VARCHAR(50) NOT NULL,
+    resource_id VARCHAR(64),
+    before JSONB,
+    after JSONB,
+    request_id VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_t_audit_logs_actor_id ON t_audit_logs(actor_id);
+CREATE INDEX IF NOT EXISTS idx_t_audit_logs_tenant_id ON t_audit_logs(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_t_audit_logs_action ON t_audit_logs(action);
+CREATE INDEX IF NOT EXISTS idx_t_audit_logs_resource ON t_audit_logs(resource_type, resource_id);
+CREATE INDEX IF NOT EXISTS idx_t_audit_logs_created_at ON t_audit_logs(created_at);
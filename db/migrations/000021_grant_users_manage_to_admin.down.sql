@@ -0,0 +1,4 @@
+-- Intentionally a no-op: the up migration only adds users.manage where it is missing, and the
+-- role profile grants it to admin as well, so removing it here could take it from databases that
+-- had it before this migration ran
+SELECT 1;
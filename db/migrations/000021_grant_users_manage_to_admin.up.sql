@@ -0,0 +1,7 @@
+-- PUT /users/:id checks users.manage instead of the admin role slug; grant it to the built-in admin
+-- role of existing databases (new databases get it from the role profile when roles are seeded)
+UPDATE m_roles
+SET permissions = permissions || '["users.manage"]'::jsonb,
+    version = version + 1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE slug = 'admin' AND NOT permissions ? 'users.manage';
@@ -0,0 +1,5 @@
+-- Schema-per-tenant was dropped before any table moved into a tenant schema: every tenant's data
+-- is in public, isolated by tenant_id where a table has one
+DROP INDEX IF EXISTS idx_m_tenants_schema_name;
+
+ALTER TABLE m_tenants DROP COLUMN IF EXISTS schema_name;
@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL migrations, so the API and cmd/migrate binaries run them without
+// the db/migrations directory being deployed next to them
+package migrations
+
+import "embed"
+
+// FS holds the NNNNNN_name.up.sql / .down.sql files of this directory
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,22 @@
+package dto
+
+// CreateAbuseReportRequest represents a request to report abuse or a security issue
+type CreateAbuseReportRequest struct {
+	Category      string `json:"category" validate:"required,oneof=security content spam harassment other"`
+	Subject       string `json:"subject" validate:"required,min=5,max=200"`
+	Description   string `json:"description" validate:"required,min=10,max=5000"`
+	TargetURL     string `json:"target_url" validate:"omitempty,url,max=2048"`
+	ReporterEmail string `json:"reporter_email" validate:"omitempty,email"` // Optional contact for anonymous reporters
+}
+
+// TriageAbuseReportRequest represents an admin request to update a report's triage status
+type TriageAbuseReportRequest struct {
+	Status      string `json:"status" validate:"required,oneof=new triaging resolved dismissed"`
+	TriageNotes string `json:"triage_notes" validate:"omitempty,max=5000"`
+}
+
+// ReportMetadata represents request metadata captured with a report
+type ReportMetadata struct {
+	IPAddress string
+	UserAgent string
+}
@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/google/uuid"
+)
+
+// AbuseReportResponse represents an abuse report response
+type AbuseReportResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	ReporterID    *uuid.UUID `json:"reporter_id,omitempty"`
+	ReporterEmail string     `json:"reporter_email,omitempty"`
+	Category      string     `json:"category"`
+	Subject       string     `json:"subject"`
+	Description   string     `json:"description"`
+	TargetURL     string     `json:"target_url,omitempty"`
+	Status        string     `json:"status"`
+	TriageNotes   string     `json:"triage_notes,omitempty"`
+	IPAddress     string     `json:"ip_address,omitempty"`
+	TriagedBy     *uuid.UUID `json:"triaged_by,omitempty"`
+	ResolvedAt    *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// SubmittedReportResponse represents the public acknowledgement of a submitted report
+type SubmittedReportResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AbuseReportsResponse represents a paginated list of abuse reports
+type AbuseReportsResponse struct {
+	Reports []AbuseReportResponse `json:"reports"`
+	Meta    utils.PaginationMeta  `json:"meta"`
+}
+
+// PageItems returns the listed abuse reports (utils.Paginated)
+func (r AbuseReportsResponse) PageItems() any {
+	return r.Reports
+}
+
+// PageMeta returns the pagination metadata (utils.Paginated)
+func (r AbuseReportsResponse) PageMeta() utils.PaginationMeta {
+	return r.Meta
+}
@@ -0,0 +1,178 @@
+package abuse
+
+import (
+	"go_boilerplate/internal/modules/abuse/dto"
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/middleware"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// AbuseReportHandler defines the interface for abuse report HTTP handlers
+type AbuseReportHandler interface {
+	SecurityTxt(c *fiber.Ctx) error
+	SubmitReport(c *fiber.Ctx) error
+	GetReports(c *fiber.Ctx) error
+	GetReport(c *fiber.Ctx) error
+	TriageReport(c *fiber.Ctx) error
+}
+
+// abuseReportHandler implements AbuseReportHandler interface
+type abuseReportHandler struct {
+	service AbuseReportService
+	cfg     *config.Config
+}
+
+// NewAbuseReportHandler creates a new abuse report handler
+func NewAbuseReportHandler(service AbuseReportService, cfg *config.Config) AbuseReportHandler {
+	return &abuseReportHandler{service: service, cfg: cfg}
+}
+
+// SecurityTxt serves the security.txt document
+// @Summary security.txt
+// @Description Security contact information for researchers (RFC 9116).
+// @Tags Security
+// @Produce plain
+// @Success 200 {string} string "security.txt document"
+// @Router /.well-known/security.txt [get]
+func (h *abuseReportHandler) SecurityTxt(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	return c.SendString(BuildSecurityTxt(h.cfg.Security.SecurityTxt))
+}
+
+// SubmitReport submits a new abuse report
+// @Summary Submit abuse report
+// @Description Report abusive content or a security issue. Authentication is optional; requests are rate limited per IP.
+// @Tags Abuse Reports
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAbuseReportRequest true "Report data"
+// @Success 201 {object} utils.APIResponse{data=dto.SubmittedReportResponse} "Report submitted"
+// @Failure 400 {object} utils.ProblemDetails "Invalid request"
+// @Failure 429 {object} utils.ProblemDetails "Too many reports"
+// @Router /abuse-reports [post]
+func (h *abuseReportHandler) SubmitReport(c *fiber.Ctx) error {
+	req := c.Locals("validatedBody").(*dto.CreateAbuseReportRequest)
+
+	// Attach the reporter when the request is authenticated
+	var reporterID *uuid.UUID
+	if userIDStr, ok := middleware.GetUserIDFromContext(c); ok {
+		if id, err := uuid.Parse(userIDStr); err == nil {
+			reporterID = &id
+		}
+	}
+	if req.ReporterEmail == "" {
+		if email, ok := middleware.GetEmailFromContext(c); ok {
+			req.ReporterEmail = email
+		}
+	}
+
+	metadata := dto.ReportMetadata{
+		IPAddress: c.IP(),
+		UserAgent: string(c.Request().Header.UserAgent()),
+	}
+
+	report, err := h.service.SubmitReport(c.UserContext(), req, reporterID, metadata)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to submit report", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusCreated, report, "Report submitted successfully")
+}
+
+// GetReports gets all abuse reports with pagination
+// @Summary Admin: List abuse reports
+// @Description Retrieve a paginated list of abuse reports, optionally filtered by triage status (Admin only).
+// @Tags Abuse Reports
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 10, max: 100)"
+// @Param status query string false "Filter by status (new, triaging, resolved, dismissed)"
+// @Success 200 {object} utils.APIResponse{data=dto.AbuseReportsResponse} "Reports retrieved"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Failure 403 {object} utils.ProblemDetails "Forbidden"
+// @Router /abuse-reports [get]
+func (h *abuseReportHandler) GetReports(c *fiber.Ctx) error {
+	// Parse query parameters
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	response, err := h.service.GetReports(c.UserContext(), page, limit, c.Query("status"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get reports", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, response, "Reports retrieved successfully")
+}
+
+// GetReport gets an abuse report by ID
+// @Summary Admin: Get abuse report
+// @Description Retrieve a single abuse report by its ID (Admin only).
+// @Tags Abuse Reports
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Report ID (UUID)"
+// @Success 200 {object} utils.APIResponse{data=dto.AbuseReportResponse} "Report retrieved"
+// @Failure 400 {object} utils.ProblemDetails "Invalid report ID"
+// @Failure 404 {object} utils.ProblemDetails "Report not found"
+// @Router /abuse-reports/{id} [get]
+func (h *abuseReportHandler) GetReport(c *fiber.Ctx) error {
+	reportID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid report ID", err)
+	}
+
+	report, err := h.service.GetReport(c.UserContext(), reportID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get report", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, report, "Report retrieved successfully")
+}
+
+// TriageReport updates the triage status of an abuse report
+// @Summary Admin: Triage abuse report
+// @Description Update the triage status and notes of an abuse report (Admin only).
+// @Tags Abuse Reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Report ID (UUID)"
+// @Param request body dto.TriageAbuseReportRequest true "Triage data"
+// @Success 200 {object} utils.APIResponse{data=dto.AbuseReportResponse} "Report updated"
+// @Failure 400 {object} utils.ProblemDetails "Invalid request"
+// @Router /abuse-reports/{id} [patch]
+func (h *abuseReportHandler) TriageReport(c *fiber.Ctx) error {
+	reportID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid report ID", err)
+	}
+
+	adminIDStr, ok := middleware.GetUserIDFromContext(c)
+	if !ok {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Unauthorized", nil)
+	}
+	adminID, err := uuid.Parse(adminIDStr)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid auth user ID", err)
+	}
+
+	req := c.Locals("validatedBody").(*dto.TriageAbuseReportRequest)
+
+	report, err := h.service.TriageReport(c.UserContext(), reportID, req, adminID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to update report", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, report, "Report updated successfully")
+}
@@ -0,0 +1,61 @@
+package abuse
+
+import (
+	"time"
+
+	"go_boilerplate/internal/modules/abuse/dto"
+
+	"github.com/google/uuid"
+)
+
+// Abuse report triage statuses
+const (
+	StatusNew       = "new"
+	StatusTriaging  = "triaging"
+	StatusResolved  = "resolved"
+	StatusDismissed = "dismissed"
+)
+
+// AbuseReport represents a content or security issue reported by a user or researcher
+type AbuseReport struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ReporterID    *uuid.UUID `json:"reporter_id" gorm:"type:uuid;index"` // Set when the reporter is authenticated
+	ReporterEmail string     `json:"reporter_email" gorm:"type:varchar(255)"`
+	Category      string     `json:"category" gorm:"type:varchar(50);not null;index"`
+	Subject       string     `json:"subject" gorm:"type:varchar(200);not null"`
+	Description   string     `json:"description" gorm:"type:text;not null"`
+	TargetURL     string     `json:"target_url" gorm:"type:varchar(2048)"`
+	Status        string     `json:"status" gorm:"type:varchar(20);not null;default:'new';index"`
+	TriageNotes   string     `json:"triage_notes" gorm:"type:text"`
+	IPAddress     string     `json:"ip_address" gorm:"type:varchar(45)"`
+	UserAgent     string     `json:"user_agent" gorm:"type:text"`
+	TriagedBy     *uuid.UUID `json:"triaged_by" gorm:"type:uuid"`
+	ResolvedAt    *time.Time `json:"resolved_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for AbuseReport model
+func (AbuseReport) TableName() string {
+	return "t_abuse_reports"
+}
+
+// ToResponse converts AbuseReport to AbuseReportResponse
+func (r *AbuseReport) ToResponse() dto.AbuseReportResponse {
+	return dto.AbuseReportResponse{
+		ID:            r.ID,
+		ReporterID:    r.ReporterID,
+		ReporterEmail: r.ReporterEmail,
+		Category:      r.Category,
+		Subject:       r.Subject,
+		Description:   r.Description,
+		TargetURL:     r.TargetURL,
+		Status:        r.Status,
+		TriageNotes:   r.TriageNotes,
+		IPAddress:     r.IPAddress,
+		TriagedBy:     r.TriagedBy,
+		ResolvedAt:    r.ResolvedAt,
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+	}
+}
@@ -0,0 +1,16 @@
+package abuse
+
+import "go_boilerplate/internal/shared/permission"
+
+// Abuse report permissions
+const (
+	PermAbuseReportsRead   = "abuse_reports.read"
+	PermAbuseReportsUpdate = "abuse_reports.update"
+)
+
+func init() {
+	permission.Register(
+		permission.Permission{Name: PermAbuseReportsRead, Description: "View abuse reports"},
+		permission.Permission{Name: PermAbuseReportsUpdate, Description: "Triage abuse reports"},
+	)
+}
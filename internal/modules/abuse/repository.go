@@ -0,0 +1,70 @@
+package abuse
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AbuseReportRepository defines the interface for abuse report data operations
+type AbuseReportRepository interface {
+	Create(ctx context.Context, report *AbuseReport) error
+	FindByID(ctx context.Context, id uuid.UUID) (*AbuseReport, error)
+	FindAll(ctx context.Context, offset, limit int, status string) ([]AbuseReport, int64, error)
+	Update(ctx context.Context, report *AbuseReport) error
+}
+
+// abuseReportRepository implements AbuseReportRepository interface
+type abuseReportRepository struct {
+	db *gorm.DB
+}
+
+// NewAbuseReportRepository creates a new abuse report repository
+func NewAbuseReportRepository(db *gorm.DB) AbuseReportRepository {
+	return &abuseReportRepository{db: db}
+}
+
+// Create creates a new abuse report
+func (r *abuseReportRepository) Create(ctx context.Context, report *AbuseReport) error {
+	return r.db.WithContext(ctx).Create(report).Error
+}
+
+// FindByID finds an abuse report by ID
+func (r *abuseReportRepository) FindByID(ctx context.Context, id uuid.UUID) (*AbuseReport, error) {
+	var report AbuseReport
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&report).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// FindAll finds abuse reports with pagination, optionally filtered by status
+func (r *abuseReportRepository) FindAll(ctx context.Context, offset, limit int, status string) ([]AbuseReport, int64, error) {
+	var reports []AbuseReport
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&AbuseReport{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Find reports with pagination
+	err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&reports).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reports, total, nil
+}
+
+// Update updates an abuse report
+func (r *abuseReportRepository) Update(ctx context.Context, report *AbuseReport) error {
+	return r.db.WithContext(ctx).Save(report).Error
+}
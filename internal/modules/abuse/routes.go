@@ -0,0 +1,85 @@
+package abuse
+
+import (
+	"sync/atomic"
+	"time"
+
+	"go_boilerplate/internal/modules/abuse/dto"
+	"go_boilerplate/internal/modules/email"
+	"go_boilerplate/internal/shared/apiversion"
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/middleware"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// RegisterRoutes registers security.txt and abuse report routes
+func RegisterRoutes(app *fiber.App, db *gorm.DB, cfg *config.Config, logger *logrus.Logger) {
+	// Route admin notifications to the log/webhook, and to the admin inbox if email is enabled
+	notifier := email.NewAdminNotifier(cfg, db, logger)
+
+	// Initialize repository, service and handler
+	reportRepo := NewAbuseReportRepository(db)
+	reportService := NewAbuseReportService(reportRepo, notifier, logger)
+	reportHandler := NewAbuseReportHandler(reportService, cfg)
+
+	// security.txt (only served when a contact is configured, as Contact is mandatory)
+	if len(cfg.Security.SecurityTxt.Contacts) > 0 {
+		app.Get("/.well-known/security.txt", reportHandler.SecurityTxt)
+		logger.Info("✓ security.txt registered")
+	}
+
+	// Create API route group
+	api := apiversion.Group(app, "v1")
+	reports := api.Group("/abuse-reports")
+
+	// Public submission, rate limited per IP
+	reports.Post("/",
+		reportRateLimit(cfg),
+		middleware.BodyLimit(cfg.Server.PublicBodyLimit),
+		middleware.OptionalAuth(cfg),
+		middleware.BodyValidator(&dto.CreateAbuseReportRequest{}),
+		reportHandler.SubmitReport,
+	)
+
+	// Triage routes - Admin and SuperAdmin only
+	adminOnly := reports.Group("/")
+	adminOnly.Use(middleware.JWTAuth(cfg))
+	adminOnly.Use(middleware.RequireRole(cfg, "admin", "super_admin"))
+	adminOnly.Get("/", reportHandler.GetReports)                                                                   // List reports
+	adminOnly.Get("/:id", reportHandler.GetReport)                                                                 // Get report by ID
+	adminOnly.Patch("/:id", middleware.BodyValidator(&dto.TriageAbuseReportRequest{}), reportHandler.TriageReport) // Update triage status
+}
+
+// reportRateLimit limits submissions per IP to ABUSE_REPORT_RATE_LIMIT an hour. A config reload that
+// changes the limit swaps in a new limiter, which starts counting afresh.
+func reportRateLimit(cfg *config.Config) fiber.Handler {
+	newLimiter := func(max int) fiber.Handler {
+		return limiter.New(limiter.Config{
+			Max:        max,
+			Expiration: 1 * time.Hour,
+			LimitReached: func(c *fiber.Ctx) error {
+				return utils.ErrorResponse(c, fiber.StatusTooManyRequests, "Too many reports, please try again later", nil)
+			},
+		})
+	}
+
+	var current atomic.Pointer[fiber.Handler]
+	handler := newLimiter(cfg.Hot().AbuseRateLimit)
+	current.Store(&handler)
+
+	cfg.OnReload(func(old, new config.HotConfig) {
+		if new.AbuseRateLimit != old.AbuseRateLimit {
+			handler := newLimiter(new.AbuseRateLimit)
+			current.Store(&handler)
+		}
+	})
+
+	return func(c *fiber.Ctx) error {
+		return (*current.Load())(c)
+	}
+}
@@ -0,0 +1,202 @@
+package abuse
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"go_boilerplate/internal/modules/abuse/dto"
+	"go_boilerplate/internal/shared/apperror"
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/database/repository"
+	"go_boilerplate/internal/shared/notify"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// AbuseReportService defines the interface for abuse report business logic
+type AbuseReportService interface {
+	SubmitReport(ctx context.Context, req *dto.CreateAbuseReportRequest, reporterID *uuid.UUID, metadata dto.ReportMetadata) (*dto.SubmittedReportResponse, error)
+	GetReport(ctx context.Context, id uuid.UUID) (*dto.AbuseReportResponse, error)
+	GetReports(ctx context.Context, page, limit int, status string) (*dto.AbuseReportsResponse, error)
+	TriageReport(ctx context.Context, id uuid.UUID, req *dto.TriageAbuseReportRequest, adminID uuid.UUID) (*dto.AbuseReportResponse, error)
+}
+
+// ErrAbuseReportNotFound is returned for unknown abuse report IDs
+var ErrAbuseReportNotFound = apperror.New(apperror.ErrNotFound, "abuse report not found").WithCode("abuse_report_not_found")
+
+// abuseReportService implements AbuseReportService interface
+type abuseReportService struct {
+	repo     AbuseReportRepository
+	notifier notify.Notifier
+	logger   *logrus.Logger
+}
+
+// NewAbuseReportService creates a new abuse report service
+func NewAbuseReportService(repo AbuseReportRepository, notifier notify.Notifier, logger *logrus.Logger) AbuseReportService {
+	return &abuseReportService{
+		repo:     repo,
+		notifier: notifier,
+		logger:   logger,
+	}
+}
+
+// SubmitReport stores a new report and notifies administrators
+func (s *abuseReportService) SubmitReport(ctx context.Context, req *dto.CreateAbuseReportRequest, reporterID *uuid.UUID, metadata dto.ReportMetadata) (*dto.SubmittedReportResponse, error) {
+	report := &AbuseReport{
+		ReporterID:    reporterID,
+		ReporterEmail: req.ReporterEmail,
+		Category:      req.Category,
+		Subject:       req.Subject,
+		Description:   req.Description,
+		TargetURL:     req.TargetURL,
+		Status:        StatusNew,
+		IPAddress:     metadata.IPAddress,
+		UserAgent:     metadata.UserAgent,
+	}
+
+	if err := s.repo.Create(ctx, report); err != nil {
+		return nil, err
+	}
+
+	// Notify admins asynchronously (don't block the reporter)
+	notification := s.buildNotification(report)
+	go func() {
+		if err := s.notifier.Notify(notification); err != nil {
+			s.logger.Errorf("Failed to notify admins about abuse report %s: %v", report.ID, err)
+		}
+	}()
+
+	return &dto.SubmittedReportResponse{
+		ID:        report.ID,
+		Status:    report.Status,
+		CreatedAt: report.CreatedAt,
+	}, nil
+}
+
+// GetReport gets an abuse report by ID
+func (s *abuseReportService) GetReport(ctx context.Context, id uuid.UUID) (*dto.AbuseReportResponse, error) {
+	report, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, repository.LookupError(err, ErrAbuseReportNotFound, "abuse report")
+	}
+
+	response := report.ToResponse()
+	return &response, nil
+}
+
+// GetReports gets abuse reports with pagination, optionally filtered by status
+func (s *abuseReportService) GetReports(ctx context.Context, page, limit int, status string) (*dto.AbuseReportsResponse, error) {
+	// Calculate offset
+	offset := (page - 1) * limit
+
+	// Find reports
+	reports, total, err := s.repo.FindAll(ctx, offset, limit, status)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response
+	reportResponses := make([]dto.AbuseReportResponse, len(reports))
+	for i, report := range reports {
+		reportResponses[i] = report.ToResponse()
+	}
+
+	// Calculate total pages
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &dto.AbuseReportsResponse{
+		Reports: reportResponses,
+		Meta: utils.PaginationMeta{
+			Page:       page,
+			Limit:      limit,
+			Total:      int(total),
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// TriageReport updates the triage status and notes of a report
+func (s *abuseReportService) TriageReport(ctx context.Context, id uuid.UUID, req *dto.TriageAbuseReportRequest, adminID uuid.UUID) (*dto.AbuseReportResponse, error) {
+	report, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, repository.LookupError(err, ErrAbuseReportNotFound, "abuse report")
+	}
+
+	report.Status = req.Status
+	report.TriagedBy = &adminID
+	if req.TriageNotes != "" {
+		report.TriageNotes = req.TriageNotes
+	}
+
+	// Track when a report is closed, and reset it if reopened
+	if req.Status == StatusResolved || req.Status == StatusDismissed {
+		now := time.Now()
+		report.ResolvedAt = &now
+	} else {
+		report.ResolvedAt = nil
+	}
+
+	if err := s.repo.Update(ctx, report); err != nil {
+		return nil, err
+	}
+
+	response := report.ToResponse()
+	return &response, nil
+}
+
+// buildNotification creates the admin notification for a new report
+func (s *abuseReportService) buildNotification(report *AbuseReport) notify.Notification {
+	level := notify.LevelWarning
+	if report.Category == "security" {
+		level = notify.LevelCritical
+	}
+
+	fields := map[string]string{
+		"report_id": report.ID.String(),
+		"category":  report.Category,
+		"subject":   report.Subject,
+	}
+	if report.TargetURL != "" {
+		fields["target_url"] = report.TargetURL
+	}
+	if report.ReporterEmail != "" {
+		fields["reporter_email"] = report.ReporterEmail
+	}
+
+	return notify.Notification{
+		Title:   "New abuse report",
+		Message: "A new " + report.Category + " report was submitted and is awaiting triage.",
+		Level:   level,
+		Fields:  fields,
+	}
+}
+
+// BuildSecurityTxt renders a security.txt document (RFC 9116) from configuration
+func BuildSecurityTxt(cfg config.SecurityTxtConfig) string {
+	var b strings.Builder
+
+	for _, contact := range cfg.Contacts {
+		b.WriteString("Contact: " + contact + "\n")
+	}
+	b.WriteString("Expires: " + cfg.Expires.UTC().Format(time.RFC3339) + "\n")
+
+	optional := []struct{ field, value string }{
+		{"Encryption", cfg.Encryption},
+		{"Acknowledgments", cfg.Acknowledgments},
+		{"Policy", cfg.Policy},
+		{"Hiring", cfg.Hiring},
+		{"Canonical", cfg.Canonical},
+		{"Preferred-Languages", cfg.PreferredLanguages},
+	}
+	for _, line := range optional {
+		if line.value != "" {
+			b.WriteString(line.field + ": " + line.value + "\n")
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/google/uuid"
+)
+
+// AuditEventResponse represents an audit event response
+type AuditEventResponse struct {
+	ID         uuid.UUID       `json:"id"`
+	ActorID    *uuid.UUID      `json:"actor_id,omitempty"`
+	TenantID   *uuid.UUID      `json:"tenant_id,omitempty"`
+	Method     string          `json:"method"`
+	Path       string          `json:"path"`
+	Route      string          `json:"route,omitempty"`
+	Status     int             `json:"status"`
+	Body       json.RawMessage `json:"body,omitempty" swaggertype:"object"`
+	IPAddress  string          `json:"ip_address,omitempty"`
+	UserAgent  string          `json:"user_agent,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	DurationMs int64           `json:"duration_ms"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditEventsResponse represents a paginated list of audit events
+type AuditEventsResponse struct {
+	Events []AuditEventResponse `json:"events"`
+	Meta   utils.PaginationMeta `json:"meta"`
+}
+
+// PageItems returns the listed audit events (utils.Paginated)
+func (r AuditEventsResponse) PageItems() any {
+	return r.Events
+}
+
+// PageMeta returns the pagination metadata (utils.Paginated)
+func (r AuditEventsResponse) PageMeta() utils.PaginationMeta {
+	return r.Meta
+}
+
+// AuditLogResponse represents an audit log entry response
+type AuditLogResponse struct {
+	ID           uuid.UUID       `json:"id"`
+	ActorID      *uuid.UUID      `json:"actor_id,omitempty"`
+	TenantID     *uuid.UUID      `json:"tenant_id,omitempty"`
+	Action       string          `json:"action"`
+	ResourceType string          `json:"resource_type"`
+	ResourceID   string          `json:"resource_id,omitempty"`
+	Before       json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After        json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	RequestID    string          `json:"request_id,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// AuditLogsResponse represents a paginated list of audit log entries
+type AuditLogsResponse struct {
+	Logs []AuditLogResponse   `json:"logs"`
+	Meta utils.PaginationMeta `json:"meta"`
+}
+
+// PageItems returns the listed audit log entries (utils.Paginated)
+func (r AuditLogsResponse) PageItems() any {
+	return r.Logs
+}
+
+// PageMeta returns the pagination metadata (utils.Paginated)
+func (r AuditLogsResponse) PageMeta() utils.PaginationMeta {
+	return r.Meta
+}
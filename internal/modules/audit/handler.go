@@ -0,0 +1,290 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"time"
+
+	"go_boilerplate/internal/modules/audit/dto"
+	"go_boilerplate/internal/shared/filter"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// AuditEventHandler defines the interface for audit log HTTP handlers
+type AuditEventHandler interface {
+	GetEvents(c *fiber.Ctx) error
+	GetEvent(c *fiber.Ctx) error
+}
+
+// auditEventHandler implements AuditEventHandler interface
+type auditEventHandler struct {
+	service AuditEventService
+}
+
+// auditEventFilters are the fields accepted by ?filter[field][op]= on the audit log
+var auditEventFilters = filter.Fields{
+	"actor_id":   {Column: "actor_id", Type: filter.UUID},
+	"method":     {Column: "method", Type: filter.String},
+	"path":       {Column: "path", Type: filter.String},
+	"route":      {Column: "route", Type: filter.String},
+	"status":     {Column: "status", Type: filter.Int},
+	"request_id": {Column: "request_id", Type: filter.String},
+	"created_at": {Column: "created_at", Type: filter.Time},
+}
+
+// NewAuditEventHandler creates a new audit event handler
+func NewAuditEventHandler(service AuditEventService) AuditEventHandler {
+	return &auditEventHandler{service: service}
+}
+
+// GetEvents gets audit events with pagination
+// @Summary Admin: List audit events
+// @Description Retrieve recorded POST/PUT/PATCH/DELETE requests with actor, status and redacted body, newest first (requires audit_events.read).
+// @Tags Audit
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 20, max: 100)"
+// @Param filter[actor_id] query string false "Filter by actor (user ID)"
+// @Param filter[method] query string false "Filter by HTTP method"
+// @Param filter[path][like] query string false "Filter by request path"
+// @Param filter[status][gte] query int false "Filter by response status (operators: eq, ne, gt, gte, lt, lte, in)"
+// @Param filter[created_at][gte] query string false "Recorded at or after (RFC 3339 or YYYY-MM-DD; operators: eq, gt, gte, lt, lte)"
+// @Success 200 {object} utils.APIResponse{data=dto.AuditEventsResponse} "Audit events retrieved"
+// @Failure 400 {object} utils.ProblemDetails "Invalid filter"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Failure 403 {object} utils.ProblemDetails "Forbidden"
+// @Router /audit-events [get]
+func (h *auditEventHandler) GetEvents(c *fiber.Ctx) error {
+	// Parse query parameters
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 20)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// Parse filters
+	f, err := filter.FromQuery(c, auditEventFilters)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid filter", err)
+	}
+
+	response, err := h.service.GetEvents(c.UserContext(), page, limit, f)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get audit events", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, response, "Audit events retrieved successfully")
+}
+
+// GetEvent gets an audit event by ID
+// @Summary Admin: Get audit event
+// @Description Retrieve a single audit event by its ID (requires audit_events.read).
+// @Tags Audit
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Audit event ID (UUID)"
+// @Success 200 {object} utils.APIResponse{data=dto.AuditEventResponse} "Audit event retrieved"
+// @Failure 400 {object} utils.ProblemDetails "Invalid audit event ID"
+// @Failure 404 {object} utils.ProblemDetails "Audit event not found"
+// @Router /audit-events/{id} [get]
+func (h *auditEventHandler) GetEvent(c *fiber.Ctx) error {
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid audit event ID", err)
+	}
+
+	event, err := h.service.GetEvent(c.UserContext(), eventID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get audit event", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, event, "Audit event retrieved successfully")
+}
+
+// Audit log export formats
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatJSON = "json"
+)
+
+// auditLogFilters are the fields accepted by ?filter[field][op]= on the audit log of changes
+var auditLogFilters = filter.Fields{
+	"actor_id":      {Column: "actor_id", Type: filter.UUID},
+	"action":        {Column: "action", Type: filter.String},
+	"resource_type": {Column: "resource_type", Type: filter.String},
+	"resource_id":   {Column: "resource_id", Type: filter.String},
+	"request_id":    {Column: "request_id", Type: filter.String},
+	"created_at":    {Column: "created_at", Type: filter.Time},
+}
+
+// AuditLogHandler defines the interface for audit log of changes HTTP handlers
+type AuditLogHandler interface {
+	GetLogs(c *fiber.Ctx) error
+	GetLog(c *fiber.Ctx) error
+	ExportLogs(c *fiber.Ctx) error
+}
+
+// auditLogHandler implements AuditLogHandler interface
+type auditLogHandler struct {
+	service AuditLogService
+}
+
+// NewAuditLogHandler creates a new audit log handler
+func NewAuditLogHandler(service AuditLogService) AuditLogHandler {
+	return &auditLogHandler{service: service}
+}
+
+// GetLogs gets audit log entries with pagination
+// @Summary Admin: List audit log
+// @Description Retrieve changes made to users, roles and sessions with their actor and redacted state before and after, newest first (requires audit_logs.read).
+// @Tags Audit
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 20, max: 100)"
+// @Param filter[actor_id] query string false "Filter by actor (user ID)"
+// @Param filter[action] query string false "Filter by action (e.g. user.updated; operators: eq, ne, like, in)"
+// @Param filter[resource_type] query string false "Filter by resource type (user, role, session)"
+// @Param filter[resource_id] query string false "Filter by resource ID"
+// @Param filter[created_at][gte] query string false "Recorded at or after (RFC 3339 or YYYY-MM-DD; operators: eq, gt, gte, lt, lte)"
+// @Success 200 {object} utils.APIResponse{data=dto.AuditLogsResponse} "Audit log retrieved"
+// @Failure 400 {object} utils.ProblemDetails "Invalid filter"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Failure 403 {object} utils.ProblemDetails "Forbidden"
+// @Router /admin/audit [get]
+func (h *auditLogHandler) GetLogs(c *fiber.Ctx) error {
+	// Parse query parameters
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 20)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// Parse filters
+	f, err := filter.FromQuery(c, auditLogFilters)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid filter", err)
+	}
+
+	response, err := h.service.GetLogs(c.UserContext(), page, limit, f)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get audit log", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, response, "Audit log retrieved successfully")
+}
+
+// GetLog gets an audit log entry by ID
+// @Summary Admin: Get audit log entry
+// @Description Retrieve a single audit log entry by its ID (requires audit_logs.read).
+// @Tags Audit
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Audit log entry ID (UUID)"
+// @Success 200 {object} utils.APIResponse{data=dto.AuditLogResponse} "Audit log entry retrieved"
+// @Failure 400 {object} utils.ProblemDetails "Invalid audit log entry ID"
+// @Failure 404 {object} utils.ProblemDetails "Audit log entry not found"
+// @Router /admin/audit/{id} [get]
+func (h *auditLogHandler) GetLog(c *fiber.Ctx) error {
+	logID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid audit log entry ID", err)
+	}
+
+	log, err := h.service.GetLog(c.UserContext(), logID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get audit log entry", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, log, "Audit log entry retrieved successfully")
+}
+
+// ExportLogs downloads audit log entries
+// @Summary Admin: Export audit log
+// @Description Download the newest audit log entries matching the filters (up to AUDIT_LOG_EXPORT_MAX_ROWS) as CSV or a JSON array (requires audit_logs.read).
+// @Tags Audit
+// @Produce text/csv,json
+// @Security BearerAuth
+// @Param format query string false "csv (default) or json"
+// @Param filter[actor_id] query string false "Filter by actor (user ID)"
+// @Param filter[action] query string false "Filter by action"
+// @Param filter[resource_type] query string false "Filter by resource type"
+// @Param filter[resource_id] query string false "Filter by resource ID"
+// @Param filter[created_at][gte] query string false "Recorded at or after (RFC 3339 or YYYY-MM-DD)"
+// @Success 200 {file} file "Audit log export"
+// @Failure 400 {object} utils.ProblemDetails "Invalid format or filter"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Failure 403 {object} utils.ProblemDetails "Forbidden"
+// @Router /admin/audit/export [get]
+func (h *auditLogHandler) ExportLogs(c *fiber.Ctx) error {
+	format := c.Query("format", ExportFormatCSV)
+	if format != ExportFormatCSV && format != ExportFormatJSON {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid export format, want csv or json", nil)
+	}
+
+	f, err := filter.FromQuery(c, auditLogFilters)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid filter", err)
+	}
+
+	logs, err := h.service.ExportLogs(c.UserContext(), f)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to export audit log", err)
+	}
+
+	fileName := "audit-log-" + time.Now().UTC().Format("20060102-150405") + "." + format
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+fileName+`"`)
+	if format == ExportFormatJSON {
+		return c.JSON(logs)
+	}
+
+	body, err := auditLogCSV(logs)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to export audit log", err)
+	}
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	return c.Send(body)
+}
+
+// auditLogCSV encodes audit log entries as CSV with a header row; before and after are JSON
+func auditLogCSV(logs []dto.AuditLogResponse) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"id", "created_at", "actor_id", "tenant_id", "action", "resource_type", "resource_id", "request_id", "before", "after"})
+	for _, log := range logs {
+		_ = w.Write([]string{
+			log.ID.String(),
+			log.CreatedAt.UTC().Format(time.RFC3339),
+			optionalUUID(log.ActorID),
+			optionalUUID(log.TenantID),
+			log.Action,
+			log.ResourceType,
+			log.ResourceID,
+			log.RequestID,
+			string(log.Before),
+			string(log.After),
+		})
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// optionalUUID formats an optional ID, empty when nil
+func optionalUUID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"go_boilerplate/internal/modules/audit/dto"
+
+	"github.com/google/uuid"
+)
+
+// AuditEvent is a recorded mutating request (POST/PUT/PATCH/DELETE)
+type AuditEvent struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ActorID    *uuid.UUID `json:"actor_id" gorm:"type:uuid;index"`  // Nil for anonymous requests (e.g. login, register)
+	TenantID   *uuid.UUID `json:"tenant_id" gorm:"type:uuid;index"` // Nil when tenancy is disabled or the request named no tenant
+	Method     string     `json:"method" gorm:"type:varchar(10);not null"`
+	Path       string     `json:"path" gorm:"type:varchar(2048);not null"`
+	Route      string     `json:"route" gorm:"type:varchar(255);index"`
+	Status     int        `json:"status" gorm:"not null;index"`
+	Body       string     `json:"body" gorm:"type:text"` // Redacted JSON request body
+	IPAddress  string     `json:"ip_address" gorm:"type:varchar(45)"`
+	UserAgent  string     `json:"user_agent" gorm:"type:text"`
+	RequestID  string     `json:"request_id" gorm:"type:varchar(64)"`
+	DurationMs int64      `json:"duration_ms" gorm:"not null;default:0"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"index"`
+}
+
+// TableName specifies the table name for AuditEvent model
+func (AuditEvent) TableName() string {
+	return "t_audit_events"
+}
+
+// ToResponse converts AuditEvent to AuditEventResponse
+func (e *AuditEvent) ToResponse() dto.AuditEventResponse {
+	response := dto.AuditEventResponse{
+		ID:         e.ID,
+		ActorID:    e.ActorID,
+		TenantID:   e.TenantID,
+		Method:     e.Method,
+		Path:       e.Path,
+		Route:      e.Route,
+		Status:     e.Status,
+		IPAddress:  e.IPAddress,
+		UserAgent:  e.UserAgent,
+		RequestID:  e.RequestID,
+		DurationMs: e.DurationMs,
+		CreatedAt:  e.CreatedAt,
+	}
+	if e.Body != "" && json.Valid([]byte(e.Body)) {
+		response.Body = json.RawMessage(e.Body)
+	}
+	return response
+}
+
+// AuditLog is a change made through a service: who did what to which resource, with the resource's
+// state before and after (see Trail)
+type AuditLog struct {
+	ID           uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ActorID      *uuid.UUID      `json:"actor_id" gorm:"type:uuid;index"`                                                // Nil for changes made by the system (jobs, seeding)
+	TenantID     *uuid.UUID      `json:"tenant_id" gorm:"type:uuid;index"`                                               // Nil when tenancy is disabled or the request named no tenant
+	Action       string          `json:"action" gorm:"type:varchar(100);not null;index"`                                 // <resource>.<verb>, e.g. user.updated
+	ResourceType string          `json:"resource_type" gorm:"type:varchar(50);not null;index:idx_t_audit_logs_resource"` // e.g. user, role, session
+	ResourceID   string          `json:"resource_id" gorm:"type:varchar(64);index:idx_t_audit_logs_resource"`
+	Before       json.RawMessage `json:"before" gorm:"type:jsonb"` // Redacted state before the change; null for creations
+	After        json.RawMessage `json:"after" gorm:"type:jsonb"`  // Redacted state after the change; null for deletions
+	RequestID    string          `json:"request_id" gorm:"type:varchar(64)"`
+	CreatedAt    time.Time       `json:"created_at" gorm:"index"`
+}
+
+// TableName specifies the table name for AuditLog model
+func (AuditLog) TableName() string {
+	return "t_audit_logs"
+}
+
+// ToResponse converts AuditLog to AuditLogResponse
+func (l *AuditLog) ToResponse() dto.AuditLogResponse {
+	return dto.AuditLogResponse{
+		ID:           l.ID,
+		ActorID:      l.ActorID,
+		TenantID:     l.TenantID,
+		Action:       l.Action,
+		ResourceType: l.ResourceType,
+		ResourceID:   l.ResourceID,
+		Before:       l.Before,
+		After:        l.After,
+		RequestID:    l.RequestID,
+		CreatedAt:    l.CreatedAt,
+	}
+}
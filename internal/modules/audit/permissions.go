@@ -0,0 +1,16 @@
+package audit
+
+import "go_boilerplate/internal/shared/permission"
+
+// Audit log permissions
+const (
+	PermAuditEventsRead = "audit_events.read"
+	PermAuditLogsRead   = "audit_logs.read"
+)
+
+func init() {
+	permission.Register(
+		permission.Permission{Name: PermAuditEventsRead, Description: "View the audit log of mutating requests"},
+		permission.Permission{Name: PermAuditLogsRead, Description: "View and export the audit log of changes to users, roles and sessions"},
+	)
+}
@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"context"
+	"sync"
+
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/middleware"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Recorder buffers audit entries in memory and periodically flushes them to the database,
+// so recording never adds a query to the audited request
+type Recorder struct {
+	repo    AuditEventRepository
+	logger  *logrus.Logger
+	limit   int
+	mu      sync.Mutex
+	pending []AuditEvent
+	dropped int
+}
+
+// NewRecorder creates a new audit recorder
+func NewRecorder(repo AuditEventRepository, cfg config.AuditConfig, logger *logrus.Logger) *Recorder {
+	return &Recorder{
+		repo:   repo,
+		logger: logger,
+		limit:  cfg.BufferSize,
+	}
+}
+
+// Record implements middleware.AuditRecorder
+func (r *Recorder) Record(entry middleware.AuditEntry) {
+	event := AuditEvent{
+		Method:     entry.Method,
+		Path:       entry.Path,
+		Route:      entry.Route,
+		TenantID:   entry.TenantID,
+		Status:     entry.Status,
+		Body:       entry.Body,
+		IPAddress:  entry.IPAddress,
+		UserAgent:  entry.UserAgent,
+		RequestID:  entry.RequestID,
+		DurationMs: entry.DurationMs,
+		CreatedAt:  entry.OccurredAt,
+	}
+	if id, err := uuid.Parse(entry.ActorID); err == nil {
+		event.ActorID = &id
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.limit > 0 && len(r.pending) >= r.limit {
+		r.dropped++
+		return
+	}
+	r.pending = append(r.pending, event)
+}
+
+// Flush writes buffered events to the database
+func (r *Recorder) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	events, dropped := r.pending, r.dropped
+	r.pending, r.dropped = nil, 0
+	r.mu.Unlock()
+
+	if dropped > 0 {
+		r.logger.Warnf("Audit buffer full: dropped %d events (raise AUDIT_BUFFER_SIZE or lower AUDIT_FLUSH_INTERVAL)", dropped)
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	if err := r.repo.CreateBatch(ctx, events); err != nil {
+		// Keep the events for the next flush, as far as the buffer allows
+		r.mu.Lock()
+		r.pending = append(events, r.pending...)
+		if r.limit > 0 && len(r.pending) > r.limit {
+			r.dropped += len(r.pending) - r.limit
+			r.pending = r.pending[:r.limit]
+		}
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"go_boilerplate/internal/shared/filter"
+	"go_boilerplate/internal/shared/tenant"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditEventRepository defines the interface for audit event data operations
+type AuditEventRepository interface {
+	CreateBatch(ctx context.Context, events []AuditEvent) error
+	FindByID(ctx context.Context, id uuid.UUID) (*AuditEvent, error)
+	FindAll(ctx context.Context, offset, limit int, f filter.Filter) ([]AuditEvent, int64, error)
+}
+
+// auditEventRepository implements AuditEventRepository interface
+type auditEventRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditEventRepository creates a new audit event repository
+func NewAuditEventRepository(db *gorm.DB) AuditEventRepository {
+	return &auditEventRepository{db: db}
+}
+
+// CreateBatch inserts buffered audit events
+func (r *auditEventRepository) CreateBatch(ctx context.Context, events []AuditEvent) error {
+	return r.db.WithContext(ctx).CreateInBatches(events, 500).Error
+}
+
+// FindByID finds an audit event by ID within the request tenant
+func (r *auditEventRepository) FindByID(ctx context.Context, id uuid.UUID) (*AuditEvent, error) {
+	var event AuditEvent
+	if err := r.db.WithContext(ctx).Scopes(tenant.Scope(ctx)).Where("id = ?", id).First(&event).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// FindAll finds audit events of the request tenant matching the filter with pagination, newest first
+func (r *auditEventRepository) FindAll(ctx context.Context, offset, limit int, f filter.Filter) ([]AuditEvent, int64, error) {
+	var events []AuditEvent
+	var total int64
+	db := r.db.WithContext(ctx).Scopes(tenant.Scope(ctx))
+
+	// Count total
+	if err := db.Model(&AuditEvent{}).Scopes(f.Scope()).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Find events with pagination
+	err := db.Scopes(f.Scope()).Offset(offset).Limit(limit).Order("created_at DESC").Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+// AuditLogRepository defines the interface for audit log data operations
+type AuditLogRepository interface {
+	Create(ctx context.Context, log *AuditLog) error
+	FindByID(ctx context.Context, id uuid.UUID) (*AuditLog, error)
+	FindAll(ctx context.Context, offset, limit int, f filter.Filter) ([]AuditLog, int64, error)
+	FindLatest(ctx context.Context, limit int, f filter.Filter) ([]AuditLog, error)
+	DeleteOlderThan(ctx context.Context, cutoff time.Time, resourceTypes, excluded []string, batchSize int) (int64, error)
+}
+
+// auditLogRepository implements AuditLogRepository interface
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+// Create inserts an audit log entry
+func (r *auditLogRepository) Create(ctx context.Context, log *AuditLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+// FindByID finds an audit log entry by ID within the request tenant
+func (r *auditLogRepository) FindByID(ctx context.Context, id uuid.UUID) (*AuditLog, error) {
+	var log AuditLog
+	if err := r.db.WithContext(ctx).Scopes(tenant.Scope(ctx)).Where("id = ?", id).First(&log).Error; err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+// FindAll finds audit log entries of the request tenant matching the filter with pagination, newest first
+func (r *auditLogRepository) FindAll(ctx context.Context, offset, limit int, f filter.Filter) ([]AuditLog, int64, error) {
+	var logs []AuditLog
+	var total int64
+	db := r.db.WithContext(ctx).Scopes(tenant.Scope(ctx))
+
+	// Count total
+	if err := db.Model(&AuditLog{}).Scopes(f.Scope()).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Find entries with pagination
+	err := db.Scopes(f.Scope()).Offset(offset).Limit(limit).Order("created_at DESC").Find(&logs).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
+
+// FindLatest finds the newest limit audit log entries of the request tenant matching the filter
+func (r *auditLogRepository) FindLatest(ctx context.Context, limit int, f filter.Filter) ([]AuditLog, error) {
+	var logs []AuditLog
+	err := r.db.WithContext(ctx).Scopes(tenant.Scope(ctx), f.Scope()).Limit(limit).Order("created_at DESC").Find(&logs).Error
+	return logs, err
+}
+
+// DeleteOlderThan deletes entries created before cutoff, batchSize at a time so a large purge
+// doesn't hold long locks: entries of resourceTypes, or with none given, of every resource type but
+// the excluded ones
+func (r *auditLogRepository) DeleteOlderThan(ctx context.Context, cutoff time.Time, resourceTypes, excluded []string, batchSize int) (int64, error) {
+	var deleted int64
+	for {
+		batch := r.db.WithContext(ctx).Model(&AuditLog{}).Select("id").Where("created_at < ?", cutoff).Limit(batchSize)
+		if len(resourceTypes) > 0 {
+			batch = batch.Where("resource_type IN ?", resourceTypes)
+		} else if len(excluded) > 0 {
+			batch = batch.Where("resource_type NOT IN ?", excluded)
+		}
+
+		result := r.db.WithContext(ctx).Where("id IN (?)", batch).Delete(&AuditLog{})
+		deleted += result.RowsAffected
+		if result.Error != nil || result.RowsAffected < int64(batchSize) {
+			return deleted, result.Error
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package audit
+
+import (
+	"go_boilerplate/internal/shared/apiversion"
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// RegisterRoutes registers the audit log query routes
+// Requests are recorded by middleware.Audit, registered globally in main, and changes by the
+// services through Trail
+func RegisterRoutes(app *fiber.App, db *gorm.DB, cfg *config.Config, logger *logrus.Logger) {
+	// Initialize repository, service and handler
+	eventService := NewAuditEventService(NewAuditEventRepository(db))
+	eventHandler := NewAuditEventHandler(eventService)
+
+	// Create API route group
+	api := apiversion.Group(app, "v1")
+	events := api.Group("/audit-events")
+	events.Use(middleware.JWTAuth(cfg))
+	events.Use(middleware.ConcurrencyLimit(cfg.Concurrency, cfg.Concurrency.ReportMaxInFlight)) // Filtered scans of a large table
+	canRead := middleware.RequirePermission(cfg, PermAuditEventsRead)
+
+	events.Get("/", canRead, eventHandler.GetEvents)   // List audit events
+	events.Get("/:id", canRead, eventHandler.GetEvent) // Get audit event by ID
+
+	// Audit log of changes recorded by the services (audit.Trail)
+	logHandler := NewAuditLogHandler(NewAuditLogService(NewAuditLogRepository(db), cfg.Audit.ExportMaxRows))
+	logs := api.Group("/admin/audit")
+	logs.Use(middleware.JWTAuth(cfg))
+	logs.Use(middleware.ConcurrencyLimit(cfg.Concurrency, cfg.Concurrency.ReportMaxInFlight))
+	canReadLogs := middleware.RequirePermission(cfg, PermAuditLogsRead)
+
+	logs.Get("/", canReadLogs, logHandler.GetLogs)          // List audit log entries
+	logs.Get("/export", canReadLogs, logHandler.ExportLogs) // Export audit log entries (CSV or JSON)
+	logs.Get("/:id", canReadLogs, logHandler.GetLog)        // Get audit log entry by ID
+}
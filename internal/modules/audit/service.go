@@ -0,0 +1,152 @@
+package audit
+
+import (
+	"context"
+	"math"
+
+	"go_boilerplate/internal/modules/audit/dto"
+	"go_boilerplate/internal/shared/apperror"
+	"go_boilerplate/internal/shared/database/repository"
+	"go_boilerplate/internal/shared/filter"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/google/uuid"
+)
+
+// AuditEventService defines the interface for audit log queries
+type AuditEventService interface {
+	GetEvent(ctx context.Context, id uuid.UUID) (*dto.AuditEventResponse, error)
+	GetEvents(ctx context.Context, page, limit int, f filter.Filter) (*dto.AuditEventsResponse, error)
+}
+
+// ErrAuditEventNotFound is returned for unknown audit event IDs
+var ErrAuditEventNotFound = apperror.New(apperror.ErrNotFound, "audit event not found").WithCode("audit_event_not_found")
+
+// auditEventService implements AuditEventService interface
+type auditEventService struct {
+	repo AuditEventRepository
+}
+
+// NewAuditEventService creates a new audit event service
+func NewAuditEventService(repo AuditEventRepository) AuditEventService {
+	return &auditEventService{repo: repo}
+}
+
+// GetEvent gets an audit event by ID
+func (s *auditEventService) GetEvent(ctx context.Context, id uuid.UUID) (*dto.AuditEventResponse, error) {
+	event, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, repository.LookupError(err, ErrAuditEventNotFound, "audit event")
+	}
+
+	response := event.ToResponse()
+	return &response, nil
+}
+
+// GetEvents gets audit events matching the filter with pagination
+func (s *auditEventService) GetEvents(ctx context.Context, page, limit int, f filter.Filter) (*dto.AuditEventsResponse, error) {
+	// Calculate offset
+	offset := (page - 1) * limit
+
+	// Find events
+	events, total, err := s.repo.FindAll(ctx, offset, limit, f)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response
+	eventResponses := make([]dto.AuditEventResponse, len(events))
+	for i, event := range events {
+		eventResponses[i] = event.ToResponse()
+	}
+
+	// Calculate total pages
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &dto.AuditEventsResponse{
+		Events: eventResponses,
+		Meta: utils.PaginationMeta{
+			Page:       page,
+			Limit:      limit,
+			Total:      int(total),
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// AuditLogService defines the interface for audit log queries
+type AuditLogService interface {
+	GetLog(ctx context.Context, id uuid.UUID) (*dto.AuditLogResponse, error)
+	GetLogs(ctx context.Context, page, limit int, f filter.Filter) (*dto.AuditLogsResponse, error)
+	ExportLogs(ctx context.Context, f filter.Filter) ([]dto.AuditLogResponse, error)
+}
+
+// ErrAuditLogNotFound is returned for unknown audit log entry IDs
+var ErrAuditLogNotFound = apperror.New(apperror.ErrNotFound, "audit log entry not found").WithCode("audit_log_not_found")
+
+// auditLogService implements AuditLogService interface
+type auditLogService struct {
+	repo          AuditLogRepository
+	exportMaxRows int
+}
+
+// NewAuditLogService creates a new audit log service; exports return the newest exportMaxRows entries
+func NewAuditLogService(repo AuditLogRepository, exportMaxRows int) AuditLogService {
+	return &auditLogService{repo: repo, exportMaxRows: exportMaxRows}
+}
+
+// GetLog gets an audit log entry by ID
+func (s *auditLogService) GetLog(ctx context.Context, id uuid.UUID) (*dto.AuditLogResponse, error) {
+	log, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, repository.LookupError(err, ErrAuditLogNotFound, "audit log")
+	}
+
+	response := log.ToResponse()
+	return &response, nil
+}
+
+// GetLogs gets audit log entries matching the filter with pagination
+func (s *auditLogService) GetLogs(ctx context.Context, page, limit int, f filter.Filter) (*dto.AuditLogsResponse, error) {
+	// Calculate offset
+	offset := (page - 1) * limit
+
+	// Find entries
+	logs, total, err := s.repo.FindAll(ctx, offset, limit, f)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response
+	logResponses := make([]dto.AuditLogResponse, len(logs))
+	for i, log := range logs {
+		logResponses[i] = log.ToResponse()
+	}
+
+	// Calculate total pages
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &dto.AuditLogsResponse{
+		Logs: logResponses,
+		Meta: utils.PaginationMeta{
+			Page:       page,
+			Limit:      limit,
+			Total:      int(total),
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// ExportLogs gets the newest audit log entries matching the filter, up to AUDIT_LOG_EXPORT_MAX_ROWS
+func (s *auditLogService) ExportLogs(ctx context.Context, f filter.Filter) ([]dto.AuditLogResponse, error) {
+	logs, err := s.repo.FindLatest(ctx, s.exportMaxRows, f)
+	if err != nil {
+		return nil, err
+	}
+
+	logResponses := make([]dto.AuditLogResponse, len(logs))
+	for i, log := range logs {
+		logResponses[i] = log.ToResponse()
+	}
+	return logResponses, nil
+}
@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"time"
+
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/middleware"
+	"go_boilerplate/internal/shared/observability"
+	"go_boilerplate/internal/shared/tenant"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// purgeBatchSize is the number of audit log entries deleted per statement
+const purgeBatchSize = 1000
+
+// Resource identifies what a change was made to, e.g. Resource{Type: "user", ID: userID.String()}
+type Resource struct {
+	Type string
+	ID   string
+}
+
+// Trail records changes made through the services in t_audit_logs: who did what to which
+// resource, with its state before and after (redacted like request bodies, AUDIT_REDACT_FIELDS).
+// It complements the request log of middleware.Audit, which sees requests but not their effect.
+// A nil *Trail records nothing, which is the case with AUDIT_LOG_ENABLED=false.
+type Trail struct {
+	repo   AuditLogRepository
+	cfg    config.AuditConfig
+	logger *logrus.Logger
+}
+
+// NewTrail creates an audit trail; it returns nil while AUDIT_LOG_ENABLED is off
+func NewTrail(db *gorm.DB, cfg config.AuditConfig, logger *logrus.Logger) *Trail {
+	if !cfg.LogEnabled {
+		return nil
+	}
+	return &Trail{repo: NewAuditLogRepository(db), cfg: cfg, logger: logger}
+}
+
+// ActorFromContext returns the ID of the user authenticated for the request in ctx, or "" outside
+// requests (jobs, seeding) and for anonymous ones
+func ActorFromContext(ctx context.Context) string {
+	claims, ok := middleware.ClaimsFromContext(ctx)
+	if !ok || claims.UserID == uuid.Nil {
+		return ""
+	}
+	return claims.UserID.String()
+}
+
+// Record stores a change made by actor (a user ID; "" for the system). action is named
+// "<resource>.<verb>" (user.updated); before is nil for creations and after for deletions.
+// The change has already been made, so a failure is logged rather than returned.
+func (t *Trail) Record(ctx context.Context, actor, action string, resource Resource, before, after any) {
+	if t == nil {
+		return
+	}
+
+	entry := AuditLog{
+		TenantID:     tenant.IDFromContext(ctx),
+		Action:       action,
+		ResourceType: resource.Type,
+		ResourceID:   resource.ID,
+		Before:       t.encode(before),
+		After:        t.encode(after),
+		CreatedAt:    time.Now(),
+	}
+	if id, err := uuid.Parse(actor); err == nil {
+		entry.ActorID = &id
+	}
+	if request, ok := observability.RequestFromContext(ctx); ok {
+		entry.RequestID = request.ID
+	}
+
+	if err := t.repo.Create(context.WithoutCancel(ctx), &entry); err != nil {
+		t.logger.WithContext(ctx).WithFields(logrus.Fields{
+			"action":        action,
+			"resource_type": resource.Type,
+			"resource_id":   resource.ID,
+			"actor_id":      actor,
+		}).Errorf("Failed to record audit log: %v", err)
+	}
+}
+
+// encode returns the redacted JSON of a resource state, or nil without one
+func (t *Trail) encode(state any) json.RawMessage {
+	if state == nil {
+		return nil
+	}
+	encoded, err := middleware.RedactJSON(state, t.cfg.RedactFields)
+	if err != nil || string(encoded) == "null" {
+		return nil
+	}
+	return encoded
+}
+
+// Purge deletes entries older than their retention: AUDIT_LOG_RETENTION_RESOURCES for the listed
+// resource types, AUDIT_LOG_RETENTION for the others. It is meant to be scheduled as an exclusive job.
+func (t *Trail) Purge(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	overridden := slices.Sorted(maps.Keys(t.cfg.ResourceRetentions))
+	var purged int64
+	for _, resourceType := range overridden {
+		retention := t.cfg.ResourceRetentions[resourceType]
+		if retention <= 0 {
+			continue
+		}
+		rows, err := t.repo.DeleteOlderThan(ctx, time.Now().Add(-retention), []string{resourceType}, nil, purgeBatchSize)
+		purged += rows
+		if err != nil {
+			return err
+		}
+	}
+	if t.cfg.LogRetention > 0 {
+		rows, err := t.repo.DeleteOlderThan(ctx, time.Now().Add(-t.cfg.LogRetention), nil, overridden, purgeBatchSize)
+		purged += rows
+		if err != nil {
+			return err
+		}
+	}
+
+	if purged > 0 {
+		t.logger.WithField("rows", purged).Infof("Purged %d expired audit log entries", purged)
+	}
+	return nil
+}
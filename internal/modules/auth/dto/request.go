@@ -4,12 +4,15 @@ package dto
 type RegisterRequest struct {
 	Name     string `json:"name" validate:"required,min=3,max=100"`
 	Email    string `json:"email" validate:"required,email"`
+	Username string `json:"username" validate:"omitempty,username"` // Optional unique handle
 	Password string `json:"password" validate:"required,min=6,max=50"`
 }
 
 // LoginRequest represents a login request
+// Either email or username identifies the account
 type LoginRequest struct {
-	Email    string `json:"email" validate:"required,email"`
+	Email    string `json:"email" validate:"required_without=Username,omitempty,email"`
+	Username string `json:"username" validate:"required_without=Email"`
 	Password string `json:"password" validate:"required"`
 }
 
@@ -40,4 +43,5 @@ type SessionMetadata struct {
 	IPAddress string
 	UserAgent string
 	DeviceID  string
+	Locale    string // negotiated locale, used for emails sent during the request
 }
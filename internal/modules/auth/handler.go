@@ -1,7 +1,10 @@
 package auth
 
 import (
+	"strings"
+
 	"go_boilerplate/internal/modules/auth/dto"
+	"go_boilerplate/internal/shared/i18n"
 	"go_boilerplate/internal/shared/middleware"
 	"go_boilerplate/internal/shared/utils"
 
@@ -36,22 +39,22 @@ func NewAuthHandler(service AuthService) AuthHandler {
 
 // Register registers a new user
 // @Summary Register a new user
-// @Description Create a new user account with name, email and password.
+// @Description Create a new user account with name, email, password and an optional username.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param request body dto.RegisterRequest true "Registration data"
 // @Success 201 {object} utils.APIResponse{data=dto.AuthResponse} "Registration successful"
-// @Failure 400 {object} utils.APIResponse "Invalid request data"
+// @Failure 400 {object} utils.ProblemDetails "Invalid request data"
 // @Router /auth/register [post]
 func (h *authHandler) Register(c *fiber.Ctx) error {
 	// Get validated body from context
 	req := c.Locals("validatedBody").(*dto.RegisterRequest)
 
 	// Register user
-	response, err := h.service.Register(req, h.getMetadata(c))
+	response, err := h.service.Register(c.UserContext(), req, h.getMetadata(c))
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Registration failed", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Registration failed", err)
 	}
 
 	return utils.SuccessResponse(c, fiber.StatusCreated, response, "Registration successful")
@@ -59,22 +62,22 @@ func (h *authHandler) Register(c *fiber.Ctx) error {
 
 // Login logs in a user
 // @Summary Login user
-// @Description Authenticate user and return tokens.
+// @Description Authenticate user with email or username and return tokens.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param request body dto.LoginRequest true "Login credentials"
 // @Success 200 {object} utils.APIResponse{data=dto.AuthResponse} "Login successful"
-// @Failure 401 {object} utils.APIResponse "Invalid credentials"
+// @Failure 401 {object} utils.ProblemDetails "Invalid credentials"
 // @Router /auth/login [post]
 func (h *authHandler) Login(c *fiber.Ctx) error {
 	// Get validated body from context
 	req := c.Locals("validatedBody").(*dto.LoginRequest)
 
 	// Login user
-	response, err := h.service.Login(req, h.getMetadata(c))
+	response, err := h.service.Login(c.UserContext(), req, h.getMetadata(c))
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Login failed", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Login failed", err)
 	}
 
 	message := "Login successful"
@@ -93,16 +96,16 @@ func (h *authHandler) Login(c *fiber.Ctx) error {
 // @Produce json
 // @Param request body dto.RefreshTokenRequest true "Refresh token"
 // @Success 200 {object} utils.APIResponse{data=dto.AuthResponse} "Token refreshed"
-// @Failure 401 {object} utils.APIResponse "Invalid or expired refresh token"
+// @Failure 401 {object} utils.ProblemDetails "Invalid or expired refresh token"
 // @Router /auth/refresh [post]
 func (h *authHandler) RefreshToken(c *fiber.Ctx) error {
 	// Get validated body from context
 	req := c.Locals("validatedBody").(*dto.RefreshTokenRequest)
 
 	// Refresh token
-	response, err := h.service.RefreshToken(req.RefreshToken, h.getMetadata(c))
+	response, err := h.service.RefreshToken(c.UserContext(), req.RefreshToken, h.getMetadata(c))
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Token refresh failed", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Token refresh failed", err)
 	}
 
 	return utils.SuccessResponse(c, fiber.StatusOK, response, "Token refreshed successfully")
@@ -110,20 +113,20 @@ func (h *authHandler) RefreshToken(c *fiber.Ctx) error {
 
 // Logout logs out a user
 // @Summary Logout user
-// @Description Invalidate the refresh token.
+// @Description Invalidate the refresh token. A bearer access token sent in the Authorization header is revoked as well.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param request body dto.RefreshTokenRequest true "Refresh token to invalidate"
 // @Success 200 {object} utils.APIResponse "Logout successful"
-// @Failure 500 {object} utils.APIResponse "Logout failed"
+// @Failure 500 {object} utils.ProblemDetails "Logout failed"
 // @Router /auth/logout [post]
 func (h *authHandler) Logout(c *fiber.Ctx) error {
 	// Get validated body from context
 	req := c.Locals("validatedBody").(*dto.RefreshTokenRequest)
 
 	// Logout user
-	if err := h.service.Logout(req.RefreshToken); err != nil {
+	if err := h.service.Logout(c.UserContext(), req.RefreshToken, bearerToken(c)); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Logout failed", err)
 	}
 
@@ -138,13 +141,13 @@ func (h *authHandler) Logout(c *fiber.Ctx) error {
 // @Produce json
 // @Param request body dto.VerifyEmailRequest true "Verification data"
 // @Success 200 {object} utils.APIResponse "Email verified"
-// @Failure 400 {object} utils.APIResponse "Invalid or expired code"
+// @Failure 400 {object} utils.ProblemDetails "Invalid or expired code"
 // @Router /auth/verify-email [post]
 func (h *authHandler) VerifyEmail(c *fiber.Ctx) error {
 	req := c.Locals("validatedBody").(*dto.VerifyEmailRequest)
 
-	if err := h.service.VerifyEmail(req); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Email verification failed", err)
+	if err := h.service.VerifyEmail(c.UserContext(), req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Email verification failed", err)
 	}
 
 	return utils.SuccessResponse(c, fiber.StatusOK, nil, "Email verified successfully")
@@ -158,14 +161,14 @@ func (h *authHandler) VerifyEmail(c *fiber.Ctx) error {
 // @Produce json
 // @Param request body dto.Verify2FARequest true "2FA data"
 // @Success 200 {object} utils.APIResponse{data=dto.AuthResponse} "2FA verified"
-// @Failure 401 {object} utils.APIResponse "Invalid or expired OTP"
+// @Failure 401 {object} utils.ProblemDetails "Invalid or expired OTP"
 // @Router /auth/verify-2fa [post]
 func (h *authHandler) Verify2FA(c *fiber.Ctx) error {
 	req := c.Locals("validatedBody").(*dto.Verify2FARequest)
 
-	response, err := h.service.Verify2FA(req, h.getMetadata(c))
+	response, err := h.service.Verify2FA(c.UserContext(), req, h.getMetadata(c))
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "2FA verification failed", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "2FA verification failed", err)
 	}
 
 	return utils.SuccessResponse(c, fiber.StatusOK, response, "2FA verified successfully")
@@ -179,13 +182,13 @@ func (h *authHandler) Verify2FA(c *fiber.Ctx) error {
 // @Produce json
 // @Param request body dto.ResendCodeRequest true "Email address"
 // @Success 200 {object} utils.APIResponse "Code resent"
-// @Failure 400 {object} utils.APIResponse "Invalid request"
+// @Failure 400 {object} utils.ProblemDetails "Invalid request"
 // @Router /auth/resend-verification [post]
 func (h *authHandler) ResendVerification(c *fiber.Ctx) error {
 	req := c.Locals("validatedBody").(*dto.ResendCodeRequest)
 
-	if err := h.service.ResendVerification(req.Email); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to resend activation code", err)
+	if err := h.service.ResendVerification(c.UserContext(), req.Email, i18n.FromContext(c.UserContext())); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to resend activation code", err)
 	}
 
 	return utils.SuccessResponse(c, fiber.StatusOK, nil, "Activation code resent successfully")
@@ -199,13 +202,13 @@ func (h *authHandler) ResendVerification(c *fiber.Ctx) error {
 // @Produce json
 // @Param request body dto.ResendCodeRequest true "Email address"
 // @Success 200 {object} utils.APIResponse "Code resent"
-// @Failure 400 {object} utils.APIResponse "Invalid request"
+// @Failure 400 {object} utils.ProblemDetails "Invalid request"
 // @Router /auth/resend-2fa [post]
 func (h *authHandler) Resend2FA(c *fiber.Ctx) error {
 	req := c.Locals("validatedBody").(*dto.ResendCodeRequest)
 
-	if err := h.service.Resend2FA(req.Email); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to resend 2FA code", err)
+	if err := h.service.Resend2FA(c.UserContext(), req.Email, i18n.FromContext(c.UserContext())); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to resend 2FA code", err)
 	}
 
 	return utils.SuccessResponse(c, fiber.StatusOK, nil, "2FA code resent successfully")
@@ -218,7 +221,7 @@ func (h *authHandler) Resend2FA(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {object} utils.APIResponse{data=[]dto.Session} "Sessions retrieved"
-// @Failure 401 {object} utils.APIResponse "Unauthorized"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
 // @Router /auth/sessions [get]
 func (h *authHandler) GetSessions(c *fiber.Ctx) error {
 	userIDStr, ok := middleware.GetUserIDFromContext(c)
@@ -227,7 +230,7 @@ func (h *authHandler) GetSessions(c *fiber.Ctx) error {
 	}
 
 	userID, _ := uuid.Parse(userIDStr)
-	sessions, err := h.service.GetSessions(userID)
+	sessions, err := h.service.GetSessions(c.UserContext(), userID)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get sessions", err)
 	}
@@ -243,8 +246,8 @@ func (h *authHandler) GetSessions(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param id path string true "Session ID (UUID)"
 // @Success 200 {object} utils.APIResponse "Session deleted"
-// @Failure 400 {object} utils.APIResponse "Invalid session ID"
-// @Failure 401 {object} utils.APIResponse "Unauthorized"
+// @Failure 400 {object} utils.ProblemDetails "Invalid session ID"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
 // @Router /auth/sessions/{id} [delete]
 func (h *authHandler) DeleteSession(c *fiber.Ctx) error {
 	userIDStr, ok := middleware.GetUserIDFromContext(c)
@@ -258,7 +261,7 @@ func (h *authHandler) DeleteSession(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid session ID", err)
 	}
 
-	if err := h.service.DeleteSession(userID, sessionID); err != nil {
+	if err := h.service.DeleteSession(c.UserContext(), userID, sessionID); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to delete session", err)
 	}
 
@@ -273,8 +276,8 @@ func (h *authHandler) DeleteSession(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param id path string true "Session ID (UUID)"
 // @Success 200 {object} utils.APIResponse "Session blocked"
-// @Failure 400 {object} utils.APIResponse "Invalid session ID"
-// @Failure 401 {object} utils.APIResponse "Unauthorized"
+// @Failure 400 {object} utils.ProblemDetails "Invalid session ID"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
 // @Router /auth/sessions/{id}/block [patch]
 func (h *authHandler) BlockSession(c *fiber.Ctx) error {
 	userIDStr, ok := middleware.GetUserIDFromContext(c)
@@ -288,18 +291,28 @@ func (h *authHandler) BlockSession(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid session ID", err)
 	}
 
-	if err := h.service.BlockSession(userID, sessionID); err != nil {
+	if err := h.service.BlockSession(c.UserContext(), userID, sessionID); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to block session", err)
 	}
 
 	return utils.SuccessResponse(c, fiber.StatusOK, nil, "Session blocked successfully")
 }
 
+// bearerToken returns the token of a "Bearer" Authorization header, or "" without one
+func bearerToken(c *fiber.Ctx) string {
+	scheme, token, ok := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return token
+}
+
 // getMetadata extracts session metadata from fiber.Ctx
 func (h *authHandler) getMetadata(c *fiber.Ctx) dto.SessionMetadata {
 	return dto.SessionMetadata{
 		IPAddress: c.IP(),
 		UserAgent: string(c.Request().Header.UserAgent()),
 		DeviceID:  c.Get("X-Device-ID"),
+		Locale:    i18n.FromContext(c.UserContext()),
 	}
 }
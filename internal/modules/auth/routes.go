@@ -1,12 +1,17 @@
 package auth
 
 import (
+	"go_boilerplate/internal/modules/audit"
 	"go_boilerplate/internal/modules/auth/dto"
 	"go_boilerplate/internal/modules/email"
 	"go_boilerplate/internal/modules/role"
 	"go_boilerplate/internal/modules/user"
+	"go_boilerplate/internal/shared/apiversion"
+	"go_boilerplate/internal/shared/cache"
 	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/events"
 	sharedmiddleware "go_boilerplate/internal/shared/middleware"
+	"go_boilerplate/internal/shared/session"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/redis/go-redis/v9"
@@ -14,35 +19,48 @@ import (
 	"gorm.io/gorm"
 )
 
+// Auth route paths; LoginPath and RefreshPath are the full paths main.go keeps out of load shedding
+const (
+	apiVersion   = "v1"
+	groupPath    = "/auth"
+	loginRoute   = "/login"
+	refreshRoute = "/refresh"
+
+	LoginPath   = apiversion.Prefix + apiVersion + groupPath + loginRoute
+	RefreshPath = apiversion.Prefix + apiVersion + groupPath + refreshRoute
+)
+
 // RegisterRoutes registers all auth-related routes
-func RegisterRoutes(app *fiber.App, db *gorm.DB, cfg *config.Config, logger *logrus.Logger, redisClient *redis.Client) {
+func RegisterRoutes(app *fiber.App, db *gorm.DB, cfg *config.Config, logger *logrus.Logger, redisClient *redis.Client, store cache.Cache, sessionStore session.Store, bus events.EventBus) {
 	// Initialize repositories
 	userRepo := user.NewUserRepository(db)
 	roleRepo := role.NewRoleRepository(db)
 
+	// Changes made through the services are recorded in the audit trail
+	trail := audit.NewTrail(db, cfg.Audit, logger)
+
 	// Initialize user service with role repository
-	userService := user.NewUserServiceWithRole(userRepo, roleRepo)
+	userService := user.NewUserServiceWithRole(userRepo, roleRepo, nil, user.NewProfileCache(store, cfg.RBAC.ProfileCacheTTL), cache.NewResponseCache(redisClient, cfg.Cache), bus, trail, cfg.RBAC.DefaultRoleSlug)
 
-	// Initialize email service (optional, will check before sending)
-	var emailService email.EmailService
-	if cfg.Email.Enabled {
-		emailService = email.NewEmailService(cfg, logger)
-	}
+	// Initialize email service; emails are queued for the send-queued-email job, and dropped while
+	// email is disabled
+	emailService := email.NewQueuedEmailService(cfg, db, logger)
 
 	// Initialize auth service
-	authService := NewAuthService(userService, db, cfg, emailService, redisClient)
+	authService := NewAuthService(userService, db, cfg, emailService, redisClient, sessionStore, trail)
 
 	// Initialize auth handler
 	authHandler := NewAuthHandler(authService)
 
 	// Create API route group
-	api := app.Group("/api/v1")
+	api := apiversion.Group(app, apiVersion)
 
 	// Public auth routes
-	auth := api.Group("/auth")
+	auth := api.Group(groupPath)
+	auth.Use(sharedmiddleware.BodyLimit(cfg.Server.PublicBodyLimit))
 	auth.Post("/register", sharedmiddleware.BodyValidator(&dto.RegisterRequest{}), authHandler.Register)
-	auth.Post("/login", sharedmiddleware.BodyValidator(&dto.LoginRequest{}), authHandler.Login)
-	auth.Post("/refresh", sharedmiddleware.BodyValidator(&dto.RefreshTokenRequest{}), authHandler.RefreshToken)
+	auth.Post(loginRoute, sharedmiddleware.BodyValidator(&dto.LoginRequest{}), authHandler.Login)
+	auth.Post(refreshRoute, sharedmiddleware.BodyValidator(&dto.RefreshTokenRequest{}), authHandler.RefreshToken)
 	auth.Post("/logout", sharedmiddleware.BodyValidator(&dto.RefreshTokenRequest{}), authHandler.Logout)
 
 	// Add new verification endpoints
@@ -3,13 +3,20 @@ package auth
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
+	"go_boilerplate/internal/modules/audit"
 	"go_boilerplate/internal/modules/auth/dto"
 	"go_boilerplate/internal/modules/email"
 	"go_boilerplate/internal/modules/user"
 	userdto "go_boilerplate/internal/modules/user/dto"
+	"go_boilerplate/internal/shared/apperror"
 	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/database/replica"
+	"go_boilerplate/internal/shared/observability"
+	"go_boilerplate/internal/shared/session"
+	"go_boilerplate/internal/shared/tenant"
 	"go_boilerplate/internal/shared/utils"
 
 	"github.com/google/uuid"
@@ -19,19 +26,32 @@ import (
 
 // AuthService defines the interface for authentication business logic
 type AuthService interface {
-	Register(req *dto.RegisterRequest, metadata dto.SessionMetadata) (*dto.AuthResponse, error)
-	Login(req *dto.LoginRequest, metadata dto.SessionMetadata) (*dto.AuthResponse, error)
-	RefreshToken(refreshToken string, metadata dto.SessionMetadata) (*dto.AuthResponse, error)
-	Logout(refreshToken string) error
-	VerifyEmail(req *dto.VerifyEmailRequest) error
-	Verify2FA(req *dto.Verify2FARequest, metadata dto.SessionMetadata) (*dto.AuthResponse, error)
-	ResendVerification(email string) error
-	Resend2FA(email string) error
-	GetSessions(userID uuid.UUID) ([]dto.Session, error)
-	DeleteSession(userID uuid.UUID, sessionID uuid.UUID) error
-	BlockSession(userID uuid.UUID, sessionID uuid.UUID) error
+	Register(ctx context.Context, req *dto.RegisterRequest, metadata dto.SessionMetadata) (*dto.AuthResponse, error)
+	Login(ctx context.Context, req *dto.LoginRequest, metadata dto.SessionMetadata) (*dto.AuthResponse, error)
+	RefreshToken(ctx context.Context, refreshToken string, metadata dto.SessionMetadata) (*dto.AuthResponse, error)
+	Logout(ctx context.Context, refreshToken, accessToken string) error
+	VerifyEmail(ctx context.Context, req *dto.VerifyEmailRequest) error
+	Verify2FA(ctx context.Context, req *dto.Verify2FARequest, metadata dto.SessionMetadata) (*dto.AuthResponse, error)
+	ResendVerification(ctx context.Context, email, locale string) error
+	Resend2FA(ctx context.Context, email, locale string) error
+	GetSessions(ctx context.Context, userID uuid.UUID) ([]dto.Session, error)
+	DeleteSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error
+	BlockSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error
 }
 
+// Errors returned by AuthService
+var (
+	ErrAccountNotVerified        = apperror.New(apperror.ErrForbidden, "account not verified. please verify your email").WithCode("account_not_verified")
+	ErrAccountAlreadyVerified    = apperror.New(apperror.ErrConflict, "account already verified").WithCode("account_already_verified")
+	ErrInvalidActivationCode     = apperror.New(apperror.ErrBadRequest, "invalid or expired activation code").WithCode("invalid_activation_code")
+	ErrInvalidOTP                = apperror.New(apperror.ErrUnauthorized, "invalid or expired OTP").WithCode("invalid_otp")
+	ErrInvalidRefreshToken       = apperror.New(apperror.ErrUnauthorized, "invalid or expired refresh token").WithCode("invalid_refresh_token")
+	ErrSessionInvalid            = apperror.New(apperror.ErrUnauthorized, "session not found, expired, or blocked").WithCode("session_invalid")
+	ErrEmailVerificationDisabled = apperror.New(apperror.ErrBadRequest, "email verification is not enabled").WithCode("email_verification_disabled")
+	ErrTwoFactorDisabled         = apperror.New(apperror.ErrBadRequest, "2FA is not enabled").WithCode("two_factor_disabled")
+	ErrTenantMismatch            = apperror.New(apperror.ErrForbidden, "refresh token was issued for another tenant").WithCode("tenant_mismatch")
+)
+
 // authService implements AuthService interface
 type authService struct {
 	userService  user.UserService
@@ -40,6 +60,10 @@ type authService struct {
 	cfg          *config.Config
 	emailService email.EmailService
 	redis        *redis.Client
+	metrics      *observability.Counter
+	sessions     session.Store
+	denylist     *session.Denylist
+	trail        *audit.Trail // Records logins, logouts, verifications and session changes (nil records nothing)
 }
 
 // NewAuthService creates a new auth service
@@ -49,6 +73,8 @@ func NewAuthService(
 	cfg *config.Config,
 	emailService email.EmailService,
 	redis *redis.Client,
+	sessions session.Store,
+	trail *audit.Trail,
 ) AuthService {
 	jwtManager := utils.NewJWTManager(
 		cfg.JWT.Secret,
@@ -64,23 +90,35 @@ func NewAuthService(
 		cfg:          cfg,
 		emailService: emailService,
 		redis:        redis,
+		metrics:      observability.NewCounter(redis),
+		sessions:     sessions,
+		denylist:     session.NewDenylist(redis),
+		trail:        trail,
 	}
 }
 
 // Register registers a new user
-func (s *authService) Register(req *dto.RegisterRequest, metadata dto.SessionMetadata) (*dto.AuthResponse, error) {
+func (s *authService) Register(ctx context.Context, req *dto.RegisterRequest, metadata dto.SessionMetadata) (*dto.AuthResponse, error) {
+	ctx, span := observability.StartSpan(ctx, "auth.Register")
+	defer span.End()
+
 	// Create user request
 	createUserReq := &userdto.CreateUserRequest{
 		Name:     req.Name,
 		Email:    req.Email,
+		Username: req.Username,
 		Password: req.Password,
+		// Without email verification the user is created verified, in the same transaction
+		Verified: !s.cfg.Security.EmailVerificationEnabled,
 	}
 
 	// Create user (with default role assigned)
-	createdUser, err := s.userService.CreateUser(createUserReq)
+	createdUser, err := s.userService.CreateUser(ctx, createUserReq)
 	if err != nil {
 		return nil, err
 	}
+	s.metrics.Incr(context.WithoutCancel(ctx), observability.MetricRegistrations)
+	observability.RecordRegistration(observability.AuthMethodPassword)
 
 	// Check if email verification is enabled
 	if s.cfg.Security.EmailVerificationEnabled {
@@ -88,50 +126,57 @@ func (s *authService) Register(req *dto.RegisterRequest, metadata dto.SessionMet
 		code := utils.RandomIntString(6)
 		// Save 6-digit code to Redis with 10m expiry
 		key := "activation:" + req.Email
-		if err := s.redis.Set(context.Background(), key, code, 10*time.Minute).Err(); err != nil {
-			return nil, errors.New("failed to save verification code")
+		if err := s.redis.Set(ctx, key, code, 10*time.Minute).Err(); err != nil {
+			return nil, apperror.Wrap(apperror.ErrInternal, "failed to save verification code", err)
 		}
 
-		// Send email asynchronously
-		go func() {
-			if s.emailService != nil {
-				s.emailService.SendVerificationEmail(req.Email, code)
-			}
-		}()
+		// Queue the email; the send-queued-email job delivers it
+		if err := s.emailService.SendVerificationEmail(req.Email, code, metadata.Locale); err != nil {
+			return nil, apperror.Wrap(apperror.ErrInternal, "failed to send verification code", err)
+		}
 
 		return &dto.AuthResponse{
 			Message: "Registration successful. Please check your email to activate your account.",
 		}, nil
 	}
 
-	// If verification disabled, set verified = true immediately (if not already default)
-	if !s.cfg.Security.EmailVerificationEnabled {
-		s.db.Model(&user.User{}).Where("id = ?", createdUser.ID).Update("is_verified", true)
-	}
-
-	return s.generateAuthResponse(createdUser.ID, metadata)
+	// The new user isn't on the read replicas yet
+	return s.generateAuthResponse(replica.WithPrimary(ctx), createdUser.ID, metadata)
 }
 
 // Login authenticates a user
-func (s *authService) Login(req *dto.LoginRequest, metadata dto.SessionMetadata) (*dto.AuthResponse, error) {
+func (s *authService) Login(ctx context.Context, req *dto.LoginRequest, metadata dto.SessionMetadata) (*dto.AuthResponse, error) {
+	ctx, span := observability.StartSpan(ctx, "auth.Login")
+	defer span.End()
+
+	// Either email or username identifies the account
+	login := req.Email
+	if login == "" {
+		login = req.Username
+	}
+
 	// Validate password
-	authenticatedUser, err := s.userService.ValidatePassword(req.Email, req.Password)
+	authenticatedUser, err := s.userService.ValidatePassword(ctx, login, req.Password)
 	if err != nil {
-		return nil, errors.New("invalid email or password")
+		if errors.Is(err, user.ErrInvalidCredentials) {
+			s.metrics.Incr(context.WithoutCancel(ctx), observability.MetricLoginFailures)
+			observability.RecordLogin(observability.AuthMethodPassword, observability.LoginFailed)
+		}
+		return nil, err
 	}
 
 	// Check verification status (skip for SuperAdmin)
 	// Get full profile to check role
-	userWithRole, err := s.userService.GetProfileWithRole(authenticatedUser.ID)
+	userWithRole, err := s.userService.GetProfileWithRole(ctx, authenticatedUser.ID)
 	if err != nil {
-		return nil, errors.New("failed to load user profile")
+		return nil, apperror.Wrap(apperror.ErrInternal, "failed to load user profile", err)
 	}
 
-	isSuperAdmin := userWithRole.Role != nil && userWithRole.Role.Slug == "super_admin"
+	isSuperAdmin := userWithRole.HasRole("super_admin")
 
 	// If verification enabled and user not verified, deny login (unless SuperAdmin)
 	if s.cfg.Security.EmailVerificationEnabled && !userWithRole.IsVerified && !isSuperAdmin {
-		return nil, errors.New("account not verified. please verify your email")
+		return nil, ErrAccountNotVerified
 	}
 
 	// Check Two-Factor Authentication
@@ -139,17 +184,15 @@ func (s *authService) Login(req *dto.LoginRequest, metadata dto.SessionMetadata)
 	if s.cfg.Security.TwoFactorEnabled && !isSuperAdmin {
 		// Generate 2FA code
 		code := utils.RandomIntString(6)
-		key := "2fa:" + req.Email
-		if err := s.redis.Set(context.Background(), key, code, 5*time.Minute).Err(); err != nil {
-			return nil, errors.New("failed to generate 2fa code")
+		key := "2fa:" + authenticatedUser.Email
+		if err := s.redis.Set(ctx, key, code, 5*time.Minute).Err(); err != nil {
+			return nil, apperror.Wrap(apperror.ErrInternal, "failed to generate 2fa code", err)
 		}
 
-		// Send Email
-		go func() {
-			if s.emailService != nil {
-				s.emailService.SendTwoFactorEmail(req.Email, code)
-			}
-		}()
+		// Queue the email
+		if err := s.emailService.SendTwoFactorEmail(authenticatedUser.Email, code, metadata.Locale); err != nil {
+			return nil, apperror.Wrap(apperror.ErrInternal, "failed to send 2fa code", err)
+		}
 
 		return &dto.AuthResponse{
 			User:        userWithRole,
@@ -159,138 +202,166 @@ func (s *authService) Login(req *dto.LoginRequest, metadata dto.SessionMetadata)
 	}
 
 	// Normal Login
-	return s.generateAuthResponse(authenticatedUser.ID, metadata)
+	return s.loginResponse(ctx, authenticatedUser.ID, metadata)
 }
 
 // VerifyEmail verifies user email
-func (s *authService) VerifyEmail(req *dto.VerifyEmailRequest) error {
+func (s *authService) VerifyEmail(ctx context.Context, req *dto.VerifyEmailRequest) error {
+	ctx, span := observability.StartSpan(ctx, "auth.VerifyEmail")
+	defer span.End()
+
+	if s.redis == nil {
+		return ErrInvalidActivationCode
+	}
+
 	key := "activation:" + req.Email
-	storedCode, err := s.redis.Get(context.Background(), key).Result()
+	storedCode, err := s.redis.Get(ctx, key).Result()
 	if err != nil || storedCode != req.Code {
-		return errors.New("invalid or expired activation code")
+		return ErrInvalidActivationCode
 	}
 
 	// Update user status
-	if err := s.db.Model(&user.User{}).Where("email = ?", req.Email).Update("is_verified", true).Error; err != nil {
-		return errors.New("failed to verify user")
+	if err := s.db.WithContext(ctx).Model(&user.User{}).Where("email = ?", req.Email).Update("is_verified", true).Error; err != nil {
+		return apperror.Wrap(apperror.ErrInternal, "failed to verify user", err)
+	}
+	// A login attempt may have cached the unverified profile
+	if account, err := s.userService.GetByEmail(replica.WithPrimary(ctx), req.Email); err == nil {
+		s.userService.InvalidateProfile(ctx, account.ID)
+		s.trail.Record(ctx, account.ID.String(), "user.verified", userResource(account.ID),
+			map[string]bool{"is_verified": false}, map[string]bool{"is_verified": true})
 	}
 
 	// Delete code
-	s.redis.Del(context.Background(), key)
+	s.redis.Del(ctx, key)
 	return nil
 }
 
 // Verify2FA verifies login OTP
-func (s *authService) Verify2FA(req *dto.Verify2FARequest, metadata dto.SessionMetadata) (*dto.AuthResponse, error) {
+func (s *authService) Verify2FA(ctx context.Context, req *dto.Verify2FARequest, metadata dto.SessionMetadata) (*dto.AuthResponse, error) {
+	ctx, span := observability.StartSpan(ctx, "auth.Verify2FA")
+	defer span.End()
+
+	if s.redis == nil {
+		return nil, ErrInvalidOTP
+	}
+
 	key := "2fa:" + req.Email
-	storedCode, err := s.redis.Get(context.Background(), key).Result()
+	storedCode, err := s.redis.Get(ctx, key).Result()
 	if err != nil || storedCode != req.Code {
-		return nil, errors.New("invalid or expired OTP")
+		s.metrics.Incr(context.WithoutCancel(ctx), observability.MetricLoginFailures)
+		observability.RecordLogin(observability.AuthMethodPassword, observability.LoginFailed)
+		return nil, ErrInvalidOTP
 	}
 
 	// Get User
-	foundUser, err := s.userService.GetByEmail(req.Email)
+	foundUser, err := s.userService.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, err
 	}
 
 	// Delete code
-	s.redis.Del(context.Background(), key)
+	s.redis.Del(ctx, key)
 
-	return s.generateAuthResponse(foundUser.ID, metadata)
+	return s.loginResponse(ctx, foundUser.ID, metadata)
 }
 
 // ResendVerification resends the activation code
-func (s *authService) ResendVerification(email string) error {
+func (s *authService) ResendVerification(ctx context.Context, email, locale string) error {
 	if !s.cfg.Security.EmailVerificationEnabled {
-		return errors.New("email verification is not enabled")
+		return ErrEmailVerificationDisabled
 	}
 
 	// Check if user exists and is not verified
-	user, err := s.userService.GetByEmail(email)
+	account, err := s.userService.GetByEmail(ctx, email)
 	if err != nil {
-		return errors.New("user not found")
+		return err
 	}
 
-	if user.IsVerified {
-		return errors.New("account already verified")
+	if account.IsVerified {
+		return ErrAccountAlreadyVerified
 	}
 
 	// Generate and send code
 	code := utils.RandomIntString(6)
 	key := "activation:" + email
-	if err := s.redis.Set(context.Background(), key, code, 10*time.Minute).Err(); err != nil {
-		return errors.New("failed to resend verification code")
+	if err := s.redis.Set(ctx, key, code, 10*time.Minute).Err(); err != nil {
+		return apperror.Wrap(apperror.ErrInternal, "failed to resend verification code", err)
 	}
 
-	go func() {
-		if s.emailService != nil {
-			s.emailService.SendVerificationEmail(email, code)
-		}
-	}()
+	if err := s.emailService.SendVerificationEmail(email, code, locale); err != nil {
+		return apperror.Wrap(apperror.ErrInternal, "failed to resend verification code", err)
+	}
 
 	return nil
 }
 
 // Resend2FA resends the 2FA code
-func (s *authService) Resend2FA(email string) error {
+func (s *authService) Resend2FA(ctx context.Context, email, locale string) error {
 	if !s.cfg.Security.TwoFactorEnabled {
-		return errors.New("2FA is not enabled")
+		return ErrTwoFactorDisabled
 	}
 
 	// Check if user exists
-	_, err := s.userService.GetByEmail(email)
-	if err != nil {
-		return errors.New("user not found")
+	if _, err := s.userService.GetByEmail(ctx, email); err != nil {
+		return err
 	}
 
 	// Generate and send code
 	code := utils.RandomIntString(6)
 	key := "2fa:" + email
-	if err := s.redis.Set(context.Background(), key, code, 5*time.Minute).Err(); err != nil {
-		return errors.New("failed to resend 2FA code")
+	if err := s.redis.Set(ctx, key, code, 5*time.Minute).Err(); err != nil {
+		return apperror.Wrap(apperror.ErrInternal, "failed to resend 2FA code", err)
 	}
 
-	go func() {
-		if s.emailService != nil {
-			s.emailService.SendTwoFactorEmail(email, code)
-		}
-	}()
+	if err := s.emailService.SendTwoFactorEmail(email, code, locale); err != nil {
+		return apperror.Wrap(apperror.ErrInternal, "failed to resend 2FA code", err)
+	}
 
 	return nil
 }
 
+// loginResponse issues the tokens of a completed login and counts it
+func (s *authService) loginResponse(ctx context.Context, userID uuid.UUID, metadata dto.SessionMetadata) (*dto.AuthResponse, error) {
+	response, err := s.generateAuthResponse(ctx, userID, metadata)
+	if err == nil {
+		observability.RecordLogin(observability.AuthMethodPassword, observability.LoginSucceeded)
+		s.trail.Record(ctx, userID.String(), "user.logged_in", userResource(userID), nil, map[string]string{
+			"ip_address": metadata.IPAddress,
+			"user_agent": metadata.UserAgent,
+			"device_id":  metadata.DeviceID,
+		})
+	}
+	return response, err
+}
+
 // generateAuthResponse helps to dry up token generation logic
-func (s *authService) generateAuthResponse(userID uuid.UUID, metadata dto.SessionMetadata) (*dto.AuthResponse, error) {
+func (s *authService) generateAuthResponse(ctx context.Context, userID uuid.UUID, metadata dto.SessionMetadata) (*dto.AuthResponse, error) {
 	// Load user with role information
-	userWithRole, err := s.userService.GetProfileWithRole(userID)
+	userWithRole, err := s.userService.GetProfileWithRole(ctx, userID)
 	if err != nil {
-		return nil, errors.New("failed to load user role")
+		return nil, apperror.Wrap(apperror.ErrInternal, "failed to load user role", err)
 	}
 
 	// Generate tokens with role information
-	roleSlug := ""
-	permissions := []string{}
-	if userWithRole.Role != nil {
-		roleSlug = userWithRole.Role.Slug
-		permissions = userWithRole.Role.Permissions
-	}
-
 	accessToken, refreshToken, err := s.jwtManager.GenerateTokenPair(
 		userID,
 		userWithRole.Email,
-		roleSlug,
-		permissions,
+		userWithRole.RoleSlugs(),
+		userWithRole.Permissions(),
+		tenant.ClaimFromContext(ctx),
 	)
 	if err != nil {
-		return nil, errors.New("failed to generate tokens")
+		return nil, apperror.Wrap(apperror.ErrInternal, "failed to generate tokens", err)
 	}
 
 	// Save session to database
-	if err := s.saveSession(userID, refreshToken, metadata); err != nil {
+	if err := s.saveSession(ctx, userID, refreshToken, metadata); err != nil {
 		return nil, err
 	}
 
+	// Record the login (best-effort, must not fail authentication)
+	_ = s.userService.RecordLogin(ctx, userID)
+
 	// Calculate expires in
 	expiresIn := int64(s.cfg.JWT.AccessExpiry.Seconds())
 
@@ -302,52 +373,55 @@ func (s *authService) generateAuthResponse(userID uuid.UUID, metadata dto.Sessio
 	}, nil
 }
 
-
 // RefreshToken refreshes an access token using a refresh token
-func (s *authService) RefreshToken(refreshToken string, metadata dto.SessionMetadata) (*dto.AuthResponse, error) {
+func (s *authService) RefreshToken(ctx context.Context, refreshToken string, metadata dto.SessionMetadata) (*dto.AuthResponse, error) {
+	ctx, span := observability.StartSpan(ctx, "auth.RefreshToken")
+	defer span.End()
+
 	// Validate refresh token
 	claims, err := s.jwtManager.ValidateToken(refreshToken)
 	if err != nil {
-		return nil, errors.New("invalid or expired refresh token")
+		return nil, ErrInvalidRefreshToken
 	}
 
-	// Check if session exists in database
-	var storedSession dto.Session
-	if err := s.db.Where("token = ? AND expires_at > ? AND is_blocked = ?", refreshToken, time.Now(), false).First(&storedSession).Error; err != nil {
-		return nil, errors.New("session not found, expired, or blocked")
+	// A refresh token is only good in the tenant it was issued for
+	if !tenant.MatchesClaim(ctx, claims.TenantID) {
+		return nil, ErrTenantMismatch
+	}
+
+	// Check if the session exists and is still usable
+	if _, err := s.sessions.FindActive(ctx, refreshToken); errors.Is(err, session.ErrNotFound) {
+		return nil, ErrSessionInvalid.WithCause(err)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to find session: %w", err)
 	}
 
 	// Get user profile with role
-	userProfile, err := s.userService.GetProfileWithRole(claims.UserID)
+	userProfile, err := s.userService.GetProfileWithRole(ctx, claims.UserID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, err
 	}
 
 	// Generate new tokens with role information
-	roleSlug := ""
-	permissions := []string{}
-	if userProfile.Role != nil {
-		roleSlug = userProfile.Role.Slug
-		permissions = userProfile.Role.Permissions
-	}
-
 	newAccessToken, newRefreshToken, err := s.jwtManager.GenerateTokenPair(
 		claims.UserID,
 		claims.Email,
-		roleSlug,
-		permissions,
+		userProfile.RoleSlugs(),
+		userProfile.Permissions(),
+		tenant.ClaimFromContext(ctx),
 	)
 	if err != nil {
-		return nil, errors.New("failed to generate new tokens")
+		return nil, apperror.Wrap(apperror.ErrInternal, "failed to generate new tokens", err)
 	}
 
 	// Delete old session
-	s.db.Delete(&storedSession)
+	_ = s.sessions.Delete(ctx, refreshToken)
 
 	// Save new session
-	if err := s.saveSession(claims.UserID, newRefreshToken, metadata); err != nil {
+	if err := s.saveSession(ctx, claims.UserID, newRefreshToken, metadata); err != nil {
 		return nil, err
 	}
+	s.metrics.Incr(context.WithoutCancel(ctx), observability.MetricTokenRefreshes)
 
 	// Calculate expires in
 	expiresIn := int64(s.cfg.JWT.AccessExpiry.Seconds())
@@ -361,30 +435,44 @@ func (s *authService) RefreshToken(refreshToken string, metadata dto.SessionMeta
 }
 
 // Logout logs out a user by deleting their refresh token
-func (s *authService) Logout(refreshToken string) error {
-	// Delete session from database
-	if err := s.db.Where("token = ?", refreshToken).Delete(&dto.Session{}).Error; err != nil {
+// The access token sent along, if any and still valid, is denied for the rest of its lifetime
+func (s *authService) Logout(ctx context.Context, refreshToken, accessToken string) error {
+	ctx, span := observability.StartSpan(ctx, "auth.Logout")
+	defer span.End()
+
+	// Delete session from the store
+	if err := s.sessions.Delete(ctx, refreshToken); err != nil {
 		return err
 	}
+	if claims, err := s.jwtManager.ValidateToken(refreshToken); err == nil {
+		s.trail.Record(ctx, claims.UserID.String(), "user.logged_out", userResource(claims.UserID), nil, nil)
+	}
 
-	return nil
+	if accessToken == "" {
+		return nil
+	}
+	claims, err := s.jwtManager.ValidateToken(accessToken)
+	if err != nil || claims.ExpiresAt == nil {
+		return nil
+	}
+	return s.denylist.Deny(ctx, accessToken, claims.ExpiresAt.Time)
 }
 
-// saveSession saves a session to the database
-func (s *authService) saveSession(userID uuid.UUID, token string, metadata dto.SessionMetadata) error {
+// saveSession saves a session to the session store
+func (s *authService) saveSession(ctx context.Context, userID uuid.UUID, token string, metadata dto.SessionMetadata) error {
 	expiresAt := time.Now().Add(s.cfg.JWT.RefreshExpiry)
 
 	session := &dto.Session{
-		UserID:    userID,
-		Token:     token,
-		IPAddress: metadata.IPAddress,
-		UserAgent: metadata.UserAgent,
-		DeviceID:  metadata.DeviceID,
-		ExpiresAt: expiresAt,
+		UserID:     userID,
+		Token:      token,
+		IPAddress:  metadata.IPAddress,
+		UserAgent:  metadata.UserAgent,
+		DeviceID:   metadata.DeviceID,
+		ExpiresAt:  expiresAt,
 		LastActive: time.Now(),
 	}
 
-	if err := s.db.Create(session).Error; err != nil {
+	if err := s.sessions.Create(ctx, session); err != nil {
 		return err
 	}
 
@@ -392,26 +480,39 @@ func (s *authService) saveSession(userID uuid.UUID, token string, metadata dto.S
 }
 
 // GetSessions returns all active sessions for a user
-func (s *authService) GetSessions(userID uuid.UUID) ([]dto.Session, error) {
-	var sessions []dto.Session
-	if err := s.db.Where("user_id = ?", userID).Order("last_active desc").Find(&sessions).Error; err != nil {
+func (s *authService) GetSessions(ctx context.Context, userID uuid.UUID) ([]dto.Session, error) {
+	sessions, err := s.sessions.ListByUser(ctx, userID)
+	if err != nil {
 		return nil, err
 	}
 	return sessions, nil
 }
 
 // DeleteSession deletes a specific session
-func (s *authService) DeleteSession(userID uuid.UUID, sessionID uuid.UUID) error {
-	if err := s.db.Where("id = ? AND user_id = ?", sessionID, userID).Delete(&dto.Session{}).Error; err != nil {
+func (s *authService) DeleteSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error {
+	if err := s.sessions.DeleteForUser(ctx, userID, sessionID); err != nil {
 		return err
 	}
+	s.recordSession(ctx, "session.deleted", userID, sessionID)
 	return nil
 }
 
 // BlockSession blocks a specific session
-func (s *authService) BlockSession(userID uuid.UUID, sessionID uuid.UUID) error {
-	if err := s.db.Model(&dto.Session{}).Where("id = ? AND user_id = ?", sessionID, userID).Update("is_blocked", true).Error; err != nil {
+func (s *authService) BlockSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error {
+	if err := s.sessions.BlockForUser(ctx, userID, sessionID); err != nil {
 		return err
 	}
+	s.recordSession(ctx, "session.blocked", userID, sessionID)
 	return nil
 }
+
+// recordSession adds a change of one of userID's sessions, made by the authenticated user, to the audit trail
+func (s *authService) recordSession(ctx context.Context, action string, userID, sessionID uuid.UUID) {
+	s.trail.Record(ctx, audit.ActorFromContext(ctx), action, audit.Resource{Type: "session", ID: sessionID.String()},
+		nil, map[string]uuid.UUID{"user_id": userID})
+}
+
+// userResource identifies a user in the audit trail
+func userResource(userID uuid.UUID) audit.Resource {
+	return audit.Resource{Type: "user", ID: userID.String()}
+}
@@ -0,0 +1,133 @@
+package email
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"go_boilerplate/internal/modules/email/dto"
+)
+
+// maxAttachmentsSize bounds the attachments of one email; it is Postmark's limit, the lowest of
+// the providers (SendGrid 30MB, Mailgun 25MB, SES 40MB)
+const maxAttachmentsSize = 10 << 20
+
+// ErrAttachmentsTooLarge is returned when the attachments of an email exceed maxAttachmentsSize
+var ErrAttachmentsTooLarge = fmt.Errorf("email attachments exceed %d MB", maxAttachmentsSize>>20)
+
+// Attachment is a file to attach to an email. One with a ContentID is an inline image, shown where
+// the HTML body references it as <img src="cid:ContentID">.
+type Attachment struct {
+	Filename    string
+	ContentType string // Detected from the file name, then the content, when empty
+	ContentID   string
+	Reader      io.Reader // Read in full when the email is sent or queued
+}
+
+// File is a loaded attachment, as senders deliver it and the queue stores it
+type File struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	ContentID   string `json:"content_id,omitempty"`
+	Content     []byte `json:"content"`
+}
+
+// Inline reports whether the file is an inline image referenced by its ContentID
+func (f File) Inline() bool {
+	return f.ContentID != ""
+}
+
+// Files are the attachments of a queued email, stored as JSONB
+type Files []File
+
+// Value implements the driver.Valuer interface for database storage
+func (f Files) Value() (driver.Value, error) {
+	if f == nil {
+		return "[]", nil
+	}
+	return json.Marshal(f)
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (f *Files) Scan(value interface{}) error {
+	if value == nil {
+		*f = nil
+		return nil
+	}
+
+	data, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(data, f)
+}
+
+// loadAttachments reads the attachments, so the email can be queued and retried
+func loadAttachments(attachments []Attachment) (Files, error) {
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+
+	files := make(Files, 0, len(attachments))
+	remaining := int64(maxAttachmentsSize)
+	for _, attachment := range attachments {
+		if attachment.Filename == "" {
+			return nil, errors.New("email attachment has no filename")
+		}
+		if attachment.Reader == nil {
+			return nil, fmt.Errorf("email attachment %s has no content", attachment.Filename)
+		}
+
+		content, err := io.ReadAll(io.LimitReader(attachment.Reader, remaining+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read email attachment %s: %w", attachment.Filename, err)
+		}
+		remaining -= int64(len(content))
+		if remaining < 0 {
+			return nil, ErrAttachmentsTooLarge
+		}
+
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = mime.TypeByExtension(filepath.Ext(attachment.Filename))
+		}
+		if contentType == "" {
+			contentType = http.DetectContentType(content)
+		}
+
+		files = append(files, File{
+			Filename:    filepath.Base(attachment.Filename),
+			ContentType: contentType,
+			ContentID:   strings.Trim(attachment.ContentID, "<>"),
+			Content:     content,
+		})
+	}
+	return files, nil
+}
+
+// AttachmentsFromRequest converts the base64 attachments of a SendEmailRequest
+func AttachmentsFromRequest(requests []dto.AttachmentRequest) ([]Attachment, error) {
+	attachments := make([]Attachment, len(requests))
+	for i, req := range requests {
+		content, err := base64.StdEncoding.DecodeString(req.Content)
+		if err != nil {
+			return nil, fmt.Errorf("attachment %s: %w", req.Filename, err)
+		}
+		attachments[i] = Attachment{
+			Filename:    req.Filename,
+			ContentType: req.ContentType,
+			ContentID:   req.ContentID,
+			Reader:      bytes.NewReader(content),
+		}
+	}
+	return attachments, nil
+}
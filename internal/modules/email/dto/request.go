@@ -2,9 +2,19 @@ package dto
 
 // SendEmailRequest represents an email sending request
 type SendEmailRequest struct {
-	To      string `json:"to" validate:"required,email"`
-	Subject string `json:"subject" validate:"required"`
-	Body    string `json:"body" validate:"required"`
+	To          string              `json:"to" validate:"required,email"`
+	Subject     string              `json:"subject" validate:"required"`
+	Body        string              `json:"body" validate:"required"`
+	Attachments []AttachmentRequest `json:"attachments" validate:"omitempty,max=10,dive"`
+}
+
+// AttachmentRequest represents a file attached to an email; a content ID makes it an inline image
+// the body references as <img src="cid:...">
+type AttachmentRequest struct {
+	Filename    string `json:"filename" validate:"required,max=255"`
+	ContentType string `json:"content_type" validate:"omitempty,max=255"` // Detected when empty
+	ContentID   string `json:"content_id" validate:"omitempty,max=255"`
+	Content     string `json:"content" validate:"required,base64"` // Base64-encoded file
 }
 
 // SendWelcomeEmailRequest represents a welcome email request
@@ -15,7 +25,7 @@ type SendWelcomeEmailRequest struct {
 
 // SendPasswordResetRequest represents a password reset email request
 type SendPasswordResetRequest struct {
-	To           string `json:"to" validate:"required,email"`
-	ResetToken   string `json:"reset_token" validate:"required"`
-	ResetLink    string `json:"reset_link" validate:"required"`
+	To         string `json:"to" validate:"required,email"`
+	ResetToken string `json:"reset_token" validate:"required"`
+	ResetLink  string `json:"reset_link" validate:"required"`
 }
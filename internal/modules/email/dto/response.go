@@ -1,6 +1,12 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/google/uuid"
+)
 
 // EmailResponse represents an email response
 type EmailResponse struct {
@@ -9,3 +15,45 @@ type EmailResponse struct {
 	To      string    `json:"to"`
 	Subject string    `json:"subject"`
 }
+
+// EmailMessageResponse represents an outgoing email in the delivery log
+type EmailMessageResponse struct {
+	ID                uuid.UUID            `json:"id"`
+	Recipient         string               `json:"recipient"`
+	Subject           string               `json:"subject"`
+	Template          string               `json:"template,omitempty"`
+	Attachments       []AttachmentResponse `json:"attachments,omitempty"` // Only returned for a single email
+	Status            string               `json:"status"`
+	Attempts          int                  `json:"attempts"`
+	LastError         string               `json:"last_error,omitempty"`
+	Provider          string               `json:"provider,omitempty"`
+	ProviderMessageID string               `json:"provider_message_id,omitempty"`
+	NextAttemptAt     time.Time            `json:"next_attempt_at"`
+	SentAt            *time.Time           `json:"sent_at,omitempty"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+}
+
+// AttachmentResponse describes an attachment of an outgoing email
+type AttachmentResponse struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	ContentID   string `json:"content_id,omitempty"` // Set for inline images
+	Size        int    `json:"size"`                 // Bytes
+}
+
+// EmailMessagesResponse represents a paginated list of outgoing emails
+type EmailMessagesResponse struct {
+	Messages []EmailMessageResponse `json:"messages"`
+	Meta     utils.PaginationMeta   `json:"meta"`
+}
+
+// PageItems returns the listed emails (utils.Paginated)
+func (r EmailMessagesResponse) PageItems() any {
+	return r.Messages
+}
+
+// PageMeta returns the pagination metadata (utils.Paginated)
+func (r EmailMessagesResponse) PageMeta() utils.PaginationMeta {
+	return r.Meta
+}
@@ -0,0 +1,62 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// unsafeFileChars are replaced in the recipient part of file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9@._-]+`)
+
+// FileSender writes each message to EMAIL_FILE_DIR as an HTML file instead of delivering it, so
+// rendered emails can be opened in a browser. The headers are kept in a comment at the top.
+type FileSender struct {
+	dir string
+}
+
+// NewFileSender creates a sender that writes messages to dir, created on first use
+func NewFileSender(dir string) *FileSender {
+	return &FileSender{dir: dir}
+}
+
+// Name implements Sender
+func (s *FileSender) Name() string {
+	return ProviderFile
+}
+
+// Send implements Sender and returns the file name as the message ID. Files are named
+// <UTC time>-<recipient>.html, so they sort by send time; they may hold one-time codes and are only
+// readable by the owner. Attachments are written next to it as <name>-<n>-<filename>, and the
+// body's cid: references point to them, so inline images show in the browser.
+func (s *FileSender) Send(_ context.Context, message Message) (string, error) {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create email directory: %w", err)
+	}
+
+	base := time.Now().UTC().Format("20060102T150405.000000000Z") + "-" + unsafeFileChars.ReplaceAllString(message.To, "_")
+	body := message.HTML
+	for i, file := range message.Attachments {
+		name := fmt.Sprintf("%s-%d-%s", base, i+1, unsafeFileChars.ReplaceAllString(file.Filename, "_"))
+		if err := os.WriteFile(filepath.Join(s.dir, name), file.Content, 0o600); err != nil {
+			return "", fmt.Errorf("failed to write email attachment: %w", err)
+		}
+		if file.Inline() {
+			body = strings.ReplaceAll(body, "cid:"+file.ContentID, name)
+		}
+	}
+
+	name := base + ".html"
+	// The comment can't be closed early by a header: escaping turns ">" into "&gt;"
+	content := fmt.Sprintf("<!--\nFrom: %s\nTo: %s\nSubject: %s\n-->\n%s\n",
+		html.EscapeString(message.From), html.EscapeString(message.To), html.EscapeString(message.Subject), body)
+	if err := os.WriteFile(filepath.Join(s.dir, name), []byte(content), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write email: %w", err)
+	}
+	return name, nil
+}
@@ -0,0 +1,135 @@
+package email
+
+import (
+	"go_boilerplate/internal/shared/filter"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// EmailMessageHandler defines the interface for the email delivery log HTTP handlers
+type EmailMessageHandler interface {
+	GetMessages(c *fiber.Ctx) error
+	GetMessage(c *fiber.Ctx) error
+	ResendMessage(c *fiber.Ctx) error
+}
+
+// emailMessageHandler implements EmailMessageHandler interface
+type emailMessageHandler struct {
+	service EmailMessageService
+}
+
+// emailMessageFilters are the fields accepted by ?filter[field][op]= on the delivery log
+var emailMessageFilters = filter.Fields{
+	"recipient":           {Column: "recipient", Type: filter.String},
+	"subject":             {Column: "subject", Type: filter.String},
+	"template":            {Column: "template", Type: filter.String},
+	"status":              {Column: "status", Type: filter.String},
+	"provider":            {Column: "provider", Type: filter.String},
+	"provider_message_id": {Column: "provider_message_id", Type: filter.String},
+	"attempts":            {Column: "attempts", Type: filter.Int},
+	"created_at":          {Column: "created_at", Type: filter.Time},
+	"sent_at":             {Column: "sent_at", Type: filter.Time},
+}
+
+// NewEmailMessageHandler creates a new email delivery log handler
+func NewEmailMessageHandler(service EmailMessageService) EmailMessageHandler {
+	return &emailMessageHandler{service: service}
+}
+
+// GetMessages gets outgoing emails with pagination
+// @Summary Admin: List emails
+// @Description Retrieve the delivery log of outgoing email (queued or sent directly) with status, attempts, last error and provider message ID, newest first (requires emails.read). Bodies are never returned.
+// @Tags Emails
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 20, max: 100)"
+// @Param filter[recipient][like] query string false "Filter by recipient address"
+// @Param filter[template] query string false "Filter by template (e.g. verification_code)"
+// @Param filter[status] query string false "Filter by status: pending, sent, dead or suppressed"
+// @Param filter[provider] query string false "Filter by provider (e.g. smtp, ses)"
+// @Param filter[provider_message_id] query string false "Filter by the provider's message ID"
+// @Param filter[created_at][gte] query string false "Created at or after (RFC 3339 or YYYY-MM-DD; operators: eq, gt, gte, lt, lte)"
+// @Success 200 {object} utils.APIResponse{data=dto.EmailMessagesResponse} "Emails retrieved"
+// @Failure 400 {object} utils.ProblemDetails "Invalid filter"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Failure 403 {object} utils.ProblemDetails "Forbidden"
+// @Router /emails [get]
+func (h *emailMessageHandler) GetMessages(c *fiber.Ctx) error {
+	// Parse query parameters
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 20)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// Parse filters
+	f, err := filter.FromQuery(c, emailMessageFilters)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid filter", err)
+	}
+
+	response, err := h.service.GetMessages(c.UserContext(), page, limit, f)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get emails", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, response, "Emails retrieved successfully")
+}
+
+// GetMessage gets an outgoing email by ID
+// @Summary Admin: Get email
+// @Description Retrieve a single entry of the email delivery log by its ID (requires emails.read).
+// @Tags Emails
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Email ID (UUID)"
+// @Success 200 {object} utils.APIResponse{data=dto.EmailMessageResponse} "Email retrieved"
+// @Failure 400 {object} utils.ProblemDetails "Invalid email ID"
+// @Failure 404 {object} utils.ProblemDetails "Email not found"
+// @Router /emails/{id} [get]
+func (h *emailMessageHandler) GetMessage(c *fiber.Ctx) error {
+	messageID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid email ID", err)
+	}
+
+	message, err := h.service.GetMessage(c.UserContext(), messageID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get email", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, message, "Email retrieved successfully")
+}
+
+// ResendMessage queues an outgoing email again
+// @Summary Admin: Resend email
+// @Description Queue a copy of a sent, dead or suppressed email to the same recipient with the same rendered body; the copy is a new log entry, itself suppressed when it hits the recipient limit or duplicate window (requires emails.resend).
+// @Tags Emails
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Email ID (UUID)"
+// @Success 202 {object} utils.APIResponse{data=dto.EmailMessageResponse} "Email queued"
+// @Failure 400 {object} utils.ProblemDetails "Invalid email ID"
+// @Failure 404 {object} utils.ProblemDetails "Email not found"
+// @Failure 409 {object} utils.ProblemDetails "Email still pending, or email disabled"
+// @Router /emails/{id}/resend [post]
+func (h *emailMessageHandler) ResendMessage(c *fiber.Ctx) error {
+	messageID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid email ID", err)
+	}
+
+	message, err := h.service.Resend(c.UserContext(), messageID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to resend email", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusAccepted, message, "Email queued for delivery")
+}
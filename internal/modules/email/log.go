@@ -0,0 +1,43 @@
+package email
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogSender writes messages to the application log instead of delivering them, so development
+// runs the full email path without any mail server
+type LogSender struct {
+	logger *logrus.Logger
+}
+
+// NewLogSender creates a sender that logs messages
+func NewLogSender(logger *logrus.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+// Name implements Sender
+func (s *LogSender) Name() string {
+	return ProviderLog
+}
+
+// Send implements Sender; the rendered body is logged in full, attachments by name and size
+func (s *LogSender) Send(_ context.Context, message Message) (string, error) {
+	fields := logrus.Fields{
+		"from":    message.From,
+		"to":      message.To,
+		"subject": message.Subject,
+		"html":    message.HTML,
+	}
+	if len(message.Attachments) > 0 {
+		names := make([]string, len(message.Attachments))
+		for i, file := range message.Attachments {
+			names[i] = fmt.Sprintf("%s (%s, %d bytes)", file.Filename, file.ContentType, len(file.Content))
+		}
+		fields["attachments"] = names
+	}
+	s.logger.WithFields(fields).Info("Email logged instead of delivered")
+	return "", nil
+}
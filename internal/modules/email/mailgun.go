@@ -0,0 +1,131 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+
+	"go_boilerplate/internal/shared/config"
+)
+
+// MailgunSender sends email through the Mailgun Messages API of EMAIL_MAILGUN_DOMAIN (set
+// EMAIL_API_URL=https://api.eu.mailgun.net for EU domains); sandbox mode sends with o:testmode, so
+// Mailgun accepts the message without delivering it
+type MailgunSender struct {
+	client   *http.Client
+	apiKey   string
+	domain   string
+	endpoint string
+	sandbox  bool
+}
+
+// NewMailgunSender creates a Mailgun sender
+func NewMailgunSender(cfg config.EmailConfig, client *http.Client) *MailgunSender {
+	endpoint := cfg.APIURL
+	if endpoint == "" {
+		endpoint = "https://api.mailgun.net"
+	}
+	return &MailgunSender{
+		client:   client,
+		apiKey:   cfg.APIKey,
+		domain:   cfg.MailgunDomain,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		sandbox:  cfg.Sandbox,
+	}
+}
+
+// Name implements Sender
+func (s *MailgunSender) Name() string {
+	return ProviderMailgun
+}
+
+// Send implements Sender
+func (s *MailgunSender) Send(ctx context.Context, message Message) (string, error) {
+	form := url.Values{
+		"from":    {message.From},
+		"to":      {message.To},
+		"subject": {message.Subject},
+		"html":    {message.HTML},
+	}
+	if s.sandbox {
+		form.Set("o:testmode", "yes")
+	}
+
+	body, contentType, err := mailgunBody(form, message.Attachments)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint+"/v3/"+url.PathEscape(s.domain)+"/messages", body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.SetBasicAuth("api", s.apiKey)
+
+	resp, err := do(s.client, req)
+	if err != nil {
+		return "", err
+	}
+
+	var answer struct {
+		ID      string `json:"id"` // <message ID>
+		Message string `json:"message"`
+	}
+	if resp.status == http.StatusOK {
+		_ = json.Unmarshal(resp.body, &answer)
+		return strings.Trim(answer.ID, "<>"), nil
+	}
+	if json.Unmarshal(resp.body, &answer) != nil {
+		answer.Message = strings.TrimSpace(string(resp.body)) // Some errors are plain text
+	}
+	return "", &ProviderError{Provider: ProviderMailgun, Status: resp.status, Message: answer.Message, Kind: kindOfStatus(resp.status)}
+}
+
+// mailgunBody encodes the form, as multipart with the files when there are attachments. Mailgun
+// takes attachments as "attachment" parts and inline images as "inline" parts, whose file name is
+// the Content-ID the body references.
+func mailgunBody(form url.Values, files []File) (io.Reader, string, error) {
+	if len(files) == 0 {
+		return strings.NewReader(form.Encode()), "application/x-www-form-urlencoded", nil
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, values := range form {
+		for _, value := range values {
+			if err := writer.WriteField(name, value); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	for _, file := range files {
+		field, filename := "attachment", file.Filename
+		if file.Inline() {
+			field, filename = "inline", file.ContentID
+		}
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, field, quoteEscaper.Replace(filename)))
+		header.Set("Content-Type", file.ContentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(file.Content); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return &body, writer.FormDataContentType(), nil
+}
+
+// quoteEscaper escapes file names in Content-Disposition, as mime/multipart does
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
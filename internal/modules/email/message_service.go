@@ -0,0 +1,117 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"go_boilerplate/internal/modules/email/dto"
+	"go_boilerplate/internal/shared/apperror"
+	"go_boilerplate/internal/shared/filter"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmailMessageService defines the interface for the email delivery log
+type EmailMessageService interface {
+	GetMessage(ctx context.Context, id uuid.UUID) (*dto.EmailMessageResponse, error)
+	GetMessages(ctx context.Context, page, limit int, f filter.Filter) (*dto.EmailMessagesResponse, error)
+	Resend(ctx context.Context, id uuid.UUID) (*dto.EmailMessageResponse, error)
+}
+
+// Delivery log errors
+var (
+	ErrEmailMessageNotFound = apperror.New(apperror.ErrNotFound, "email not found").WithCode("email_not_found")
+	ErrEmailPending         = apperror.New(apperror.ErrConflict, "email is still pending delivery").WithCode("email_pending")
+	ErrEmailDisabled        = apperror.New(apperror.ErrConflict, "email is disabled").WithCode("email_disabled")
+)
+
+// emailMessageService implements EmailMessageService interface
+type emailMessageService struct {
+	repo  EmailMessageRepository
+	queue *Queue // Nil while email is disabled
+}
+
+// NewEmailMessageService creates a new email delivery log service; resent emails go to queue,
+// which is nil while email is disabled
+func NewEmailMessageService(repo EmailMessageRepository, queue *Queue) EmailMessageService {
+	return &emailMessageService{repo: repo, queue: queue}
+}
+
+// GetMessage gets an email by ID
+func (s *emailMessageService) GetMessage(ctx context.Context, id uuid.UUID) (*dto.EmailMessageResponse, error) {
+	message, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := message.ToResponse()
+	return &response, nil
+}
+
+// GetMessages gets emails matching the filter with pagination
+func (s *emailMessageService) GetMessages(ctx context.Context, page, limit int, f filter.Filter) (*dto.EmailMessagesResponse, error) {
+	// Calculate offset
+	offset := (page - 1) * limit
+
+	// Find emails
+	messages, total, err := s.repo.FindAll(ctx, offset, limit, f)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response
+	messageResponses := make([]dto.EmailMessageResponse, len(messages))
+	for i, message := range messages {
+		messageResponses[i] = message.ToResponse()
+	}
+
+	// Calculate total pages
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+
+	return &dto.EmailMessagesResponse{
+		Messages: messageResponses,
+		Meta: utils.PaginationMeta{
+			Page:       page,
+			Limit:      limit,
+			Total:      int(total),
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+// Resend queues a copy of a sent, dead or suppressed email, with the same rendered body and
+// attachments; the original entry stays in the log as it was. The copy is subject to the same
+// recipient limit and duplicate window as any other email.
+func (s *emailMessageService) Resend(ctx context.Context, id uuid.UUID) (*dto.EmailMessageResponse, error) {
+	if s.queue == nil {
+		return nil, ErrEmailDisabled
+	}
+
+	message, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if message.Status == StatusPending {
+		return nil, ErrEmailPending
+	}
+
+	resent, err := s.queue.Enqueue(ctx, message.Template, message.Recipient, message.Subject, message.Body, message.Attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	response := resent.ToResponse()
+	return &response, nil
+}
+
+// find loads an email, mapping a missing row to ErrEmailMessageNotFound
+func (s *emailMessageService) find(ctx context.Context, id uuid.UUID) (*EmailMessage, error) {
+	message, err := s.repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrEmailMessageNotFound
+	}
+	return message, err
+}
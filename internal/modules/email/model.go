@@ -0,0 +1,75 @@
+package email
+
+import (
+	"time"
+
+	"go_boilerplate/internal/modules/email/dto"
+
+	"github.com/google/uuid"
+)
+
+// Delivery states of an email
+const (
+	StatusPending = "pending" // Waiting for its first attempt or a retry
+	StatusSent    = "sent"
+	StatusDead    = "dead" // Failed EMAIL_QUEUE_MAX_ATTEMPTS times or was rejected; kept for inspection until EMAIL_QUEUE_RETENTION
+	// Never sent: the recipient reached EMAIL_QUEUE_RECIPIENT_LIMIT, or the email duplicates one
+	// queued within EMAIL_QUEUE_DEDUP_WINDOW; last_error says which
+	StatusSuppressed = "suppressed"
+)
+
+// EmailMessage is an outgoing email: queued for the send-queued-email job, or recorded after a
+// direct send. Status, Attempts and LastError track its delivery, so the table doubles as the
+// delivery log behind the /emails admin endpoints.
+type EmailMessage struct {
+	ID                uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Recipient         string     `json:"recipient" gorm:"type:varchar(255);not null;index:idx_t_email_messages_recipient_created_at,priority:1"`
+	Subject           string     `json:"subject" gorm:"type:varchar(255);not null"`
+	Body              string     `json:"-" gorm:"type:text;not null"`                // Rendered HTML
+	Attachments       Files      `json:"-" gorm:"type:jsonb"`                        // Attachments and inline images, with their content
+	Template          string     `json:"template" gorm:"type:varchar(100);not null"` // Registered template name; empty for raw emails
+	ContentHash       string     `json:"-" gorm:"type:varchar(64);not null"`         // SHA-256 of the template, subject, body and attachments, to find duplicates
+	Status            string     `json:"status" gorm:"type:varchar(20);not null;default:pending;index:idx_t_email_messages_due,priority:1"`
+	Attempts          int        `json:"attempts" gorm:"not null;default:0"`
+	LastError         string     `json:"last_error" gorm:"type:text"`
+	Provider          string     `json:"provider" gorm:"type:varchar(20);not null"`                                 // EMAIL_PROVIDER of the last attempt
+	ProviderMessageID string     `json:"provider_message_id" gorm:"type:varchar(255);not null"`                     // ID the provider gave the message, to look it up in its dashboard
+	NextAttemptAt     time.Time  `json:"next_attempt_at" gorm:"not null;index:idx_t_email_messages_due,priority:2"` // Also when the lease of a claimed message ends
+	SentAt            *time.Time `json:"sent_at"`
+	CreatedAt         time.Time  `json:"created_at" gorm:"index;index:idx_t_email_messages_recipient_created_at,priority:2"`
+	UpdatedAt         time.Time  `json:"updated_at" gorm:"index"`
+}
+
+// TableName specifies the table name for EmailMessage model
+func (EmailMessage) TableName() string {
+	return "t_email_messages"
+}
+
+// ToResponse converts EmailMessage to EmailMessageResponse; the body is left out, as it may hold
+// one-time codes and reset links, and attachments are described without their content
+func (m *EmailMessage) ToResponse() dto.EmailMessageResponse {
+	response := dto.EmailMessageResponse{
+		ID:                m.ID,
+		Recipient:         m.Recipient,
+		Subject:           m.Subject,
+		Template:          m.Template,
+		Status:            m.Status,
+		Attempts:          m.Attempts,
+		LastError:         m.LastError,
+		Provider:          m.Provider,
+		ProviderMessageID: m.ProviderMessageID,
+		NextAttemptAt:     m.NextAttemptAt,
+		SentAt:            m.SentAt,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
+	}
+	for _, file := range m.Attachments {
+		response.Attachments = append(response.Attachments, dto.AttachmentResponse{
+			Filename:    file.Filename,
+			ContentType: file.ContentType,
+			ContentID:   file.ContentID,
+			Size:        len(file.Content),
+		})
+	}
+	return response
+}
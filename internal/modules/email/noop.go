@@ -0,0 +1,46 @@
+package email
+
+import "github.com/sirupsen/logrus"
+
+// noopEmailService is the EmailService while EMAIL_ENABLED is false: every email is dropped with a
+// debug log, so callers don't need to check whether email is enabled
+type noopEmailService struct {
+	logger *logrus.Logger
+}
+
+// NewNoopEmailService creates an email service that sends nothing
+func NewNoopEmailService(logger *logrus.Logger) EmailService {
+	return &noopEmailService{logger: loggerOrDefault(logger)}
+}
+
+// SendEmail drops the email
+func (s *noopEmailService) SendEmail(to, subject, _ string, _ ...Attachment) error {
+	s.logger.Debugf("Email disabled, not sending %q to %s", subject, to)
+	return nil
+}
+
+// SendTemplate drops the email
+func (s *noopEmailService) SendTemplate(to, _, name string, _ any) error {
+	s.logger.Debugf("Email disabled, not sending %s email to %s", name, to)
+	return nil
+}
+
+// SendWelcomeEmail drops the email
+func (s *noopEmailService) SendWelcomeEmail(to, _, locale string) error {
+	return s.SendTemplate(to, locale, TemplateWelcome, nil)
+}
+
+// SendPasswordResetEmail drops the email
+func (s *noopEmailService) SendPasswordResetEmail(to, _, locale string) error {
+	return s.SendTemplate(to, locale, TemplatePasswordReset, nil)
+}
+
+// SendVerificationEmail drops the email
+func (s *noopEmailService) SendVerificationEmail(to, _, locale string) error {
+	return s.SendTemplate(to, locale, TemplateVerificationCode, nil)
+}
+
+// SendTwoFactorEmail drops the email
+func (s *noopEmailService) SendTwoFactorEmail(to, _, locale string) error {
+	return s.SendTemplate(to, locale, TemplateTwoFactorCode, nil)
+}
@@ -0,0 +1,59 @@
+package email
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/notify"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// NewAdminNotifier creates the notifier used for admin alerts
+// Notifications go to the log/webhook, and to NOTIFY_EMAIL if email is enabled; emails are sent
+// directly, not queued, and recorded in db afterwards
+func NewAdminNotifier(cfg *config.Config, db *gorm.DB, logger *logrus.Logger) notify.Notifier {
+	notifier := notify.New(cfg, logger)
+	if cfg.Email.Enabled && cfg.Notify.Email != "" {
+		notifier = notify.Multi(notifier, NewNotifier(NewEmailService(cfg, db, logger), cfg.Notify.Email))
+	}
+	return notifier
+}
+
+// emailNotifier delivers admin notifications by email
+type emailNotifier struct {
+	service EmailService
+	to      string
+}
+
+// NewNotifier creates a notifier that emails notifications to the given address
+func NewNotifier(service EmailService, to string) notify.Notifier {
+	return &emailNotifier{service: service, to: to}
+}
+
+// Notify sends the notification as a simple HTML email
+func (n *emailNotifier) Notify(notification notify.Notification) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<p>%s</p>", html.EscapeString(notification.Message))
+
+	if len(notification.Fields) > 0 {
+		keys := make([]string, 0, len(notification.Fields))
+		for k := range notification.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		b.WriteString("<ul>")
+		for _, k := range keys {
+			fmt.Fprintf(&b, "<li><strong>%s:</strong> %s</li>", html.EscapeString(k), html.EscapeString(notification.Fields[k]))
+		}
+		b.WriteString("</ul>")
+	}
+
+	subject := fmt.Sprintf("[%s] %s", strings.ToUpper(notification.Level), notification.Title)
+	return n.service.SendEmail(n.to, subject, b.String())
+}
@@ -0,0 +1,16 @@
+package email
+
+import "go_boilerplate/internal/shared/permission"
+
+// Email delivery log permissions
+const (
+	PermEmailsRead   = "emails.read"
+	PermEmailsResend = "emails.resend"
+)
+
+func init() {
+	permission.Register(
+		permission.Permission{Name: PermEmailsRead, Description: "View the delivery log of outgoing email"},
+		permission.Permission{Name: PermEmailsResend, Description: "Queue a logged email again"},
+	)
+}
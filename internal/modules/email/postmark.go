@@ -0,0 +1,108 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go_boilerplate/internal/shared/config"
+)
+
+// postmarkTestToken is Postmark's test server token: requests are validated but nothing is sent
+const postmarkTestToken = "POSTMARK_API_TEST"
+
+// PostmarkSender sends email through the Postmark API with a server token (EMAIL_API_KEY); sandbox
+// mode uses the test token instead
+type PostmarkSender struct {
+	client   *http.Client
+	token    string
+	endpoint string
+}
+
+// NewPostmarkSender creates a Postmark sender
+func NewPostmarkSender(cfg config.EmailConfig, client *http.Client) *PostmarkSender {
+	endpoint := cfg.APIURL
+	if endpoint == "" {
+		endpoint = "https://api.postmarkapp.com"
+	}
+	token := cfg.APIKey
+	if cfg.Sandbox {
+		token = postmarkTestToken
+	}
+	return &PostmarkSender{client: client, token: token, endpoint: strings.TrimSuffix(endpoint, "/")}
+}
+
+// Name implements Sender
+func (s *PostmarkSender) Name() string {
+	return ProviderPostmark
+}
+
+// Send implements Sender
+func (s *PostmarkSender) Send(ctx context.Context, message Message) (string, error) {
+	payload := map[string]any{
+		"From":          message.From,
+		"To":            message.To,
+		"Subject":       message.Subject,
+		"HtmlBody":      message.HTML,
+		"MessageStream": "outbound",
+	}
+	if len(message.Attachments) > 0 {
+		payload["Attachments"] = postmarkAttachments(message.Attachments)
+	}
+
+	header := http.Header{"X-Postmark-Server-Token": {s.token}}
+	resp, err := postJSON(ctx, s.client, s.endpoint+"/email", payload, header)
+	if err != nil {
+		return "", err
+	}
+
+	var answer struct {
+		MessageID string `json:"MessageID"`
+		ErrorCode int    `json:"ErrorCode"`
+		Message   string `json:"Message"`
+	}
+	_ = json.Unmarshal(resp.body, &answer)
+	if resp.status == http.StatusOK && answer.ErrorCode == 0 {
+		return answer.MessageID, nil
+	}
+	providerErr := &ProviderError{
+		Provider: ProviderPostmark,
+		Status:   resp.status,
+		Message:  answer.Message,
+		Kind:     postmarkErrorKind(resp.status, answer.ErrorCode),
+	}
+	if answer.ErrorCode != 0 {
+		providerErr.Code = strconv.Itoa(answer.ErrorCode)
+	}
+	return "", providerErr
+}
+
+// postmarkAttachments converts attachments to Postmark attachment objects; Content is base64, as
+// encoding/json encodes []byte, and inline images are named by a "cid:" ContentID
+func postmarkAttachments(files []File) []map[string]any {
+	attachments := make([]map[string]any, len(files))
+	for i, file := range files {
+		attachment := map[string]any{
+			"Name":        file.Filename,
+			"Content":     file.Content,
+			"ContentType": file.ContentType,
+		}
+		if file.Inline() {
+			attachment["ContentID"] = "cid:" + file.ContentID
+		}
+		attachments[i] = attachment
+	}
+	return attachments
+}
+
+// postmarkErrorKind classifies a Postmark error: 422 answers carry an ErrorCode, of which 10 is a
+// bad server token and the others (invalid or inactive recipient, unconfirmed sender signature...)
+// concern the message
+func postmarkErrorKind(status, code int) error {
+	if status == http.StatusUnprocessableEntity && code == 10 {
+		return ErrUnauthorized
+	}
+	return kindOfStatus(status)
+}
@@ -0,0 +1,216 @@
+package email
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"go_boilerplate/internal/shared/config"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// maxRetryBackoff caps the doubling EMAIL_QUEUE_RETRY_BACKOFF
+const maxRetryBackoff = time.Hour
+
+// Queue keeps outgoing email in t_email_messages, so a slow or unreachable SMTP server delays
+// mail instead of blocking requests or dropping it. Every instance runs the worker: messages
+// are claimed with FOR UPDATE SKIP LOCKED and leased for EMAIL_QUEUE_LEASE, after which a
+// message whose worker died is picked up again.
+type Queue struct {
+	db      *gorm.DB
+	sender  Sender
+	from    string
+	sandbox bool
+	cfg     config.EmailQueueConfig
+	logger  *logrus.Logger
+}
+
+// NewQueue creates an email queue; sender delivers the messages from SMTP_FROM (see NewSender)
+func NewQueue(db *gorm.DB, sender Sender, cfg *config.Config, logger *logrus.Logger) *Queue {
+	return &Queue{
+		db:      db,
+		sender:  sender,
+		from:    cfg.Email.SMTPFrom,
+		sandbox: cfg.Email.Sandbox,
+		cfg:     cfg.EmailQueue,
+		logger:  loggerOrDefault(logger),
+	}
+}
+
+// Enqueue stores a rendered email and its attachments for the worker to deliver; template names
+// the registered template it was rendered from, if any. An email over the recipient's hourly limit
+// or duplicating a recent one is stored as suppressed instead, without an error, so a retry loop
+// or a burst of requests can't flood an inbox.
+func (q *Queue) Enqueue(ctx context.Context, template, to, subject, body string, attachments Files) (*EmailMessage, error) {
+	message := EmailMessage{
+		Recipient:     to,
+		Subject:       subject,
+		Body:          body,
+		Attachments:   attachments,
+		Template:      template,
+		ContentHash:   contentHash(template, subject, body, attachments),
+		Status:        StatusPending,
+		NextAttemptAt: time.Now(),
+	}
+
+	reason, err := q.suppression(ctx, &message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check email limits: %w", err)
+	}
+	if reason != "" {
+		message.Status = StatusSuppressed
+		message.LastError = reason
+		q.logger.WithField("template", template).Warnf("Suppressed email to %s: %s", to, reason)
+	}
+
+	if err := q.db.WithContext(ctx).Create(&message).Error; err != nil {
+		return nil, fmt.Errorf("failed to queue email: %w", err)
+	}
+	return &message, nil
+}
+
+// suppression returns why message must not be sent, or "" when it may: a message with the same
+// content queued for the recipient within EMAIL_QUEUE_DEDUP_WINDOW, or EMAIL_QUEUE_RECIPIENT_LIMIT
+// messages queued for the recipient in the last hour. Suppressed messages count toward neither.
+// Concurrent enqueues may each pass the check, so the limit is approximate.
+func (q *Queue) suppression(ctx context.Context, message *EmailMessage) (string, error) {
+	recent := q.db.WithContext(ctx).Model(&EmailMessage{}).Where("recipient = ? AND status <> ?", message.Recipient, StatusSuppressed)
+
+	if q.cfg.DedupWindow > 0 {
+		var duplicates int64
+		err := recent.Session(&gorm.Session{}).
+			Where("content_hash = ? AND created_at > ?", message.ContentHash, message.NextAttemptAt.Add(-q.cfg.DedupWindow)).
+			Count(&duplicates).Error
+		if err != nil {
+			return "", err
+		}
+		if duplicates > 0 {
+			return fmt.Sprintf("duplicate of an email queued within %s", q.cfg.DedupWindow), nil
+		}
+	}
+
+	if q.cfg.RecipientLimit > 0 {
+		var sent int64
+		err := recent.Session(&gorm.Session{}).
+			Where("created_at > ?", message.NextAttemptAt.Add(-time.Hour)).
+			Count(&sent).Error
+		if err != nil {
+			return "", err
+		}
+		if sent >= int64(q.cfg.RecipientLimit) {
+			return fmt.Sprintf("recipient limit of %d emails per hour reached", q.cfg.RecipientLimit), nil
+		}
+	}
+	return "", nil
+}
+
+// Process claims up to EMAIL_QUEUE_BATCH_SIZE due messages and delivers them; it is meant to be
+// scheduled every EMAIL_QUEUE_POLL_INTERVAL
+func (q *Queue) Process(ctx context.Context) error {
+	messages, err := q.claim(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to claim queued emails: %w", err)
+	}
+	for i := range messages {
+		// Messages left unsent go out again once their lease ends
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		q.deliver(ctx, &messages[i])
+	}
+	return nil
+}
+
+// Purge deletes sent, dead and suppressed messages last updated more than EMAIL_QUEUE_RETENTION ago
+func (q *Queue) Purge(ctx context.Context) error {
+	if q.cfg.Retention <= 0 {
+		return nil
+	}
+	result := q.db.WithContext(ctx).
+		Where("status IN ? AND updated_at < ?", []string{StatusSent, StatusDead, StatusSuppressed}, time.Now().Add(-q.cfg.Retention)).
+		Delete(&EmailMessage{})
+	if result.RowsAffected > 0 {
+		q.logger.WithField("rows", result.RowsAffected).Infof("Purged %d delivered, dead or suppressed emails", result.RowsAffected)
+	}
+	return result.Error
+}
+
+// claim leases due messages to this worker, counting the attempt up front so a message that
+// crashes its worker still runs out of attempts
+func (q *Queue) claim(ctx context.Context) ([]EmailMessage, error) {
+	now := time.Now()
+	var messages []EmailMessage
+	err := q.db.WithContext(ctx).Raw(`UPDATE t_email_messages
+		SET attempts = attempts + 1, next_attempt_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM t_email_messages
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now.Add(q.cfg.Lease), now, StatusPending, now, q.cfg.BatchSize).Scan(&messages).Error
+	return messages, err
+}
+
+// deliver sends a claimed message and records the outcome: sent, retried after a backoff, or dead
+// once it used its last attempt or the provider rejected it
+func (q *Queue) deliver(ctx context.Context, message *EmailMessage) {
+	// A send interrupted by shutdown may still go out, so it runs to completion (providers time out)
+	email := Message{From: q.from, To: message.Recipient, Subject: message.Subject, HTML: message.Body, Attachments: message.Attachments}
+	providerID, sendErr := sendMessage(context.WithoutCancel(ctx), q.sender, email, q.sandbox, q.logger)
+
+	now := time.Now()
+	updates := map[string]any{"updated_at": now, "provider": q.sender.Name()}
+	log := q.logger.WithFields(logrus.Fields{"email_id": message.ID, "attempts": message.Attempts})
+	switch {
+	case sendErr == nil:
+		updates["status"] = StatusSent
+		updates["sent_at"] = now
+		updates["last_error"] = ""
+		updates["provider_message_id"] = providerID
+	case message.Attempts >= q.cfg.MaxAttempts || errors.Is(sendErr, ErrRejected):
+		updates["status"] = StatusDead
+		updates["last_error"] = sendErr.Error()
+		log.Errorf("Giving up on email to %s: %v", message.Recipient, sendErr)
+	default:
+		backoff := q.backoff(message.Attempts)
+		updates["next_attempt_at"] = now.Add(backoff)
+		updates["last_error"] = sendErr.Error()
+		log.Warnf("Email to %s failed, retrying in %s: %v", message.Recipient, backoff, sendErr)
+	}
+
+	// Record the outcome even when shutting down, or a sent message goes out again after its lease
+	err := q.db.WithContext(context.WithoutCancel(ctx)).Model(&EmailMessage{}).Where("id = ?", message.ID).Updates(updates).Error
+	if err != nil {
+		log.Errorf("Failed to record delivery of email to %s: %v", message.Recipient, err)
+	}
+}
+
+// contentHash identifies the content of an email, so identical emails to a recipient can be found
+func contentHash(template, subject, body string, attachments Files) string {
+	hash := sha256.New()
+	for _, part := range []string{template, subject, body} {
+		fmt.Fprintf(hash, "%d:%s", len(part), part)
+	}
+	for _, file := range attachments {
+		fmt.Fprintf(hash, "%d:%s%d:", len(file.Filename), file.Filename, len(file.Content))
+		hash.Write(file.Content)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// backoff is the delay before the retry following the given attempt: EMAIL_QUEUE_RETRY_BACKOFF,
+// doubled after each failure up to maxRetryBackoff
+func (q *Queue) backoff(attempts int) time.Duration {
+	backoff := q.cfg.RetryBackoff
+	for i := 1; i < attempts && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxRetryBackoff)
+}
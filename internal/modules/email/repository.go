@@ -0,0 +1,56 @@
+package email
+
+import (
+	"context"
+
+	"go_boilerplate/internal/shared/filter"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmailMessageRepository defines the interface for delivery log queries
+type EmailMessageRepository interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*EmailMessage, error)
+	FindAll(ctx context.Context, offset, limit int, f filter.Filter) ([]EmailMessage, int64, error)
+}
+
+// emailMessageRepository implements EmailMessageRepository interface
+type emailMessageRepository struct {
+	db *gorm.DB
+}
+
+// NewEmailMessageRepository creates a new email message repository
+func NewEmailMessageRepository(db *gorm.DB) EmailMessageRepository {
+	return &emailMessageRepository{db: db}
+}
+
+// FindByID finds an email by ID
+func (r *emailMessageRepository) FindByID(ctx context.Context, id uuid.UUID) (*EmailMessage, error) {
+	var message EmailMessage
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&message).Error; err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// FindAll finds emails matching the filter with pagination, newest first; bodies and attachments
+// are not loaded
+func (r *emailMessageRepository) FindAll(ctx context.Context, offset, limit int, f filter.Filter) ([]EmailMessage, int64, error) {
+	var messages []EmailMessage
+	var total int64
+	db := r.db.WithContext(ctx)
+
+	// Count total
+	if err := db.Model(&EmailMessage{}).Scopes(f.Scope()).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Find emails with pagination
+	err := db.Omit("body", "attachments").Scopes(f.Scope()).Offset(offset).Limit(limit).Order("created_at DESC").Find(&messages).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return messages, total, nil
+}
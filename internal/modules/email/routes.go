@@ -0,0 +1,48 @@
+package email
+
+import (
+	"go_boilerplate/internal/shared/apiversion"
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// RegisterRoutes registers the email delivery log routes and, in development, the template preview
+// Entries are written by the queue (Queue) and by direct sends (NewEmailService)
+func RegisterRoutes(app *fiber.App, db *gorm.DB, cfg *config.Config, logger *logrus.Logger) {
+	// Resent emails are queued for the send-queued-email job, which only runs while email is enabled
+	var queue *Queue
+	if cfg.Email.Enabled {
+		queue = NewQueue(db, NewSender(cfg.Email, logger), cfg, logger)
+	}
+
+	// Initialize repository, service and handler
+	messageService := NewEmailMessageService(NewEmailMessageRepository(db), queue)
+	messageHandler := NewEmailMessageHandler(messageService)
+
+	// Create API route group
+	api := apiversion.Group(app, "v1")
+	emails := api.Group("/emails")
+	emails.Use(middleware.JWTAuth(cfg))
+	canRead := middleware.RequirePermission(cfg, PermEmailsRead)
+	canResend := middleware.RequirePermission(cfg, PermEmailsResend)
+
+	emails.Get("/", canRead, messageHandler.GetMessages)                // List emails
+	emails.Get("/:id", canRead, messageHandler.GetMessage)              // Get email by ID
+	emails.Post("/:id/resend", canResend, messageHandler.ResendMessage) // Queue an email again
+
+	// Template preview for iterating on templates in the browser (development only, unauthenticated)
+	if cfg.Server.IsDevelopment() {
+		templates, err := NewRegistry()
+		if err != nil {
+			logger.Errorf("Failed to parse email templates: %v", err)
+			return
+		}
+		previewHandler := NewTemplatePreviewHandler(templates)
+		app.Get("/dev/emails/:template/preview", previewHandler.Preview) // Render a template with sample data
+		logger.Info("✓ Email template preview registered")
+	}
+}
@@ -0,0 +1,163 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Providers selectable with EMAIL_PROVIDER
+const (
+	ProviderSMTP     = "smtp"
+	ProviderSES      = "ses"
+	ProviderSendGrid = "sendgrid"
+	ProviderMailgun  = "mailgun"
+	ProviderPostmark = "postmark"
+	ProviderLog      = "log"  // Development: logs messages instead of delivering them
+	ProviderFile     = "file" // Development: writes messages to EMAIL_FILE_DIR
+)
+
+// sendTimeout bounds a single API call to a provider
+const sendTimeout = 15 * time.Second
+
+// Kinds of provider errors, matched with errors.Is; other errors are temporary
+var (
+	// ErrRejected means the provider refused the message itself (invalid or suppressed recipient,
+	// unverified sender); sending it again fails the same way, so the queue doesn't retry it
+	ErrRejected = errors.New("email rejected by provider")
+	// ErrUnauthorized means the provider refused the credentials
+	ErrUnauthorized = errors.New("email provider refused the credentials")
+	// ErrRateLimited means the provider throttled the request
+	ErrRateLimited = errors.New("email provider rate limit exceeded")
+)
+
+// Message is a rendered email ready to be sent
+type Message struct {
+	From    string // Address or "Name <address>"
+	To      string
+	Subject string
+	HTML    string
+	// Attachments and inline images (see Attachment)
+	Attachments []File
+}
+
+// Sender delivers messages through an email provider
+type Sender interface {
+	// Send delivers a message and returns the ID the provider gave it (empty if it has none);
+	// provider errors wrap ErrRejected, ErrUnauthorized or ErrRateLimited when they are of that kind
+	Send(ctx context.Context, message Message) (string, error)
+	// Name returns the provider name, e.g. smtp
+	Name() string
+}
+
+// NewSender creates the sender selected by EMAIL_PROVIDER; EMAIL_SANDBOX switches API providers to
+// their test mode
+func NewSender(cfg config.EmailConfig, logger *logrus.Logger) Sender {
+	client := utils.NewHTTPClient(sendTimeout)
+	switch cfg.Provider {
+	case ProviderSES:
+		return NewSESSender(cfg, client)
+	case ProviderSendGrid:
+		return NewSendGridSender(cfg, client)
+	case ProviderMailgun:
+		return NewMailgunSender(cfg, client)
+	case ProviderPostmark:
+		return NewPostmarkSender(cfg, client)
+	case ProviderLog:
+		return NewLogSender(loggerOrDefault(logger))
+	case ProviderFile:
+		return NewFileSender(cfg.FileDir)
+	default:
+		return NewSMTPSender(cfg)
+	}
+}
+
+// ProviderError is an error answered by an email provider
+type ProviderError struct {
+	Provider string
+	Status   int    // HTTP status, or the SMTP reply code
+	Code     string // Provider error code, if any
+	Message  string
+	Kind     error // ErrRejected, ErrUnauthorized, ErrRateLimited or nil for temporary errors
+}
+
+// Error describes the error with the provider's own message
+func (e *ProviderError) Error() string {
+	msg := fmt.Sprintf("%s: status %d", e.Provider, e.Status)
+	if e.Code != "" {
+		msg += " (" + e.Code + ")"
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Unwrap returns the kind, so errors.Is(err, ErrRejected) works
+func (e *ProviderError) Unwrap() error {
+	return e.Kind
+}
+
+// kindOfStatus maps the HTTP statuses API providers share; providers refine it with their codes
+func kindOfStatus(status int) error {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrUnauthorized
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status == http.StatusBadRequest || status == http.StatusRequestEntityTooLarge || status == http.StatusUnprocessableEntity:
+		return ErrRejected
+	default:
+		return nil
+	}
+}
+
+// response is a provider's answer, read in full
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// postJSON sends payload as JSON
+func postJSON(ctx context.Context, client *http.Client, url string, payload any, header http.Header) (*response, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	return do(client, req)
+}
+
+// do sends req and reads the response
+func do(client *http.Client, req *http.Request) (*response, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	return &response{status: resp.StatusCode, header: resp.Header, body: body}, nil
+}
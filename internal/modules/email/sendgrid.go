@@ -0,0 +1,106 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"go_boilerplate/internal/shared/config"
+)
+
+// SendGridSender sends email through the SendGrid v3 Mail Send API; sandbox mode validates the
+// request without delivering it
+type SendGridSender struct {
+	client   *http.Client
+	apiKey   string
+	endpoint string
+	sandbox  bool
+}
+
+// NewSendGridSender creates a SendGrid sender
+func NewSendGridSender(cfg config.EmailConfig, client *http.Client) *SendGridSender {
+	endpoint := cfg.APIURL
+	if endpoint == "" {
+		endpoint = "https://api.sendgrid.com"
+	}
+	return &SendGridSender{client: client, apiKey: cfg.APIKey, endpoint: strings.TrimSuffix(endpoint, "/"), sandbox: cfg.Sandbox}
+}
+
+// Name implements Sender
+func (s *SendGridSender) Name() string {
+	return ProviderSendGrid
+}
+
+// Send implements Sender
+func (s *SendGridSender) Send(ctx context.Context, message Message) (string, error) {
+	from, err := mail.ParseAddress(message.From)
+	if err != nil {
+		return "", err
+	}
+
+	type address struct {
+		Email string `json:"email"`
+		Name  string `json:"name,omitempty"`
+	}
+	payload := map[string]any{
+		"personalizations": []any{map[string]any{"to": []address{{Email: message.To}}}},
+		"from":             address{Email: from.Address, Name: from.Name},
+		"subject":          message.Subject,
+		"content":          []any{map[string]string{"type": "text/html", "value": message.HTML}},
+	}
+	if len(message.Attachments) > 0 {
+		payload["attachments"] = sendGridAttachments(message.Attachments)
+	}
+	if s.sandbox {
+		payload["mail_settings"] = map[string]any{"sandbox_mode": map[string]bool{"enable": true}}
+	}
+
+	header := http.Header{"Authorization": {"Bearer " + s.apiKey}}
+	resp, err := postJSON(ctx, s.client, s.endpoint+"/v3/mail/send", payload, header)
+	if err != nil {
+		return "", err
+	}
+	// 202 when queued for delivery, 200 in sandbox mode
+	if resp.status == http.StatusAccepted || resp.status == http.StatusOK {
+		return resp.header.Get("X-Message-Id"), nil
+	}
+
+	var answer struct {
+		Errors []struct {
+			Message string `json:"message"`
+			Field   string `json:"field"`
+		} `json:"errors"`
+	}
+	_ = json.Unmarshal(resp.body, &answer)
+	messages := make([]string, 0, len(answer.Errors))
+	for _, e := range answer.Errors {
+		if e.Field != "" {
+			messages = append(messages, e.Field+": "+e.Message)
+		} else {
+			messages = append(messages, e.Message)
+		}
+	}
+	return "", &ProviderError{Provider: ProviderSendGrid, Status: resp.status, Message: strings.Join(messages, "; "), Kind: kindOfStatus(resp.status)}
+}
+
+// sendGridAttachments converts attachments to SendGrid attachment objects; content is base64, as
+// encoding/json encodes []byte
+func sendGridAttachments(files []File) []map[string]any {
+	attachments := make([]map[string]any, len(files))
+	for i, file := range files {
+		attachment := map[string]any{
+			"content":     file.Content,
+			"type":        file.ContentType,
+			"filename":    file.Filename,
+			"disposition": "attachment",
+		}
+		if file.Inline() {
+			attachment["disposition"] = "inline"
+			attachment["content_id"] = file.ContentID
+		}
+		attachments[i] = attachment
+	}
+	return attachments
+}
@@ -1,142 +1,204 @@
 package email
 
 import (
-	"bytes"
-	"embed"
+	"context"
 	"fmt"
-	"html/template"
 	"time"
 
 	"go_boilerplate/internal/modules/email/dto"
 	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/observability"
 
 	"github.com/sirupsen/logrus"
-	"gopkg.in/gomail.v2"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
 )
 
-//go:embed templates/*.html
-var templatesFS embed.FS
-
 // EmailService defines the interface for email operations
 type EmailService interface {
-	SendEmail(to, subject, body string) error
-	SendWelcomeEmail(to, name string) error
-	SendPasswordResetEmail(to, resetLink string) error
-	SendVerificationEmail(to, code string) error
-	SendTwoFactorEmail(to, code string) error
+	// SendEmail sends an HTML email with optional attachments and inline images
+	SendEmail(to, subject, body string, attachments ...Attachment) error
+	// SendTemplate sends a registered template (see Registry) rendered in locale
+	SendTemplate(to, locale, name string, data any) error
+	SendWelcomeEmail(to, name, locale string) error
+	SendPasswordResetEmail(to, resetLink, locale string) error
+	SendVerificationEmail(to, code, locale string) error
+	SendTwoFactorEmail(to, code, locale string) error
 }
 
 // emailService implements EmailService interface
 type emailService struct {
 	cfg       *config.Config
-	dialer    *gomail.Dialer
+	sender    Sender
 	logger    *logrus.Logger
-	templates *template.Template
+	templates *Registry
+	db        *gorm.DB // Records direct sends in t_email_messages; nil skips it
+	queue     *Queue   // Set by NewQueuedEmailService
+}
+
+// NewEmailService creates an email service that sends through EMAIL_PROVIDER right away and
+// records each email in t_email_messages (the delivery log) afterwards; db may be nil to skip that.
+// While email is disabled it returns the no-op service.
+func NewEmailService(cfg *config.Config, db *gorm.DB, logger *logrus.Logger) EmailService {
+	if !cfg.Email.Enabled {
+		return NewNoopEmailService(logger)
+	}
+	service := newEmailService(cfg, logger)
+	service.db = db
+	return service
 }
 
-// NewEmailService creates a new email service
-func NewEmailService(cfg *config.Config, logger *logrus.Logger) EmailService {
-	dialer := gomail.NewDialer(
-		cfg.Email.SMTPHost,
-		cfg.Email.SMTPPort,
-		cfg.Email.SMTPUser,
-		cfg.Email.SMTPPassword,
-	)
+// NewQueuedEmailService creates an email service that renders emails and queues them in
+// t_email_messages; the send-queued-email job delivers them (see Queue). While email is disabled it
+// returns the no-op service, as nothing would deliver the queue.
+func NewQueuedEmailService(cfg *config.Config, db *gorm.DB, logger *logrus.Logger) EmailService {
+	if !cfg.Email.Enabled {
+		return NewNoopEmailService(logger)
+	}
+	service := newEmailService(cfg, logger)
+	service.queue = NewQueue(db, service.sender, cfg, logger)
+	return service
+}
+
+// newEmailService creates the service sending through the configured provider
+func newEmailService(cfg *config.Config, logger *logrus.Logger) *emailService {
+	logger = loggerOrDefault(logger)
 
 	// Parse templates from embedded FS
-	tmpl, err := template.ParseFS(templatesFS, "templates/*.html")
+	tmpl, err := NewRegistry()
 	if err != nil {
 		logger.Errorf("Failed to parse email templates: %v", err)
 	}
 
 	return &emailService{
 		cfg:       cfg,
-		dialer:    dialer,
+		sender:    NewSender(cfg.Email, logger),
 		logger:    logger,
 		templates: tmpl,
 	}
 }
 
-// SendEmail sends an email
-func (s *emailService) SendEmail(to, subject, body string) error {
-	// Create message
-	m := gomail.NewMessage()
-	m.SetHeader("From", s.cfg.Email.SMTPFrom)
-	m.SetHeader("To", to)
-	m.SetHeader("Subject", subject)
-	m.SetBody("text/html", body)
-
-	// Send email
-	if err := s.dialer.DialAndSend(m); err != nil {
-		s.logger.Errorf("Failed to send email to %s: %v", to, err)
+// SendEmail sends an email, or queues it when the service was created by NewQueuedEmailService;
+// attachments are read right away
+func (s *emailService) SendEmail(to, subject, body string, attachments ...Attachment) error {
+	files, err := loadAttachments(attachments)
+	if err != nil {
 		return err
 	}
-
-	s.logger.Infof("Email sent successfully to %s", to)
-	return nil
+	return s.dispatch("", to, subject, body, files)
 }
 
-// SendWelcomeEmail sends a welcome email
-func (s *emailService) SendWelcomeEmail(to, name string) error {
-	body, err := s.renderTemplate("welcome.html", map[string]interface{}{
-		"Name": name,
-	})
-	if err != nil {
-		return err
+// SendTemplate renders a template and sends it
+func (s *emailService) SendTemplate(to, locale, name string, data any) error {
+	if s.templates == nil {
+		return fmt.Errorf("templates not initialized")
 	}
 
-	return s.SendEmail(to, "Welcome to Our Platform!", body)
-}
-
-// SendPasswordResetEmail sends a password reset email
-func (s *emailService) SendPasswordResetEmail(to, resetLink string) error {
-	body, err := s.renderTemplate("password_reset.html", map[string]interface{}{
-		"ResetLink": resetLink,
-	})
+	subject, body, err := s.templates.Render(name, locale, data)
 	if err != nil {
+		s.logger.Errorf("Failed to render template %s: %v", name, err)
 		return err
 	}
 
-	return s.SendEmail(to, "Password Reset Request", body)
+	return s.dispatch(name, to, subject, body, nil)
 }
 
-// SendVerificationEmail sends an account verification email
-func (s *emailService) SendVerificationEmail(to, code string) error {
-	body, err := s.renderTemplate("verification_code.html", map[string]interface{}{
-		"Code": code,
-	})
-	if err != nil {
+// dispatch queues a rendered email, or sends it and records the outcome
+func (s *emailService) dispatch(template, to, subject, body string, attachments Files) error {
+	ctx := context.Background()
+	if s.queue != nil {
+		_, err := s.queue.Enqueue(ctx, template, to, subject, body, attachments)
 		return err
 	}
 
-	return s.SendEmail(to, "Verify Your Account", body)
+	message := Message{From: s.cfg.Email.SMTPFrom, To: to, Subject: subject, HTML: body, Attachments: attachments}
+	providerID, err := sendMessage(ctx, s.sender, message, s.cfg.Email.Sandbox, s.logger)
+	s.record(ctx, template, message, providerID, err)
+	return err
 }
 
-// SendTwoFactorEmail sends a 2FA verification email
-func (s *emailService) SendTwoFactorEmail(to, code string) error {
-	body, err := s.renderTemplate("2fa_code.html", map[string]interface{}{
-		"Code": code,
-	})
-	if err != nil {
-		return err
+// record adds a direct send to the delivery log; failing to record it is only logged, as the
+// email already went out
+func (s *emailService) record(ctx context.Context, template string, message Message, providerID string, sendErr error) {
+	if s.db == nil {
+		return
 	}
 
-	return s.SendEmail(to, "Your Login Verification Code", body)
+	now := time.Now()
+	entry := EmailMessage{
+		Recipient:         message.To,
+		Subject:           message.Subject,
+		Body:              message.HTML,
+		Attachments:       message.Attachments,
+		Template:          template,
+		ContentHash:       contentHash(template, message.Subject, message.HTML, message.Attachments),
+		Status:            StatusSent,
+		Attempts:          1,
+		Provider:          s.sender.Name(),
+		ProviderMessageID: providerID,
+		NextAttemptAt:     now,
+		SentAt:            &now,
+	}
+	if sendErr != nil {
+		entry.Status = StatusDead
+		entry.LastError = sendErr.Error()
+		entry.SentAt = nil
+	}
+	if err := s.db.WithContext(ctx).Create(&entry).Error; err != nil {
+		s.logger.Errorf("Failed to record email to %s: %v", message.To, err)
+	}
 }
 
-// renderTemplate renders an HTML template with data
-func (s *emailService) renderTemplate(name string, data interface{}) (string, error) {
-	if s.templates == nil {
-		return "", fmt.Errorf("templates not initialized")
+// loggerOrDefault returns logger, or logrus' standard logger when it is nil, so a service created
+// without a logger logs its failures instead of panicking on them
+func loggerOrDefault(logger *logrus.Logger) *logrus.Logger {
+	if logger == nil {
+		return logrus.StandardLogger()
 	}
+	return logger
+}
 
-	var buf bytes.Buffer
-	if err := s.templates.ExecuteTemplate(&buf, name, data); err != nil {
-		s.logger.Errorf("Failed to render template %s: %v", name, err)
+// sendMessage sends a message through sender and logs the outcome; it returns the provider's
+// message ID
+func sendMessage(ctx context.Context, sender Sender, message Message, sandbox bool, logger *logrus.Logger) (string, error) {
+	// API providers' HTTP calls become child spans (see utils.NewHTTPClient); SMTP has only this one
+	ctx, span := observability.StartSpan(ctx, "email.send", attribute.String("email.provider", sender.Name()))
+	providerID, err := sender.Send(ctx, message)
+	observability.EndSpan(span, err)
+	if err != nil {
+		logger.Errorf("Failed to send email to %s via %s: %v", message.To, sender.Name(), err)
 		return "", err
 	}
 
-	return buf.String(), nil
+	switch {
+	case sender.Name() == ProviderLog || sender.Name() == ProviderFile:
+		logger.Infof("Email to %s captured by the %s transport (not delivered)", message.To, sender.Name())
+	case sandbox:
+		logger.Infof("Email to %s accepted by %s in sandbox mode (not delivered)", message.To, sender.Name())
+	default:
+		logger.Infof("Email sent successfully to %s via %s", message.To, sender.Name())
+	}
+	return providerID, nil
+}
+
+// SendWelcomeEmail sends a welcome email
+func (s *emailService) SendWelcomeEmail(to, name, locale string) error {
+	return s.SendTemplate(to, locale, TemplateWelcome, WelcomeData{Name: name})
+}
+
+// SendPasswordResetEmail sends a password reset email
+func (s *emailService) SendPasswordResetEmail(to, resetLink, locale string) error {
+	return s.SendTemplate(to, locale, TemplatePasswordReset, PasswordResetData{ResetLink: resetLink})
+}
+
+// SendVerificationEmail sends an account verification email
+func (s *emailService) SendVerificationEmail(to, code, locale string) error {
+	return s.SendTemplate(to, locale, TemplateVerificationCode, CodeData{Code: code})
+}
+
+// SendTwoFactorEmail sends a 2FA verification email
+func (s *emailService) SendTwoFactorEmail(to, code, locale string) error {
+	return s.SendTemplate(to, locale, TemplateTwoFactorCode, CodeData{Code: code})
 }
 
 // BuildEmailResponse creates an email response
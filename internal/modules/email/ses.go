@@ -0,0 +1,143 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go_boilerplate/internal/shared/awssig"
+	"go_boilerplate/internal/shared/config"
+)
+
+// sesSimulatorSuccess is the SES mailbox simulator address that accepts everything; sandbox mode
+// sends every message to it
+const sesSimulatorSuccess = "success@simulator.amazonses.com"
+
+// SESSender sends email through the Amazon SES v2 API in EMAIL_SES_REGION, signed with the
+// credentials of the AWS_* environment variables
+type SESSender struct {
+	client      *http.Client
+	credentials awssig.Credentials
+	region      string
+	endpoint    string
+	sandbox     bool
+}
+
+// NewSESSender creates an SES sender
+func NewSESSender(cfg config.EmailConfig, client *http.Client) *SESSender {
+	endpoint := cfg.APIURL
+	if endpoint == "" {
+		endpoint = "https://email." + cfg.SESRegion + ".amazonaws.com"
+	}
+	return &SESSender{
+		client:      client,
+		credentials: awssig.FromEnv(),
+		region:      cfg.SESRegion,
+		endpoint:    strings.TrimSuffix(endpoint, "/"),
+		sandbox:     cfg.Sandbox,
+	}
+}
+
+// Name implements Sender
+func (s *SESSender) Name() string {
+	return ProviderSES
+}
+
+// Send implements Sender
+func (s *SESSender) Send(ctx context.Context, message Message) (string, error) {
+	if !s.credentials.Valid() {
+		return "", fmt.Errorf("%w: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required", ErrUnauthorized)
+	}
+
+	to := message.To
+	if s.sandbox {
+		to = sesSimulatorSuccess
+	}
+	type content struct {
+		Data    string `json:"Data"`
+		Charset string `json:"Charset"`
+	}
+	simple := map[string]any{
+		"Subject": content{Data: message.Subject, Charset: "UTF-8"},
+		"Body":    map[string]any{"Html": content{Data: message.HTML, Charset: "UTF-8"}},
+	}
+	if len(message.Attachments) > 0 {
+		simple["Attachments"] = sesAttachments(message.Attachments)
+	}
+	payload := map[string]any{
+		"FromEmailAddress": message.From,
+		"Destination":      map[string]any{"ToAddresses": []string{to}},
+		"Content":          map[string]any{"Simple": simple},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint+"/v2/email/outbound-emails", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	s.credentials.Sign(req, body, "ses", s.region, time.Now().UTC())
+
+	resp, err := do(s.client, req)
+	if err != nil {
+		return "", err
+	}
+	if resp.status == http.StatusOK {
+		var answer struct {
+			MessageID string `json:"MessageId"`
+		}
+		_ = json.Unmarshal(resp.body, &answer)
+		return answer.MessageID, nil
+	}
+
+	// Errors carry {"message": ...} and X-Amzn-ErrorType: MessageRejected:http://internal.amazon.com/...
+	var answer struct {
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal(resp.body, &answer)
+	code, _, _ := strings.Cut(resp.header.Get("X-Amzn-ErrorType"), ":")
+	return "", &ProviderError{Provider: ProviderSES, Status: resp.status, Code: code, Message: answer.Message, Kind: sesErrorKind(resp.status, code)}
+}
+
+// sesAttachments converts attachments to SES Attachment objects; RawContent is base64, as
+// encoding/json encodes []byte
+func sesAttachments(files []File) []map[string]any {
+	attachments := make([]map[string]any, len(files))
+	for i, file := range files {
+		attachment := map[string]any{
+			"FileName":           file.Filename,
+			"ContentType":        file.ContentType,
+			"RawContent":         file.Content,
+			"ContentDisposition": "ATTACHMENT",
+		}
+		if file.Inline() {
+			attachment["ContentDisposition"] = "INLINE"
+			attachment["ContentId"] = file.ContentID
+		}
+		attachments[i] = attachment
+	}
+	return attachments
+}
+
+// sesErrorKind classifies an SES error code
+func sesErrorKind(status int, code string) error {
+	switch code {
+	case "MessageRejected", "MailFromDomainNotVerifiedException", "BadRequestException":
+		return ErrRejected
+	case "TooManyRequestsException", "LimitExceededException":
+		return ErrRateLimited
+	case "AccessDeniedException", "UnrecognizedClientException", "InvalidSignatureException", "ExpiredTokenException":
+		return ErrUnauthorized
+	case "AccountSuspendedException", "SendingPausedException":
+		return nil
+	default:
+		return kindOfStatus(status)
+	}
+}
@@ -0,0 +1,102 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/mail"
+	"net/textproto"
+	"strings"
+
+	"go_boilerplate/internal/shared/config"
+
+	"github.com/google/uuid"
+	"gopkg.in/gomail.v2"
+)
+
+// SMTPSender sends email through an SMTP server (SMTP_HOST); it has no sandbox mode
+type SMTPSender struct {
+	dialer *gomail.Dialer
+}
+
+// NewSMTPSender creates an SMTP sender
+func NewSMTPSender(cfg config.EmailConfig) *SMTPSender {
+	return &SMTPSender{dialer: gomail.NewDialer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPassword)}
+}
+
+// Name implements Sender
+func (s *SMTPSender) Name() string {
+	return ProviderSMTP
+}
+
+// Send implements Sender and returns the Message-ID it generated. The server's reply code classifies
+// errors: 530/534/535 are ErrUnauthorized, other 5xx replies ErrRejected and 4xx replies temporary.
+func (s *SMTPSender) Send(_ context.Context, message Message) (string, error) {
+	from, err := mail.ParseAddress(message.From)
+	if err != nil {
+		return "", err
+	}
+
+	// SMTP servers don't report an ID, so the message gets one under the sender's domain
+	_, domain, _ := strings.Cut(from.Address, "@")
+	id := uuid.NewString() + "@" + domain
+
+	m := gomail.NewMessage()
+	m.SetHeader("From", message.From)
+	m.SetHeader("To", message.To)
+	m.SetHeader("Subject", message.Subject)
+	m.SetHeader("Message-ID", "<"+id+">")
+	m.SetBody("text/html", message.HTML)
+	for _, file := range message.Attachments {
+		attachFile(m, file)
+	}
+
+	// Sending over the connection rather than with DialAndSend keeps the server's reply in the error
+	conn, err := s.dialer.Dial()
+	if err != nil {
+		return "", smtpError(err)
+	}
+	defer conn.Close()
+	if err := conn.Send(from.Address, []string{message.To}, m); err != nil {
+		return "", smtpError(err)
+	}
+	return id, nil
+}
+
+// attachFile adds an attachment, or an inline image whose Content-ID the body references
+func attachFile(m *gomail.Message, file File) {
+	contentType := mime.FormatMediaType(file.ContentType, map[string]string{"name": file.Filename})
+	if contentType == "" {
+		contentType = file.ContentType
+	}
+	header := map[string][]string{"Content-Type": {contentType}}
+	copyContent := gomail.SetCopyFunc(func(w io.Writer) error {
+		_, err := w.Write(file.Content)
+		return err
+	})
+
+	if file.Inline() {
+		header["Content-ID"] = []string{"<" + file.ContentID + ">"}
+		m.Embed(file.Filename, gomail.SetHeader(header), copyContent)
+		return
+	}
+	m.Attach(file.Filename, gomail.SetHeader(header), copyContent)
+}
+
+// smtpError classifies an SMTP reply; other errors (e.g. connection failures) are returned as is
+func smtpError(err error) error {
+	var reply *textproto.Error
+	if !errors.As(err, &reply) {
+		return err
+	}
+
+	var kind error
+	switch {
+	case reply.Code == 530 || reply.Code == 534 || reply.Code == 535:
+		kind = ErrUnauthorized
+	case reply.Code >= 500:
+		kind = ErrRejected
+	}
+	return &ProviderError{Provider: ProviderSMTP, Status: reply.Code, Message: reply.Msg, Kind: kind}
+}
@@ -0,0 +1,135 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"go_boilerplate/internal/shared/i18n"
+)
+
+//go:embed templates/*.html templates/layouts/*.html templates/partials/*.html
+var templatesFS embed.FS
+
+// Built-in templates, named after their file in templates/
+const (
+	TemplateWelcome          = "welcome"
+	TemplatePasswordReset    = "password_reset"
+	TemplateVerificationCode = "verification_code"
+	TemplateTwoFactorCode    = "2fa_code"
+)
+
+// WelcomeData is the data of the welcome template
+type WelcomeData struct {
+	Name string
+}
+
+// PasswordResetData is the data of the password_reset template
+type PasswordResetData struct {
+	ResetLink string
+}
+
+// CodeData is the data of the verification_code and 2fa_code templates
+type CodeData struct {
+	Code string
+}
+
+// Registry holds the email templates: every templates/<name>.html is registered as <name>, parsed
+// together with templates/layouts and templates/partials. A page defines "subject", "heading",
+// "accent" (header colour) and "content", then renders {{template "layout" .}}; pages are parsed
+// separately so they can all define the same blocks. In every template, {{t "..."}} translates
+// text into the recipient's locale and {{locale}} returns it.
+type Registry struct {
+	pages map[string]*template.Template
+}
+
+// NewRegistry parses the embedded templates
+func NewRegistry() (*Registry, error) {
+	return newRegistry(templatesFS)
+}
+
+// newRegistry parses the templates of fsys
+func newRegistry(fsys fs.FS) (*Registry, error) {
+	files, err := fs.Glob(fsys, "templates/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	// `t` and `locale` are rebound to the recipient's locale on render
+	funcs := template.FuncMap{
+		"t":      func(msg string) string { return msg },
+		"locale": func() string { return i18n.Default() },
+	}
+	base, err := template.New("").Funcs(funcs).ParseFS(fsys, "templates/layouts/*.html", "templates/partials/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	registry := &Registry{pages: make(map[string]*template.Template, len(files))}
+	for _, file := range files {
+		page, err := base.Clone()
+		if err == nil {
+			page, err = page.ParseFS(fsys, file)
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(path.Base(file), ".html")
+		for _, block := range []string{"subject", "heading", "accent", "content"} {
+			if page.Lookup(block) == nil {
+				return nil, fmt.Errorf("email template %s does not define %q", file, block)
+			}
+		}
+		registry.pages[name] = page
+	}
+	return registry, nil
+}
+
+// Names returns the registered template names, sorted
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.pages))
+	for name := range r.pages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Has reports whether a template is registered as name
+func (r *Registry) Has(name string) bool {
+	_, ok := r.pages[name]
+	return ok
+}
+
+// Render renders the subject and HTML body of a template in locale
+func (r *Registry) Render(name, locale string, data any) (subject, body string, err error) {
+	page, ok := r.pages[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown email template %q", name)
+	}
+
+	tmpl, err := page.Clone()
+	if err != nil {
+		return "", "", err
+	}
+	tmpl.Funcs(template.FuncMap{
+		"t":      func(msg string) string { return i18n.Translate(locale, msg) },
+		"locale": func() string { return locale },
+	})
+
+	var subjectBuf, bodyBuf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subjectBuf, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&bodyBuf, name+".html", data); err != nil {
+		return "", "", err
+	}
+	// The subject is plain text, not HTML
+	return html.UnescapeString(strings.TrimSpace(subjectBuf.String())), strings.TrimSpace(bodyBuf.String()), nil
+}
@@ -0,0 +1,8 @@
+package dto
+
+// SetFeatureFlagRequest represents an admin override of a feature flag
+type SetFeatureFlagRequest struct {
+	Enabled    bool     `json:"enabled"`                                       // On for everyone
+	Percentage int      `json:"percentage" validate:"gte=0,lte=100"`           // On for this share of users
+	Users      []string `json:"users" validate:"omitempty,max=1000,dive,uuid"` // User IDs the flag is always on for
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+// FeatureFlagOverrideResponse represents a runtime override of a feature flag
+type FeatureFlagOverrideResponse struct {
+	Enabled    bool      `json:"enabled"`
+	Percentage int       `json:"percentage"`
+	Users      []string  `json:"users,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// FeatureFlagResponse represents a feature flag: its FEATURE_FLAGS default and its override, if any
+type FeatureFlagResponse struct {
+	Name     string                       `json:"name"`
+	Default  bool                         `json:"default"`
+	Override *FeatureFlagOverrideResponse `json:"override,omitempty"`
+}
+
+// FeatureFlagsResponse represents the list of feature flags
+type FeatureFlagsResponse struct {
+	Flags []FeatureFlagResponse `json:"flags"`
+}
@@ -0,0 +1,115 @@
+package featureflag
+
+import (
+	"go_boilerplate/internal/modules/featureflag/dto"
+	"go_boilerplate/internal/shared/flags"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FeatureFlagHandler defines the interface for feature flag HTTP handlers
+type FeatureFlagHandler interface {
+	GetFlags(c *fiber.Ctx) error
+	SetFlag(c *fiber.Ctx) error
+	DeleteFlag(c *fiber.Ctx) error
+}
+
+// featureFlagHandler implements FeatureFlagHandler interface
+type featureFlagHandler struct {
+	flags *flags.Flags
+}
+
+// NewFeatureFlagHandler creates a new feature flag handler
+func NewFeatureFlagHandler(features *flags.Flags) FeatureFlagHandler {
+	return &featureFlagHandler{flags: features}
+}
+
+// GetFlags lists feature flags
+// @Summary Admin: List feature flags
+// @Description List every flag enabled in FEATURE_FLAGS or overridden at runtime, with its override (requires feature_flags.read).
+// @Tags Feature Flags
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.APIResponse{data=dto.FeatureFlagsResponse} "Feature flags retrieved"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Failure 403 {object} utils.ProblemDetails "Forbidden"
+// @Router /feature-flags [get]
+func (h *featureFlagHandler) GetFlags(c *fiber.Ctx) error {
+	states, err := h.flags.List(c.UserContext())
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get feature flags", err)
+	}
+
+	response := dto.FeatureFlagsResponse{Flags: make([]dto.FeatureFlagResponse, len(states))}
+	for i, state := range states {
+		response.Flags[i] = toFeatureFlagResponse(state)
+	}
+	return utils.SuccessResponse(c, fiber.StatusOK, response, "Feature flags retrieved successfully")
+}
+
+// SetFlag overrides a feature flag
+// @Summary Admin: Override feature flag
+// @Description Turn a flag on for everyone, a percentage of users or listed users, on every instance within FEATURE_FLAGS_CACHE_TTL (requires feature_flags.manage). Needs Redis.
+// @Tags Feature Flags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Flag name (lowercase letters, digits, '.', '_', '-')"
+// @Param request body dto.SetFeatureFlagRequest true "Override"
+// @Success 200 {object} utils.APIResponse{data=dto.FeatureFlagResponse} "Feature flag updated"
+// @Failure 400 {object} utils.ProblemDetails "Invalid request"
+// @Failure 503 {object} utils.ProblemDetails "Redis unavailable"
+// @Router /feature-flags/{name} [put]
+func (h *featureFlagHandler) SetFlag(c *fiber.Ctx) error {
+	req := c.Locals("validatedBody").(*dto.SetFeatureFlagRequest)
+
+	state, err := h.flags.Set(c.UserContext(), flags.Flag{
+		Name:       c.Params("name"),
+		Enabled:    req.Enabled,
+		Percentage: req.Percentage,
+		Users:      req.Users,
+	})
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to update feature flag", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, toFeatureFlagResponse(*state), "Feature flag updated successfully")
+}
+
+// DeleteFlag removes the override of a feature flag
+// @Summary Admin: Remove feature flag override
+// @Description Remove a runtime override so FEATURE_FLAGS decides again (requires feature_flags.manage).
+// @Tags Feature Flags
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Flag name"
+// @Success 200 {object} utils.APIResponse "Override removed"
+// @Failure 404 {object} utils.ProblemDetails "No override"
+// @Failure 503 {object} utils.ProblemDetails "Redis unavailable"
+// @Router /feature-flags/{name} [delete]
+func (h *featureFlagHandler) DeleteFlag(c *fiber.Ctx) error {
+	removed, err := h.flags.Delete(c.UserContext(), c.Params("name"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to remove feature flag override", err)
+	}
+	if !removed {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "Feature flag override not found", nil)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, nil, "Feature flag override removed successfully")
+}
+
+// toFeatureFlagResponse converts a flag state to its response
+func toFeatureFlagResponse(state flags.State) dto.FeatureFlagResponse {
+	response := dto.FeatureFlagResponse{Name: state.Name, Default: state.Default}
+	if state.Override != nil {
+		response.Override = &dto.FeatureFlagOverrideResponse{
+			Enabled:    state.Override.Enabled,
+			Percentage: state.Override.Percentage,
+			Users:      state.Override.Users,
+			UpdatedAt:  state.Override.UpdatedAt,
+		}
+	}
+	return response
+}
@@ -0,0 +1,16 @@
+package featureflag
+
+import "go_boilerplate/internal/shared/permission"
+
+// Feature flag permissions
+const (
+	PermFeatureFlagsRead   = "feature_flags.read"
+	PermFeatureFlagsManage = "feature_flags.manage"
+)
+
+func init() {
+	permission.Register(
+		permission.Permission{Name: PermFeatureFlagsRead, Description: "View feature flags and their overrides"},
+		permission.Permission{Name: PermFeatureFlagsManage, Description: "Override feature flags at runtime"},
+	)
+}
@@ -0,0 +1,29 @@
+package featureflag
+
+import (
+	"go_boilerplate/internal/modules/featureflag/dto"
+	"go_boilerplate/internal/shared/apiversion"
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/flags"
+	"go_boilerplate/internal/shared/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// RegisterRoutes registers the feature flag admin routes
+// Overrides are stored in Redis, so they apply to the evaluator of every module and instance
+func RegisterRoutes(app *fiber.App, cfg *config.Config, logger *logrus.Logger, features *flags.Flags) {
+	flagHandler := NewFeatureFlagHandler(features)
+
+	// Create API route group
+	api := apiversion.Group(app, "v1")
+	featureFlags := api.Group("/feature-flags")
+	featureFlags.Use(middleware.JWTAuth(cfg))
+	canRead := middleware.RequirePermission(cfg, PermFeatureFlagsRead)
+	canManage := middleware.RequirePermission(cfg, PermFeatureFlagsManage)
+
+	featureFlags.Get("/", canRead, flagHandler.GetFlags)                                                               // List flags and overrides
+	featureFlags.Put("/:name", canManage, middleware.BodyValidator(&dto.SetFeatureFlagRequest{}), flagHandler.SetFlag) // Override a flag
+	featureFlags.Delete("/:name", canManage, flagHandler.DeleteFlag)                                                   // Remove an override
+}
@@ -0,0 +1,7 @@
+package dto
+
+// SetLogLevelRequest represents a runtime change of the log level
+type SetLogLevelRequest struct {
+	Level  string `json:"level" validate:"required,oneof=debug info warn error"` // New level
+	Module string `json:"module" validate:"omitempty,max=50"`                    // Module to change (e.g. auth); empty changes every logger
+}
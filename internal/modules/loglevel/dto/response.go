@@ -0,0 +1,14 @@
+package dto
+
+// ModuleLogLevelResponse represents the level of a module logger
+type ModuleLogLevelResponse struct {
+	Name       string `json:"name"`
+	Level      string `json:"level"`
+	Overridden bool   `json:"overridden"` // False while the module follows the root level
+}
+
+// LogLevelResponse represents the log levels of the instance
+type LogLevelResponse struct {
+	Level   string                   `json:"level"`
+	Modules []ModuleLogLevelResponse `json:"modules"`
+}
@@ -0,0 +1,127 @@
+package loglevel
+
+import (
+	"context"
+
+	"go_boilerplate/internal/modules/loglevel/dto"
+	"go_boilerplate/internal/shared/apperror"
+	"go_boilerplate/internal/shared/events"
+	"go_boilerplate/internal/shared/middleware"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// EventLogLevelChanged is published when an admin changes a log level, so every instance applies it
+const EventLogLevelChanged = "log_level.changed"
+
+// ErrUnknownLogModule is returned when changing the level of a module without a logger
+var ErrUnknownLogModule = apperror.New(apperror.ErrBadRequest, "unknown log module").WithCode("unknown_log_module")
+
+// LogLevelChanged is the payload of EventLogLevelChanged
+type LogLevelChanged struct {
+	Level  string `json:"level"`
+	Module string `json:"module,omitempty"`
+}
+
+// LogLevelHandler defines the interface for runtime log level HTTP handlers
+type LogLevelHandler interface {
+	GetLogLevel(c *fiber.Ctx) error
+	SetLogLevel(c *fiber.Ctx) error
+}
+
+// logLevelHandler implements LogLevelHandler interface
+type logLevelHandler struct {
+	logs *utils.LogLevels
+	bus  events.EventBus
+}
+
+// NewLogLevelHandler creates a new log level handler; changes are published on bus (nil applies
+// them to this instance only)
+func NewLogLevelHandler(logs *utils.LogLevels, bus events.EventBus) LogLevelHandler {
+	return &logLevelHandler{logs: logs, bus: bus}
+}
+
+// GetLogLevel returns the log levels of the instance
+// @Summary Admin: Get log levels
+// @Description Get the root log level and the level of every module logger of the instance serving the request (super_admin only).
+// @Tags Log Levels
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.APIResponse{data=dto.LogLevelResponse} "Log levels retrieved"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Failure 403 {object} utils.ProblemDetails "Forbidden"
+// @Router /admin/log-level [get]
+func (h *logLevelHandler) GetLogLevel(c *fiber.Ctx) error {
+	return utils.SuccessResponse(c, fiber.StatusOK, h.response(), "Log levels retrieved successfully")
+}
+
+// SetLogLevel changes the log level at runtime
+// @Summary Admin: Set log level
+// @Description Change the level of every logger, or of one module's logger, without a restart (super_admin only). Setting the root level drops module overrides. The change is broadcast on the event bus, so with EVENT_BUS_DRIVER=redis every instance applies it; it lasts until the instance restarts or LOG_LEVEL is reloaded.
+// @Tags Log Levels
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.SetLogLevelRequest true "Level and optional module"
+// @Success 200 {object} utils.APIResponse{data=dto.LogLevelResponse} "Log level updated"
+// @Failure 400 {object} utils.ProblemDetails "Invalid request or unknown module"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Failure 403 {object} utils.ProblemDetails "Forbidden"
+// @Router /admin/log-level [put]
+func (h *logLevelHandler) SetLogLevel(c *fiber.Ctx) error {
+	req := c.Locals("validatedBody").(*dto.SetLogLevelRequest)
+
+	change := LogLevelChanged{Level: req.Level, Module: req.Module}
+	if err := apply(h.logs, change); err != nil {
+		modules := h.logs.Modules()
+		names := make([]string, len(modules))
+		for i, module := range modules {
+			names[i] = module.Name
+		}
+		return utils.ProblemResponse(c, fiber.StatusBadRequest, "Unknown log module", ErrUnknownLogModule, map[string]any{
+			"modules": names,
+		})
+	}
+
+	userID, _ := middleware.GetUserIDFromContext(c)
+	h.logs.Root().WithFields(logrus.Fields{
+		"log_level":  change.Level,
+		"log_module": change.Module,
+		"user_id":    userID,
+	}).Warn("Log level changed")
+
+	if h.bus != nil {
+		_ = h.bus.Publish(context.WithoutCancel(c.UserContext()), EventLogLevelChanged, change)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, h.response(), "Log level updated successfully")
+}
+
+// response lists the log levels of the instance
+func (h *logLevelHandler) response() dto.LogLevelResponse {
+	modules := h.logs.Modules()
+	response := dto.LogLevelResponse{
+		Level:   utils.LogLevelName(h.logs.Root().GetLevel()),
+		Modules: make([]dto.ModuleLogLevelResponse, len(modules)),
+	}
+	for i, module := range modules {
+		response.Modules[i] = dto.ModuleLogLevelResponse{
+			Name:       module.Name,
+			Level:      utils.LogLevelName(module.Level),
+			Overridden: module.Overridden,
+		}
+	}
+	return response
+}
+
+// apply changes the root level, or the level of change.Module
+func apply(logs *utils.LogLevels, change LogLevelChanged) error {
+	level := utils.ParseLogLevel(change.Level)
+	if change.Module == "" {
+		logs.SetLevel(level)
+		return nil
+	}
+	return logs.SetModuleLevel(change.Module, level)
+}
@@ -0,0 +1,44 @@
+package loglevel
+
+import (
+	"context"
+	"errors"
+
+	"go_boilerplate/internal/modules/loglevel/dto"
+	"go_boilerplate/internal/shared/apiversion"
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/events"
+	"go_boilerplate/internal/shared/middleware"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RegisterRoutes registers the runtime log level routes
+// Changes are applied to every instance through the event bus (nil applies them locally only)
+func RegisterRoutes(app *fiber.App, cfg *config.Config, logs *utils.LogLevels, bus events.EventBus) {
+	logLevelHandler := NewLogLevelHandler(logs, bus)
+
+	// Apply changes made on any instance; a module this instance doesn't have is skipped
+	if bus != nil {
+		bus.Subscribe(EventLogLevelChanged, func(_ context.Context, event events.Event) error {
+			var change LogLevelChanged
+			if err := event.Decode(&change); err != nil {
+				return err
+			}
+			if err := apply(logs, change); err != nil && !errors.Is(err, utils.ErrUnknownLogModule) {
+				return err
+			}
+			return nil
+		})
+	}
+
+	// Create API route group - SuperAdmin only
+	api := apiversion.Group(app, "v1")
+	logLevel := api.Group("/admin/log-level")
+	logLevel.Use(middleware.JWTAuth(cfg))
+	logLevel.Use(middleware.RequireRole(cfg, "super_admin"))
+
+	logLevel.Get("/", logLevelHandler.GetLogLevel)                                                      // Get log levels
+	logLevel.Put("/", middleware.BodyValidator(&dto.SetLogLevelRequest{}), logLevelHandler.SetLogLevel) // Set the root or a module level
+}
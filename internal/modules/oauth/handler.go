@@ -46,7 +46,7 @@ func (h *oauthHandler) GoogleLogin(c *fiber.Ctx) error {
 // @Produce json
 // @Param code query string true "Authorization code from Google"
 // @Success 200 {object} utils.APIResponse "Login successful"
-// @Failure 400 {object} utils.APIResponse "Authentication failed"
+// @Failure 400 {object} utils.ProblemDetails "Authentication failed"
 // @Router /oauth/google/callback [get]
 func (h *oauthHandler) GoogleCallback(c *fiber.Ctx) error {
 	// Get authorization code
@@ -56,7 +56,7 @@ func (h *oauthHandler) GoogleCallback(c *fiber.Ctx) error {
 	}
 
 	// Handle OAuth callback
-	response, err := h.service.HandleGoogleCallback(code)
+	response, err := h.service.HandleGoogleCallback(c.UserContext(), code)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "OAuth authentication failed", err)
 	}
@@ -86,7 +86,7 @@ func (h *oauthHandler) GitHubLogin(c *fiber.Ctx) error {
 // @Produce json
 // @Param code query string true "Authorization code from GitHub"
 // @Success 200 {object} utils.APIResponse "Login successful"
-// @Failure 400 {object} utils.APIResponse "Authentication failed"
+// @Failure 400 {object} utils.ProblemDetails "Authentication failed"
 // @Router /oauth/github/callback [get]
 func (h *oauthHandler) GitHubCallback(c *fiber.Ctx) error {
 	// Get authorization code
@@ -96,7 +96,7 @@ func (h *oauthHandler) GitHubCallback(c *fiber.Ctx) error {
 	}
 
 	// Handle OAuth callback
-	response, err := h.service.HandleGitHubCallback(code)
+	response, err := h.service.HandleGitHubCallback(c.UserContext(), code)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "OAuth authentication failed", err)
 	}
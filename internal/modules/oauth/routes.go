@@ -1,37 +1,44 @@
 package oauth
 
 import (
-	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/modules/audit"
+	"go_boilerplate/internal/modules/role"
 	"go_boilerplate/internal/modules/user"
-	"go_boilerplate/internal/modules/oauth/dto"
+	"go_boilerplate/internal/shared/apiversion"
+	"go_boilerplate/internal/shared/cache"
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/events"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 // RegisterRoutes registers all OAuth-related routes
-func RegisterRoutes(app *fiber.App, db *gorm.DB, cfg *config.Config, logger *logrus.Logger) {
-	// Auto migrate OAuth account model
-	db.AutoMigrate(&dto.OAuthAccount{})
+func RegisterRoutes(app *fiber.App, db *gorm.DB, cfg *config.Config, logger *logrus.Logger, redisClient *redis.Client, store cache.Cache, bus events.EventBus) {
+	if !cfg.OAuth.Enabled() {
+		logger.Info("✗ OAuth routes skipped (no provider enabled)")
+		return
+	}
 
 	// Initialize user service (OAuth service depends on it)
+	// New OAuth users are assigned the default role, so the service needs the role repository
 	userRepo := user.NewUserRepository(db)
-	userService := user.NewUserService(userRepo)
+	userService := user.NewUserServiceWithRole(userRepo, role.NewRoleRepository(db), nil, user.NewProfileCache(store, cfg.RBAC.ProfileCacheTTL), cache.NewResponseCache(redisClient, cfg.Cache), bus, audit.NewTrail(db, cfg.Audit, logger), cfg.RBAC.DefaultRoleSlug)
 
 	// Initialize OAuth service
-	oauthService := NewOAuthService(db, cfg, userService)
+	oauthService := NewOAuthService(db, cfg, userService, logger)
 
 	// Initialize OAuth handler
 	oauthHandler := NewOAuthHandler(oauthService)
 
-	// Create API route group
-	api := app.Group("/api/v1")
+	// Create OAuth route group
+	oauth := apiversion.Group(app, "v1").Group("/oauth")
 
 	// Register Google OAuth routes if enabled
 	if cfg.OAuth.Google.Enabled {
 		logger.Info("✓ Google OAuth routes registered (enabled)")
-		oauth := api.Group("/oauth")
 		oauth.Get("/google", oauthHandler.GoogleLogin)
 		oauth.Get("/google/callback", oauthHandler.GoogleCallback)
 	} else {
@@ -41,7 +48,6 @@ func RegisterRoutes(app *fiber.App, db *gorm.DB, cfg *config.Config, logger *log
 	// Register GitHub OAuth routes if enabled
 	if cfg.OAuth.GitHub.Enabled {
 		logger.Info("✓ GitHub OAuth routes registered (enabled)")
-		oauth := api.Group("/oauth")
 		oauth.Get("/github", oauthHandler.GitHubLogin)
 		oauth.Get("/github/callback", oauthHandler.GitHubCallback)
 	} else {
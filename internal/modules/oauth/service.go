@@ -2,17 +2,25 @@ package oauth
 
 import (
 	"context"
-	"errors"
+	"net/http"
+	"time"
 
 	authdto "go_boilerplate/internal/modules/auth/dto"
 	"go_boilerplate/internal/modules/email"
 	"go_boilerplate/internal/modules/oauth/dto"
 	"go_boilerplate/internal/modules/user"
 	userdto "go_boilerplate/internal/modules/user/dto"
+	"go_boilerplate/internal/shared/apperror"
 	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/database/encryption"
+	"go_boilerplate/internal/shared/database/replica"
+	"go_boilerplate/internal/shared/i18n"
+	"go_boilerplate/internal/shared/observability"
+	"go_boilerplate/internal/shared/tenant"
 	"go_boilerplate/internal/shared/utils"
 
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/github"
 	"golang.org/x/oauth2/google"
@@ -22,9 +30,9 @@ import (
 // OAuthService defines the interface for OAuth operations
 type OAuthService interface {
 	GetGoogleAuthURL() string
-	HandleGoogleCallback(code string) (*authdto.AuthResponse, error)
+	HandleGoogleCallback(ctx context.Context, code string) (*authdto.AuthResponse, error)
 	GetGitHubAuthURL() string
-	HandleGitHubCallback(code string) (*authdto.AuthResponse, error)
+	HandleGitHubCallback(ctx context.Context, code string) (*authdto.AuthResponse, error)
 }
 
 // oauthService implements OAuthService interface
@@ -33,11 +41,13 @@ type oauthService struct {
 	cfg          *config.Config
 	userService  user.UserService
 	emailService email.EmailService
+	logger       *logrus.Logger
 	jwtManager   *utils.JWTManager
+	httpClient   *http.Client
 }
 
 // NewOAuthService creates a new OAuth service
-func NewOAuthService(db *gorm.DB, cfg *config.Config, userService user.UserService) OAuthService {
+func NewOAuthService(db *gorm.DB, cfg *config.Config, userService user.UserService, logger *logrus.Logger) OAuthService {
 	jwtManager := utils.NewJWTManager(
 		cfg.JWT.Secret,
 		cfg.JWT.AccessExpiry,
@@ -45,20 +55,18 @@ func NewOAuthService(db *gorm.DB, cfg *config.Config, userService user.UserServi
 		cfg.JWT.Issuer,
 	)
 
-	// Initialize email service (optional, will check before sending)
-	var emailService email.EmailService
-	if cfg.Email.Enabled {
-		// Import logger here - we'll get it from context or create a new one
-		// For now, we'll initialize without logger
-		emailService = email.NewEmailService(cfg, nil)
-	}
+	// Initialize email service; emails are queued for the send-queued-email job, and dropped while
+	// email is disabled
+	emailService := email.NewQueuedEmailService(cfg, db, logger)
 
 	return &oauthService{
 		db:           db,
 		cfg:          cfg,
 		userService:  userService,
 		emailService: emailService,
+		logger:       logger,
 		jwtManager:   jwtManager,
+		httpClient:   utils.NewHTTPClient(10 * time.Second),
 	}
 }
 
@@ -79,7 +87,10 @@ func (s *oauthService) GetGoogleAuthURL() string {
 }
 
 // HandleGoogleCallback handles Google OAuth callback
-func (s *oauthService) HandleGoogleCallback(code string) (*authdto.AuthResponse, error) {
+func (s *oauthService) HandleGoogleCallback(ctx context.Context, code string) (*authdto.AuthResponse, error) {
+	ctx, span := observability.StartSpan(ctx, "oauth.HandleGoogleCallback")
+	defer span.End()
+
 	// Exchange code for token
 	oauth2Config := &oauth2.Config{
 		ClientID:     s.cfg.OAuth.Google.ClientID,
@@ -88,9 +99,9 @@ func (s *oauthService) HandleGoogleCallback(code string) (*authdto.AuthResponse,
 		Endpoint:     google.Endpoint,
 	}
 
-	token, err := oauth2Config.Exchange(context.Background(), code)
+	token, err := oauth2Config.Exchange(s.clientContext(ctx), code)
 	if err != nil {
-		return nil, errors.New("failed to exchange token")
+		return nil, apperror.New(apperror.ErrBadRequest, "failed to exchange token").WithCode("oauth_exchange_failed")
 	}
 
 	// Get user info from Google
@@ -103,7 +114,7 @@ func (s *oauthService) HandleGoogleCallback(code string) (*authdto.AuthResponse,
 		Provider: "google",
 	}
 
-	return s.handleOAuthUser(userInfo, token)
+	return s.handleOAuthUser(ctx, userInfo, token, i18n.FromContext(ctx))
 }
 
 // GetGitHubAuthURL returns the GitHub OAuth URL
@@ -120,7 +131,10 @@ func (s *oauthService) GetGitHubAuthURL() string {
 }
 
 // HandleGitHubCallback handles GitHub OAuth callback
-func (s *oauthService) HandleGitHubCallback(code string) (*authdto.AuthResponse, error) {
+func (s *oauthService) HandleGitHubCallback(ctx context.Context, code string) (*authdto.AuthResponse, error) {
+	ctx, span := observability.StartSpan(ctx, "oauth.HandleGitHubCallback")
+	defer span.End()
+
 	// Exchange code for token
 	oauth2Config := &oauth2.Config{
 		ClientID:     s.cfg.OAuth.GitHub.ClientID,
@@ -129,9 +143,9 @@ func (s *oauthService) HandleGitHubCallback(code string) (*authdto.AuthResponse,
 		Endpoint:     github.Endpoint,
 	}
 
-	token, err := oauth2Config.Exchange(context.Background(), code)
+	token, err := oauth2Config.Exchange(s.clientContext(ctx), code)
 	if err != nil {
-		return nil, errors.New("failed to exchange token")
+		return nil, apperror.New(apperror.ErrBadRequest, "failed to exchange token").WithCode("oauth_exchange_failed")
 	}
 
 	// Get user info from GitHub
@@ -144,14 +158,19 @@ func (s *oauthService) HandleGitHubCallback(code string) (*authdto.AuthResponse,
 		Provider: "github",
 	}
 
-	return s.handleOAuthUser(userInfo, token)
+	return s.handleOAuthUser(ctx, userInfo, token, i18n.FromContext(ctx))
+}
+
+// clientContext makes the oauth2 library use the shared, instrumented HTTP client
+func (s *oauthService) clientContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, s.httpClient)
 }
 
-// handleOAuthUser handles OAuth user login/registration
-func (s *oauthService) handleOAuthUser(userInfo *dto.OAuthUserInfo, token *oauth2.Token) (*authdto.AuthResponse, error) {
+// handleOAuthUser handles OAuth user login/registration; locale is used for the welcome email
+func (s *oauthService) handleOAuthUser(ctx context.Context, userInfo *dto.OAuthUserInfo, token *oauth2.Token, locale string) (*authdto.AuthResponse, error) {
 	// Check if OAuth account exists
 	var oauthAccount dto.OAuthAccount
-	err := s.db.Where("provider = ? AND provider_id = ?", userInfo.Provider, userInfo.ID).First(&oauthAccount).Error
+	err := s.db.WithContext(ctx).Where("provider = ? AND provider_id = ?", userInfo.Provider, userInfo.ID).First(&oauthAccount).Error
 
 	var userID uuid.UUID
 	isNewUser := false
@@ -161,12 +180,12 @@ func (s *oauthService) handleOAuthUser(userInfo *dto.OAuthUserInfo, token *oauth
 		userID = oauthAccount.UserID
 
 		// Update token
-		oauthAccount.AccessToken = token.AccessToken
+		oauthAccount.AccessToken = encryption.EncryptedString(token.AccessToken)
 		if token.RefreshToken != "" {
-			oauthAccount.RefreshToken = token.RefreshToken
+			oauthAccount.RefreshToken = encryption.EncryptedString(token.RefreshToken)
 		}
 		oauthAccount.ExpiresAt = token.Expiry
-		s.db.Save(&oauthAccount)
+		s.db.WithContext(ctx).Save(&oauthAccount)
 	} else {
 		// OAuth account doesn't exist, create new user
 		isNewUser = true
@@ -177,29 +196,31 @@ func (s *oauthService) handleOAuthUser(userInfo *dto.OAuthUserInfo, token *oauth
 			Password: uuid.New().String(), // Random password for OAuth users
 		}
 
-		createdUser, err := s.userService.CreateUser(createUserReq)
+		createdUser, err := s.userService.CreateUser(ctx, createUserReq)
 		if err != nil {
 			// User might already exist with this email, link accounts
 			// For simplicity, we'll return an error here
-			return nil, errors.New("failed to create user")
+			return nil, apperror.New(apperror.ErrInternal, "failed to create user")
 		}
 
 		userID = createdUser.ID
+		ctx = replica.WithPrimary(ctx) // The new user isn't on the read replicas yet
+		observability.RecordRegistration(userInfo.Provider)
 
 		// Create OAuth account
 		oauthAccount = dto.OAuthAccount{
 			UserID:       userID,
 			Provider:     userInfo.Provider,
 			ProviderID:   userInfo.ID,
-			AccessToken:  token.AccessToken,
-			RefreshToken: token.RefreshToken,
+			AccessToken:  encryption.EncryptedString(token.AccessToken),
+			RefreshToken: encryption.EncryptedString(token.RefreshToken),
 			ExpiresAt:    token.Expiry,
 		}
-		s.db.Create(&oauthAccount)
+		s.db.WithContext(ctx).Create(&oauthAccount)
 	}
 
 	// Send welcome email if enabled and this is a new user
-	if isNewUser && s.emailService != nil && s.cfg.Email.Enabled {
+	if isNewUser {
 		// Check if welcome email is enabled for this provider
 		sendWelcomeEmail := false
 		if userInfo.Provider == "google" && s.cfg.OAuth.Google.SendWelcomeEmail {
@@ -209,36 +230,29 @@ func (s *oauthService) handleOAuthUser(userInfo *dto.OAuthUserInfo, token *oauth
 		}
 
 		if sendWelcomeEmail {
-			// Send welcome email asynchronously (don't block the response)
-			go func() {
-				if err := s.emailService.SendWelcomeEmail(userInfo.Email, userInfo.Name); err != nil {
-					// Log error but don't fail the OAuth flow
-					// In production, you might want to use proper logger
-					println("Failed to send welcome email:", err.Error())
-				}
-			}()
+			// Queue the welcome email; failing to do so doesn't fail the OAuth flow
+			if err := s.emailService.SendWelcomeEmail(userInfo.Email, userInfo.Name, locale); err != nil {
+				s.logger.Errorf("Failed to queue welcome email for %s: %v", userInfo.Email, err)
+			}
 		}
 	}
 
 	// Get user profile with role information
-	userProfile, err := s.userService.GetProfileWithRole(userID)
+	userProfile, err := s.userService.GetProfileWithRole(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Generate JWT tokens with role information
-	roleSlug := ""
-	permissions := []string{}
-	if userProfile.Role != nil {
-		roleSlug = userProfile.Role.Slug
-		permissions = userProfile.Role.Permissions
-	}
-
-	accessToken, refreshToken, err := s.jwtManager.GenerateTokenPair(userID, userProfile.Email, roleSlug, permissions)
+	accessToken, refreshToken, err := s.jwtManager.GenerateTokenPair(userID, userProfile.Email, userProfile.RoleSlugs(), userProfile.Permissions(), tenant.ClaimFromContext(ctx))
 	if err != nil {
-		return nil, errors.New("failed to generate tokens")
+		return nil, apperror.New(apperror.ErrInternal, "failed to generate tokens")
 	}
 
+	// Record the login (best-effort, must not fail authentication)
+	_ = s.userService.RecordLogin(ctx, userID)
+	observability.RecordLogin(userInfo.Provider, observability.LoginSucceeded)
+
 	// Calculate expires in
 	expiresIn := int64(s.cfg.JWT.AccessExpiry.Seconds())
 
@@ -6,17 +6,28 @@ import (
 
 // CreateRoleRequest represents a request to create a role
 type CreateRoleRequest struct {
-	Name        string   `json:"name" validate:"required,min=3,max=100"`
-	Slug        string   `json:"slug" validate:"required,min=2,max=50,alphanum"`
-	Permissions []string `json:"permissions" validate:"required,min=1"`
-	Description string   `json:"description" validate:"omitempty,max=500"`
+	Name        string     `json:"name" validate:"required,min=3,max=100"`
+	Slug        string     `json:"slug" validate:"required,min=2,max=50,alphanum"`
+	Permissions []string   `json:"permissions" validate:"required,min=1,dive,permission"`
+	Description string     `json:"description" validate:"omitempty,max=500"`
+	ParentID    *uuid.UUID `json:"parent_id" validate:"omitempty"` // Inherit permissions from this role
 }
 
 // UpdateRoleRequest represents a request to update a role
 type UpdateRoleRequest struct {
-	Name        string   `json:"name" validate:"omitempty,min=3,max=100"`
-	Permissions []string `json:"permissions" validate:"omitempty,min=1"`
-	Description string   `json:"description" validate:"omitempty,max=500"`
+	Name        string     `json:"name" validate:"omitempty,min=3,max=100"`
+	Permissions []string   `json:"permissions" validate:"omitempty,min=1,dive,permission"`
+	Description string     `json:"description" validate:"omitempty,max=500"`
+	ParentID    *uuid.UUID `json:"parent_id" validate:"omitempty"`                 // Inherit permissions from this role
+	ClearParent bool       `json:"clear_parent" validate:"excluded_with=ParentID"` // Stop inheriting from the current parent
+	Version     *int       `json:"version" validate:"omitempty,gte=1"`             // Optional: version the client read; 409 if the role changed since
+}
+
+// CloneRoleRequest represents a request to copy a role under a new name and slug
+type CloneRoleRequest struct {
+	Name        string `json:"name" validate:"required,min=3,max=100"`
+	Slug        string `json:"slug" validate:"required,min=2,max=50,alphanum"`
+	Description string `json:"description" validate:"omitempty,max=500"` // Defaults to the source role's description
 }
 
 // AssignRoleRequest represents a request to assign a role to a user
@@ -10,29 +10,58 @@ import (
 
 // RoleResponse represents a role response
 type RoleResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Slug        string    `json:"slug"`
-	Permissions []string  `json:"permissions"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Slug        string     `json:"slug"`
+	Permissions []string   `json:"permissions"`
+	Description string     `json:"description"`
+	ParentID    *uuid.UUID `json:"parent_id,omitempty"`
+	IsSystem    bool       `json:"is_system"`
+	Version     int        `json:"version"` // Send back in updates to detect concurrent changes
+	// EffectivePermissions includes permissions inherited from parent roles (only set when fetching a single role)
+	EffectivePermissions []string  `json:"effective_permissions,omitempty"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
 }
 
 // RolesResponse represents a paginated list of roles
 type RolesResponse struct {
-	Roles []RoleResponse     `json:"roles"`
+	Roles []RoleResponse       `json:"roles"`
 	Meta  utils.PaginationMeta `json:"meta"`
 }
 
+// PageItems returns the listed roles (utils.Paginated)
+func (r RolesResponse) PageItems() any {
+	return r.Roles
+}
+
+// PageMeta returns the pagination metadata (utils.Paginated)
+func (r RolesResponse) PageMeta() utils.PaginationMeta {
+	return r.Meta
+}
+
+// RolePermissionDiff describes how a role's permissions differ from its declared profile
+type RolePermissionDiff struct {
+	RoleID  uuid.UUID `json:"role_id"`
+	Slug    string    `json:"slug"`
+	Added   []string  `json:"added"`
+	Removed []string  `json:"removed"`
+}
+
+// PermissionSyncResponse represents the result of reconciling roles against the permission catalog
+type PermissionSyncResponse struct {
+	DryRun  bool                 `json:"dry_run"`
+	Changes []RolePermissionDiff `json:"changes"`
+}
+
 // UserRoleResponse represents user with role information
 type UserRoleResponse struct {
-	ID        uuid.UUID       `json:"id"`
-	Name      string          `json:"name"`
-	Email     string          `json:"email"`
-	Role      *RoleInfo       `json:"role"`
-	CreatedAt time.Time       `json:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at"`
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      *RoleInfo `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // RoleInfo represents simplified role information
@@ -1,7 +1,12 @@
 package role
 
 import (
+	"errors"
+
 	"go_boilerplate/internal/modules/role/dto"
+	"go_boilerplate/internal/shared/filter"
+	"go_boilerplate/internal/shared/permission"
+	"go_boilerplate/internal/shared/serializer"
 	"go_boilerplate/internal/shared/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -14,7 +19,11 @@ type RoleHandler interface {
 	GetRole(c *fiber.Ctx) error
 	CreateRole(c *fiber.Ctx) error
 	UpdateRole(c *fiber.Ctx) error
+	CloneRole(c *fiber.Ctx) error
 	DeleteRole(c *fiber.Ctx) error
+	GetPermissions(c *fiber.Ctx) error
+	PreviewPermissionSync(c *fiber.Ctx) error
+	ApplyPermissionSync(c *fiber.Ctx) error
 }
 
 // roleHandler implements RoleHandler interface
@@ -22,6 +31,13 @@ type roleHandler struct {
 	service RoleService
 }
 
+// roleFilters are the fields accepted by ?filter[field][op]= on the role list
+var roleFilters = filter.Fields{
+	"name":       {Column: "name", Type: filter.String},
+	"slug":       {Column: "slug", Type: filter.String},
+	"created_at": {Column: "created_at", Type: filter.Time},
+}
+
 // NewRoleHandler creates a new role handler
 func NewRoleHandler(service RoleService) RoleHandler {
 	return &roleHandler{service: service}
@@ -29,15 +45,20 @@ func NewRoleHandler(service RoleService) RoleHandler {
 
 // GetRoles gets all roles with pagination
 // @Summary List all roles
-// @Description Retrieve a paginated list of all user roles (SuperAdmin only).
+// @Description Retrieve a paginated list of all user roles (requires roles.read).
 // @Tags Roles
 // @Produce json
 // @Security BearerAuth
 // @Param page query int false "Page number (default: 1)"
 // @Param limit query int false "Items per page (default: 10, max: 100)"
+// @Param filter[name][like] query string false "Filter by name (operators: eq, ne, like, in, null)"
+// @Param filter[slug] query string false "Filter by slug"
+// @Param filter[created_at][gte] query string false "Created at or after (RFC 3339 or YYYY-MM-DD; operators: eq, gt, gte, lt, lte)"
+// @Param fields query string false "Comma-separated role fields to return (id is always returned), e.g. id,name,slug"
 // @Success 200 {object} utils.APIResponse{data=[]dto.RoleResponse} "Roles retrieved"
-// @Failure 401 {object} utils.APIResponse "Unauthorized"
-// @Failure 403 {object} utils.APIResponse "Forbidden"
+// @Failure 400 {object} utils.ProblemDetails "Invalid filter"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Failure 403 {object} utils.ProblemDetails "Forbidden"
 // @Router /roles [get]
 func (h *roleHandler) GetRoles(c *fiber.Ctx) error {
 	// Parse query parameters
@@ -51,8 +72,25 @@ func (h *roleHandler) GetRoles(c *fiber.Ctx) error {
 		limit = 10
 	}
 
+	// Parse filters
+	f, err := filter.FromQuery(c, roleFilters)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid filter", err)
+	}
+
+	// Parse sparse fieldset
+	opts, err := serializer.FromQuery(c)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid include", err)
+	}
+
 	// Get roles
-	response, err := h.service.GetAllRoles(page, limit)
+	roles, err := h.service.GetAllRoles(c.UserContext(), page, limit, f)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get roles", err)
+	}
+
+	response, err := opts.Apply(roles, "roles")
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get roles", err)
 	}
@@ -62,15 +100,16 @@ func (h *roleHandler) GetRoles(c *fiber.Ctx) error {
 
 // GetRole gets a role by ID
 // @Summary Get role by ID
-// @Description Retrieve details of a specific role by its ID (SuperAdmin only).
+// @Description Retrieve details of a specific role by its ID (requires roles.read).
 // @Tags Roles
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Role ID (UUID)"
+// @Param fields query string false "Comma-separated role fields to return (id is always returned), e.g. id,name,effective_permissions"
 // @Success 200 {object} utils.APIResponse{data=dto.RoleResponse} "Role retrieved"
-// @Failure 400 {object} utils.APIResponse "Invalid role ID"
-// @Failure 401 {object} utils.APIResponse "Unauthorized"
-// @Failure 404 {object} utils.APIResponse "Role not found"
+// @Failure 400 {object} utils.ProblemDetails "Invalid role ID"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Failure 404 {object} utils.ProblemDetails "Role not found"
 // @Router /roles/{id} [get]
 func (h *roleHandler) GetRole(c *fiber.Ctx) error {
 	// Parse role ID
@@ -79,13 +118,24 @@ func (h *roleHandler) GetRole(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid role ID", err)
 	}
 
+	// Parse sparse fieldset
+	opts, err := serializer.FromQuery(c)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid include", err)
+	}
+
 	// Get role
-	role, err := h.service.GetRole(roleID)
+	role, err := h.service.GetRole(c.UserContext(), roleID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get role", err)
+	}
+
+	response, err := opts.Apply(role, "")
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusNotFound, "Role not found", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get role", err)
 	}
 
-	return utils.SuccessResponse(c, fiber.StatusOK, role, "Role retrieved successfully")
+	return utils.SuccessResponse(c, fiber.StatusOK, response, "Role retrieved successfully")
 }
 
 // CreateRole creates a new role
@@ -97,17 +147,17 @@ func (h *roleHandler) GetRole(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param request body dto.CreateRoleRequest true "Role data"
 // @Success 201 {object} utils.APIResponse{data=dto.RoleResponse} "Role created"
-// @Failure 400 {object} utils.APIResponse "Invalid request"
-// @Failure 401 {object} utils.APIResponse "Unauthorized"
+// @Failure 400 {object} utils.ProblemDetails "Invalid request"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
 // @Router /roles [post]
 func (h *roleHandler) CreateRole(c *fiber.Ctx) error {
 	// Get validated body
 	validatedBody := c.Locals("validatedBody").(*dto.CreateRoleRequest)
 
 	// Create role
-	role, err := h.service.CreateRole(validatedBody)
+	role, err := h.service.CreateRole(c.UserContext(), validatedBody)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to create role", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to create role", err)
 	}
 
 	return utils.SuccessResponse(c, fiber.StatusCreated, role, "Role created successfully")
@@ -123,8 +173,9 @@ func (h *roleHandler) CreateRole(c *fiber.Ctx) error {
 // @Param id path string true "Role ID (UUID)"
 // @Param request body dto.UpdateRoleRequest true "Update data"
 // @Success 200 {object} utils.APIResponse{data=dto.RoleResponse} "Role updated"
-// @Failure 400 {object} utils.APIResponse "Invalid request"
-// @Failure 401 {object} utils.APIResponse "Unauthorized"
+// @Failure 400 {object} utils.ProblemDetails "Invalid request"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Failure 409 {object} utils.ProblemDetails "Version conflict: the role was modified since it was read"
 // @Router /roles/{id} [put]
 func (h *roleHandler) UpdateRole(c *fiber.Ctx) error {
 	// Parse role ID
@@ -137,23 +188,62 @@ func (h *roleHandler) UpdateRole(c *fiber.Ctx) error {
 	validatedBody := c.Locals("validatedBody").(*dto.UpdateRoleRequest)
 
 	// Update role
-	role, err := h.service.UpdateRole(roleID, validatedBody)
+	role, err := h.service.UpdateRole(c.UserContext(), roleID, validatedBody)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to update role", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to update role", err)
 	}
 
 	return utils.SuccessResponse(c, fiber.StatusOK, role, "Role updated successfully")
 }
 
+// CloneRole copies a role under a new name and slug
+// @Summary Clone role
+// @Description Create a custom role with the permissions, description and parent of an existing role (SuperAdmin only).
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Source role ID (UUID)"
+// @Param request body dto.CloneRoleRequest true "Name and slug of the new role"
+// @Success 201 {object} utils.APIResponse{data=dto.RoleResponse} "Role cloned"
+// @Failure 400 {object} utils.ProblemDetails "Invalid request"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Failure 404 {object} utils.ProblemDetails "Role not found"
+// @Router /roles/{id}/clone [post]
+func (h *roleHandler) CloneRole(c *fiber.Ctx) error {
+	// Parse role ID
+	roleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid role ID", err)
+	}
+
+	// Get validated body
+	validatedBody := c.Locals("validatedBody").(*dto.CloneRoleRequest)
+
+	// Clone role
+	role, err := h.service.CloneRole(c.UserContext(), roleID, validatedBody)
+	if err != nil {
+		if errors.Is(err, ErrRoleNotFound) {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Failed to clone role", err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to clone role", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusCreated, role, "Role cloned successfully")
+}
+
 // DeleteRole deletes a role
 // @Summary Delete role
-// @Description Permantently remove a security role (SuperAdmin only).
+// @Description Permantently remove a security role (SuperAdmin only). System roles cannot be deleted; roles still assigned to users require reassign_to.
 // @Tags Roles
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Role ID (UUID)"
+// @Param reassign_to query string false "Role ID (UUID) to move the role's users to"
 // @Success 200 {object} utils.APIResponse "Role deleted"
-// @Failure 400 {object} utils.APIResponse "Invalid role ID"
+// @Failure 400 {object} utils.ProblemDetails "Invalid role ID"
+// @Failure 403 {object} utils.ProblemDetails "System role"
+// @Failure 409 {object} utils.ProblemDetails "Role still assigned to users"
 // @Router /roles/{id} [delete]
 func (h *roleHandler) DeleteRole(c *fiber.Ctx) error {
 	// Parse role ID
@@ -162,10 +252,78 @@ func (h *roleHandler) DeleteRole(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid role ID", err)
 	}
 
+	// Parse optional reassignment target
+	var reassignTo *uuid.UUID
+	if raw := c.Query("reassign_to"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid reassign_to role ID", err)
+		}
+		reassignTo = &id
+	}
+
 	// Delete role
-	if err := h.service.DeleteRole(roleID); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to delete role", err)
+	if err := h.service.DeleteRole(c.UserContext(), roleID, reassignTo); err != nil {
+		switch {
+		case errors.Is(err, ErrSystemRole):
+			return utils.ErrorResponse(c, fiber.StatusForbidden, "Failed to delete role", err)
+		case errors.Is(err, ErrRoleInUse):
+			return utils.ErrorResponse(c, fiber.StatusConflict, "Failed to delete role", err)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to delete role", err)
 	}
 
 	return utils.SuccessResponse(c, fiber.StatusOK, nil, "Role deleted successfully")
 }
+
+// GetPermissions lists the permission catalog
+// @Summary List permissions
+// @Description Retrieve every permission registered by the application modules; role permissions must come from this list (requires roles.read).
+// @Tags Roles
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.APIResponse{data=[]permission.Permission} "Permissions retrieved"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Failure 403 {object} utils.ProblemDetails "Forbidden"
+// @Router /permissions [get]
+func (h *roleHandler) GetPermissions(c *fiber.Ctx) error {
+	return utils.SuccessResponse(c, fiber.StatusOK, permission.All(), "Permissions retrieved successfully")
+}
+
+// PreviewPermissionSync shows how built-in roles differ from their declared profiles
+// @Summary Preview role permission sync
+// @Description Dry run: compare the built-in roles (super_admin, admin, user) with their declarative profiles resolved against the permission catalog (SuperAdmin only).
+// @Tags Roles
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.APIResponse{data=dto.PermissionSyncResponse} "Pending changes"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Failure 403 {object} utils.ProblemDetails "Forbidden"
+// @Router /roles/permission-sync [get]
+func (h *roleHandler) PreviewPermissionSync(c *fiber.Ctx) error {
+	response, err := h.service.PlanPermissionSync(c.UserContext())
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to compute permission sync", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, response, "Permission sync preview generated")
+}
+
+// ApplyPermissionSync reconciles built-in roles with their declared profiles
+// @Summary Apply role permission sync
+// @Description Rewrite the permissions of built-in roles that drifted from their profiles and return the applied changes (SuperAdmin only). Preview with GET first.
+// @Tags Roles
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.APIResponse{data=dto.PermissionSyncResponse} "Applied changes"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Failure 403 {object} utils.ProblemDetails "Forbidden"
+// @Router /roles/permission-sync [post]
+func (h *roleHandler) ApplyPermissionSync(c *fiber.Ctx) error {
+	response, err := h.service.ApplyPermissionSync(c.UserContext())
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to apply permission sync", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, response, "Role permissions synchronized")
+}
@@ -37,13 +37,16 @@ func (s *StringSlice) Scan(value interface{}) error {
 
 // Role represents a role in the system with granular permissions
 type Role struct {
-	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Name        string     `json:"name" gorm:"type:varchar(100);not null;uniqueIndex"`
-	Slug        string     `json:"slug" gorm:"type:varchar(50);not null;uniqueIndex"`
+	ID          uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name        string      `json:"name" gorm:"type:varchar(100);not null;uniqueIndex"`
+	Slug        string      `json:"slug" gorm:"type:varchar(50);not null;uniqueIndex"`
 	Permissions StringSlice `json:"permissions" gorm:"type:jsonb;not null"` // JSONB type
-	Description string     `json:"description" gorm:"type:text"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	Description string      `json:"description" gorm:"type:text"`
+	ParentID    *uuid.UUID  `json:"parent_id,omitempty" gorm:"type:uuid;index"` // Role whose permissions are inherited (transitively)
+	IsSystem    bool        `json:"is_system" gorm:"not null;default:false"`    // Built-in role (super_admin, admin, user); cannot be deleted
+	Version     int         `json:"version" gorm:"not null;default:1"`          // Optimistic lock (optimistic.Plugin); bumped by every update
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
 }
 
 // TableName specifies the table name for Role model
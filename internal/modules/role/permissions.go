@@ -0,0 +1,22 @@
+package role
+
+import "go_boilerplate/internal/shared/permission"
+
+// Role permissions
+const (
+	PermRolesCreate = "roles.create"
+	PermRolesRead   = "roles.read"
+	PermRolesUpdate = "roles.update"
+	PermRolesDelete = "roles.delete"
+	PermRolesAssign = "roles.assign"
+)
+
+func init() {
+	permission.Register(
+		permission.Permission{Name: PermRolesCreate, Description: "Create roles"},
+		permission.Permission{Name: PermRolesRead, Description: "View roles"},
+		permission.Permission{Name: PermRolesUpdate, Description: "Update roles"},
+		permission.Permission{Name: PermRolesDelete, Description: "Delete roles"},
+		permission.Permission{Name: PermRolesAssign, Description: "Assign roles to users"},
+	)
+}
@@ -0,0 +1,67 @@
+package role
+
+import (
+	"sort"
+
+	"go_boilerplate/internal/shared/permission"
+)
+
+// Profile declares the permissions a built-in role should hold as catalog patterns
+// (an exact name, `*`, `resource.*` or `*.action`), so roles pick up permissions added by new modules
+type Profile struct {
+	Name        string
+	Slug        string
+	Description string
+	Grants      []string
+}
+
+// DefaultProfiles are the built-in roles seeded at startup and reconciled by the permission sync
+var DefaultProfiles = []Profile{
+	{
+		Name:        "SuperAdmin",
+		Slug:        "super_admin",
+		Description: "Full system access with all permissions",
+		Grants:      []string{permission.Wildcard},
+	},
+	{
+		Name:        "Admin",
+		Slug:        "admin",
+		Description: "Administrative access for user and role management",
+		Grants:      []string{"users.*", "abuse_reports.*", "*.read", PermRolesAssign},
+	},
+	{
+		Name:        "User",
+		Slug:        "user",
+		Description: "Standard user access with self-profile management",
+		Grants:      []string{"users.read", "users.update"},
+	},
+}
+
+// Permissions resolves the profile grants against the permission catalog
+func (p Profile) Permissions() []string {
+	return permission.Expand(p.Grants...)
+}
+
+// diffPermissions returns the permissions to add to and remove from current to reach desired
+func diffPermissions(current, desired []string) (added, removed []string) {
+	have := make(map[string]bool, len(current))
+	for _, p := range current {
+		have[p] = true
+	}
+	want := make(map[string]bool, len(desired))
+	for _, p := range desired {
+		want[p] = true
+		if !have[p] {
+			added = append(added, p)
+		}
+	}
+	for _, p := range current {
+		if !want[p] {
+			removed = append(removed, p)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
@@ -1,101 +1,83 @@
 package role
 
 import (
+	"context"
 	"errors"
 
+	"go_boilerplate/internal/shared/database/repository"
+	"go_boilerplate/internal/shared/filter"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 // RoleRepository defines the interface for role data operations
 type RoleRepository interface {
-	Create(role *Role) error
-	FindByID(id uuid.UUID) (*Role, error)
-	FindBySlug(slug string) (*Role, error)
-	FindAll(offset, limit int) ([]Role, int64, error)
-	Update(role *Role) error
-	Delete(id uuid.UUID) error
-	ExistsBySlug(slug string) (bool, error)
-	ExistsByName(name string) (bool, error)
+	Create(ctx context.Context, role *Role) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Role, error)
+	FindBySlug(ctx context.Context, slug string) (*Role, error)
+	FindAll(ctx context.Context, offset, limit int, f filter.Filter) ([]Role, int64, error)
+	Update(ctx context.Context, role *Role) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ExistsBySlug(ctx context.Context, slug string) (bool, error)
+	ExistsByName(ctx context.Context, name string) (bool, error)
+	ClearParent(ctx context.Context, parentID uuid.UUID) error
+	CountUsers(ctx context.Context, roleID uuid.UUID) (int64, error)
+	ReassignUsers(ctx context.Context, fromRoleID, toRoleID uuid.UUID) error
 }
 
-// roleRepository implements RoleRepository interface
+// roleRepository implements RoleRepository interface; Create, FindByID, FindAll, Update and
+// Delete come from repository.Repository
 type roleRepository struct {
+	repository.Repository[Role]
 	db *gorm.DB
 }
 
 // NewRoleRepository creates a new role repository
 func NewRoleRepository(db *gorm.DB) RoleRepository {
-	return &roleRepository{db: db}
-}
-
-// Create creates a new role
-func (r *roleRepository) Create(role *Role) error {
-	return r.db.Create(role).Error
-}
-
-// FindByID finds a role by ID
-func (r *roleRepository) FindByID(id uuid.UUID) (*Role, error) {
-	var role Role
-	err := r.db.Where("id = ?", id).First(&role).Error
-	if err != nil {
-		return nil, err
-	}
-	return &role, nil
+	return &roleRepository{Repository: repository.New[Role](db), db: db}
 }
 
 // FindBySlug finds a role by slug
-func (r *roleRepository) FindBySlug(slug string) (*Role, error) {
-	var role Role
-	err := r.db.Where("slug = ?", slug).First(&role).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, nil // Return nil if not found
-		}
-		return nil, err
+func (r *roleRepository) FindBySlug(ctx context.Context, slug string) (*Role, error) {
+	role, err := r.FindOne(ctx, "slug = ?", slug)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil // Return nil if not found
 	}
-	return &role, nil
+	return role, err
 }
 
-// FindAll finds all roles with pagination
-func (r *roleRepository) FindAll(offset, limit int) ([]Role, int64, error) {
-	var roles []Role
-	var total int64
-
-	// Count total
-	if err := r.db.Model(&Role{}).Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
-
-	// Find roles with pagination
-	err := r.db.Offset(offset).Limit(limit).Order("created_at DESC").Find(&roles).Error
-	if err != nil {
-		return nil, 0, err
-	}
-
-	return roles, total, nil
+// ExistsBySlug checks if a role with the given slug exists
+func (r *roleRepository) ExistsBySlug(ctx context.Context, slug string) (bool, error) {
+	return r.Exists(ctx, "slug = ?", slug)
 }
 
-// Update updates a role
-func (r *roleRepository) Update(role *Role) error {
-	return r.db.Save(role).Error
+// ExistsByName checks if a role with the given name exists
+func (r *roleRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
+	return r.Exists(ctx, "name = ?", name)
 }
 
-// Delete deletes a role by ID
-func (r *roleRepository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&Role{}, "id = ?", id).Error
+// ClearParent removes parentID as the parent of every role inheriting from it
+func (r *roleRepository) ClearParent(ctx context.Context, parentID uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&Role{}).Where("parent_id = ?", parentID).Update("parent_id", nil).Error
 }
 
-// ExistsBySlug checks if a role with the given slug exists
-func (r *roleRepository) ExistsBySlug(slug string) (bool, error) {
+// CountUsers counts the users holding a role
+func (r *roleRepository) CountUsers(ctx context.Context, roleID uuid.UUID) (int64, error) {
 	var count int64
-	err := r.db.Model(&Role{}).Where("slug = ?", slug).Count(&count).Error
-	return count > 0, err
+	err := r.db.WithContext(ctx).Table("m_user_roles").Where("role_id = ?", roleID).Count(&count).Error
+	return count, err
 }
 
-// ExistsByName checks if a role with the given name exists
-func (r *roleRepository) ExistsByName(name string) (bool, error) {
-	var count int64
-	err := r.db.Model(&Role{}).Where("name = ?", name).Count(&count).Error
-	return count > 0, err
+// ReassignUsers moves every user holding fromRoleID to toRoleID
+// Users who already hold toRoleID simply lose fromRoleID
+func (r *roleRepository) ReassignUsers(ctx context.Context, fromRoleID, toRoleID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`INSERT INTO m_user_roles (user_id, role_id)
+			SELECT user_id, ? FROM m_user_roles WHERE role_id = ?
+			ON CONFLICT DO NOTHING`, toRoleID, fromRoleID).Error; err != nil {
+			return err
+		}
+		return tx.Exec("DELETE FROM m_user_roles WHERE role_id = ?", fromRoleID).Error
+	})
 }
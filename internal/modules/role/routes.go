@@ -1,40 +1,61 @@
 package role
 
 import (
+	"time"
+
+	"go_boilerplate/internal/modules/audit"
 	"go_boilerplate/internal/modules/role/dto"
+	"go_boilerplate/internal/shared/apiversion"
+	"go_boilerplate/internal/shared/cache"
 	"go_boilerplate/internal/shared/config"
 	"go_boilerplate/internal/shared/middleware"
 
+	"go_boilerplate/internal/shared/permission"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 // RegisterRoutes registers all role-related routes
-func RegisterRoutes(app *fiber.App, db *gorm.DB, cfg *config.Config, logger *logrus.Logger) {
+func RegisterRoutes(app *fiber.App, db *gorm.DB, cfg *config.Config, logger *logrus.Logger, redisClient *redis.Client, store cache.Cache) {
 	// Initialize repository
 	roleRepo := NewRoleRepository(db)
 
 	// Initialize service
-	roleService := NewRoleService(roleRepo)
+	responses := cache.NewResponseCache(redisClient, cfg.Cache)
+	profiles := cache.NewNamespace(store, cache.NamespaceUserRoles)
+	roleService := NewRoleServiceWithCaches(roleRepo, permission.NewCache(redisClient, cfg.RBAC.PermissionCacheTTL), profiles, responses, audit.NewTrail(db, cfg.Audit, logger))
 
 	// Initialize handler
 	roleHandler := NewRoleHandler(roleService)
 
 	// Create API route group
-	api := app.Group("/api/v1")
+	api := apiversion.Group(app, "v1")
 
-	// Protected routes - require SuperAdmin role
+	// Protected routes - reads need roles.read, mutations need the SuperAdmin role
 	roles := api.Group("/roles")
 	roles.Use(middleware.JWTAuth(cfg))
-	roles.Use(middleware.RequireRole(cfg, "super_admin"))
+	roles.Use(middleware.Compress(cfg), middleware.ETag(cfg))
+	superAdmin := middleware.RequireRole(cfg, "super_admin")
+	canRead := middleware.RequirePermission(cfg, PermRolesRead)
 
-	// Role CRUD routes (only SuperAdmin can manage roles)
-	roles.Get("/", roleHandler.GetRoles)                        // Get all roles (with pagination)
-	roles.Get("/:id", roleHandler.GetRole)                      // Get role by ID
-	roles.Post("/", middleware.BodyValidator(&dto.CreateRoleRequest{}), roleHandler.CreateRole) // Create role (SuperAdmin only)
-	roles.Put("/:id", middleware.BodyValidator(&dto.UpdateRoleRequest{}), roleHandler.UpdateRole) // Update role (SuperAdmin only)
-	roles.Delete("/:id", roleHandler.DeleteRole)                // Delete role (SuperAdmin only)
+	// The role list is the same for every reader and only changes through this service, so it is shared and kept longer
+	listCache := middleware.Cache(responses, middleware.CacheRule{Namespace: cache.NamespaceRoles, TTL: 5 * time.Minute, Key: "{path}?{query}|{tenant}|{locale}"})
 
-	logger.Info("✓ Role routes registered (SuperAdmin only)")
+	// Role CRUD routes (only SuperAdmin can manage roles)
+	roles.Get("/", canRead, listCache, roleHandler.GetRoles)                                                       // Get all roles (with pagination, cached)
+	roles.Get("/permission-sync", superAdmin, roleHandler.PreviewPermissionSync)                                   // Dry-run diff of built-in roles vs. profiles
+	roles.Post("/permission-sync", superAdmin, roleHandler.ApplyPermissionSync)                                    // Apply the permission sync
+	roles.Get("/:id", canRead, roleHandler.GetRole)                                                                // Get role by ID
+	roles.Post("/", superAdmin, middleware.BodyValidator(&dto.CreateRoleRequest{}), roleHandler.CreateRole)        // Create role (SuperAdmin only)
+	roles.Put("/:id", superAdmin, middleware.BodyValidator(&dto.UpdateRoleRequest{}), roleHandler.UpdateRole)      // Update role (SuperAdmin only)
+	roles.Post("/:id/clone", superAdmin, middleware.BodyValidator(&dto.CloneRoleRequest{}), roleHandler.CloneRole) // Copy a role under a new name/slug (SuperAdmin only)
+	roles.Delete("/:id", superAdmin, roleHandler.DeleteRole)                                                       // Delete role (SuperAdmin only)
+
+	// Permission catalog (used when composing roles)
+	api.Get("/permissions", middleware.JWTAuth(cfg), canRead, roleHandler.GetPermissions)
+
+	logger.Info("✓ Role routes registered")
 }
@@ -0,0 +1,158 @@
+package role
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// newRoutesTestApp registers the role routes like cmd/cli routes check: the database is never
+// reachable, so requests that pass authorization fail later with 500, never with 401/403
+func newRoutesTestApp(t *testing.T) (*fiber.App, *utils.JWTManager) {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.JWT = config.JWTConfig{Secret: "role-routes-test", AccessExpiry: time.Minute, RefreshExpiry: time.Hour, Issuer: "test"}
+
+	db, err := gorm.Open(postgres.Open("host=127.0.0.1 port=1 user=test dbname=test sslmode=disable connect_timeout=1"), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	app := fiber.New()
+	RegisterRoutes(app, db, cfg, logger, nil, nil)
+	return app, utils.NewJWTManager(cfg.JWT.Secret, cfg.JWT.AccessExpiry, cfg.JWT.RefreshExpiry, cfg.JWT.Issuer)
+}
+
+type routeCaller struct {
+	name        string
+	roles       []string
+	permissions []string
+}
+
+var (
+	superAdminCaller  = routeCaller{"super_admin", []string{"super_admin"}, []string{"*"}}
+	roleReaderCaller  = routeCaller{"admin with roles.read", []string{"admin"}, []string{"roles.read", "users.read"}}
+	wildcardCaller    = routeCaller{"admin with wildcard", []string{"admin"}, []string{"*"}}
+	rolesPatternUser  = routeCaller{"roles.* pattern", []string{"admin"}, []string{"roles.*"}}
+	readPatternUser   = routeCaller{"*.read pattern", []string{"admin"}, []string{"*.read"}}
+	plainUserCaller   = routeCaller{"user", []string{"user"}, []string{"users.read", "users.update"}}
+	roleManagerCaller = routeCaller{"admin with every role permission", []string{"admin"}, []string{"roles.create", "roles.read", "roles.update", "roles.delete", "roles.assign"}}
+)
+
+func TestRoleRoutesAuthorization(t *testing.T) {
+	app, jwtManager := newRoutesTestApp(t)
+	roleID := "7f3c2a9e-1b4d-4c8e-9a6f-2d5e8b1c0a47"
+
+	reads := []struct{ method, path string }{
+		{fiber.MethodGet, "/api/v1/roles"},
+		{fiber.MethodGet, "/api/v1/roles/" + roleID},
+		{fiber.MethodGet, "/api/v1/permissions"},
+	}
+	mutations := []struct{ method, path, body string }{
+		{fiber.MethodPost, "/api/v1/roles", `{}`},
+		{fiber.MethodPut, "/api/v1/roles/" + roleID, `{}`},
+		{fiber.MethodDelete, "/api/v1/roles/" + roleID, ""},
+		{fiber.MethodPost, "/api/v1/roles/" + roleID + "/clone", `{}`},
+		{fiber.MethodGet, "/api/v1/roles/permission-sync", ""},
+		{fiber.MethodPost, "/api/v1/roles/permission-sync", ""},
+	}
+
+	// Reads need roles.read (or the wildcard); patterns in a token grant nothing beyond their name
+	for _, route := range reads {
+		for _, tc := range []struct {
+			caller  routeCaller
+			allowed bool
+		}{
+			{superAdminCaller, true},
+			{roleReaderCaller, true},
+			{wildcardCaller, true},
+			{rolesPatternUser, false},
+			{readPatternUser, false},
+			{plainUserCaller, false},
+		} {
+			t.Run(route.method+" "+route.path+" as "+tc.caller.name, func(t *testing.T) {
+				checkRouteAccess(t, app, jwtManager, tc.caller, route.method, route.path, "", tc.allowed)
+			})
+		}
+	}
+
+	// Mutations need the super_admin role; no permission grants them
+	for _, route := range mutations {
+		for _, tc := range []struct {
+			caller  routeCaller
+			allowed bool
+		}{
+			{superAdminCaller, true},
+			{wildcardCaller, false},
+			{roleManagerCaller, false},
+			{roleReaderCaller, false},
+			{plainUserCaller, false},
+		} {
+			t.Run(route.method+" "+route.path+" as "+tc.caller.name, func(t *testing.T) {
+				checkRouteAccess(t, app, jwtManager, tc.caller, route.method, route.path, route.body, tc.allowed)
+			})
+		}
+	}
+}
+
+func TestRoleRoutesRequireAuthentication(t *testing.T) {
+	app, _ := newRoutesTestApp(t)
+
+	for _, path := range []string{"/api/v1/roles", "/api/v1/permissions"} {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil), -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != fiber.StatusBadRequest && resp.StatusCode != fiber.StatusUnauthorized {
+			t.Errorf("GET %s without a token: status %d, want 400 or 401", path, resp.StatusCode)
+		}
+	}
+}
+
+func checkRouteAccess(t *testing.T, app *fiber.App, jwtManager *utils.JWTManager, caller routeCaller, method, path, body string, allowed bool) {
+	t.Helper()
+
+	token, err := jwtManager.GenerateAccessToken(uuid.New(), caller.name+"@example.com", caller.roles, caller.permissions, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	if body != "" {
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	}
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	denied := resp.StatusCode == fiber.StatusUnauthorized || resp.StatusCode == fiber.StatusForbidden
+	if allowed && denied {
+		t.Fatalf("status %d, want the request to pass authorization", resp.StatusCode)
+	}
+	if !allowed && resp.StatusCode != fiber.StatusForbidden {
+		t.Fatalf("status %d, want %d", resp.StatusCode, fiber.StatusForbidden)
+	}
+}
@@ -1,29 +1,66 @@
 package role
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"math"
+	"sort"
 
+	"go_boilerplate/internal/modules/audit"
 	"go_boilerplate/internal/modules/role/dto"
+	"go_boilerplate/internal/shared/apperror"
+	"go_boilerplate/internal/shared/cache"
+	"go_boilerplate/internal/shared/database/replica"
+	"go_boilerplate/internal/shared/database/repository"
+	"go_boilerplate/internal/shared/filter"
+	"go_boilerplate/internal/shared/permission"
 	"go_boilerplate/internal/shared/utils"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // RoleService defines the interface for role business logic
 type RoleService interface {
-	GetRole(roleID uuid.UUID) (*dto.RoleResponse, error)
-	GetRoleBySlug(slug string) (*dto.RoleResponse, error)
-	GetAllRoles(page, limit int) (*dto.RolesResponse, error)
-	CreateRole(req *dto.CreateRoleRequest) (*dto.RoleResponse, error)
-	UpdateRole(roleID uuid.UUID, req *dto.UpdateRoleRequest) (*dto.RoleResponse, error)
-	DeleteRole(roleID uuid.UUID) error
-	SeedInitialRoles() error
+	GetRole(ctx context.Context, roleID uuid.UUID) (*dto.RoleResponse, error)
+	GetRoleBySlug(ctx context.Context, slug string) (*dto.RoleResponse, error)
+	GetAllRoles(ctx context.Context, page, limit int, f filter.Filter) (*dto.RolesResponse, error)
+	CreateRole(ctx context.Context, req *dto.CreateRoleRequest) (*dto.RoleResponse, error)
+	UpdateRole(ctx context.Context, roleID uuid.UUID, req *dto.UpdateRoleRequest) (*dto.RoleResponse, error)
+	CloneRole(ctx context.Context, roleID uuid.UUID, req *dto.CloneRoleRequest) (*dto.RoleResponse, error)
+	DeleteRole(ctx context.Context, roleID uuid.UUID, reassignTo *uuid.UUID) error
+	EffectivePermissions(ctx context.Context, roleID uuid.UUID) ([]string, error)
+	SeedInitialRoles(ctx context.Context) error
+	PlanPermissionSync(ctx context.Context) (*dto.PermissionSyncResponse, error)
+	ApplyPermissionSync(ctx context.Context) (*dto.PermissionSyncResponse, error)
 }
 
+// Errors returned when a role can't be deleted
+var (
+	ErrSystemRole = apperror.New(apperror.ErrForbidden, "system roles cannot be deleted").WithCode("system_role")
+	ErrRoleInUse  = apperror.New(apperror.ErrConflict, "role is still assigned to users; pass reassign_to to move them to another role").WithCode("role_in_use")
+)
+
+// ErrRoleNotFound is returned when a role (or a role being assigned) doesn't exist
+var ErrRoleNotFound = apperror.New(apperror.ErrNotFound, "role not found").WithCode("role_not_found")
+
+// Errors returned when a role can't be created or changed
+var (
+	ErrRoleSlugTaken        = apperror.New(apperror.ErrConflict, "role with this slug already exists").WithCode("role_slug_taken")
+	ErrRoleNameTaken        = apperror.New(apperror.ErrConflict, "role with this name already exists").WithCode("role_name_taken")
+	ErrParentRoleNotFound   = apperror.New(apperror.ErrValidation, "parent role not found").WithCode("parent_role_not_found")
+	ErrRoleInheritanceLoop  = apperror.New(apperror.ErrValidation, "role cannot inherit from itself or one of its descendants").WithCode("role_inheritance_cycle")
+	ErrReassignRoleNotFound = apperror.New(apperror.ErrValidation, "reassignment role not found").WithCode("reassign_role_not_found")
+)
+
 // roleService implements RoleService interface
 type roleService struct {
-	repo RoleRepository
+	repo      RoleRepository
+	permCache *permission.Cache    // Invalidated when role permissions change (nil-safe)
+	profiles  *cache.Namespace     // Users with their roles (cache.NamespaceUserRoles), cleared after role changes (nil-safe)
+	responses *cache.ResponseCache // Cached role and user lists, invalidated after writes (nil-safe)
+	trail     *audit.Trail         // Records changes to roles (nil records nothing)
 }
 
 // NewRoleService creates a new role service
@@ -31,35 +68,48 @@ func NewRoleService(repo RoleRepository) RoleService {
 	return &roleService{repo: repo}
 }
 
+// NewRoleServiceWithCaches creates a role service that invalidates live permission checks, cached
+// user profiles and cached responses on changes, and records them in trail
+func NewRoleServiceWithCaches(repo RoleRepository, permCache *permission.Cache, profiles *cache.Namespace, responses *cache.ResponseCache, trail *audit.Trail) RoleService {
+	return &roleService{repo: repo, permCache: permCache, profiles: profiles, responses: responses, trail: trail}
+}
+
 // GetRole gets a role by ID
-func (s *roleService) GetRole(roleID uuid.UUID) (*dto.RoleResponse, error) {
-	roleModel, err := s.repo.FindByID(roleID)
+func (s *roleService) GetRole(ctx context.Context, roleID uuid.UUID) (*dto.RoleResponse, error) {
+	roleModel, err := s.repo.FindByID(ctx, roleID)
 	if err != nil {
-		return nil, errors.New("role not found")
+		return nil, repository.LookupError(err, ErrRoleNotFound, "role")
 	}
 
 	response := s.modelToResponse(roleModel)
+	response.EffectivePermissions, err = s.resolvePermissions(ctx, roleModel)
+	if err != nil {
+		return nil, err
+	}
 	return &response, nil
 }
 
 // GetRoleBySlug gets a role by slug
-func (s *roleService) GetRoleBySlug(slug string) (*dto.RoleResponse, error) {
-	roleModel, err := s.repo.FindBySlug(slug)
-	if err != nil || roleModel == nil {
-		return nil, errors.New("role not found")
+func (s *roleService) GetRoleBySlug(ctx context.Context, slug string) (*dto.RoleResponse, error) {
+	roleModel, err := s.repo.FindBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find role: %w", err)
+	}
+	if roleModel == nil {
+		return nil, ErrRoleNotFound
 	}
 
 	response := s.modelToResponse(roleModel)
 	return &response, nil
 }
 
-// GetAllRoles gets all roles with pagination
-func (s *roleService) GetAllRoles(page, limit int) (*dto.RolesResponse, error) {
+// GetAllRoles gets all roles matching the filter with pagination
+func (s *roleService) GetAllRoles(ctx context.Context, page, limit int, f filter.Filter) (*dto.RolesResponse, error) {
 	// Calculate offset
 	offset := (page - 1) * limit
 
 	// Find roles
-	roles, total, err := s.repo.FindAll(offset, limit)
+	roles, total, err := s.repo.FindAll(ctx, offset, limit, f)
 	if err != nil {
 		return nil, err
 	}
@@ -85,23 +135,30 @@ func (s *roleService) GetAllRoles(page, limit int) (*dto.RolesResponse, error) {
 }
 
 // CreateRole creates a new role
-func (s *roleService) CreateRole(req *dto.CreateRoleRequest) (*dto.RoleResponse, error) {
+func (s *roleService) CreateRole(ctx context.Context, req *dto.CreateRoleRequest) (*dto.RoleResponse, error) {
 	// Check if slug already exists
-	exists, err := s.repo.ExistsBySlug(req.Slug)
+	exists, err := s.repo.ExistsBySlug(ctx, req.Slug)
 	if err != nil {
 		return nil, err
 	}
 	if exists {
-		return nil, errors.New("role with this slug already exists")
+		return nil, ErrRoleSlugTaken
 	}
 
 	// Check if name already exists
-	exists, err = s.repo.ExistsByName(req.Name)
+	exists, err = s.repo.ExistsByName(ctx, req.Name)
 	if err != nil {
 		return nil, err
 	}
 	if exists {
-		return nil, errors.New("role with this name already exists")
+		return nil, ErrRoleNameTaken
+	}
+
+	// Parent role must exist
+	if req.ParentID != nil {
+		if _, err := s.repo.FindByID(ctx, *req.ParentID); err != nil {
+			return nil, repository.LookupError(err, ErrParentRoleNotFound, "parent role")
+		}
 	}
 
 	// Create role model
@@ -110,31 +167,65 @@ func (s *roleService) CreateRole(req *dto.CreateRoleRequest) (*dto.RoleResponse,
 		Slug:        req.Slug,
 		Permissions: StringSlice(req.Permissions),
 		Description: req.Description,
+		ParentID:    req.ParentID,
 	}
 
 	// Save role
-	if err := s.repo.Create(roleModel); err != nil {
+	if err := s.repo.Create(ctx, roleModel); err != nil {
 		return nil, err
 	}
+	s.invalidateResponses(ctx)
 
 	response := s.modelToResponse(roleModel)
+	s.record(ctx, "role.created", roleModel.ID, nil, response)
 	return &response, nil
 }
 
+// CloneRole creates a custom role with the permissions, description and parent of an existing role
+func (s *roleService) CloneRole(ctx context.Context, roleID uuid.UUID, req *dto.CloneRoleRequest) (*dto.RoleResponse, error) {
+	source, err := s.repo.FindByID(ctx, roleID)
+	if err != nil {
+		return nil, repository.LookupError(err, ErrRoleNotFound, "role")
+	}
+
+	description := req.Description
+	if description == "" {
+		description = source.Description
+	}
+
+	return s.CreateRole(ctx, &dto.CreateRoleRequest{
+		Name:        req.Name,
+		Slug:        req.Slug,
+		Permissions: append([]string(nil), source.Permissions...),
+		Description: description,
+		ParentID:    source.ParentID,
+	})
+}
+
 // UpdateRole updates a role
-func (s *roleService) UpdateRole(roleID uuid.UUID, req *dto.UpdateRoleRequest) (*dto.RoleResponse, error) {
-	// Find role
-	roleModel, err := s.repo.FindByID(roleID)
+func (s *roleService) UpdateRole(ctx context.Context, roleID uuid.UUID, req *dto.UpdateRoleRequest) (*dto.RoleResponse, error) {
+	// Find role on the primary: a lagging replica would report an outdated version
+	roleModel, err := s.repo.FindByID(replica.WithPrimary(ctx), roleID)
 	if err != nil {
-		return nil, errors.New("role not found")
+		return nil, repository.LookupError(err, ErrRoleNotFound, "role")
+	}
+	before := s.modelToResponse(roleModel)
+
+	// Saving checks the version (optimistic.Plugin); the client's version catches changes made
+	// since it read the role, not only during this request
+	if req.Version != nil {
+		roleModel.Version = *req.Version
 	}
 
 	// Update fields if provided
 	if req.Name != "" {
 		// Check if new name already exists (excluding current role)
-		existingRole, _ := s.repo.FindBySlug(roleModel.Slug)
+		existingRole, err := s.repo.FindBySlug(ctx, roleModel.Slug)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find role: %w", err)
+		}
 		if existingRole != nil && existingRole.ID != roleID {
-			return nil, errors.New("role with this name already exists")
+			return nil, ErrRoleNameTaken
 		}
 		roleModel.Name = req.Name
 	}
@@ -147,74 +238,192 @@ func (s *roleService) UpdateRole(roleID uuid.UUID, req *dto.UpdateRoleRequest) (
 		roleModel.Description = req.Description
 	}
 
+	if req.ClearParent {
+		roleModel.ParentID = nil
+	} else if req.ParentID != nil {
+		if err := s.checkParent(ctx, roleID, *req.ParentID); err != nil {
+			return nil, err
+		}
+		roleModel.ParentID = req.ParentID
+	}
+
 	// Save changes
-	if err := s.repo.Update(roleModel); err != nil {
+	if err := s.repo.Update(ctx, roleModel); err != nil {
 		return nil, err
 	}
+	s.invalidatePermissions(ctx)
+	s.invalidateResponses(ctx)
 
 	response := s.modelToResponse(roleModel)
+	s.record(ctx, "role.updated", roleID, before, response)
 	return &response, nil
 }
 
 // DeleteRole deletes a role
-func (s *roleService) DeleteRole(roleID uuid.UUID) error {
+// Roles still assigned to users are only deleted when reassignTo names the role those users move to
+func (s *roleService) DeleteRole(ctx context.Context, roleID uuid.UUID, reassignTo *uuid.UUID) error {
 	// Check if role exists
-	_, err := s.repo.FindByID(roleID)
+	roleModel, err := s.repo.FindByID(ctx, roleID)
+	if err != nil {
+		return repository.LookupError(err, ErrRoleNotFound, "role")
+	}
+
+	if roleModel.IsSystem {
+		return ErrSystemRole
+	}
+
+	assigned, err := s.repo.CountUsers(ctx, roleID)
 	if err != nil {
-		return errors.New("role not found")
+		return err
+	}
+	if assigned > 0 {
+		if reassignTo == nil {
+			return ErrRoleInUse
+		}
+		if *reassignTo == roleID {
+			return apperror.New(apperror.ErrValidation, "cannot reassign users to the role being deleted")
+		}
+		if _, err := s.repo.FindByID(ctx, *reassignTo); err != nil {
+			return repository.LookupError(err, ErrReassignRoleNotFound, "reassignment role")
+		}
+		if err := s.repo.ReassignUsers(ctx, roleID, *reassignTo); err != nil {
+			return err
+		}
+	}
+
+	// Child roles stop inheriting from the deleted role
+	if err := s.repo.ClearParent(ctx, roleID); err != nil {
+		return err
 	}
 
 	// Delete role
-	if err := s.repo.Delete(roleID); err != nil {
+	if err := s.repo.Delete(ctx, roleID); err != nil {
 		return err
 	}
+	s.invalidatePermissions(ctx)
+	s.invalidateResponses(ctx)
+	s.record(ctx, "role.deleted", roleID, s.modelToResponse(roleModel), reassignment(reassignTo))
 
 	return nil
 }
 
-// SeedInitialRoles seeds the database with initial roles
-func (s *roleService) SeedInitialRoles() error {
-	initialRoles := []*dto.CreateRoleRequest{
-		{
-			Name:        "SuperAdmin",
-			Slug:        "super_admin",
-			Permissions: []string{"*"},
-			Description: "Full system access with all permissions",
-		},
-		{
-			Name:        "Admin",
-			Slug:        "admin",
-			Permissions: []string{
-				"users.create",
-				"users.read",
-				"users.update",
-				"users.delete",
-				"roles.read",
-				"roles.assign",
-			},
-			Description: "Administrative access for user and role management",
-		},
-		{
-			Name:        "User",
-			Slug:        "user",
-			Permissions: []string{
-				"users.read",
-				"users.update",
-			},
-			Description: "Standard user access with self-profile management",
-		},
+// EffectivePermissions returns a role's own permissions merged with those inherited from its ancestors
+func (s *roleService) EffectivePermissions(ctx context.Context, roleID uuid.UUID) ([]string, error) {
+	roleModel, err := s.repo.FindByID(ctx, roleID)
+	if err != nil {
+		return nil, repository.LookupError(err, ErrRoleNotFound, "role")
+	}
+
+	return s.resolvePermissions(ctx, roleModel)
+}
+
+// resolvePermissions walks up the parent chain collecting permissions
+// A visited set guards against cycles created outside the API (e.g. direct SQL)
+func (s *roleService) resolvePermissions(ctx context.Context, roleModel *Role) ([]string, error) {
+	set := map[string]struct{}{}
+	visited := map[uuid.UUID]bool{}
+
+	for current := roleModel; current != nil && !visited[current.ID]; {
+		visited[current.ID] = true
+		for _, p := range current.Permissions {
+			set[p] = struct{}{}
+		}
+
+		if current.ParentID == nil {
+			break
+		}
+		parent, err := s.repo.FindByID(ctx, *current.ParentID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				break
+			}
+			return nil, err
+		}
+		current = parent
+	}
+
+	permissions := make([]string, 0, len(set))
+	for p := range set {
+		permissions = append(permissions, p)
+	}
+	sort.Strings(permissions)
+	return permissions, nil
+}
+
+// checkParent ensures parentID exists and making it the parent of roleID doesn't create a cycle
+func (s *roleService) checkParent(ctx context.Context, roleID, parentID uuid.UUID) error {
+	visited := map[uuid.UUID]bool{}
+	for id := &parentID; id != nil; {
+		if *id == roleID {
+			return ErrRoleInheritanceLoop
+		}
+		if visited[*id] {
+			break
+		}
+		visited[*id] = true
+
+		parent, err := s.repo.FindByID(ctx, *id)
+		if err != nil {
+			if *id == parentID {
+				return repository.LookupError(err, ErrParentRoleNotFound, "parent role")
+			}
+			return fmt.Errorf("failed to find role: %w", err)
+		}
+		id = parent.ParentID
+	}
+	return nil
+}
+
+// record adds a change of a role, made by the authenticated user, to the audit trail
+func (s *roleService) record(ctx context.Context, action string, roleID uuid.UUID, before, after any) {
+	s.trail.Record(ctx, audit.ActorFromContext(ctx), action, audit.Resource{Type: "role", ID: roleID.String()}, before, after)
+}
+
+// reassignment is the audit trail state after deleting a role: where its users moved, if anywhere
+func reassignment(reassignTo *uuid.UUID) any {
+	if reassignTo == nil {
+		return nil
 	}
+	return map[string]uuid.UUID{"reassigned_to": *reassignTo}
+}
+
+// invalidatePermissions drops every cached live permission set and user profile after a role change
+// Role changes can affect any user (directly or through inheritance), so the whole cache goes
+func (s *roleService) invalidatePermissions(ctx context.Context) {
+	_ = s.permCache.InvalidateAll(context.WithoutCancel(ctx))
+	_ = s.profiles.Clear(context.WithoutCancel(ctx))
+}
+
+// invalidateResponses drops cached role lists, and user lists which embed role names, after a role change
+func (s *roleService) invalidateResponses(ctx context.Context) {
+	_ = s.responses.Invalidate(context.WithoutCancel(ctx), cache.NamespaceRoles, cache.NamespaceUsers)
+}
 
-	for _, roleReq := range initialRoles {
-		existing, _ := s.repo.FindBySlug(roleReq.Slug)
+// SeedInitialRoles seeds the database with the built-in role profiles
+// Existing roles keep their permissions (use the permission sync to reconcile them) but are flagged as system roles
+func (s *roleService) SeedInitialRoles(ctx context.Context) error {
+	for _, profile := range DefaultProfiles {
+		existing, err := s.repo.FindBySlug(ctx, profile.Slug)
+		if err != nil {
+			return fmt.Errorf("failed to find role %q: %w", profile.Slug, err)
+		}
 		if existing == nil {
 			roleModel := &Role{
-				Name:        roleReq.Name,
-				Slug:        roleReq.Slug,
-				Permissions: StringSlice(roleReq.Permissions),
-				Description: roleReq.Description,
+				Name:        profile.Name,
+				Slug:        profile.Slug,
+				Permissions: StringSlice(profile.Permissions()),
+				Description: profile.Description,
+				IsSystem:    true,
 			}
-			if err := s.repo.Create(roleModel); err != nil {
+			if err := s.repo.Create(ctx, roleModel); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !existing.IsSystem {
+			existing.IsSystem = true
+			if err := s.repo.Update(ctx, existing); err != nil {
 				return err
 			}
 		}
@@ -223,6 +432,72 @@ func (s *roleService) SeedInitialRoles() error {
 	return nil
 }
 
+// PlanPermissionSync reports how the built-in roles differ from their profiles without changing anything
+func (s *roleService) PlanPermissionSync(ctx context.Context) (*dto.PermissionSyncResponse, error) {
+	changes, _, err := s.permissionSyncChanges(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PermissionSyncResponse{DryRun: true, Changes: changes}, nil
+}
+
+// ApplyPermissionSync rewrites the permissions of every built-in role that drifted from its profile
+func (s *roleService) ApplyPermissionSync(ctx context.Context) (*dto.PermissionSyncResponse, error) {
+	changes, roles, err := s.permissionSyncChanges(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, roleModel := range roles {
+		if err := s.repo.Update(ctx, roleModel); err != nil {
+			return nil, err
+		}
+		s.record(ctx, "role.permissions_synced", roleModel.ID, map[string][]string{"removed": changes[i].Removed}, map[string][]string{"added": changes[i].Added})
+	}
+	if len(roles) > 0 {
+		s.invalidatePermissions(ctx)
+		s.invalidateResponses(ctx)
+	}
+
+	return &dto.PermissionSyncResponse{DryRun: false, Changes: changes}, nil
+}
+
+// permissionSyncChanges compares each built-in role with its profile
+// It returns the diffs and the drifted roles with their permissions already set to the profile
+// Custom roles (without a profile) and profiles whose role hasn't been seeded are skipped
+func (s *roleService) permissionSyncChanges(ctx context.Context) ([]dto.RolePermissionDiff, []*Role, error) {
+	changes := []dto.RolePermissionDiff{}
+	var roles []*Role
+
+	for _, profile := range DefaultProfiles {
+		roleModel, err := s.repo.FindBySlug(ctx, profile.Slug)
+		if err != nil {
+			return nil, nil, err
+		}
+		if roleModel == nil {
+			continue
+		}
+
+		desired := profile.Permissions()
+		added, removed := diffPermissions(roleModel.Permissions, desired)
+		if len(added) == 0 && len(removed) == 0 {
+			continue
+		}
+
+		changes = append(changes, dto.RolePermissionDiff{
+			RoleID:  roleModel.ID,
+			Slug:    roleModel.Slug,
+			Added:   added,
+			Removed: removed,
+		})
+		roleModel.Permissions = StringSlice(desired)
+		roles = append(roles, roleModel)
+	}
+
+	return changes, roles, nil
+}
+
 // modelToResponse converts Role model to RoleResponse
 func (s *roleService) modelToResponse(role *Role) dto.RoleResponse {
 	return dto.RoleResponse{
@@ -231,6 +506,9 @@ func (s *roleService) modelToResponse(role *Role) dto.RoleResponse {
 		Slug:        role.Slug,
 		Permissions: []string(role.Permissions),
 		Description: role.Description,
+		ParentID:    role.ParentID,
+		IsSystem:    role.IsSystem,
+		Version:     role.Version,
 		CreatedAt:   role.CreatedAt,
 		UpdatedAt:   role.UpdatedAt,
 	}
@@ -0,0 +1,66 @@
+package tenant
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go_boilerplate/internal/shared/tenant"
+
+	"github.com/google/uuid"
+)
+
+// SettingsData holds a tenant's configuration document as JSONB
+type SettingsData json.RawMessage
+
+// Value implements the driver.Valuer interface for database storage
+func (s SettingsData) Value() (driver.Value, error) {
+	if len(s) == 0 {
+		return "{}", nil
+	}
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (s *SettingsData) Scan(value interface{}) error {
+	if value == nil {
+		*s = SettingsData("{}")
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	*s = append(SettingsData(nil), bytes...)
+	return nil
+}
+
+// Tenant represents an organisation whose data is isolated by tenant_id
+type Tenant struct {
+	ID        uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Slug      string       `json:"slug" gorm:"type:varchar(63);uniqueIndex;not null"` // Subdomain label and X-Tenant-ID value
+	Name      string       `json:"name" gorm:"type:varchar(255);not null"`
+	Settings  SettingsData `json:"settings" gorm:"type:jsonb;not null;default:'{}'"`
+	IsActive  bool         `json:"is_active" gorm:"not null;default:true"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// TableName specifies the table name for Tenant model
+func (Tenant) TableName() string {
+	return "m_tenants"
+}
+
+// toContext converts the row to the tenant stored in request contexts
+func (t *Tenant) toContext() (*tenant.Tenant, error) {
+	settings := map[string]any{}
+	if len(t.Settings) > 0 {
+		if err := json.Unmarshal(t.Settings, &settings); err != nil {
+			return nil, err
+		}
+	}
+	return &tenant.Tenant{ID: t.ID, Slug: t.Slug, Name: t.Name, Settings: settings}, nil
+}
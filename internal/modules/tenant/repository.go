@@ -0,0 +1,42 @@
+package tenant
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TenantRepository defines the interface for tenant data operations
+type TenantRepository interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*Tenant, error)
+	FindBySlug(ctx context.Context, slug string) (*Tenant, error)
+}
+
+// tenantRepository implements TenantRepository interface
+type tenantRepository struct {
+	db *gorm.DB
+}
+
+// NewTenantRepository creates a new tenant repository
+func NewTenantRepository(db *gorm.DB) TenantRepository {
+	return &tenantRepository{db: db}
+}
+
+// FindByID finds a tenant by ID
+func (r *tenantRepository) FindByID(ctx context.Context, id uuid.UUID) (*Tenant, error) {
+	var t Tenant
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&t).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// FindBySlug finds a tenant by slug
+func (r *tenantRepository) FindBySlug(ctx context.Context, slug string) (*Tenant, error) {
+	var t Tenant
+	if err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&t).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,92 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"go_boilerplate/internal/shared/tenant"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxResolverEntries bounds the cache; misses are cached too, so arbitrary keys must not grow it forever
+const maxResolverEntries = 10000
+
+// resolverEntry is a cached lookup; a nil tenant caches an unknown or inactive key
+type resolverEntry struct {
+	tenant    *tenant.Tenant
+	expiresAt time.Time
+}
+
+// resolver implements tenant.Resolver with an in-memory cache in front of m_tenants
+type resolver struct {
+	repo TenantRepository
+	ttl  time.Duration
+
+	mu      sync.RWMutex
+	entries map[string]resolverEntry
+}
+
+// NewResolver creates a tenant resolver that caches lookups (including misses) for ttl; 0 disables the cache
+func NewResolver(repo TenantRepository, ttl time.Duration) tenant.Resolver {
+	return &resolver{repo: repo, ttl: ttl, entries: make(map[string]resolverEntry)}
+}
+
+// Resolve loads an active tenant by UUID or slug
+func (r *resolver) Resolve(ctx context.Context, key string) (*tenant.Tenant, error) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if key == "" {
+		return nil, tenant.ErrNotFound
+	}
+
+	if r.ttl > 0 {
+		r.mu.RLock()
+		entry, ok := r.entries[key]
+		r.mu.RUnlock()
+		if ok && time.Now().Before(entry.expiresAt) {
+			if entry.tenant == nil {
+				return nil, tenant.ErrNotFound
+			}
+			return entry.tenant, nil
+		}
+	}
+
+	t, err := r.load(ctx, key)
+	if err != nil && !errors.Is(err, tenant.ErrNotFound) {
+		return nil, err
+	}
+
+	if r.ttl > 0 {
+		r.mu.Lock()
+		if len(r.entries) >= maxResolverEntries {
+			r.entries = make(map[string]resolverEntry)
+		}
+		r.entries[key] = resolverEntry{tenant: t, expiresAt: time.Now().Add(r.ttl)}
+		r.mu.Unlock()
+	}
+	return t, err
+}
+
+// load reads the tenant from the database; inactive tenants are reported as not found
+func (r *resolver) load(ctx context.Context, key string) (*tenant.Tenant, error) {
+	var (
+		row *Tenant
+		err error
+	)
+	if id, parseErr := uuid.Parse(key); parseErr == nil {
+		row, err = r.repo.FindByID(ctx, id)
+	} else {
+		row, err = r.repo.FindBySlug(ctx, key)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !row.IsActive) {
+		return nil, tenant.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.toContext()
+}
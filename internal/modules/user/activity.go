@@ -0,0 +1,93 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// lastSeenKey is the Redis hash of user ID -> unix timestamp of last activity
+	lastSeenKey = "last_seen"
+	// lastSeenFlushingKey holds the snapshot being written to the database
+	lastSeenFlushingKey = "last_seen:flushing"
+)
+
+// ActivityTracker buffers last_seen timestamps in Redis and periodically flushes them to the database
+type ActivityTracker struct {
+	redis  *redis.Client
+	repo   UserRepository
+	logger *logrus.Logger
+}
+
+// NewActivityTracker creates a new activity tracker
+func NewActivityTracker(redisClient *redis.Client, repo UserRepository, logger *logrus.Logger) *ActivityTracker {
+	return &ActivityTracker{
+		redis:  redisClient,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Touch records that a user was active now
+func (t *ActivityTracker) Touch(userID string) {
+	if t.redis == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := t.redis.HSet(ctx, lastSeenKey, userID, time.Now().Unix()).Err(); err != nil {
+		t.logger.Debugf("Failed to record last_seen for user %s: %v", userID, err)
+	}
+}
+
+// Flush writes buffered last_seen timestamps to the database
+func (t *ActivityTracker) Flush(ctx context.Context) error {
+	if t.redis == nil {
+		return nil
+	}
+
+	// Snapshot the hash atomically so activity recorded during the flush isn't lost
+	if err := t.redis.Rename(ctx, lastSeenKey, lastSeenFlushingKey).Err(); err != nil {
+		if err.Error() == "ERR no such key" {
+			return nil
+		}
+		return err
+	}
+
+	entries, err := t.redis.HGetAll(ctx, lastSeenFlushingKey).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+
+	seen := make(map[uuid.UUID]time.Time, len(entries))
+	for id, ts := range entries {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		unix, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			continue
+		}
+		seen[userID] = time.Unix(unix, 0)
+	}
+
+	if err := t.repo.UpdateLastSeen(ctx, seen); err != nil {
+		return err
+	}
+
+	if err := t.redis.Del(ctx, lastSeenFlushingKey).Err(); err != nil {
+		return err
+	}
+
+	t.logger.Debugf("Flushed last_seen for %d users", len(seen))
+	return nil
+}
@@ -4,10 +4,12 @@ import "github.com/google/uuid"
 
 // CreateUserRequest represents a request to create a new user
 type CreateUserRequest struct {
-	Name     string    `json:"name" validate:"required,min=3,max=100"`
-	Email    string    `json:"email" validate:"required,email"`
-	Password string    `json:"password" validate:"required,min=6,max=50"`
-	RoleID   *uuid.UUID `json:"role_id" validate:"omitempty"` // Optional: if not provided, defaults to user role
+	Name     string      `json:"name" validate:"required,min=3,max=100"`
+	Email    string      `json:"email" validate:"required,email"`
+	Username string      `json:"username" validate:"omitempty,username"` // Optional unique handle
+	Password string      `json:"password" validate:"required,min=6,max=50"`
+	RoleIDs  []uuid.UUID `json:"role_ids" validate:"omitempty,dive,required"` // Optional: if not provided, defaults to user role
+	Verified bool        `json:"-"`                                           // Set by sign-up when email verification is disabled; never read from the body
 }
 
 // LoginRequest represents a login request
@@ -18,9 +20,11 @@ type LoginRequest struct {
 
 // UpdateUserRequest represents a request to update a user
 type UpdateUserRequest struct {
-	Name   string    `json:"name" validate:"omitempty,min=3,max=100"`
-	Email  string    `json:"email" validate:"omitempty,email"`
-	RoleID *uuid.UUID `json:"role_id" validate:"omitempty"` // Optional: can update role to user or admin only
+	Name     string      `json:"name" validate:"omitempty,min=3,max=100"`
+	Email    string      `json:"email" validate:"omitempty,email"`
+	Username string      `json:"username" validate:"omitempty,username"`
+	RoleIDs  []uuid.UUID `json:"role_ids" validate:"omitempty,dive,required"` // Optional: replaces roles; user or admin only
+	Version  *int        `json:"version" validate:"omitempty,gte=1"`          // Optional: version the client read; 409 if the user changed since
 }
 
 // ChangePasswordRequest represents a request to change password
@@ -34,3 +38,24 @@ type AssignRoleRequest struct {
 	RoleID uuid.UUID `json:"role_id" validate:"required"`
 }
 
+// SetPermissionOverrideRequest represents a request to grant or deny a permission to a single user
+type SetPermissionOverrideRequest struct {
+	Permission string `json:"permission" validate:"required,permission"`
+	Effect     string `json:"effect" validate:"required,oneof=grant deny"`
+}
+
+// UpdatePreferencesRequest represents a partial update of the current user's preferences
+// Omitted fields keep their current value
+type UpdatePreferencesRequest struct {
+	Theme         *string                               `json:"theme" validate:"omitempty,oneof=system light dark"`
+	Locale        *string                               `json:"locale" validate:"omitempty,bcp47_language_tag"`
+	Notifications *UpdateNotificationPreferencesRequest `json:"notifications" validate:"omitempty"`
+}
+
+// UpdateNotificationPreferencesRequest represents a partial update of notification preferences
+type UpdateNotificationPreferencesRequest struct {
+	Email          *bool `json:"email"`
+	SecurityAlerts *bool `json:"security_alerts"`
+	ProductUpdates *bool `json:"product_updates"`
+	Marketing      *bool `json:"marketing"`
+}
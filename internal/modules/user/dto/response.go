@@ -3,28 +3,45 @@ package dto
 import (
 	"time"
 
+	auditdto "go_boilerplate/internal/modules/audit/dto"
+	"go_boilerplate/internal/shared/permission"
+	"go_boilerplate/internal/shared/utils"
+
 	"github.com/google/uuid"
 )
 
-// UserResponse represents a user response (without password and role)
+// UserResponse represents a user response (without password; roles only with ?include=roles)
 type UserResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	IsVerified bool     `json:"is_verified"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Email      string     `json:"email"`
+	Username   *string    `json:"username,omitempty"`
+	IsVerified bool       `json:"is_verified"`
+	Version    int        `json:"version"` // Send back in updates to detect concurrent changes
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	Roles      []RoleInfo `json:"roles,omitempty"` // Only with ?include=roles
+}
+
+// AdminUserResponse represents a user response for administrators, including activity timestamps
+type AdminUserResponse struct {
+	UserResponse
+	LastLoginAt *time.Time `json:"last_login_at"`
+	LastSeenAt  *time.Time `json:"last_seen_at"`
 }
 
 // UserRoleResponse represents a user response with role information
 type UserRoleResponse struct {
-	ID        uuid.UUID  `json:"id"`
-	Name      string     `json:"name"`
-	Email     string     `json:"email"`
-	Role      *RoleInfo  `json:"role"`
-	IsVerified bool      `json:"is_verified"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
+	ID                  uuid.UUID            `json:"id"`
+	Name                string               `json:"name"`
+	Email               string               `json:"email"`
+	Username            *string              `json:"username,omitempty"`
+	Roles               []RoleInfo           `json:"roles"`
+	PermissionOverrides []PermissionOverride `json:"permission_overrides"` // Per-user grants/denies applied on top of roles
+	IsVerified          bool                 `json:"is_verified"`
+	Version             int                  `json:"version"` // Send back in updates to detect concurrent changes
+	CreatedAt           time.Time            `json:"created_at"`
+	UpdatedAt           time.Time            `json:"updated_at"`
 }
 
 // RoleInfo represents simplified role information
@@ -35,10 +52,66 @@ type RoleInfo struct {
 	Permissions []string  `json:"permissions"`
 }
 
+// PermissionOverride represents a permission granted or denied to a single user
+type PermissionOverride struct {
+	Permission string    `json:"permission"`
+	Effect     string    `json:"effect"` // grant or deny
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// RoleSlugs returns the slugs of every role assigned to the user
+func (r *UserRoleResponse) RoleSlugs() []string {
+	slugs := make([]string, len(r.Roles))
+	for i, role := range r.Roles {
+		slugs[i] = role.Slug
+	}
+	return slugs
+}
+
+// Permissions returns the permissions of all roles merged without duplicates, with the user's overrides applied
+// Denied permissions are removed even when a role grants them through a wildcard
+func (r *UserRoleResponse) Permissions() []string {
+	granted := []string{}
+	for _, role := range r.Roles {
+		granted = append(granted, role.Permissions...)
+	}
+
+	var denied []string
+	for _, o := range r.PermissionOverrides {
+		if o.Effect == "deny" {
+			denied = append(denied, o.Permission)
+		} else {
+			granted = append(granted, o.Permission)
+		}
+	}
+
+	return permission.Resolve(granted, denied)
+}
+
+// HasRole reports whether the user has the role with the given slug
+func (r *UserRoleResponse) HasRole(slug string) bool {
+	for _, role := range r.Roles {
+		if role.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
 // UsersResponse represents a paginated list of users
 type UsersResponse struct {
-	Users []UserResponse `json:"users"`
-	Meta  PaginationMeta  `json:"meta"`
+	Users []AdminUserResponse `json:"users"`
+	Meta  PaginationMeta      `json:"meta"`
+}
+
+// PageItems returns the listed users (utils.Paginated)
+func (r UsersResponse) PageItems() any {
+	return r.Users
+}
+
+// PageMeta returns the pagination metadata (utils.Paginated)
+func (r UsersResponse) PageMeta() utils.PaginationMeta {
+	return utils.PaginationMeta(r.Meta)
 }
 
 // PaginationMeta contains pagination metadata
@@ -49,4 +122,106 @@ type PaginationMeta struct {
 	TotalPages int `json:"total_pages"`
 }
 
+// Preferences represents a user's preferences document
+type Preferences struct {
+	Theme         string                  `json:"theme"`
+	Locale        string                  `json:"locale"`
+	Notifications NotificationPreferences `json:"notifications"`
+}
 
+// NotificationPreferences represents which notifications a user receives
+type NotificationPreferences struct {
+	Email          bool `json:"email"`
+	SecurityAlerts bool `json:"security_alerts"`
+	ProductUpdates bool `json:"product_updates"`
+	Marketing      bool `json:"marketing"`
+}
+
+// DefaultPreferences returns the preferences of a user who hasn't changed anything
+func DefaultPreferences() Preferences {
+	return Preferences{
+		Theme:  "system",
+		Locale: "en",
+		Notifications: NotificationPreferences{
+			Email:          true,
+			SecurityAlerts: true,
+			ProductUpdates: true,
+			Marketing:      false,
+		},
+	}
+}
+
+// PreferencesResponse represents a user's effective preferences (defaults merged with stored values)
+type PreferencesResponse struct {
+	Preferences
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// DataExportResponse represents the status of a GDPR data export
+type DataExportResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Status      string     `json:"status"` // pending, processing, completed, failed
+	FileName    string     `json:"file_name,omitempty"`
+	Size        int64      `json:"size"`
+	Error       string     `json:"error,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// DataExportArchive represents the JSON document stored inside a data export archive
+// Every table holding rows about a user belongs here; add a field when a new one lands
+type DataExportArchive struct {
+	GeneratedAt   time.Time                     `json:"generated_at"`
+	Profile       UserRoleResponse              `json:"profile"` // Includes permission overrides
+	Preferences   PreferencesResponse           `json:"preferences"`
+	OAuthAccounts []ExportedOAuth               `json:"oauth_accounts"`
+	Sessions      []ExportedSession             `json:"sessions"`
+	AuditEvents   []auditdto.AuditEventResponse `json:"audit_events"` // Requests made by the user (t_audit_events)
+	AuditLogs     []auditdto.AuditLogResponse   `json:"audit_logs"`   // Changes made by or to the user (t_audit_logs)
+	AbuseReports  []ExportedAbuseReport         `json:"abuse_reports"`
+	Emails        []ExportedEmail               `json:"emails"`
+}
+
+// ExportedOAuth represents an OAuth account link in a data export (tokens omitted)
+type ExportedOAuth struct {
+	Provider   string    `json:"provider"`
+	ProviderID string    `json:"provider_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ExportedSession represents a login session in a data export (token omitted)
+type ExportedSession struct {
+	ID         uuid.UUID `json:"id"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	DeviceID   string    `json:"device_id"`
+	IsBlocked  bool      `json:"is_blocked"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	LastActive time.Time `json:"last_active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ExportedAbuseReport represents an abuse report filed by the user (triage notes omitted)
+type ExportedAbuseReport struct {
+	ID          uuid.UUID  `json:"id"`
+	Category    string     `json:"category"`
+	Subject     string     `json:"subject"`
+	Description string     `json:"description"`
+	TargetURL   string     `json:"target_url,omitempty"`
+	Status      string     `json:"status"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ExportedEmail represents an email sent to the user (body and attachments omitted, as they may
+// hold one-time codes and reset links)
+type ExportedEmail struct {
+	ID        uuid.UUID  `json:"id"`
+	Subject   string     `json:"subject"`
+	Template  string     `json:"template,omitempty"`
+	Status    string     `json:"status"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
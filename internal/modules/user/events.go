@@ -0,0 +1,25 @@
+package user
+
+import "github.com/google/uuid"
+
+// Domain events published by the user service on the event bus (events.EventBus)
+const (
+	EventUserCreated  = "user.created"
+	EventRoleAssigned = "role.assigned"
+)
+
+// UserCreatedEvent is the payload of user.created
+type UserCreatedEvent struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Email    string    `json:"email"`
+	Username *string   `json:"username,omitempty"`
+	Roles    []string  `json:"roles"` // Slugs of the roles the user was created with
+}
+
+// RoleAssignedEvent is the payload of role.assigned, published when a user gains a role
+type RoleAssignedEvent struct {
+	UserID   uuid.UUID `json:"user_id"`
+	RoleID   uuid.UUID `json:"role_id"`
+	RoleSlug string    `json:"role_slug"`
+	Replaced bool      `json:"replaced"` // The role replaced every other role of the user (AssignRole)
+}
@@ -1,10 +1,14 @@
 package user
 
 import (
+	"errors"
 	"strconv"
 
 	userdto "go_boilerplate/internal/modules/user/dto"
+	"go_boilerplate/internal/shared/authorize"
+	"go_boilerplate/internal/shared/filter"
 	sharedmiddleware "go_boilerplate/internal/shared/middleware"
+	"go_boilerplate/internal/shared/serializer"
 	"go_boilerplate/internal/shared/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -14,22 +18,53 @@ import (
 // UserHandler defines the interface for user HTTP handlers
 type UserHandler interface {
 	GetUser(c *fiber.Ctx) error
+	GetUserByUsername(c *fiber.Ctx) error
 	GetUsers(c *fiber.Ctx) error
 	CreateUser(c *fiber.Ctx) error
 	UpdateUser(c *fiber.Ctx) error
 	DeleteUser(c *fiber.Ctx) error
 	GetCurrentUser(c *fiber.Ctx) error
 	AssignRole(c *fiber.Ctx) error
+	AttachRole(c *fiber.Ctx) error
+	DetachRole(c *fiber.Ctx) error
+	GetPermissionOverrides(c *fiber.Ctx) error
+	SetPermissionOverride(c *fiber.Ctx) error
+	RemovePermissionOverride(c *fiber.Ctx) error
+	RequestDataExport(c *fiber.Ctx) error
+	GetDataExport(c *fiber.Ctx) error
+	DownloadDataExport(c *fiber.Ctx) error
+	GetPreferences(c *fiber.Ctx) error
+	UpdatePreferences(c *fiber.Ctx) error
 }
 
 // userHandler implements UserHandler interface
 type userHandler struct {
-	service UserService
+	service           UserService
+	exportService     DataExportService
+	preferenceService PreferenceService
 }
 
+// userFilters are the fields accepted by ?filter[field][op]= on the user list
+var userFilters = filter.Fields{
+	"name":          {Column: "name", Type: filter.String},
+	"email":         {Column: "email", Type: filter.String},
+	"username":      {Column: "username", Type: filter.String},
+	"is_verified":   {Column: "is_verified", Type: filter.Bool},
+	"created_at":    {Column: "created_at", Type: filter.Time},
+	"last_login_at": {Column: "last_login_at", Type: filter.Time},
+	"last_seen_at":  {Column: "last_seen_at", Type: filter.Time},
+}
+
+// userIncludes are the relations accepted by ?include= on user responses
+var userIncludes = []string{"roles"}
+
 // NewUserHandler creates a new user handler
-func NewUserHandler(service UserService) UserHandler {
-	return &userHandler{service: service}
+func NewUserHandler(service UserService, exportService DataExportService, preferenceService PreferenceService) UserHandler {
+	return &userHandler{
+		service:           service,
+		exportService:     exportService,
+		preferenceService: preferenceService,
+	}
 }
 
 // GetUser gets a user by ID
@@ -39,9 +74,11 @@ func NewUserHandler(service UserService) UserHandler {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "User ID (UUID)"
-// @Success 200 {object} utils.APIResponse{data=userdto.UserResponse} "User retrieved"
-// @Failure 400 {object} utils.APIResponse "Invalid user ID"
-// @Failure 404 {object} utils.APIResponse "User not found"
+// @Param fields query string false "Comma-separated response fields to return (id is always returned), e.g. id,name,email"
+// @Param include query string false "Related resources to embed: roles"
+// @Success 200 {object} utils.APIResponse{data=userdto.UserResponse} "User retrieved (admins also receive last_login_at and last_seen_at)"
+// @Failure 400 {object} utils.ProblemDetails "Invalid user ID or include"
+// @Failure 404 {object} utils.ProblemDetails "User not found"
 // @Router /users/{id} [get]
 func (h *userHandler) GetUser(c *fiber.Ctx) error {
 	// Get user ID from params
@@ -50,10 +87,45 @@ func (h *userHandler) GetUser(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID", err)
 	}
 
-	// Get user
-	user, err := h.service.GetProfile(userID)
+	// Parse sparse fieldset and includes
+	opts, err := serializer.FromQuery(c, userIncludes...)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid include", err)
+	}
+
+	// Admins also see activity timestamps
+	var user any
+	if sharedmiddleware.HasAnyRole(c, "admin", "super_admin") {
+		user, err = h.service.GetAdminProfile(c.UserContext(), userID, opts.Includes("roles"))
+	} else {
+		user, err = h.service.GetProfile(c.UserContext(), userID, opts.Includes("roles"))
+	}
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve user", err)
+	}
+
+	response, err := opts.Apply(user, "")
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusNotFound, "User not found", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve user", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, response, "User retrieved successfully")
+}
+
+// GetUserByUsername gets a user by username handle
+// @Summary Get user by username
+// @Description Retrieve a user's basic profile information by their username handle.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param handle path string true "Username"
+// @Success 200 {object} utils.APIResponse{data=userdto.UserResponse} "User retrieved"
+// @Failure 404 {object} utils.ProblemDetails "User not found"
+// @Router /users/by-username/{handle} [get]
+func (h *userHandler) GetUserByUsername(c *fiber.Ctx) error {
+	user, err := h.service.GetProfileByUsername(c.UserContext(), c.Params("handle"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve user", err)
 	}
 
 	return utils.SuccessResponse(c, fiber.StatusOK, user, "User retrieved successfully")
@@ -67,8 +139,14 @@ func (h *userHandler) GetUser(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param page query int false "Page number (default: 1)"
 // @Param limit query int false "Items per page (default: 10, max: 100)"
-// @Success 200 {object} utils.APIResponse{data=[]userdto.UserResponse} "Users retrieved"
-// @Failure 500 {object} utils.APIResponse "Internal server error"
+// @Param filter[email][like] query string false "Filter by email (operators: eq, ne, like, in, null)"
+// @Param filter[is_verified] query bool false "Filter by verification status"
+// @Param filter[created_at][gte] query string false "Created at or after (RFC 3339 or YYYY-MM-DD; operators: eq, gt, gte, lt, lte)"
+// @Param fields query string false "Comma-separated user fields to return (id is always returned), e.g. id,name,email"
+// @Param include query string false "Related resources to embed: roles"
+// @Success 200 {object} utils.APIResponse{data=userdto.UsersResponse} "Users retrieved"
+// @Failure 400 {object} utils.ProblemDetails "Invalid filter or include"
+// @Failure 500 {object} utils.ProblemDetails "Internal server error"
 // @Router /users [get]
 func (h *userHandler) GetUsers(c *fiber.Ctx) error {
 	// Get pagination params
@@ -83,13 +161,30 @@ func (h *userHandler) GetUsers(c *fiber.Ctx) error {
 		limit = 10
 	}
 
+	// Parse filters
+	f, err := filter.FromQuery(c, userFilters)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid filter", err)
+	}
+
+	// Parse sparse fieldset and includes
+	opts, err := serializer.FromQuery(c, userIncludes...)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid include", err)
+	}
+
 	// Get users
-	users, err := h.service.GetAll(page, limit)
+	users, err := h.service.GetAll(c.UserContext(), page, limit, f, opts.Includes("roles"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve users", err)
+	}
+
+	response, err := opts.Apply(users, "users")
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve users", err)
 	}
 
-	return utils.SuccessResponse(c, fiber.StatusOK, users, "Users retrieved successfully")
+	return utils.SuccessResponse(c, fiber.StatusOK, response, "Users retrieved successfully")
 }
 
 // CreateUser creates a new user
@@ -101,16 +196,16 @@ func (h *userHandler) GetUsers(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param request body userdto.CreateUserRequest true "User data"
 // @Success 201 {object} utils.APIResponse{data=userdto.UserResponse} "User created"
-// @Failure 400 {object} utils.APIResponse "Invalid request"
+// @Failure 400 {object} utils.ProblemDetails "Invalid request"
 // @Router /users [post]
 func (h *userHandler) CreateUser(c *fiber.Ctx) error {
 	// Get validated body from context
 	validatedBody := c.Locals("validatedBody").(*userdto.CreateUserRequest)
 
 	// Create user
-	user, err := h.service.CreateUser(validatedBody)
+	user, err := h.service.CreateUser(c.UserContext(), validatedBody)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to create user", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to create user", err)
 	}
 
 	return utils.SuccessResponse(c, fiber.StatusCreated, user, "User created successfully")
@@ -118,7 +213,7 @@ func (h *userHandler) CreateUser(c *fiber.Ctx) error {
 
 // UpdateUser updates a user
 // @Summary Update user profile
-// @Description Update user details. Users can update their own profile, or admins can update any user.
+// @Description Update user details. Users can update their own profile, or users with users.manage (admins) can update users whose roles they hold or whose role permissions they all have (super_admin can update any user).
 // @Tags Users
 // @Accept json
 // @Produce json
@@ -126,9 +221,10 @@ func (h *userHandler) CreateUser(c *fiber.Ctx) error {
 // @Param id path string true "User ID (UUID)"
 // @Param request body userdto.UpdateUserRequest true "Update data"
 // @Success 200 {object} utils.APIResponse{data=userdto.UserResponse} "User updated"
-// @Failure 400 {object} utils.APIResponse "Invalid request"
-// @Failure 401 {object} utils.APIResponse "Unauthorized"
-// @Failure 403 {object} utils.APIResponse "Forbidden"
+// @Failure 400 {object} utils.ProblemDetails "Invalid request"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Failure 403 {object} utils.ProblemDetails "Forbidden"
+// @Failure 409 {object} utils.ProblemDetails "Version conflict: the user was modified since it was read"
 // @Router /users/{id} [put]
 func (h *userHandler) UpdateUser(c *fiber.Ctx) error {
 	// Get user ID from params
@@ -137,42 +233,59 @@ func (h *userHandler) UpdateUser(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID", err)
 	}
 
-	// Get authenticated user ID from context
-	authUserIDStr, ok := sharedmiddleware.GetUserIDFromContext(c)
-	if !ok {
-		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Unauthorized", nil)
-	}
-
-	authUserID, err := uuid.Parse(authUserIDStr)
-	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid auth user ID", err)
-	}
-
 	// Get validated body from context
 	validatedBody := c.Locals("validatedBody").(*userdto.UpdateUserRequest)
 
-	// Check if user is updating their own profile or has admin role
-	roleSlug, hasRole := sharedmiddleware.GetRoleSlugFromContext(c)
-	isAdmin := hasRole && (roleSlug == "admin" || roleSlug == "super_admin")
-
-	if authUserID != userID && !isAdmin {
+	// Users may update their own profile; updating others requires users.manage
+	if !authorize.CanAccess(c, userID, PermUsersManage) {
 		return utils.ErrorResponse(c, fiber.StatusForbidden, "You can only update your own profile", nil)
 	}
 
-	// Non-admin users cannot update their own role
-	if authUserID == userID && !isAdmin && validatedBody.RoleID != nil {
+	// Only users with users.manage can change roles, including their own
+	if validatedBody.RoleIDs != nil && !authorize.HasPermission(c, PermUsersManage) {
 		return utils.ErrorResponse(c, fiber.StatusForbidden, "You cannot update your own role", nil)
 	}
 
+	// users.manage only reaches users whose roles the caller holds too, so an admin can't edit
+	// or re-role a super_admin; super_admin may manage anyone
+	if callerID, _ := sharedmiddleware.GetUserIDFromContext(c); callerID != userID.String() && !sharedmiddleware.HasAnyRole(c, "super_admin") {
+		target, err := h.service.GetProfileWithRole(c.UserContext(), userID)
+		if err != nil {
+			return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to update user", err)
+		}
+		for _, roleInfo := range target.Roles {
+			if !holdsRole(c, roleInfo) {
+				return utils.ErrorResponse(c, fiber.StatusForbidden, "You cannot manage a user with a role you don't hold", nil)
+			}
+		}
+	}
+
 	// Update user
-	user, err := h.service.UpdateUser(userID, validatedBody)
+	user, err := h.service.UpdateUser(c.UserContext(), userID, validatedBody)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to update user", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to update user", err)
 	}
 
 	return utils.SuccessResponse(c, fiber.StatusOK, user, "User updated successfully")
 }
 
+// holdsRole reports whether the caller holds r: it is one of their roles, or every permission
+// it grants is theirs too. super_admin is only held by super_admins.
+func holdsRole(c *fiber.Ctx, r userdto.RoleInfo) bool {
+	if sharedmiddleware.HasAnyRole(c, r.Slug) {
+		return true
+	}
+	if r.Slug == "super_admin" {
+		return false
+	}
+	for _, perm := range r.Permissions {
+		if !sharedmiddleware.HasPermission(c, perm) {
+			return false
+		}
+	}
+	return true
+}
+
 // DeleteUser deletes a user
 // @Summary Admin: Delete user
 // @Description Delete a user account (Admin only).
@@ -181,7 +294,7 @@ func (h *userHandler) UpdateUser(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param id path string true "User ID (UUID)"
 // @Success 200 {object} utils.APIResponse "User deleted"
-// @Failure 400 {object} utils.APIResponse "Invalid user ID"
+// @Failure 400 {object} utils.ProblemDetails "Invalid user ID"
 // @Router /users/{id} [delete]
 func (h *userHandler) DeleteUser(c *fiber.Ctx) error {
 	// Get user ID from params
@@ -191,8 +304,8 @@ func (h *userHandler) DeleteUser(c *fiber.Ctx) error {
 	}
 
 	// Delete user
-	if err := h.service.DeleteUser(userID); err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to delete user", err)
+	if err := h.service.DeleteUser(c.UserContext(), userID); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to delete user", err)
 	}
 
 	return utils.SuccessResponse(c, fiber.StatusOK, nil, "User deleted successfully")
@@ -205,7 +318,7 @@ func (h *userHandler) DeleteUser(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {object} utils.APIResponse{data=userdto.UserRoleResponse} "Profile retrieved"
-// @Failure 401 {object} utils.APIResponse "Unauthorized"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
 // @Router /auth/me [get]
 func (h *userHandler) GetCurrentUser(c *fiber.Ctx) error {
 	// Get user ID from context
@@ -220,25 +333,25 @@ func (h *userHandler) GetCurrentUser(c *fiber.Ctx) error {
 	}
 
 	// Get user
-	user, err := h.service.GetProfile(userID)
+	user, err := h.service.GetProfile(c.UserContext(), userID, false)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusNotFound, "User not found", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve user", err)
 	}
 
 	return utils.SuccessResponse(c, fiber.StatusOK, user, "User profile retrieved successfully")
 }
 
-// AssignRole assigns a role to a user
+// AssignRole replaces every role of a user with a single role
 // @Summary Admin: Assign role
-// @Description Assign a specific role to a user account (Admin only).
+// @Description Replace all roles of a user account with a specific role (SuperAdmin only).
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "User ID (UUID)"
 // @Param request body userdto.AssignRoleRequest true "Role assignment data"
-// @Success 200 {object} utils.APIResponse{data=userdto.UserResponse} "Role assigned"
-// @Failure 400 {object} utils.APIResponse "Invalid request"
+// @Success 200 {object} utils.APIResponse{data=userdto.UserRoleResponse} "Role assigned"
+// @Failure 400 {object} utils.ProblemDetails "Invalid request"
 // @Router /users/{id}/role [patch]
 func (h *userHandler) AssignRole(c *fiber.Ctx) error {
 	// Get user ID from params
@@ -251,10 +364,296 @@ func (h *userHandler) AssignRole(c *fiber.Ctx) error {
 	validatedBody := c.Locals("validatedBody").(*userdto.AssignRoleRequest)
 
 	// Assign role
-	user, err := h.service.AssignRole(userID, validatedBody.RoleID)
+	user, err := h.service.AssignRole(c.UserContext(), userID, validatedBody.RoleID)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to assign role", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to assign role", err)
 	}
 
 	return utils.SuccessResponse(c, fiber.StatusOK, user, "Role assigned successfully")
 }
+
+// AttachRole adds a role to a user
+// @Summary Admin: Attach role
+// @Description Add a role to the roles a user account already has (SuperAdmin only).
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Param request body userdto.AssignRoleRequest true "Role to attach"
+// @Success 200 {object} utils.APIResponse{data=userdto.UserRoleResponse} "Role attached"
+// @Failure 400 {object} utils.ProblemDetails "Invalid request"
+// @Router /users/{id}/roles [post]
+func (h *userHandler) AttachRole(c *fiber.Ctx) error {
+	// Get user ID from params
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID", err)
+	}
+
+	// Get validated body from context
+	validatedBody := c.Locals("validatedBody").(*userdto.AssignRoleRequest)
+
+	// Attach role
+	user, err := h.service.AttachRole(c.UserContext(), userID, validatedBody.RoleID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to attach role", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, user, "Role attached successfully")
+}
+
+// DetachRole removes a role from a user
+// @Summary Admin: Detach role
+// @Description Remove a role from a user account; the user must keep at least one role (SuperAdmin only).
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Param roleId path string true "Role ID (UUID)"
+// @Success 200 {object} utils.APIResponse{data=userdto.UserRoleResponse} "Role detached"
+// @Failure 400 {object} utils.ProblemDetails "Invalid request"
+// @Router /users/{id}/roles/{roleId} [delete]
+func (h *userHandler) DetachRole(c *fiber.Ctx) error {
+	// Get user ID from params
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID", err)
+	}
+
+	roleID, err := uuid.Parse(c.Params("roleId"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid role ID", err)
+	}
+
+	// Detach role
+	user, err := h.service.DetachRole(c.UserContext(), userID, roleID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to detach role", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, user, "Role detached successfully")
+}
+
+// GetPermissionOverrides lists the permissions granted or denied to a user on top of their roles
+// @Summary Admin: List permission overrides
+// @Description List per-user permission grants and denies applied on top of the user's roles (SuperAdmin only).
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} utils.APIResponse{data=[]userdto.PermissionOverride} "Permission overrides retrieved"
+// @Failure 400 {object} utils.ProblemDetails "Invalid user ID"
+// @Router /users/{id}/permissions [get]
+func (h *userHandler) GetPermissionOverrides(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID", err)
+	}
+
+	overrides, err := h.service.GetPermissionOverrides(c.UserContext(), userID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get permission overrides", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, overrides, "Permission overrides retrieved successfully")
+}
+
+// SetPermissionOverride grants or denies a permission to a user
+// @Summary Admin: Set permission override
+// @Description Grant or deny a single permission to a user regardless of their roles. A deny wins over any role grant, including wildcards (SuperAdmin only).
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Param request body userdto.SetPermissionOverrideRequest true "Permission and effect"
+// @Success 200 {object} utils.APIResponse{data=[]userdto.PermissionOverride} "Permission override saved"
+// @Failure 400 {object} utils.ProblemDetails "Invalid request"
+// @Router /users/{id}/permissions [put]
+func (h *userHandler) SetPermissionOverride(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID", err)
+	}
+
+	validatedBody := c.Locals("validatedBody").(*userdto.SetPermissionOverrideRequest)
+
+	overrides, err := h.service.SetPermissionOverride(c.UserContext(), userID, validatedBody)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to save permission override", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, overrides, "Permission override saved successfully")
+}
+
+// RemovePermissionOverride removes a user's override for a permission
+// @Summary Admin: Remove permission override
+// @Description Remove a per-user permission override so the permission follows the user's roles again (SuperAdmin only).
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Param permission path string true "Permission name (e.g. users.delete)"
+// @Success 200 {object} utils.APIResponse{data=[]userdto.PermissionOverride} "Permission override removed"
+// @Failure 400 {object} utils.ProblemDetails "Invalid user ID"
+// @Failure 404 {object} utils.ProblemDetails "Override not found"
+// @Router /users/{id}/permissions/{permission} [delete]
+func (h *userHandler) RemovePermissionOverride(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid user ID", err)
+	}
+
+	overrides, err := h.service.RemovePermissionOverride(c.UserContext(), userID, c.Params("permission"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to remove permission override", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, overrides, "Permission override removed successfully")
+}
+
+// RequestDataExport starts a GDPR data export for the authenticated user
+// @Summary Request data export
+// @Description Start compiling an archive of all data held about the current user. Returns the in-progress or still downloadable export if one exists.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} utils.APIResponse{data=userdto.DataExportResponse} "Export requested"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Router /users/me/export [get]
+func (h *userHandler) RequestDataExport(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Unauthorized", nil)
+	}
+
+	export, err := h.exportService.RequestExport(c.UserContext(), userID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to request data export", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusAccepted, export, "Data export requested successfully")
+}
+
+// GetDataExport gets the status of a data export
+// @Summary Get data export status
+// @Description Retrieve the status of a data export requested by the current user.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param exportId path string true "Export ID (UUID)"
+// @Success 200 {object} utils.APIResponse{data=userdto.DataExportResponse} "Export retrieved"
+// @Failure 400 {object} utils.ProblemDetails "Invalid export ID"
+// @Failure 404 {object} utils.ProblemDetails "Export not found"
+// @Router /users/me/export/{exportId} [get]
+func (h *userHandler) GetDataExport(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Unauthorized", nil)
+	}
+
+	exportID, err := uuid.Parse(c.Params("exportId"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid export ID", err)
+	}
+
+	export, err := h.exportService.GetExport(c.UserContext(), userID, exportID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to retrieve export", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, export, "Data export retrieved successfully")
+}
+
+// DownloadDataExport downloads a completed data export archive
+// @Summary Download data export
+// @Description Download the ZIP archive of a completed data export.
+// @Tags Users
+// @Produce application/zip
+// @Security BearerAuth
+// @Param exportId path string true "Export ID (UUID)"
+// @Success 200 {file} file "ZIP archive"
+// @Failure 400 {object} utils.ProblemDetails "Invalid export ID"
+// @Failure 409 {object} utils.ProblemDetails "Export not ready or expired"
+// @Router /users/me/export/{exportId}/download [get]
+func (h *userHandler) DownloadDataExport(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Unauthorized", nil)
+	}
+
+	exportID, err := uuid.Parse(c.Params("exportId"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Invalid export ID", err)
+	}
+
+	export, err := h.exportService.DownloadExport(c.UserContext(), userID, exportID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to download export", err)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+export.FileName+`"`)
+	return c.Send(export.Archive)
+}
+
+// GetPreferences gets the authenticated user's preferences
+// @Summary Get preferences
+// @Description Retrieve the current user's preferences. Options the user never changed are returned with their default value.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.APIResponse{data=userdto.PreferencesResponse} "Preferences retrieved"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Router /users/me/preferences [get]
+func (h *userHandler) GetPreferences(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Unauthorized", nil)
+	}
+
+	preferences, err := h.preferenceService.GetPreferences(c.UserContext(), userID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to get preferences", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, preferences, "Preferences retrieved successfully")
+}
+
+// UpdatePreferences updates the authenticated user's preferences
+// @Summary Update preferences
+// @Description Update the current user's preferences. Omitted fields keep their current value.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body userdto.UpdatePreferencesRequest true "Preferences to change"
+// @Success 200 {object} utils.APIResponse{data=userdto.PreferencesResponse} "Preferences updated"
+// @Failure 400 {object} utils.ProblemDetails "Validation failed"
+// @Failure 401 {object} utils.ProblemDetails "Unauthorized"
+// @Router /users/me/preferences [put]
+func (h *userHandler) UpdatePreferences(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Unauthorized", nil)
+	}
+
+	validatedBody := c.Locals("validatedBody").(*userdto.UpdatePreferencesRequest)
+
+	preferences, err := h.preferenceService.UpdatePreferences(c.UserContext(), userID, validatedBody)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "Failed to update preferences", err)
+	}
+
+	return utils.SuccessResponse(c, fiber.StatusOK, preferences, "Preferences updated successfully")
+}
+
+// currentUserID extracts the authenticated user's ID from context
+func currentUserID(c *fiber.Ctx) (uuid.UUID, error) {
+	userIDStr, ok := sharedmiddleware.GetUserIDFromContext(c)
+	if !ok {
+		return uuid.Nil, errors.New("user not found in context")
+	}
+	return uuid.Parse(userIDStr)
+}
@@ -0,0 +1,99 @@
+package user
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	userdto "go_boilerplate/internal/modules/user/dto"
+	"go_boilerplate/internal/shared/config"
+	sharedmiddleware "go_boilerplate/internal/shared/middleware"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// rolePermissions are the permissions of the roles a stubbed target holds
+var rolePermissions = map[string][]string{
+	"super_admin": {"*"},
+	"admin":       {"users.read", "users.update", "users.manage", "roles.read"},
+	"user":        {"users.read", "users.update"},
+	"editor":      {"posts.update"},
+}
+
+// stubUserService serves the target's roles and records whether the update went through; the
+// other UserService methods are not used by UpdateUser
+type stubUserService struct {
+	UserService
+	targetRoles []string
+	updated     bool
+}
+
+func (s *stubUserService) GetProfileWithRole(_ context.Context, userID uuid.UUID) (*userdto.UserRoleResponse, error) {
+	profile := &userdto.UserRoleResponse{ID: userID}
+	for _, slug := range s.targetRoles {
+		profile.Roles = append(profile.Roles, userdto.RoleInfo{Slug: slug, Permissions: rolePermissions[slug]})
+	}
+	return profile, nil
+}
+
+func (s *stubUserService) UpdateUser(_ context.Context, userID uuid.UUID, _ *userdto.UpdateUserRequest) (*userdto.UserRoleResponse, error) {
+	s.updated = true
+	return &userdto.UserRoleResponse{ID: userID}, nil
+}
+
+func TestUpdateUserRefusesTargetsWithRolesTheCallerLacks(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.JWT = config.JWTConfig{Secret: "user-handler-test", AccessExpiry: time.Minute, RefreshExpiry: time.Hour, Issuer: "test"}
+	jwtManager := utils.NewJWTManager(cfg.JWT.Secret, cfg.JWT.AccessExpiry, cfg.JWT.RefreshExpiry, cfg.JWT.Issuer)
+
+	admin := rolePermissions["admin"]
+	for _, tc := range []struct {
+		name              string
+		callerRoles       []string
+		callerPermissions []string
+		targetRoles       []string
+		body              string
+		allowed           bool
+	}{
+		{"admin edits super_admin", []string{"admin"}, admin, []string{"super_admin"}, `{"name":"Renamed"}`, false},
+		{"admin re-roles super_admin", []string{"admin"}, admin, []string{"super_admin"}, `{"role_ids":["` + uuid.NewString() + `"]}`, false},
+		{"wildcard admin edits super_admin", []string{"admin"}, []string{"*"}, []string{"super_admin"}, `{"name":"Renamed"}`, false},
+		{"admin edits user with a role granting more", []string{"admin"}, admin, []string{"user", "editor"}, `{"name":"Renamed"}`, false},
+		{"admin edits user", []string{"admin"}, admin, []string{"user"}, `{"name":"Renamed"}`, true},
+		{"admin edits admin", []string{"admin"}, admin, []string{"admin"}, `{"name":"Renamed"}`, true},
+		{"super_admin edits super_admin", []string{"super_admin"}, []string{"*"}, []string{"super_admin"}, `{"name":"Renamed"}`, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &stubUserService{targetRoles: tc.targetRoles}
+			handler := NewUserHandler(service, nil, nil)
+
+			app := fiber.New()
+			app.Put("/users/:id", sharedmiddleware.JWTAuth(cfg), sharedmiddleware.BodyValidator(&userdto.UpdateUserRequest{}), handler.UpdateUser)
+
+			token, err := jwtManager.GenerateAccessToken(uuid.New(), "caller@example.com", tc.callerRoles, tc.callerPermissions, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest(fiber.MethodPut, "/users/"+uuid.NewString(), strings.NewReader(tc.body))
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			want := fiber.StatusForbidden
+			if tc.allowed {
+				want = fiber.StatusOK
+			}
+			if resp.StatusCode != want || service.updated != tc.allowed {
+				t.Fatalf("status = %d, updated = %v; want %d, updated = %v", resp.StatusCode, service.updated, want, tc.allowed)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package user
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"time"
 
 	roleModule "go_boilerplate/internal/modules/role"
@@ -13,16 +16,19 @@ import (
 
 // User represents a user in the system
 type User struct {
-	ID        uuid.UUID              `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Name      string                 `json:"name" gorm:"type:varchar(100);not null"`
-	Email     string                 `json:"email" gorm:"type:varchar(255);uniqueIndex;not null"`
-	Password  string                 `json:"-" gorm:"type:varchar(255);not null"` // Never expose password in JSON
-	RoleID    uuid.UUID              `json:"role_id" gorm:"type:uuid;not null"`   // Foreign key to m_roles
-	Role      *roleModule.Role       `json:"role,omitempty" gorm:"foreignKey:RoleID"` // Role relationship (eager load)
-	IsVerified bool                  `json:"is_verified" gorm:"default:false"`
-	CreatedAt time.Time              `json:"created_at"`
-	UpdatedAt time.Time              `json:"updated_at"`
-	DeletedAt gorm.DeletedAt         `json:"-" gorm:"index"` // Soft delete support
+	ID          uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name        string            `json:"name" gorm:"type:varchar(100);not null"`
+	Email       string            `json:"email" gorm:"type:varchar(255);uniqueIndex;not null"`
+	Username    *string           `json:"username,omitempty" gorm:"type:varchar(30);uniqueIndex"` // Optional unique handle (lowercase)
+	Password    string            `json:"-" gorm:"type:varchar(255);not null"`                    // Never expose password in JSON
+	Roles       []roleModule.Role `json:"roles,omitempty" gorm:"many2many:m_user_roles;"`         // Assigned roles via m_user_roles join table (eager load)
+	IsVerified  bool              `json:"is_verified" gorm:"default:false"`
+	LastLoginAt *time.Time        `json:"last_login_at"`
+	LastSeenAt  *time.Time        `json:"last_seen_at"`
+	Version     int               `json:"version" gorm:"not null;default:1"` // Optimistic lock (optimistic.Plugin); bumped by every profile update
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt    `json:"-" gorm:"index"` // Soft delete support
 }
 
 // TableName specifies the table name for User model
@@ -30,6 +36,135 @@ func (User) TableName() string {
 	return "m_users"
 }
 
+// Data export statuses
+const (
+	ExportStatusPending    = "pending"
+	ExportStatusProcessing = "processing"
+	ExportStatusCompleted  = "completed"
+	ExportStatusFailed     = "failed"
+)
+
+// DataExport represents a GDPR data export archive requested by a user
+type DataExport struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID      uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Status      string     `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	FileName    string     `json:"file_name" gorm:"type:varchar(255)"`
+	Archive     []byte     `json:"-" gorm:"type:bytea"` // ZIP archive, never exposed in JSON
+	Size        int64      `json:"size"`
+	Error       string     `json:"error,omitempty" gorm:"type:text"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"not null"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for DataExport model
+func (DataExport) TableName() string {
+	return "t_data_exports"
+}
+
+// ToResponse converts DataExport to DataExportResponse (without archive content)
+func (e *DataExport) ToResponse() dto.DataExportResponse {
+	return dto.DataExportResponse{
+		ID:          e.ID,
+		Status:      e.Status,
+		FileName:    e.FileName,
+		Size:        e.Size,
+		Error:       e.Error,
+		CompletedAt: e.CompletedAt,
+		ExpiresAt:   e.ExpiresAt,
+		CreatedAt:   e.CreatedAt,
+	}
+}
+
+// PreferencesData holds a user's preferences document as JSONB
+type PreferencesData json.RawMessage
+
+// Value implements the driver.Valuer interface for database storage
+func (p PreferencesData) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return "{}", nil
+	}
+	return string(p), nil
+}
+
+// Scan implements the sql.Scanner interface for database retrieval
+func (p *PreferencesData) Scan(value interface{}) error {
+	if value == nil {
+		*p = PreferencesData("{}")
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	*p = append(PreferencesData(nil), bytes...)
+	return nil
+}
+
+// UserPreference stores a user's preferences (one row per user)
+type UserPreference struct {
+	UserID    uuid.UUID       `json:"user_id" gorm:"type:uuid;primary_key"`
+	Data      PreferencesData `json:"data" gorm:"type:jsonb;not null"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// TableName specifies the table name for UserPreference model
+func (UserPreference) TableName() string {
+	return "m_user_preferences"
+}
+
+// ToResponse merges the stored preferences over the defaults
+// Keys missing from the stored document (e.g. options added later) keep their default value
+func (p *UserPreference) ToResponse() (dto.PreferencesResponse, error) {
+	response := dto.PreferencesResponse{Preferences: dto.DefaultPreferences()}
+	if p == nil {
+		return response, nil
+	}
+
+	if len(p.Data) > 0 {
+		if err := json.Unmarshal(p.Data, &response.Preferences); err != nil {
+			return response, err
+		}
+	}
+	response.UpdatedAt = &p.UpdatedAt
+
+	return response, nil
+}
+
+// Permission override effects
+const (
+	PermissionGrant = "grant"
+	PermissionDeny  = "deny"
+)
+
+// UserPermission grants or denies a single permission to a user on top of their roles
+type UserPermission struct {
+	UserID     uuid.UUID `json:"user_id" gorm:"type:uuid;primary_key"`
+	Permission string    `json:"permission" gorm:"type:varchar(100);primary_key"`
+	Effect     string    `json:"effect" gorm:"type:varchar(10);not null"` // grant or deny
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for UserPermission model
+func (UserPermission) TableName() string {
+	return "m_user_permissions"
+}
+
+// ToResponse converts UserPermission to PermissionOverride
+func (p *UserPermission) ToResponse() dto.PermissionOverride {
+	return dto.PermissionOverride{
+		Permission: p.Permission,
+		Effect:     p.Effect,
+		UpdatedAt:  p.UpdatedAt,
+	}
+}
+
 // BeforeCreate hook runs before creating a new user
 func (u *User) BeforeCreate(tx *gorm.DB) error {
 	// Generate UUID if not set
@@ -63,31 +198,51 @@ func (u *User) ToResponse() dto.UserResponse {
 		ID:         u.ID,
 		Name:       u.Name,
 		Email:      u.Email,
+		Username:   u.Username,
 		IsVerified: u.IsVerified,
+		Version:    u.Version,
 		CreatedAt:  u.CreatedAt,
 		UpdatedAt:  u.UpdatedAt,
 	}
 }
 
+// ToAdminResponse converts User to AdminUserResponse including activity timestamps
+func (u *User) ToAdminResponse() dto.AdminUserResponse {
+	return dto.AdminUserResponse{
+		UserResponse: u.ToResponse(),
+		LastLoginAt:  u.LastLoginAt,
+		LastSeenAt:   u.LastSeenAt,
+	}
+}
+
 // ToResponseWithRole converts User to UserResponse with role information
 func (u *User) ToResponseWithRole() dto.UserRoleResponse {
 	response := dto.UserRoleResponse{
 		ID:         u.ID,
 		Name:       u.Name,
 		Email:      u.Email,
+		Username:   u.Username,
 		IsVerified: u.IsVerified,
+		Version:    u.Version,
 		CreatedAt:  u.CreatedAt,
 		UpdatedAt:  u.UpdatedAt,
 	}
 
-	if u.Role != nil {
-		response.Role = &dto.RoleInfo{
-			ID:          u.Role.ID,
-			Name:        u.Role.Name,
-			Slug:        u.Role.Slug,
-			Permissions: []string(u.Role.Permissions),
-		}
-	}
+	response.Roles = u.RoleInfos()
 
 	return response
 }
+
+// RoleInfos converts the user's loaded roles to RoleInfo (permissions granted by each role directly)
+func (u *User) RoleInfos() []dto.RoleInfo {
+	roles := make([]dto.RoleInfo, len(u.Roles))
+	for i, role := range u.Roles {
+		roles[i] = dto.RoleInfo{
+			ID:          role.ID,
+			Name:        role.Name,
+			Slug:        role.Slug,
+			Permissions: []string(role.Permissions),
+		}
+	}
+	return roles
+}
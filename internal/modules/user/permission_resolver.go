@@ -0,0 +1,49 @@
+package user
+
+import (
+	"context"
+	"errors"
+
+	"go_boilerplate/internal/modules/role"
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/permission"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// PermissionResolver serves middleware.RequirePermissionLive from the user's current roles,
+// cached in Redis for RBAC.PermissionCacheTTL
+type PermissionResolver struct {
+	service UserService
+	cache   *permission.Cache
+}
+
+// NewPermissionResolver creates a permission resolver
+func NewPermissionResolver(service UserService, cache *permission.Cache) *PermissionResolver {
+	return &PermissionResolver{service: service, cache: cache}
+}
+
+// NewLivePermissionResolver wires a permission resolver for modules that only have the shared dependencies
+func NewLivePermissionResolver(db *gorm.DB, redisClient *redis.Client, cfg *config.Config) *PermissionResolver {
+	cache := permission.NewCache(redisClient, cfg.RBAC.PermissionCacheTTL)
+	service := NewUserServiceWithRole(NewUserRepository(db), role.NewRoleRepository(db), cache, nil, nil, nil, nil, cfg.RBAC.DefaultRoleSlug)
+	return NewPermissionResolver(service, cache)
+}
+
+// UserPermissions returns the effective permissions of a user across all of their roles
+func (r *PermissionResolver) UserPermissions(ctx context.Context, userID string) ([]string, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	return r.cache.Get(ctx, userID, func() ([]string, error) {
+		profile, err := r.service.GetProfileWithRole(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return profile.Permissions(), nil
+	})
+}
@@ -0,0 +1,22 @@
+package user
+
+import "go_boilerplate/internal/shared/permission"
+
+// User permissions
+const (
+	PermUsersCreate = "users.create"
+	PermUsersRead   = "users.read"
+	PermUsersUpdate = "users.update"
+	PermUsersDelete = "users.delete"
+	PermUsersManage = "users.manage" // Act on any user's account, not just one's own
+)
+
+func init() {
+	permission.Register(
+		permission.Permission{Name: PermUsersCreate, Description: "Create users"},
+		permission.Permission{Name: PermUsersRead, Description: "View user profiles"},
+		permission.Permission{Name: PermUsersUpdate, Description: "Update user profiles"},
+		permission.Permission{Name: PermUsersDelete, Description: "Delete users"},
+		permission.Permission{Name: PermUsersManage, Description: "Update any user's profile and roles"},
+	)
+}
@@ -0,0 +1,47 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	userdto "go_boilerplate/internal/modules/user/dto"
+	"go_boilerplate/internal/shared/cache"
+	"go_boilerplate/internal/shared/database/replica"
+
+	"github.com/google/uuid"
+)
+
+// ProfileCache keeps GetProfileWithRole results, which register, login, refresh and permission
+// checks load on every call, keyed by user ID. The user service drops a user's entry after
+// changing them; the role service clears cache.NamespaceUserRoles when a role changes.
+// A nil *ProfileCache never caches.
+type ProfileCache struct {
+	profiles *cache.Namespace
+	ttl      time.Duration
+}
+
+// NewProfileCache creates a profile cache over store (cache.New) with the given entry lifetime;
+// without a store or with a ttl of 0 it returns nil, which never caches
+func NewProfileCache(store cache.Cache, ttl time.Duration) *ProfileCache {
+	if store == nil || ttl <= 0 {
+		return nil
+	}
+	return &ProfileCache{profiles: cache.NewNamespace(store, cache.NamespaceUserRoles), ttl: ttl}
+}
+
+// Get returns the cached profile of a user, calling load on a miss. Misses load from the primary,
+// so a lagging replica can't keep an outdated profile cached for the whole ttl.
+func (c *ProfileCache) Get(ctx context.Context, userID uuid.UUID, load func(ctx context.Context) (*userdto.UserRoleResponse, error)) (*userdto.UserRoleResponse, error) {
+	if c == nil {
+		return load(ctx)
+	}
+	return cache.Remember(replica.WithPrimary(ctx), c.profiles, userID.String(), c.ttl, load)
+}
+
+// Invalidate drops the cached profile of a user
+func (c *ProfileCache) Invalidate(ctx context.Context, userID uuid.UUID) error {
+	if c == nil {
+		return nil
+	}
+	return c.profiles.Delete(ctx, userID.String())
+}
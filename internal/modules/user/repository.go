@@ -1,52 +1,71 @@
 package user
 
 import (
+	"context"
+	"time"
+
+	"go_boilerplate/internal/modules/abuse"
+	"go_boilerplate/internal/modules/audit"
+	authdto "go_boilerplate/internal/modules/auth/dto"
+	"go_boilerplate/internal/modules/email"
+	oauthdto "go_boilerplate/internal/modules/oauth/dto"
+	roleModule "go_boilerplate/internal/modules/role"
+	"go_boilerplate/internal/shared/database/repository"
+	"go_boilerplate/internal/shared/database/timeout"
+	"go_boilerplate/internal/shared/filter"
+	"go_boilerplate/internal/shared/session"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // UserRepository defines the interface for user data operations
 type UserRepository interface {
-	Create(user *User) error
-	FindByID(id uuid.UUID) (*User, error)
-	FindByIDWithRole(id uuid.UUID) (*User, error)
-	FindByEmail(email string) (*User, error)
-	FindAll(offset, limit int) ([]User, int64, error)
-	Update(user *User) error
-	Delete(id uuid.UUID) error
-	ExistsByEmail(email string) (bool, error)
-	ExistsByID(id uuid.UUID) (bool, error)
-}
-
-// userRepository implements UserRepository interface
+	Create(ctx context.Context, user *User) error
+	FindByID(ctx context.Context, id uuid.UUID) (*User, error)
+	FindByIDWithRole(ctx context.Context, id uuid.UUID) (*User, error)
+	FindByEmail(ctx context.Context, email string) (*User, error)
+	FindByUsername(ctx context.Context, username string) (*User, error)
+	FindAll(ctx context.Context, offset, limit int, f filter.Filter, withRoles bool) ([]User, int64, error)
+	Update(ctx context.Context, user *User) error
+	UpdateWithRoles(ctx context.Context, user *User, roles []roleModule.Role) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	ExistsByUsername(ctx context.Context, username string) (bool, error)
+	ExistsByID(ctx context.Context, id uuid.UUID) (bool, error)
+	AddRole(ctx context.Context, user *User, role *roleModule.Role) error
+	RemoveRole(ctx context.Context, user *User, role *roleModule.Role) error
+	ReplaceRoles(ctx context.Context, user *User, roles []roleModule.Role) error
+	UpdateLastLogin(ctx context.Context, id uuid.UUID, at time.Time) error
+	UpdateLastSeen(ctx context.Context, seen map[uuid.UUID]time.Time) error
+	FindPermissionOverrides(ctx context.Context, userID uuid.UUID) ([]UserPermission, error)
+	UpsertPermissionOverride(ctx context.Context, override *UserPermission) error
+	DeletePermissionOverride(ctx context.Context, userID uuid.UUID, permission string) (bool, error)
+}
+
+// userRepository implements UserRepository interface; FindByID, Delete (soft) and ExistsByID come
+// from repository.Repository
 type userRepository struct {
+	repository.Repository[User]
 	db *gorm.DB
 }
 
 // NewUserRepository creates a new user repository
 func NewUserRepository(db *gorm.DB) UserRepository {
-	return &userRepository{db: db}
+	return &userRepository{Repository: repository.New[User](db), db: db}
 }
 
 // Create creates a new user
-func (r *userRepository) Create(user *User) error {
-	return r.db.Create(user).Error
-}
-
-// FindByID finds a user by ID
-func (r *userRepository) FindByID(id uuid.UUID) (*User, error) {
-	var user User
-	err := r.db.Where("id = ?", id).First(&user).Error
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
+func (r *userRepository) Create(ctx context.Context, user *User) error {
+	// Link assigned roles without upserting the roles themselves
+	return r.db.WithContext(ctx).Omit("Roles.*").Create(user).Error
 }
 
 // FindByIDWithRole finds a user by ID and eagerly loads their role
-func (r *userRepository) FindByIDWithRole(id uuid.UUID) (*User, error) {
+func (r *userRepository) FindByIDWithRole(ctx context.Context, id uuid.UUID) (*User, error) {
 	var user User
-	err := r.db.Preload("Role").Where("id = ?", id).First(&user).Error
+	err := r.db.WithContext(ctx).Preload("Roles").Where("id = ?", id).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
@@ -54,27 +73,32 @@ func (r *userRepository) FindByIDWithRole(id uuid.UUID) (*User, error) {
 }
 
 // FindByEmail finds a user by email
-func (r *userRepository) FindByEmail(email string) (*User, error) {
-	var user User
-	err := r.db.Where("email = ?", email).First(&user).Error
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*User, error) {
+	return r.FindOne(ctx, "email = ?", email)
+}
+
+// FindByUsername finds a user by username
+func (r *userRepository) FindByUsername(ctx context.Context, username string) (*User, error) {
+	return r.FindOne(ctx, "username = ?", username)
 }
 
-// FindAll finds all users with pagination
-func (r *userRepository) FindAll(offset, limit int) ([]User, int64, error) {
+// FindAll finds all users matching the filter with pagination, eagerly loading their roles when withRoles is set
+func (r *userRepository) FindAll(ctx context.Context, offset, limit int, f filter.Filter, withRoles bool) ([]User, int64, error) {
 	var users []User
 	var total int64
+	db := r.db.WithContext(ctx)
 
 	// Count total users
-	if err := r.db.Model(&User{}).Count(&total).Error; err != nil {
+	if err := db.Model(&User{}).Scopes(f.Scope()).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	// Find users with pagination
-	err := r.db.Offset(offset).Limit(limit).Order("created_at DESC").Find(&users).Error
+	// Find users with pagination; roles are loaded with one extra query for the whole page
+	query := db.Scopes(f.Scope())
+	if withRoles {
+		query = query.Preload("Roles")
+	}
+	err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&users).Error
 	if err != nil {
 		return nil, 0, err
 	}
@@ -83,25 +107,215 @@ func (r *userRepository) FindAll(offset, limit int) ([]User, int64, error) {
 }
 
 // Update updates a user
-func (r *userRepository) Update(user *User) error {
-	return r.db.Save(user).Error
+func (r *userRepository) Update(ctx context.Context, user *User) error {
+	// Role assignments are managed through AddRole/RemoveRole/ReplaceRoles
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(user).Error
 }
 
-// Delete soft deletes a user
-func (r *userRepository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&User{}, "id = ?", id).Error
+// UpdateWithRoles updates a user and, when roles is not nil, replaces their roles in the same
+// transaction, so a failed role change doesn't leave the profile change applied
+func (r *userRepository) UpdateWithRoles(ctx context.Context, user *User, roles []roleModule.Role) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(user).Error; err != nil {
+			return err
+		}
+		if roles == nil {
+			return nil
+		}
+		return tx.Model(user).Omit("Roles.*").Association("Roles").Replace(roles)
+	})
 }
 
 // ExistsByEmail checks if a user exists by email
-func (r *userRepository) ExistsByEmail(email string) (bool, error) {
-	var count int64
-	err := r.db.Model(&User{}).Where("email = ?", email).Count(&count).Error
-	return count > 0, err
+func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	return r.Exists(ctx, "email = ?", email)
+}
+
+// ExistsByUsername checks if a user exists by username
+func (r *userRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	return r.Exists(ctx, "username = ?", username)
+}
+
+// AddRole assigns a role to a user
+func (r *userRepository) AddRole(ctx context.Context, user *User, role *roleModule.Role) error {
+	return r.db.WithContext(ctx).Model(user).Omit("Roles.*").Association("Roles").Append(role)
+}
+
+// RemoveRole unassigns a role from a user
+func (r *userRepository) RemoveRole(ctx context.Context, user *User, role *roleModule.Role) error {
+	return r.db.WithContext(ctx).Model(user).Association("Roles").Delete(role)
+}
+
+// ReplaceRoles replaces every role assigned to a user
+func (r *userRepository) ReplaceRoles(ctx context.Context, user *User, roles []roleModule.Role) error {
+	return r.db.WithContext(ctx).Model(user).Omit("Roles.*").Association("Roles").Replace(roles)
+}
+
+// UpdateLastLogin sets the last login timestamp of a user
+func (r *userRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&User{}).Where("id = ?", id).UpdateColumn("last_login_at", at).Error
+}
+
+// UpdateLastSeen sets the last seen timestamps of several users in one transaction
+func (r *userRepository) UpdateLastSeen(ctx context.Context, seen map[uuid.UUID]time.Time) error {
+	if len(seen) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for id, at := range seen {
+			// Never move last_seen_at backwards (e.g. a delayed flush from another instance)
+			err := tx.Model(&User{}).
+				Where("id = ? AND (last_seen_at IS NULL OR last_seen_at < ?)", id, at).
+				UpdateColumn("last_seen_at", at).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindPermissionOverrides finds the permission overrides of a user
+func (r *userRepository) FindPermissionOverrides(ctx context.Context, userID uuid.UUID) ([]UserPermission, error) {
+	var overrides []UserPermission
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("permission").Find(&overrides).Error
+	return overrides, err
+}
+
+// UpsertPermissionOverride creates or replaces the override of a user for one permission
+func (r *userRepository) UpsertPermissionOverride(ctx context.Context, override *UserPermission) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "permission"}},
+		DoUpdates: clause.AssignmentColumns([]string{"effect", "updated_at"}),
+	}).Create(override).Error
+}
+
+// DeletePermissionOverride removes the override of a user for one permission
+// It reports whether an override existed
+func (r *userRepository) DeletePermissionOverride(ctx context.Context, userID uuid.UUID, permission string) (bool, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ? AND permission = ?", userID, permission).Delete(&UserPermission{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// PreferenceRepository defines the interface for user preference data operations
+type PreferenceRepository interface {
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*UserPreference, error)
+	Upsert(ctx context.Context, preference *UserPreference) error
+}
+
+// preferenceRepository implements PreferenceRepository interface
+type preferenceRepository struct {
+	db *gorm.DB
+}
+
+// NewPreferenceRepository creates a new preference repository
+func NewPreferenceRepository(db *gorm.DB) PreferenceRepository {
+	return &preferenceRepository{db: db}
+}
+
+// FindByUserID finds the stored preferences of a user
+func (r *preferenceRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*UserPreference, error) {
+	var preference UserPreference
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&preference).Error
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+// Upsert creates or replaces the stored preferences of a user
+func (r *preferenceRepository) Upsert(ctx context.Context, preference *UserPreference) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"data", "updated_at"}),
+	}).Create(preference).Error
+}
+
+// DataExportRepository defines the interface for GDPR data export operations
+type DataExportRepository interface {
+	Create(ctx context.Context, export *DataExport) error
+	FindByIDForUser(ctx context.Context, id, userID uuid.UUID) (*DataExport, error)
+	FindActiveByUserID(ctx context.Context, userID uuid.UUID) (*DataExport, error)
+	Update(ctx context.Context, export *DataExport) error
+	FindSessionsByUserID(ctx context.Context, userID uuid.UUID) ([]authdto.Session, error)
+	FindOAuthAccountsByUserID(ctx context.Context, userID uuid.UUID) ([]oauthdto.OAuthAccount, error)
+	FindAuditEventsByUserID(ctx context.Context, userID uuid.UUID) ([]audit.AuditEvent, error)
+	FindAuditLogsByUserID(ctx context.Context, userID uuid.UUID) ([]audit.AuditLog, error)
+	FindAbuseReportsByUserID(ctx context.Context, userID uuid.UUID) ([]abuse.AbuseReport, error)
+	FindEmailMessagesByRecipient(ctx context.Context, recipient string) ([]email.EmailMessage, error)
+}
+
+// dataExportRepository implements DataExportRepository interface; Create and Update come from
+// repository.Repository
+type dataExportRepository struct {
+	repository.Repository[DataExport]
+	db       *gorm.DB
+	sessions session.Store
+}
+
+// NewDataExportRepository creates a new data export repository; sessions are read from the session store
+func NewDataExportRepository(db *gorm.DB, sessions session.Store) DataExportRepository {
+	return &dataExportRepository{Repository: repository.New[DataExport](db), db: db, sessions: sessions}
+}
+
+// FindByIDForUser finds a data export by ID that belongs to the given user
+func (r *dataExportRepository) FindByIDForUser(ctx context.Context, id, userID uuid.UUID) (*DataExport, error) {
+	return r.FindOne(ctx, "id = ? AND user_id = ?", id, userID)
+}
+
+// FindActiveByUserID finds the latest export that is still in progress or downloadable
+func (r *dataExportRepository) FindActiveByUserID(ctx context.Context, userID uuid.UUID) (*DataExport, error) {
+	var export DataExport
+	err := r.db.WithContext(ctx).Omit("archive").
+		Where("user_id = ? AND status IN ? AND expires_at > ?", userID, []string{ExportStatusPending, ExportStatusProcessing, ExportStatusCompleted}, time.Now()).
+		Order("created_at DESC").
+		First(&export).Error
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// FindSessionsByUserID finds all sessions belonging to a user
+func (r *dataExportRepository) FindSessionsByUserID(ctx context.Context, userID uuid.UUID) ([]authdto.Session, error) {
+	return r.sessions.ListByUser(ctx, userID)
+}
+
+// FindOAuthAccountsByUserID finds all OAuth accounts linked to a user
+func (r *dataExportRepository) FindOAuthAccountsByUserID(ctx context.Context, userID uuid.UUID) ([]oauthdto.OAuthAccount, error) {
+	var accounts []oauthdto.OAuthAccount
+	err := timeout.Report(r.db.WithContext(ctx)).Where("user_id = ?", userID).Order("created_at DESC").Find(&accounts).Error
+	return accounts, err
+}
+
+// FindAuditEventsByUserID finds the requests a user made (t_audit_events), in every tenant
+func (r *dataExportRepository) FindAuditEventsByUserID(ctx context.Context, userID uuid.UUID) ([]audit.AuditEvent, error) {
+	var events []audit.AuditEvent
+	err := timeout.Report(r.db.WithContext(ctx)).Where("actor_id = ?", userID).Order("created_at DESC").Find(&events).Error
+	return events, err
+}
+
+// FindAuditLogsByUserID finds the changes a user made and the changes made to their account (t_audit_logs)
+func (r *dataExportRepository) FindAuditLogsByUserID(ctx context.Context, userID uuid.UUID) ([]audit.AuditLog, error) {
+	var logs []audit.AuditLog
+	err := timeout.Report(r.db.WithContext(ctx)).
+		Where("actor_id = ? OR (resource_type = ? AND resource_id = ?)", userID, "user", userID.String()).
+		Order("created_at DESC").
+		Find(&logs).Error
+	return logs, err
+}
+
+// FindAbuseReportsByUserID finds the abuse reports a user filed while authenticated
+func (r *dataExportRepository) FindAbuseReportsByUserID(ctx context.Context, userID uuid.UUID) ([]abuse.AbuseReport, error) {
+	var reports []abuse.AbuseReport
+	err := timeout.Report(r.db.WithContext(ctx)).Where("reporter_id = ?", userID).Order("created_at DESC").Find(&reports).Error
+	return reports, err
 }
 
-// ExistsByID checks if a user exists by ID
-func (r *userRepository) ExistsByID(id uuid.UUID) (bool, error) {
-	var count int64
-	err := r.db.Model(&User{}).Where("id = ?", id).Count(&count).Error
-	return count > 0, err
+// FindEmailMessagesByRecipient finds the emails sent (or queued) to an address, without their bodies
+func (r *dataExportRepository) FindEmailMessagesByRecipient(ctx context.Context, recipient string) ([]email.EmailMessage, error) {
+	var messages []email.EmailMessage
+	err := timeout.Report(r.db.WithContext(ctx)).Omit("body", "attachments").Where("recipient = ?", recipient).Order("created_at DESC").Find(&messages).Error
+	return messages, err
 }
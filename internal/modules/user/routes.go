@@ -1,30 +1,47 @@
 package user
 
 import (
+	"go_boilerplate/internal/modules/audit"
 	"go_boilerplate/internal/modules/role"
 	"go_boilerplate/internal/modules/user/dto"
+	"go_boilerplate/internal/shared/apiversion"
+	"go_boilerplate/internal/shared/cache"
 	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/events"
 	sharedmiddleware "go_boilerplate/internal/shared/middleware"
+	"go_boilerplate/internal/shared/permission"
+	"go_boilerplate/internal/shared/session"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 // RegisterRoutes registers all user-related routes
-func RegisterRoutes(app *fiber.App, db *gorm.DB, cfg *config.Config, logger *logrus.Logger) {
+func RegisterRoutes(app *fiber.App, db *gorm.DB, cfg *config.Config, logger *logrus.Logger, redisClient *redis.Client, store cache.Cache, sessions session.Store, bus events.EventBus) {
 	// Initialize repositories
 	userRepo := NewUserRepository(db)
 	roleRepo := role.NewRoleRepository(db)
 
+	// Cached GET responses, invalidated by the service after writes
+	responses := cache.NewResponseCache(redisClient, cfg.Cache)
+
 	// Initialize user service with role repository
-	userService := NewUserServiceWithRole(userRepo, roleRepo)
+	userService := NewUserServiceWithRole(userRepo, roleRepo, permission.NewCache(redisClient, cfg.RBAC.PermissionCacheTTL), NewProfileCache(store, cfg.RBAC.ProfileCacheTTL), responses, bus, audit.NewTrail(db, cfg.Audit, logger), cfg.RBAC.DefaultRoleSlug)
+
+	// Initialize data export service (GDPR)
+	exportRepo := NewDataExportRepository(db, sessions)
+	exportService := NewDataExportService(exportRepo, userRepo, NewPreferenceRepository(db), logger)
+
+	// Initialize preference service
+	preferenceService := NewPreferenceService(NewPreferenceRepository(db))
 
 	// Initialize handler
-	userHandler := NewUserHandler(userService)
+	userHandler := NewUserHandler(userService, exportService, preferenceService)
 
 	// Create API route group
-	api := app.Group("/api/v1")
+	api := apiversion.Group(app, "v1")
 
 	// Public routes (if any)
 	// Currently, all user routes require authentication
@@ -32,21 +49,36 @@ func RegisterRoutes(app *fiber.App, db *gorm.DB, cfg *config.Config, logger *log
 	// Protected routes - All authenticated users
 	protected := api.Group("/users")
 	protected.Use(sharedmiddleware.JWTAuth(cfg))
+	protected.Use(sharedmiddleware.Compress(cfg), sharedmiddleware.ETag(cfg)) // User lists can be large
+
+	// Building and downloading exports is expensive, so both share one small in-flight cap
+	exportLimit := sharedmiddleware.ConcurrencyLimit(cfg.Concurrency, cfg.Concurrency.ReportMaxInFlight)
 
 	// Routes accessible by any authenticated user
-	protected.Get("/me", userHandler.GetCurrentUser)                       // Get current user profile
-	protected.Get("/:id", userHandler.GetUser)                             // Get user by ID
-	protected.Put("/:id", sharedmiddleware.BodyValidator(&dto.UpdateUserRequest{}), userHandler.UpdateUser) // Update user (self-profile or with permission)
+	protected.Get("/me", userHandler.GetCurrentUser)                                                                                 // Get current user profile
+	protected.Get("/me/preferences", userHandler.GetPreferences)                                                                     // Get current user preferences
+	protected.Put("/me/preferences", sharedmiddleware.BodyValidator(&dto.UpdatePreferencesRequest{}), userHandler.UpdatePreferences) // Update current user preferences
+	protected.Get("/me/export", exportLimit, userHandler.RequestDataExport)                                                          // Request GDPR data export
+	protected.Get("/me/export/:exportId", userHandler.GetDataExport)                                                                 // Get data export status
+	protected.Get("/me/export/:exportId/download", exportLimit, userHandler.DownloadDataExport)                                      // Download data export archive
+	protected.Get("/by-username/:handle", userHandler.GetUserByUsername)                                                             // Get user by username
+	protected.Get("/:id", userHandler.GetUser)                                                                                       // Get user by ID
+	protected.Put("/:id", sharedmiddleware.BodyValidator(&dto.UpdateUserRequest{}), userHandler.UpdateUser)                          // Update user (self-profile or with permission)
 
 	// Routes accessible by Admin and SuperAdmin only
 	adminOnly := protected.Group("/")
 	adminOnly.Use(sharedmiddleware.RequireRole(cfg, "admin", "super_admin"))
-	adminOnly.Get("/", userHandler.GetUsers)                               // Get all users (with pagination)
-	adminOnly.Post("/", sharedmiddleware.BodyValidator(&dto.CreateUserRequest{}), userHandler.CreateUser) // Create user
-	adminOnly.Delete("/:id", userHandler.DeleteUser)                       // Delete user
+	adminOnly.Get("/", sharedmiddleware.Cache(responses, sharedmiddleware.CacheRule{Namespace: cache.NamespaceUsers}), userHandler.GetUsers) // Get all users (with pagination, cached)
+	adminOnly.Post("/", sharedmiddleware.BodyValidator(&dto.CreateUserRequest{}), userHandler.CreateUser)                                    // Create user
+	adminOnly.Delete("/:id", userHandler.DeleteUser)                                                                                         // Delete user
 
 	// Routes accessible by SuperAdmin only
 	superAdminOnly := protected.Group("/")
 	superAdminOnly.Use(sharedmiddleware.RequireRole(cfg, "super_admin"))
-	superAdminOnly.Patch("/:id/role", sharedmiddleware.BodyValidator(&dto.AssignRoleRequest{}), userHandler.AssignRole) // Assign role to user
+	superAdminOnly.Patch("/:id/role", sharedmiddleware.BodyValidator(&dto.AssignRoleRequest{}), userHandler.AssignRole)                            // Replace all roles of user with one role
+	superAdminOnly.Post("/:id/roles", sharedmiddleware.BodyValidator(&dto.AssignRoleRequest{}), userHandler.AttachRole)                            // Attach role to user
+	superAdminOnly.Delete("/:id/roles/:roleId", userHandler.DetachRole)                                                                            // Detach role from user
+	superAdminOnly.Get("/:id/permissions", userHandler.GetPermissionOverrides)                                                                     // List per-user permission overrides
+	superAdminOnly.Put("/:id/permissions", sharedmiddleware.BodyValidator(&dto.SetPermissionOverrideRequest{}), userHandler.SetPermissionOverride) // Grant or deny a permission to user
+	superAdminOnly.Delete("/:id/permissions/:permission", userHandler.RemovePermissionOverride)                                                    // Remove a permission override
 }
@@ -1,35 +1,84 @@
 package user
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math"
+	"strings"
+	"time"
 
+	"go_boilerplate/internal/modules/audit"
+	auditdto "go_boilerplate/internal/modules/audit/dto"
 	"go_boilerplate/internal/modules/role"
 	userdto "go_boilerplate/internal/modules/user/dto"
+	"go_boilerplate/internal/shared/apperror"
+	"go_boilerplate/internal/shared/cache"
+	"go_boilerplate/internal/shared/database/replica"
+	"go_boilerplate/internal/shared/database/repository"
+	"go_boilerplate/internal/shared/events"
+	"go_boilerplate/internal/shared/filter"
+	"go_boilerplate/internal/shared/permission"
 	"go_boilerplate/internal/shared/utils"
 
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 // UserService defines the interface for user business logic
 type UserService interface {
-	GetProfile(userID uuid.UUID) (*userdto.UserResponse, error)
-	GetProfileWithRole(userID uuid.UUID) (*userdto.UserRoleResponse, error)
-	GetAll(page, limit int) (*userdto.UsersResponse, error)
-	CreateUser(req *userdto.CreateUserRequest) (*userdto.UserResponse, error)
-	UpdateUser(userID uuid.UUID, req *userdto.UpdateUserRequest) (*userdto.UserRoleResponse, error)
-	DeleteUser(userID uuid.UUID) error
-	ValidatePassword(email, password string) (*User, error)
-	AssignRole(userID uuid.UUID, roleID uuid.UUID) (*userdto.UserRoleResponse, error)
-	HasPermission(userID uuid.UUID, permission string) (bool, error)
-	HasRole(userID uuid.UUID, roleSlug string) (bool, error)
-	GetByEmail(email string) (*User, error)
+	GetProfile(ctx context.Context, userID uuid.UUID, withRoles bool) (*userdto.UserResponse, error)
+	GetProfileWithRole(ctx context.Context, userID uuid.UUID) (*userdto.UserRoleResponse, error)
+	GetAdminProfile(ctx context.Context, userID uuid.UUID, withRoles bool) (*userdto.AdminUserResponse, error)
+	GetProfileByUsername(ctx context.Context, username string) (*userdto.UserResponse, error)
+	GetAll(ctx context.Context, page, limit int, f filter.Filter, withRoles bool) (*userdto.UsersResponse, error)
+	CreateUser(ctx context.Context, req *userdto.CreateUserRequest) (*userdto.UserResponse, error)
+	UpdateUser(ctx context.Context, userID uuid.UUID, req *userdto.UpdateUserRequest) (*userdto.UserRoleResponse, error)
+	DeleteUser(ctx context.Context, userID uuid.UUID) error
+	ValidatePassword(ctx context.Context, login, password string) (*User, error)
+	AssignRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) (*userdto.UserRoleResponse, error)
+	AttachRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) (*userdto.UserRoleResponse, error)
+	DetachRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) (*userdto.UserRoleResponse, error)
+	HasPermission(ctx context.Context, userID uuid.UUID, permission string) (bool, error)
+	HasRole(ctx context.Context, userID uuid.UUID, roleSlug string) (bool, error)
+	GetPermissionOverrides(ctx context.Context, userID uuid.UUID) ([]userdto.PermissionOverride, error)
+	SetPermissionOverride(ctx context.Context, userID uuid.UUID, req *userdto.SetPermissionOverrideRequest) ([]userdto.PermissionOverride, error)
+	RemovePermissionOverride(ctx context.Context, userID uuid.UUID, permission string) ([]userdto.PermissionOverride, error)
+	GetByEmail(ctx context.Context, email string) (*User, error)
+	RecordLogin(ctx context.Context, userID uuid.UUID) error
+	InvalidateProfile(ctx context.Context, userID uuid.UUID)
 }
 
+// Errors returned by the user services
+var (
+	ErrUserNotFound               = apperror.New(apperror.ErrNotFound, "user not found").WithCode("user_not_found")
+	ErrEmailTaken                 = apperror.New(apperror.ErrConflict, "email already exists").WithCode("email_taken")
+	ErrInvalidCredentials         = apperror.New(apperror.ErrUnauthorized, "invalid credentials").WithCode("invalid_credentials")
+	ErrRoleNotAssigned            = apperror.New(apperror.ErrNotFound, "role is not assigned to user").WithCode("role_not_assigned")
+	ErrLastRole                   = apperror.New(apperror.ErrConflict, "user must keep at least one role").WithCode("last_role")
+	ErrPermissionOverrideNotFound = apperror.New(apperror.ErrNotFound, "permission override not found").WithCode("permission_override_not_found")
+	ErrUsernameInvalid            = apperror.New(apperror.ErrValidation, "username is invalid or reserved").WithCode("username_invalid")
+	ErrUsernameTaken              = apperror.New(apperror.ErrConflict, "username already exists").WithCode("username_taken")
+	ErrExportNotFound             = apperror.New(apperror.ErrNotFound, "export not found").WithCode("export_not_found")
+	ErrExportNotReady             = apperror.New(apperror.ErrConflict, "export is not ready yet").WithCode("export_not_ready")
+	ErrExportExpired              = apperror.New(apperror.ErrGone, "export has expired, please request a new one").WithCode("export_expired")
+)
+
 // userService implements UserService interface
 type userService struct {
-	repo      UserRepository
-	roleRepo  role.RoleRepository
+	repo        UserRepository
+	roleRepo    role.RoleRepository
+	roles       role.RoleService     // Resolves inherited role permissions
+	permCache   *permission.Cache    // Invalidated when a user's roles change (nil-safe)
+	profiles    *ProfileCache        // GetProfileWithRole results, invalidated after writes (nil-safe)
+	responses   *cache.ResponseCache // Cached user lists, invalidated after writes (nil-safe)
+	bus         events.EventBus      // Receives user.created and role.assigned (nil publishes nothing)
+	trail       *audit.Trail         // Records changes to users, their roles and overrides (nil records nothing)
+	defaultRole string               // Slug assigned when a create request names no role (DEFAULT_ROLE_SLUG)
 }
 
 // NewUserService creates a new user service
@@ -38,50 +87,132 @@ func NewUserService(repo UserRepository) UserService {
 }
 
 // NewUserServiceWithRole creates a new user service with role repository
-func NewUserServiceWithRole(repo UserRepository, roleRepo role.RoleRepository) UserService {
+// permCache may be nil when the caller never changes role assignments, responses, bus and trail when
+// it never writes users; profiles may be nil to always load profiles. defaultRole is the slug new users get
+func NewUserServiceWithRole(repo UserRepository, roleRepo role.RoleRepository, permCache *permission.Cache, profiles *ProfileCache, responses *cache.ResponseCache, bus events.EventBus, trail *audit.Trail, defaultRole string) UserService {
 	return &userService{
-		repo:     repo,
-		roleRepo: roleRepo,
+		repo:        repo,
+		roleRepo:    roleRepo,
+		roles:       role.NewRoleService(roleRepo),
+		permCache:   permCache,
+		profiles:    profiles,
+		responses:   responses,
+		bus:         bus,
+		trail:       trail,
+		defaultRole: defaultRole,
 	}
 }
 
-// GetProfile gets a user profile by ID
-func (s *userService) GetProfile(userID uuid.UUID) (*userdto.UserResponse, error) {
-	userModel, err := s.repo.FindByID(userID)
+// GetProfile gets a user profile by ID, with the user's roles when withRoles is set
+func (s *userService) GetProfile(ctx context.Context, userID uuid.UUID, withRoles bool) (*userdto.UserResponse, error) {
+	userModel, err := s.findUser(ctx, userID, withRoles)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, err
 	}
 
 	response := userModel.ToResponse()
+	if withRoles {
+		response.Roles = userModel.RoleInfos()
+	}
 	return &response, nil
 }
 
-// GetProfileWithRole gets a user profile with role information
-func (s *userService) GetProfileWithRole(userID uuid.UUID) (*userdto.UserRoleResponse, error) {
-	userModel, err := s.repo.FindByIDWithRole(userID)
+// GetProfileWithRole gets a user profile with role information, cached for PROFILE_CACHE_TTL
+func (s *userService) GetProfileWithRole(ctx context.Context, userID uuid.UUID) (*userdto.UserRoleResponse, error) {
+	return s.profiles.Get(ctx, userID, func(ctx context.Context) (*userdto.UserRoleResponse, error) {
+		return s.loadProfileWithRole(ctx, userID)
+	})
+}
+
+// loadProfileWithRole loads a user profile with role information from the database
+func (s *userService) loadProfileWithRole(ctx context.Context, userID uuid.UUID) (*userdto.UserRoleResponse, error) {
+	userModel, err := s.repo.FindByIDWithRole(ctx, userID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, repository.LookupError(err, ErrUserNotFound, "user")
 	}
 
 	response := userModel.ToResponseWithRole()
+
+	// Include permissions inherited from parent roles so JWT claims and permission checks see them
+	if s.roles != nil {
+		for i, roleInfo := range response.Roles {
+			permissions, err := s.roles.EffectivePermissions(ctx, roleInfo.ID)
+			if err != nil {
+				return nil, err
+			}
+			response.Roles[i].Permissions = permissions
+		}
+	}
+
+	// Per-user grants and denies are applied by response.Permissions()
+	overrides, err := s.GetPermissionOverrides(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	response.PermissionOverrides = overrides
+
+	return &response, nil
+}
+
+// GetAdminProfile gets a user profile including activity timestamps, with the user's roles when withRoles is set
+func (s *userService) GetAdminProfile(ctx context.Context, userID uuid.UUID, withRoles bool) (*userdto.AdminUserResponse, error) {
+	userModel, err := s.findUser(ctx, userID, withRoles)
+	if err != nil {
+		return nil, err
+	}
+
+	response := userModel.ToAdminResponse()
+	if withRoles {
+		response.Roles = userModel.RoleInfos()
+	}
+	return &response, nil
+}
+
+// findUser loads a user by ID, eagerly loading their roles when withRoles is set
+// A missing user is ErrUserNotFound; other failures keep their cause
+func (s *userService) findUser(ctx context.Context, userID uuid.UUID, withRoles bool) (*User, error) {
+	var userModel *User
+	var err error
+	if withRoles {
+		userModel, err = s.repo.FindByIDWithRole(ctx, userID)
+	} else {
+		userModel, err = s.repo.FindByID(ctx, userID)
+	}
+	if err != nil {
+		return nil, repository.LookupError(err, ErrUserNotFound, "user")
+	}
+	return userModel, nil
+}
+
+// GetProfileByUsername gets a user profile by username handle
+func (s *userService) GetProfileByUsername(ctx context.Context, username string) (*userdto.UserResponse, error) {
+	userModel, err := s.repo.FindByUsername(ctx, utils.NormalizeUsername(username))
+	if err != nil {
+		return nil, repository.LookupError(err, ErrUserNotFound, "user")
+	}
+
+	response := userModel.ToResponse()
 	return &response, nil
 }
 
-// GetAll gets all users with pagination
-func (s *userService) GetAll(page, limit int) (*userdto.UsersResponse, error) {
+// GetAll gets all users matching the filter with pagination, with each user's roles when withRoles is set
+func (s *userService) GetAll(ctx context.Context, page, limit int, f filter.Filter, withRoles bool) (*userdto.UsersResponse, error) {
 	// Calculate offset
 	offset := (page - 1) * limit
 
 	// Find users
-	users, total, err := s.repo.FindAll(offset, limit)
+	users, total, err := s.repo.FindAll(ctx, offset, limit, f, withRoles)
 	if err != nil {
 		return nil, err
 	}
 
 	// Convert to response
-	userResponses := make([]userdto.UserResponse, len(users))
+	userResponses := make([]userdto.AdminUserResponse, len(users))
 	for i, userModel := range users {
-		userResponses[i] = userModel.ToResponse()
+		userResponses[i] = userModel.ToAdminResponse()
+		if withRoles {
+			userResponses[i].Roles = userModel.RoleInfos()
+		}
 	}
 
 	// Calculate total pages
@@ -98,203 +229,298 @@ func (s *userService) GetAll(page, limit int) (*userdto.UsersResponse, error) {
 	}, nil
 }
 
-// CreateUser creates a new user with specified role (defaults to "user" role if not provided)
+// CreateUser creates a new user with specified role (defaults to the configured default role if not provided)
 // Only allows creating "user" or "admin" roles, not "super_admin"
-func (s *userService) CreateUser(req *userdto.CreateUserRequest) (*userdto.UserResponse, error) {
+func (s *userService) CreateUser(ctx context.Context, req *userdto.CreateUserRequest) (*userdto.UserResponse, error) {
 	// Check if email already exists
-	exists, err := s.repo.ExistsByEmail(req.Email)
+	exists, err := s.repo.ExistsByEmail(ctx, req.Email)
 	if err != nil {
 		return nil, err
 	}
 	if exists {
-		return nil, errors.New("email already exists")
+		return nil, ErrEmailTaken
 	}
 
-	// Determine role ID to assign
-	var roleID uuid.UUID
-	if req.RoleID != nil {
-		// RoleID provided in request - validate it's user or admin role only
-		role, err := s.roleRepo.FindByID(*req.RoleID)
-		if err != nil {
-			return nil, errors.New("role not found")
-		}
+	// Check if username is already taken
+	username, err := s.claimUsername(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
 
-		// Only allow "user" or "admin" roles to be assigned during creation
-		if role.Slug != "user" && role.Slug != "admin" {
-			return nil, errors.New("can only assign 'user' or 'admin' role during user creation")
+	// Determine roles to assign
+	var roles []role.Role
+	if len(req.RoleIDs) > 0 {
+		// Roles provided in request - validate they're user or admin roles only
+		roles, err = s.assignableRoles(ctx, req.RoleIDs, "creation")
+		if err != nil {
+			return nil, err
 		}
-
-		roleID = role.ID
 	} else {
-		// No role specified - default to "user" role
-		userRole, err := s.roleRepo.FindBySlug("user")
-		if err != nil || userRole == nil {
-			return nil, errors.New("default user role not found")
+		// No role specified - assign the configured default role
+		defaultRole, err := s.roleRepo.FindBySlug(ctx, s.defaultRole)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find default role %q: %w", s.defaultRole, err)
 		}
-		roleID = userRole.ID
+		if defaultRole == nil {
+			return nil, fmt.Errorf("default role %q not found", s.defaultRole)
+		}
+		roles = []role.Role{*defaultRole}
 	}
 
 	// Create user model
 	userModel := &User{
-		Name:     req.Name,
-		Email:    req.Email,
-		Password: req.Password, // Will be hashed in BeforeCreate hook
-		RoleID:   roleID, // Assign specified or default role
+		Name:       req.Name,
+		Email:      req.Email,
+		Username:   username,
+		Password:   req.Password, // Will be hashed in BeforeCreate hook
+		Roles:      roles,        // Assign specified or default roles
+		IsVerified: req.Verified, // Saved by the same insert, so the user can't be left unverified
 	}
 
 	// Save user
-	if err := s.repo.Create(userModel); err != nil {
-		return nil, err
+	if err := s.repo.Create(ctx, userModel); err != nil {
+		return nil, writeError(err)
 	}
+	s.invalidateResponses(ctx)
 
+	roleSlugs := make([]string, len(roles))
+	for i, assigned := range roles {
+		roleSlugs[i] = assigned.Slug
+	}
+	s.publish(ctx, EventUserCreated, UserCreatedEvent{
+		UserID:   userModel.ID,
+		Email:    userModel.Email,
+		Username: userModel.Username,
+		Roles:    roleSlugs,
+	})
+
+	// Without an authenticated actor this is a sign-up, made by the new user
+	actor := audit.ActorFromContext(ctx)
+	if actor == "" {
+		actor = userModel.ID.String()
+	}
 	response := userModel.ToResponse()
+	s.trail.Record(ctx, actor, "user.created", userResource(userModel.ID), nil, userModel.ToResponseWithRole())
 	return &response, nil
 }
 
 // UpdateUser updates a user
 // Only allows updating role to "user" or "admin", not "super_admin"
-func (s *userService) UpdateUser(userID uuid.UUID, req *userdto.UpdateUserRequest) (*userdto.UserRoleResponse, error) {
-	// Find user
-	userModel, err := s.repo.FindByID(userID)
+func (s *userService) UpdateUser(ctx context.Context, userID uuid.UUID, req *userdto.UpdateUserRequest) (*userdto.UserRoleResponse, error) {
+	// Find user on the primary: a lagging replica would report an outdated version
+	userModel, err := s.repo.FindByID(replica.WithPrimary(ctx), userID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, repository.LookupError(err, ErrUserNotFound, "user")
+	}
+	before := s.snapshot(ctx, userID)
+
+	// Saving checks the version (optimistic.Plugin); the client's version catches changes made
+	// since it read the user, not only during this request
+	if req.Version != nil {
+		userModel.Version = *req.Version
 	}
 
 	// Check if email is being changed and if it already exists
 	if req.Email != "" && req.Email != userModel.Email {
-		exists, err := s.repo.ExistsByEmail(req.Email)
+		exists, err := s.repo.ExistsByEmail(ctx, req.Email)
 		if err != nil {
 			return nil, err
 		}
 		if exists {
-			return nil, errors.New("email already exists")
+			return nil, ErrEmailTaken
 		}
 		userModel.Email = req.Email
 	}
 
+	// Check if username is being changed and if it is already taken
+	if req.Username != "" && (userModel.Username == nil || utils.NormalizeUsername(req.Username) != *userModel.Username) {
+		username, err := s.claimUsername(ctx, req.Username)
+		if err != nil {
+			return nil, err
+		}
+		userModel.Username = username
+	}
+
 	// Update name if provided
 	if req.Name != "" {
 		userModel.Name = req.Name
 	}
 
-	// Update role if provided
-	if req.RoleID != nil {
-		// Verify role exists
-		role, err := s.roleRepo.FindByID(*req.RoleID)
+	// Resolve roles if provided
+	// "super_admin" role can only be assigned via the role assignment endpoints (SuperAdmin only)
+	var roles []role.Role
+	if len(req.RoleIDs) > 0 {
+		roles, err = s.assignableRoles(ctx, req.RoleIDs, "update")
 		if err != nil {
-			return nil, errors.New("role not found")
-		}
-
-		// Only allow "user" or "admin" roles to be assigned during update
-		// "super_admin" role can only be assigned via AssignRole endpoint (SuperAdmin only)
-		if role.Slug != "user" && role.Slug != "admin" {
-			return nil, errors.New("can only assign 'user' or 'admin' role during user update")
+			return nil, err
 		}
-
-		userModel.RoleID = role.ID
 	}
 
-	// Save changes
-	if err := s.repo.Update(userModel); err != nil {
-		return nil, err
+	// Save changes and replace roles if provided, in one transaction
+	if err := s.repo.UpdateWithRoles(ctx, userModel, roles); err != nil {
+		return nil, writeError(err)
+	}
+	s.InvalidateProfile(ctx, userID)
+	s.invalidateResponses(ctx)
+	if roles != nil {
+		s.invalidatePermissions(ctx, userID)
 	}
 
-	// Load user with role to return complete response
-	userWithRole, err := s.repo.FindByIDWithRole(userID)
+	// Load user with role to return complete response, from the primary so it includes the changes
+	userWithRole, err := s.repo.FindByIDWithRole(replica.WithPrimary(ctx), userID)
 	if err != nil {
 		return nil, err
 	}
 
 	response := userWithRole.ToResponseWithRole()
+	s.record(ctx, "user.updated", userID, before, response)
 	return &response, nil
 }
 
 // DeleteUser deletes a user
-func (s *userService) DeleteUser(userID uuid.UUID) error {
+func (s *userService) DeleteUser(ctx context.Context, userID uuid.UUID) error {
 	// Check if user exists
-	_, err := s.repo.FindByID(userID)
+	_, err := s.repo.FindByID(ctx, userID)
 	if err != nil {
-		return errors.New("user not found")
+		return repository.LookupError(err, ErrUserNotFound, "user")
 	}
+	before := s.snapshot(ctx, userID)
 
 	// Delete user
-	if err := s.repo.Delete(userID); err != nil {
+	if err := s.repo.Delete(ctx, userID); err != nil {
 		return err
 	}
+	s.invalidatePermissions(ctx, userID)
+	s.invalidateResponses(ctx)
+	s.record(ctx, "user.deleted", userID, before, nil)
 
 	return nil
 }
 
 // ValidatePassword validates user credentials
-func (s *userService) ValidatePassword(email, password string) (*User, error) {
-	user, err := s.repo.FindByEmail(email)
+// The login may be either an email address or a username
+func (s *userService) ValidatePassword(ctx context.Context, login, password string) (*User, error) {
+	var user *User
+	var err error
+	if strings.Contains(login, "@") {
+		user, err = s.repo.FindByEmail(ctx, login)
+	} else {
+		user, err = s.repo.FindByUsername(ctx, utils.NormalizeUsername(login))
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidCredentials.WithCause(err)
+	}
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 
 	// Compare password
 	if !utils.ComparePassword(user.Password, password) {
-		return nil, errors.New("invalid credentials")
+		return nil, ErrInvalidCredentials
 	}
 
 	return user, nil
 }
 
-// AssignRole assigns a role to a user
-func (s *userService) AssignRole(userID uuid.UUID, roleID uuid.UUID) (*userdto.UserRoleResponse, error) {
+// AssignRole replaces every role of a user with the given role
+func (s *userService) AssignRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) (*userdto.UserRoleResponse, error) {
 	// Find user
-	userModel, err := s.repo.FindByID(userID)
+	userModel, err := s.repo.FindByID(ctx, userID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, repository.LookupError(err, ErrUserNotFound, "user")
 	}
+	before := s.snapshot(ctx, userID)
 
 	// Verify role exists
-	if s.roleRepo != nil {
-		_, err := s.roleRepo.FindByID(roleID)
-		if err != nil {
-			return nil, errors.New("role not found")
-		}
+	roleModel, err := s.roleRepo.FindByID(ctx, roleID)
+	if err != nil {
+		return nil, repository.LookupError(err, role.ErrRoleNotFound, "role")
 	}
 
 	// Assign role
-	userModel.RoleID = roleID
-
-	// Save changes
-	if err := s.repo.Update(userModel); err != nil {
+	if err := s.repo.ReplaceRoles(ctx, userModel, []role.Role{*roleModel}); err != nil {
 		return nil, err
 	}
+	s.invalidatePermissions(ctx, userID)
+	s.invalidateResponses(ctx)
+	s.publish(ctx, EventRoleAssigned, RoleAssignedEvent{UserID: userID, RoleID: roleID, RoleSlug: roleModel.Slug, Replaced: true})
+
+	return s.recordRoles(ctx, "user.role_assigned", userID, before)
+}
 
-	// Load user with role
-	userWithRole, err := s.repo.FindByIDWithRole(userID)
+// AttachRole adds a role to the roles a user already has
+func (s *userService) AttachRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) (*userdto.UserRoleResponse, error) {
+	// Find user
+	userModel, err := s.repo.FindByIDWithRole(ctx, userID)
 	if err != nil {
+		return nil, repository.LookupError(err, ErrUserNotFound, "user")
+	}
+
+	// Verify role exists
+	roleModel, err := s.roleRepo.FindByID(ctx, roleID)
+	if err != nil {
+		return nil, repository.LookupError(err, role.ErrRoleNotFound, "role")
+	}
+
+	// Attaching an already assigned role is a no-op
+	for _, assigned := range userModel.Roles {
+		if assigned.ID == roleID {
+			return s.GetProfileWithRole(ctx, userID)
+		}
+	}
+
+	before := userModel.ToResponseWithRole()
+	if err := s.repo.AddRole(ctx, userModel, roleModel); err != nil {
 		return nil, err
 	}
+	s.invalidatePermissions(ctx, userID)
+	s.invalidateResponses(ctx)
+	s.publish(ctx, EventRoleAssigned, RoleAssignedEvent{UserID: userID, RoleID: roleID, RoleSlug: roleModel.Slug})
 
-	response := userWithRole.ToResponseWithRole()
-	return &response, nil
+	return s.recordRoles(ctx, "user.role_attached", userID, before)
 }
 
-// HasPermission checks if a user has a specific permission
-func (s *userService) HasPermission(userID uuid.UUID, permission string) (bool, error) {
-	user, err := s.repo.FindByIDWithRole(userID)
+// DetachRole removes a role from a user, who must keep at least one role
+func (s *userService) DetachRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) (*userdto.UserRoleResponse, error) {
+	// Find user
+	userModel, err := s.repo.FindByIDWithRole(ctx, userID)
 	if err != nil {
-		return false, err
+		return nil, repository.LookupError(err, ErrUserNotFound, "user")
+	}
+
+	var assigned *role.Role
+	for i := range userModel.Roles {
+		if userModel.Roles[i].ID == roleID {
+			assigned = &userModel.Roles[i]
+			break
+		}
+	}
+	if assigned == nil {
+		return nil, ErrRoleNotAssigned
+	}
+	if len(userModel.Roles) == 1 {
+		return nil, ErrLastRole
 	}
 
-	if user.Role == nil {
-		return false, nil
+	before := userModel.ToResponseWithRole()
+	if err := s.repo.RemoveRole(ctx, userModel, assigned); err != nil {
+		return nil, err
 	}
+	s.invalidatePermissions(ctx, userID)
+	s.invalidateResponses(ctx)
 
-	// Check for wildcard permission
-	for _, p := range user.Role.Permissions {
-		if p == "*" {
-			return true, nil
-		}
+	return s.recordRoles(ctx, "user.role_detached", userID, before)
+}
+
+// HasPermission checks if a user has a specific permission through their roles and overrides
+func (s *userService) HasPermission(ctx context.Context, userID uuid.UUID, name string) (bool, error) {
+	user, err := s.GetProfileWithRole(ctx, userID)
+	if err != nil {
+		return false, err
 	}
 
-	// Check specific permission
-	for _, p := range user.Role.Permissions {
-		if p == permission {
+	for _, p := range user.Permissions() {
+		if p == permission.Wildcard || p == name {
 			return true, nil
 		}
 	}
@@ -303,20 +529,529 @@ func (s *userService) HasPermission(userID uuid.UUID, permission string) (bool,
 }
 
 // HasRole checks if a user has a specific role (by slug)
-func (s *userService) HasRole(userID uuid.UUID, roleSlug string) (bool, error) {
-	user, err := s.repo.FindByIDWithRole(userID)
+func (s *userService) HasRole(ctx context.Context, userID uuid.UUID, roleSlug string) (bool, error) {
+	user, err := s.GetProfileWithRole(ctx, userID)
 	if err != nil {
 		return false, err
 	}
 
-	if user.Role == nil {
-		return false, nil
+	return user.HasRole(roleSlug), nil
+}
+
+// GetPermissionOverrides gets the permissions granted or denied to a user on top of their roles
+func (s *userService) GetPermissionOverrides(ctx context.Context, userID uuid.UUID) ([]userdto.PermissionOverride, error) {
+	overrides, err := s.repo.FindPermissionOverrides(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]userdto.PermissionOverride, len(overrides))
+	for i, o := range overrides {
+		response[i] = o.ToResponse()
+	}
+	return response, nil
+}
+
+// SetPermissionOverride grants or denies a permission to a user, replacing any previous override for it
+func (s *userService) SetPermissionOverride(ctx context.Context, userID uuid.UUID, req *userdto.SetPermissionOverrideRequest) ([]userdto.PermissionOverride, error) {
+	exists, err := s.repo.ExistsByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrUserNotFound
+	}
+
+	override := &UserPermission{
+		UserID:     userID,
+		Permission: req.Permission,
+		Effect:     req.Effect,
+	}
+	if err := s.repo.UpsertPermissionOverride(ctx, override); err != nil {
+		return nil, err
+	}
+	s.invalidatePermissions(ctx, userID)
+	s.record(ctx, "user.permission_override_set", userID, nil, override.ToResponse())
+
+	return s.GetPermissionOverrides(replica.WithPrimary(ctx), userID)
+}
+
+// RemovePermissionOverride removes a user's override so the permission follows their roles again
+func (s *userService) RemovePermissionOverride(ctx context.Context, userID uuid.UUID, permission string) ([]userdto.PermissionOverride, error) {
+	removed, err := s.repo.DeletePermissionOverride(ctx, userID, permission)
+	if err != nil {
+		return nil, err
+	}
+	if !removed {
+		return nil, ErrPermissionOverrideNotFound
+	}
+	s.invalidatePermissions(ctx, userID)
+	s.record(ctx, "user.permission_override_removed", userID, map[string]string{"permission": permission}, nil)
+
+	return s.GetPermissionOverrides(replica.WithPrimary(ctx), userID)
+}
+
+// invalidatePermissions drops the cached live permissions and profile of a user after a role or
+// override change
+// Failures only delay the change until the cache entry expires, so they aren't surfaced
+func (s *userService) invalidatePermissions(ctx context.Context, userID uuid.UUID) {
+	_ = s.permCache.InvalidateUser(context.WithoutCancel(ctx), userID.String())
+	s.InvalidateProfile(ctx, userID)
+}
+
+// InvalidateProfile drops the cached GetProfileWithRole result of a user; services that change
+// users without going through this one (e.g. email verification) call it afterwards
+// Failures only delay the change until the cache entry expires, so they aren't surfaced
+func (s *userService) InvalidateProfile(ctx context.Context, userID uuid.UUID) {
+	_ = s.profiles.Invalidate(context.WithoutCancel(ctx), userID)
+}
+
+// invalidateResponses drops cached user lists after a write
+// Failures only delay the change until the cached responses expire, so they aren't surfaced
+func (s *userService) invalidateResponses(ctx context.Context) {
+	_ = s.responses.Invalidate(context.WithoutCancel(ctx), cache.NamespaceUsers)
+}
+
+// record adds a change of a user, made by the authenticated user, to the audit trail
+func (s *userService) record(ctx context.Context, action string, userID uuid.UUID, before, after any) {
+	s.trail.Record(ctx, audit.ActorFromContext(ctx), action, userResource(userID), before, after)
+}
+
+// recordRoles records a change of a user's roles and returns the user with their new roles
+func (s *userService) recordRoles(ctx context.Context, action string, userID uuid.UUID, before any) (*userdto.UserRoleResponse, error) {
+	response, err := s.GetProfileWithRole(replica.WithPrimary(ctx), userID)
+	if err != nil {
+		return nil, err
 	}
+	s.record(ctx, action, userID, before, response)
+	return response, nil
+}
 
-	return user.Role.Slug == roleSlug, nil
+// snapshot loads a user with their roles, as the state before a change for the audit trail
+// It returns nil without a trail, sparing the query
+func (s *userService) snapshot(ctx context.Context, userID uuid.UUID) any {
+	if s.trail == nil {
+		return nil
+	}
+	userModel, err := s.repo.FindByIDWithRole(replica.WithPrimary(ctx), userID)
+	if err != nil {
+		return nil
+	}
+	return userModel.ToResponseWithRole()
+}
+
+// userResource identifies a user in the audit trail
+func userResource(userID uuid.UUID) audit.Resource {
+	return audit.Resource{Type: "user", ID: userID.String()}
+}
+
+// publish sends a domain event after a write
+// Delivery is best-effort (see events.EventBus), so failures don't fail the write
+func (s *userService) publish(ctx context.Context, name string, payload any) {
+	if s.bus == nil {
+		return
+	}
+	_ = s.bus.Publish(context.WithoutCancel(ctx), name, payload)
+}
+
+// assignableRoles loads roles for create/update requests, which may only grant "user" or "admin"
+func (s *userService) assignableRoles(ctx context.Context, roleIDs []uuid.UUID, action string) ([]role.Role, error) {
+	roles := make([]role.Role, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		roleModel, err := s.roleRepo.FindByID(ctx, roleID)
+		if err != nil {
+			return nil, repository.LookupError(err, role.ErrRoleNotFound, "role")
+		}
+
+		if roleModel.Slug != "user" && roleModel.Slug != "admin" {
+			return nil, apperror.New(apperror.ErrForbidden, fmt.Sprintf("can only assign 'user' or 'admin' role during user %s", action)).WithCode("role_not_assignable")
+		}
+
+		roles = append(roles, *roleModel)
+	}
+	return roles, nil
+}
+
+// writeError maps a unique violation on the email or username index, left by a concurrent write
+// between the existence check and the save, to ErrEmailTaken or ErrUsernameTaken
+func writeError(err error) error {
+	switch constraint, _ := repository.UniqueViolation(err); constraint {
+	case "idx_m_users_email":
+		return ErrEmailTaken.WithCause(err)
+	case "idx_m_users_username":
+		return ErrUsernameTaken.WithCause(err)
+	}
+	return err
 }
 
 // GetByEmail gets a user by email
-func (s *userService) GetByEmail(email string) (*User, error) {
-	return s.repo.FindByEmail(email)
+func (s *userService) GetByEmail(ctx context.Context, email string) (*User, error) {
+	userModel, err := s.repo.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, repository.LookupError(err, ErrUserNotFound, "user")
+	}
+	return userModel, nil
+}
+
+// claimUsername normalizes a requested username and checks it is valid and available
+// An empty handle yields nil, since usernames are optional
+func (s *userService) claimUsername(ctx context.Context, handle string) (*string, error) {
+	if handle == "" {
+		return nil, nil
+	}
+
+	username := utils.NormalizeUsername(handle)
+	if !utils.IsValidUsername(username) {
+		return nil, ErrUsernameInvalid
+	}
+
+	exists, err := s.repo.ExistsByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrUsernameTaken
+	}
+
+	return &username, nil
+}
+
+// RecordLogin stores the time of a user's successful authentication
+func (s *userService) RecordLogin(ctx context.Context, userID uuid.UUID) error {
+	return s.repo.UpdateLastLogin(ctx, userID, time.Now())
+}
+
+// dataExportTTL is how long a compiled data export stays downloadable
+const dataExportTTL = 24 * time.Hour
+
+// DataExportService defines the interface for GDPR data export operations
+type DataExportService interface {
+	RequestExport(ctx context.Context, userID uuid.UUID) (*userdto.DataExportResponse, error)
+	GetExport(ctx context.Context, userID, exportID uuid.UUID) (*userdto.DataExportResponse, error)
+	DownloadExport(ctx context.Context, userID, exportID uuid.UUID) (*DataExport, error)
+}
+
+// dataExportService implements DataExportService interface
+type dataExportService struct {
+	repo        DataExportRepository
+	userRepo    UserRepository
+	preferences PreferenceRepository
+	logger      *logrus.Logger
+}
+
+// NewDataExportService creates a new data export service
+func NewDataExportService(repo DataExportRepository, userRepo UserRepository, preferences PreferenceRepository, logger *logrus.Logger) DataExportService {
+	return &dataExportService{
+		repo:        repo,
+		userRepo:    userRepo,
+		preferences: preferences,
+		logger:      logger,
+	}
+}
+
+// RequestExport starts compiling a data export in the background
+// If an export is already in progress or still downloadable, it is returned instead
+func (s *dataExportService) RequestExport(ctx context.Context, userID uuid.UUID) (*userdto.DataExportResponse, error) {
+	if existing, err := s.repo.FindActiveByUserID(ctx, userID); err == nil {
+		response := existing.ToResponse()
+		return &response, nil
+	}
+
+	export := &DataExport{
+		UserID:    userID,
+		Status:    ExportStatusPending,
+		ExpiresAt: time.Now().Add(dataExportTTL),
+	}
+	if err := s.repo.Create(ctx, export); err != nil {
+		return nil, err
+	}
+
+	response := export.ToResponse()
+
+	// Compile asynchronously so large accounts don't block the request
+	go s.compile(context.WithoutCancel(ctx), export)
+
+	return &response, nil
+}
+
+// GetExport gets the status of a data export
+func (s *dataExportService) GetExport(ctx context.Context, userID, exportID uuid.UUID) (*userdto.DataExportResponse, error) {
+	export, err := s.repo.FindByIDForUser(ctx, exportID, userID)
+	if err != nil {
+		return nil, repository.LookupError(err, ErrExportNotFound, "export")
+	}
+
+	response := export.ToResponse()
+	return &response, nil
+}
+
+// DownloadExport returns a completed, non-expired data export including its archive
+func (s *dataExportService) DownloadExport(ctx context.Context, userID, exportID uuid.UUID) (*DataExport, error) {
+	export, err := s.repo.FindByIDForUser(ctx, exportID, userID)
+	if err != nil {
+		return nil, repository.LookupError(err, ErrExportNotFound, "export")
+	}
+
+	if export.Status != ExportStatusCompleted {
+		return nil, ErrExportNotReady
+	}
+
+	if time.Now().After(export.ExpiresAt) {
+		return nil, ErrExportExpired
+	}
+
+	return export, nil
+}
+
+// compile gathers all user data and stores it as a ZIP archive on the export record
+func (s *dataExportService) compile(ctx context.Context, export *DataExport) {
+	export.Status = ExportStatusProcessing
+	if err := s.repo.Update(ctx, export); err != nil {
+		s.logger.Errorf("Failed to mark data export %s as processing: %v", export.ID, err)
+	}
+
+	archive, err := s.buildArchive(ctx, export.UserID)
+	if err != nil {
+		s.logger.Errorf("Failed to compile data export %s: %v", export.ID, err)
+		export.Status = ExportStatusFailed
+		export.Error = err.Error()
+	} else {
+		now := time.Now()
+		export.Status = ExportStatusCompleted
+		export.Archive = archive
+		export.Size = int64(len(archive))
+		export.FileName = fmt.Sprintf("data-export-%s.zip", now.Format("20060102-150405"))
+		export.CompletedAt = &now
+		export.ExpiresAt = now.Add(dataExportTTL)
+	}
+
+	if err := s.repo.Update(ctx, export); err != nil {
+		s.logger.Errorf("Failed to save data export %s: %v", export.ID, err)
+	}
+}
+
+// buildArchive collects the user's profile, OAuth accounts and sessions into a ZIP archive
+func (s *dataExportService) buildArchive(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	userModel, err := s.userRepo.FindByIDWithRole(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load profile: %w", err)
+	}
+
+	overrides, err := s.userRepo.FindPermissionOverrides(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load permission overrides: %w", err)
+	}
+
+	preference, err := s.preferences.FindByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to load preferences: %w", err)
+	}
+	preferences, err := preference.ToResponse()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode preferences: %w", err)
+	}
+
+	accounts, err := s.repo.FindOAuthAccountsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load oauth accounts: %w", err)
+	}
+
+	sessions, err := s.repo.FindSessionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	auditEvents, err := s.repo.FindAuditEventsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load audit events: %w", err)
+	}
+
+	auditLogs, err := s.repo.FindAuditLogsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load audit logs: %w", err)
+	}
+
+	reports, err := s.repo.FindAbuseReportsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load abuse reports: %w", err)
+	}
+
+	emails, err := s.repo.FindEmailMessagesByRecipient(ctx, userModel.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load emails: %w", err)
+	}
+
+	data := userdto.DataExportArchive{
+		GeneratedAt:   time.Now(),
+		Profile:       userModel.ToResponseWithRole(),
+		Preferences:   preferences,
+		OAuthAccounts: make([]userdto.ExportedOAuth, len(accounts)),
+		Sessions:      make([]userdto.ExportedSession, len(sessions)),
+		AuditEvents:   make([]auditdto.AuditEventResponse, len(auditEvents)),
+		AuditLogs:     make([]auditdto.AuditLogResponse, len(auditLogs)),
+		AbuseReports:  make([]userdto.ExportedAbuseReport, len(reports)),
+		Emails:        make([]userdto.ExportedEmail, len(emails)),
+	}
+
+	data.Profile.PermissionOverrides = make([]userdto.PermissionOverride, len(overrides))
+	for i, override := range overrides {
+		data.Profile.PermissionOverrides[i] = override.ToResponse()
+	}
+
+	// Tokens are credentials, not personal data, so they are left out of the export
+	for i, account := range accounts {
+		data.OAuthAccounts[i] = userdto.ExportedOAuth{
+			Provider:   account.Provider,
+			ProviderID: account.ProviderID,
+			CreatedAt:  account.CreatedAt,
+			UpdatedAt:  account.UpdatedAt,
+		}
+	}
+
+	for i, session := range sessions {
+		data.Sessions[i] = userdto.ExportedSession{
+			ID:         session.ID,
+			IPAddress:  session.IPAddress,
+			UserAgent:  session.UserAgent,
+			DeviceID:   session.DeviceID,
+			IsBlocked:  session.IsBlocked,
+			ExpiresAt:  session.ExpiresAt,
+			LastActive: session.LastActive,
+			CreatedAt:  session.CreatedAt,
+		}
+	}
+
+	for i, event := range auditEvents {
+		data.AuditEvents[i] = event.ToResponse()
+	}
+
+	for i, entry := range auditLogs {
+		data.AuditLogs[i] = entry.ToResponse()
+	}
+
+	// Triage notes are the moderators' working notes, not the reporter's data
+	for i, report := range reports {
+		data.AbuseReports[i] = userdto.ExportedAbuseReport{
+			ID:          report.ID,
+			Category:    report.Category,
+			Subject:     report.Subject,
+			Description: report.Description,
+			TargetURL:   report.TargetURL,
+			Status:      report.Status,
+			ResolvedAt:  report.ResolvedAt,
+			CreatedAt:   report.CreatedAt,
+		}
+	}
+
+	for i, message := range emails {
+		data.Emails[i] = userdto.ExportedEmail{
+			ID:        message.ID,
+			Subject:   message.Subject,
+			Template:  message.Template,
+			Status:    message.Status,
+			SentAt:    message.SentAt,
+			CreatedAt: message.CreatedAt,
+		}
+	}
+
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode export: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("export.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive entry: %w", err)
+	}
+	if _, err := w.Write(content); err != nil {
+		return nil, fmt.Errorf("failed to write archive entry: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// PreferenceService defines the interface for user preference operations
+type PreferenceService interface {
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*userdto.PreferencesResponse, error)
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, req *userdto.UpdatePreferencesRequest) (*userdto.PreferencesResponse, error)
+}
+
+// preferenceService implements PreferenceService interface
+type preferenceService struct {
+	repo PreferenceRepository
+}
+
+// NewPreferenceService creates a new preference service
+func NewPreferenceService(repo PreferenceRepository) PreferenceService {
+	return &preferenceService{repo: repo}
+}
+
+// GetPreferences gets a user's effective preferences, falling back to defaults
+func (s *preferenceService) GetPreferences(ctx context.Context, userID uuid.UUID) (*userdto.PreferencesResponse, error) {
+	preference, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	response, err := preference.ToResponse()
+	if err != nil {
+		return nil, apperror.New(apperror.ErrInternal, "failed to read preferences")
+	}
+
+	return &response, nil
+}
+
+// UpdatePreferences applies a partial update over the user's effective preferences
+func (s *preferenceService) UpdatePreferences(ctx context.Context, userID uuid.UUID, req *userdto.UpdatePreferencesRequest) (*userdto.PreferencesResponse, error) {
+	current, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := current.Preferences
+	if req.Theme != nil {
+		preferences.Theme = *req.Theme
+	}
+	if req.Locale != nil {
+		preferences.Locale = *req.Locale
+	}
+	if n := req.Notifications; n != nil {
+		if n.Email != nil {
+			preferences.Notifications.Email = *n.Email
+		}
+		if n.SecurityAlerts != nil {
+			preferences.Notifications.SecurityAlerts = *n.SecurityAlerts
+		}
+		if n.ProductUpdates != nil {
+			preferences.Notifications.ProductUpdates = *n.ProductUpdates
+		}
+		if n.Marketing != nil {
+			preferences.Notifications.Marketing = *n.Marketing
+		}
+	}
+
+	data, err := json.Marshal(preferences)
+	if err != nil {
+		return nil, err
+	}
+
+	preference := &UserPreference{
+		UserID: userID,
+		Data:   PreferencesData(data),
+	}
+	if err := s.repo.Upsert(ctx, preference); err != nil {
+		return nil, err
+	}
+
+	response, err := preference.ToResponse()
+	if err != nil {
+		return nil, apperror.New(apperror.ErrInternal, "failed to read preferences")
+	}
+
+	return &response, nil
 }
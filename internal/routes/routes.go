@@ -0,0 +1,80 @@
+package routes
+
+import (
+	abuseModule "go_boilerplate/internal/modules/abuse"
+	auditModule "go_boilerplate/internal/modules/audit"
+	authModule "go_boilerplate/internal/modules/auth"
+	emailModule "go_boilerplate/internal/modules/email"
+	featureFlagModule "go_boilerplate/internal/modules/featureflag"
+	logLevelModule "go_boilerplate/internal/modules/loglevel"
+	oauthModule "go_boilerplate/internal/modules/oauth"
+	roleModule "go_boilerplate/internal/modules/role"
+	userModule "go_boilerplate/internal/modules/user"
+
+	// [MODULE_IMPORT_MARKER]
+	"go_boilerplate/internal/shared/cache"
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/events"
+	"go_boilerplate/internal/shared/flags"
+	"go_boilerplate/internal/shared/session"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// Register registers the routes of every module; modules publish domain events on bus (nil publishes nothing)
+// and log through their logger from logs, whose level can be changed at runtime
+// Shared by the API server and `cmd/cli routes check`, so it must not have side effects beyond routing
+func Register(app *fiber.App, db *gorm.DB, cfg *config.Config, logs *utils.LogLevels, redisClient *redis.Client, bus events.EventBus) {
+	logger := logs.Root()
+	logger.Info("Registering module routes...")
+
+	// Feature flags (FEATURE_FLAGS with Redis overrides); gate routes with middleware.RequireFeature(features, "name")
+	features := flags.New(cfg, redisClient)
+
+	// Key/value cache (CACHE_DRIVER) shared by the modules, so invalidations reach every reader
+	store := cache.New(cfg.CacheStore, redisClient)
+
+	// Sessions holding refresh tokens (SESSION_STORE), managed by auth and exported by users
+	sessions := session.NewStore(cfg.Sessions, db, redisClient)
+
+	// Auth routes (register, login, refresh, logout)
+	authModule.RegisterRoutes(app, db, cfg, logs.Module("auth"), redisClient, store, sessions, bus)
+	logger.Info("✓ Auth routes registered")
+
+	// User routes (CRUD operations)
+	userModule.RegisterRoutes(app, db, cfg, logs.Module("user"), redisClient, store, sessions, bus)
+	logger.Info("✓ User routes registered")
+
+	// Role routes (manage roles - SuperAdmin only)
+	roleModule.RegisterRoutes(app, db, cfg, logs.Module("role"), redisClient, store)
+	logger.Info("✓ Role routes registered")
+
+	// OAuth routes (Google, GitHub)
+	oauthModule.RegisterRoutes(app, db, cfg, logs.Module("oauth"), redisClient, store, bus)
+	logger.Info("✓ OAuth routes registered")
+
+	// Abuse report routes (security.txt, public reports, admin triage)
+	abuseModule.RegisterRoutes(app, db, cfg, logs.Module("abuse"))
+	logger.Info("✓ Abuse report routes registered")
+
+	// Audit log routes (query recorded mutating requests)
+	auditModule.RegisterRoutes(app, db, cfg, logs.Module("audit"))
+	logger.Info("✓ Audit log routes registered")
+
+	// Email delivery log routes (query outgoing email, resend)
+	emailModule.RegisterRoutes(app, db, cfg, logs.Module("email"))
+	logger.Info("✓ Email routes registered")
+
+	// Feature flag routes (list and override flags at runtime)
+	featureFlagModule.RegisterRoutes(app, cfg, logs.Module("featureflag"), features)
+	logger.Info("✓ Feature flag routes registered")
+
+	// Log level routes (change the root or a module's log level at runtime)
+	logLevelModule.RegisterRoutes(app, cfg, logs, bus)
+	logger.Info("✓ Log level routes registered")
+
+	// [MODULE_ROUTE_MARKER]
+}
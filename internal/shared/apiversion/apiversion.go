@@ -0,0 +1,103 @@
+package apiversion
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Prefix is the path prefix of every API version (/api/v1, /api/v2, ...)
+const Prefix = "/api/"
+
+// MediaTypePrefix lets clients pick a version through Accept: application/vnd.go-boilerplate.v2+json
+const MediaTypePrefix = "application/vnd.go-boilerplate."
+
+// RequestHeader lets clients pick a version explicitly (Accept-Version: v2)
+const RequestHeader = "Accept-Version"
+
+// ResponseHeader reports the version that served the request
+const ResponseHeader = "API-Version"
+
+var (
+	mu       sync.RWMutex
+	versions = map[string]bool{}
+)
+
+// Group returns the route group of a version (/api/<version>) and registers the version,
+// so modules can add a v2 next to v1 without any other wiring:
+//
+//	v1 := apiversion.Group(app, "v1")
+//	v2 := apiversion.Group(app, "v2")
+func Group(router fiber.Router, version string, handlers ...fiber.Handler) fiber.Router {
+	if !Valid(version) {
+		panic("apiversion: invalid version " + strconv.Quote(version) + ", want v<number>")
+	}
+
+	mu.Lock()
+	versions[version] = true
+	mu.Unlock()
+
+	return router.Group(Prefix+version, handlers...)
+}
+
+// Registered reports whether a module registered routes under version
+func Registered(version string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return versions[version]
+}
+
+// Versions returns every registered version, oldest first
+func Versions() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	list := make([]string, 0, len(versions))
+	for version := range versions {
+		list = append(list, version)
+	}
+	sort.Slice(list, func(i, j int) bool { return number(list[i]) < number(list[j]) })
+	return list
+}
+
+// Valid reports whether version has the v<number> form
+func Valid(version string) bool {
+	return number(version) > 0
+}
+
+// FromPath returns the version segment of an API path (/api/v2/users → v2)
+func FromPath(path string) (string, bool) {
+	if !strings.HasPrefix(path, Prefix) {
+		return "", false
+	}
+	segment, _, _ := strings.Cut(path[len(Prefix):], "/")
+	return segment, Valid(segment)
+}
+
+// FromAccept returns the version named by a vendor media type in an Accept header, if any
+func FromAccept(accept string) (string, bool) {
+	for _, mediaType := range strings.Split(accept, ",") {
+		mediaType, _, _ = strings.Cut(strings.TrimSpace(mediaType), ";")
+		if rest, ok := strings.CutPrefix(mediaType, MediaTypePrefix); ok {
+			version, _, _ := strings.Cut(rest, "+")
+			return version, true
+		}
+	}
+	return "", false
+}
+
+// number parses the numeric part of a version, 0 when invalid
+func number(version string) int {
+	digits, ok := strings.CutPrefix(version, "v")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.Atoi(digits)
+	if err != nil || n <= 0 || strconv.Itoa(n) != digits {
+		return 0
+	}
+	return n
+}
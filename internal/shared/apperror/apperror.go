@@ -0,0 +1,150 @@
+package apperror
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Error is an application error with a stable, machine-readable code and the HTTP status it maps to.
+// Services return them (directly or wrapped) and utils.ErrorResponse renders them as problem+json:
+//
+//	return nil, apperror.New(apperror.ErrNotFound, "user not found")
+//
+// errors.Is(err, apperror.ErrNotFound) matches every error created from that kind.
+type Error struct {
+	Code    string // Stable machine-readable code, e.g. not_found or email_taken
+	Status  int    // HTTP status
+	Message string // Client-facing detail (English; translated when rendered)
+	Err     error  // Underlying cause; logged, never sent to clients
+
+	kind   *Error // Kind the error was created from; nil for the kinds below
+	origin *Error // Module error a WithCause copy was made from
+}
+
+// Error kinds; create errors from them with New or Wrap
+var (
+	ErrBadRequest   = &Error{Code: "bad_request", Status: fiber.StatusBadRequest}
+	ErrValidation   = &Error{Code: "validation_failed", Status: fiber.StatusBadRequest}
+	ErrUnauthorized = &Error{Code: "unauthorized", Status: fiber.StatusUnauthorized}
+	ErrForbidden    = &Error{Code: "forbidden", Status: fiber.StatusForbidden}
+	ErrNotFound     = &Error{Code: "not_found", Status: fiber.StatusNotFound}
+	ErrConflict     = &Error{Code: "conflict", Status: fiber.StatusConflict}
+	ErrGone         = &Error{Code: "gone", Status: fiber.StatusGone}
+	ErrRateLimited  = &Error{Code: "rate_limited", Status: fiber.StatusTooManyRequests}
+	ErrUnavailable  = &Error{Code: "service_unavailable", Status: fiber.StatusServiceUnavailable}
+	ErrInternal     = &Error{Code: "internal_error", Status: fiber.StatusInternalServerError}
+)
+
+// New creates an error of the given kind
+func New(kind *Error, message string) *Error {
+	return &Error{Code: kind.Code, Status: kind.Status, Message: message, kind: kind}
+}
+
+// Wrap creates an error of the given kind caused by err
+func Wrap(kind *Error, message string, err error) *Error {
+	e := New(kind, message)
+	e.Err = err
+	return e
+}
+
+// WithCode returns a copy of e with a more specific code (e.g. email_taken instead of conflict)
+func (e *Error) WithCode(code string) *Error {
+	copied := *e
+	copied.Code = code
+	return &copied
+}
+
+// WithCause returns a copy of e caused by err; errors.Is still matches e, so module errors such as
+// user.ErrUserNotFound keep the repository error that produced them
+func (e *Error) WithCause(err error) *Error {
+	copied := e.derive()
+	copied.Err = err
+	return copied
+}
+
+// WithMessage returns a copy of e with a more specific client-facing message (e.g. naming the
+// rejected filter field); errors.Is still matches e
+func (e *Error) WithMessage(message string) *Error {
+	copied := e.derive()
+	copied.Message = message
+	return copied
+}
+
+// derive copies e, remembering the module error it was derived from
+func (e *Error) derive() *Error {
+	copied := *e
+	if copied.origin == nil {
+		copied.origin = e
+	}
+	return &copied
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	message := e.Message
+	if message == "" {
+		message = e.Code
+	}
+	if e.Err != nil {
+		return message + ": " + e.Err.Error()
+	}
+	return message
+}
+
+// Unwrap returns the underlying cause
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether e was created from the kind target, or derived from target by WithCause or WithMessage
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return (t.kind == nil && e.kind == t) || (e.origin != nil && e.origin == t)
+}
+
+// As returns the first *Error in err's chain
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+// CodeForStatus returns the code of the kind mapping to status, for errors that aren't an *Error
+func CodeForStatus(status int) string {
+	switch status {
+	case fiber.StatusBadRequest:
+		return ErrBadRequest.Code
+	case fiber.StatusUnauthorized:
+		return ErrUnauthorized.Code
+	case fiber.StatusForbidden:
+		return ErrForbidden.Code
+	case fiber.StatusNotFound:
+		return ErrNotFound.Code
+	case fiber.StatusMethodNotAllowed:
+		return "method_not_allowed"
+	case fiber.StatusConflict:
+		return ErrConflict.Code
+	case fiber.StatusGone:
+		return ErrGone.Code
+	case fiber.StatusRequestEntityTooLarge:
+		return "payload_too_large"
+	case fiber.StatusUnsupportedMediaType:
+		return "unsupported_media_type"
+	case fiber.StatusNotAcceptable:
+		return "not_acceptable"
+	case fiber.StatusTooManyRequests:
+		return ErrRateLimited.Code
+	case fiber.StatusServiceUnavailable:
+		return ErrUnavailable.Code
+	}
+	if status >= fiber.StatusInternalServerError {
+		return ErrInternal.Code
+	}
+	return ErrBadRequest.Code
+}
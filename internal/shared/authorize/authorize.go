@@ -0,0 +1,79 @@
+package authorize
+
+import (
+	"go_boilerplate/internal/shared/middleware"
+	"go_boilerplate/internal/shared/observability"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// OwnerLookup resolves the owner of the resource targeted by the request
+type OwnerLookup func(c *fiber.Ctx) (uuid.UUID, error)
+
+// CanAccess implements "own-or-admin": the authenticated user may access a resource they own,
+// or any resource when their token grants permission (exact name or `*`)
+func CanAccess(c *fiber.Ctx, resourceOwnerID uuid.UUID, perm string) bool {
+	userID, ok := middleware.GetUserIDFromContext(c)
+	if !ok {
+		return false
+	}
+
+	if resourceOwnerID != uuid.Nil && userID == resourceOwnerID.String() {
+		recordDecision(c, perm, true, "owner")
+		return true
+	}
+
+	if HasPermission(c, perm) {
+		recordDecision(c, perm, true, "")
+		return true
+	}
+
+	recordDecision(c, perm, false, "not owner and missing permission")
+	return false
+}
+
+// HasPermission reports whether the authenticated user holds perm, through the token permissions
+// or the configured authorizer (see middleware.UseAuthorizer)
+func HasPermission(c *fiber.Ctx, perm string) bool {
+	return middleware.HasPermission(c, perm)
+}
+
+// RequireOwnerOr allows the request when the authenticated user owns the resource resolved by lookup
+// or holds perm; must be used after JWTAuth
+func RequireOwnerOr(perm string, lookup OwnerLookup) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, ok := middleware.GetUserIDFromContext(c); !ok {
+			return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Unauthorized", nil)
+		}
+
+		ownerID, err := lookup(c)
+		if err != nil {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Resource not found", nil)
+		}
+
+		if !CanAccess(c, ownerID, perm) {
+			return utils.ProblemResponse(c, fiber.StatusForbidden, "You can only access your own resources", nil, map[string]any{"required": perm})
+		}
+
+		return c.Next()
+	}
+}
+
+// OwnerFromParam uses a route parameter as the owner ID, for resources keyed by user (e.g. /users/:id)
+func OwnerFromParam(name string) OwnerLookup {
+	return func(c *fiber.Ctx) (uuid.UUID, error) {
+		return uuid.Parse(c.Params(name))
+	}
+}
+
+// recordDecision adds the ownership check to the debug trace of X-Debug requests
+func recordDecision(c *fiber.Ctx, perm string, granted bool, reason string) {
+	observability.FromContext(c.UserContext()).RecordAuthDecision(observability.DebugAuthDecision{
+		Check:    "ownership",
+		Required: []string{perm},
+		Granted:  granted,
+		Reason:   reason,
+	})
+}
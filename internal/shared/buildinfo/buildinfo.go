@@ -0,0 +1,81 @@
+// Package buildinfo describes the running binary. The version, commit and build time are set at
+// build time (see the Makefile's LDFLAGS):
+//
+//	go build -ldflags "-X go_boilerplate/internal/shared/buildinfo.Version=v1.4.0 \
+//		-X go_boilerplate/internal/shared/buildinfo.Commit=$(git rev-parse --short HEAD) \
+//		-X go_boilerplate/internal/shared/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/api
+//
+// Without them, the commit and build time fall back to the VCS stamp Go records when building
+// inside a git checkout.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// unknown is reported for values neither ldflags nor the VCS stamp provide
+const unknown = "unknown"
+
+// Set with -ldflags "-X go_boilerplate/internal/shared/buildinfo.<Name>=<value>"
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// Info is the build of the running binary
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the build of the running binary
+var Get = sync.OnceValue(func() Info {
+	info := Info{Version: Version, Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = shortCommit(setting.Value)
+			case setting.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = unknown
+	}
+	if info.Commit == "" {
+		info.Commit = unknown
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = unknown
+	}
+	return info
+})
+
+// String formats the build for the startup log, e.g. "v1.4.0 (commit 3f2a9c1, built 2025-01-31T10:00:00Z, go1.25.5)"
+func (i Info) String() string {
+	return i.Version + " (commit " + i.Commit + ", built " + i.BuildTime + ", " + i.GoVersion + ")"
+}
+
+// Handler answers GET /version with the build of the running binary
+func Handler(c *fiber.Ctx) error {
+	return c.JSON(Get())
+}
+
+// shortCommit abbreviates a full commit hash like git rev-parse --short
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
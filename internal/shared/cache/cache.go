@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go_boilerplate/internal/shared/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cache drivers (CACHE_DRIVER)
+const (
+	DriverRedis  = "redis"  // Shared by every instance
+	DriverMemory = "memory" // In-process LRU; invalidations only reach the instance that made them
+)
+
+// ErrMiss is returned by Get when nothing is cached under the key
+var ErrMiss = errors.New("cache miss")
+
+// Cache stores values under keys for a limited time, so services can cache without depending on
+// go-redis. A ttl of 0 keeps the value until it is deleted or evicted.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// New returns the cache selected by CACHE_DRIVER; without a Redis client it falls back to memory,
+// and the redis driver keeps values in memory while Redis fails
+func New(cfg config.CacheStoreConfig, client *redis.Client) Cache {
+	if cfg.Driver == DriverRedis && client != nil {
+		return NewFallback(NewRedis(client), NewMemory(cfg.MemoryMaxEntries))
+	}
+	return NewMemory(cfg.MemoryMaxEntries)
+}
+
+// Remember returns the value cached under key, calling load and caching its result for ttl on a
+// miss. Values are stored as JSON. Cache failures fall back to load, so an outage only costs
+// latency; a nil cache always loads.
+func Remember[T any](ctx context.Context, c Cache, key string, ttl time.Duration, load func(ctx context.Context) (T, error)) (T, error) {
+	if c == nil {
+		return load(ctx)
+	}
+
+	if data, err := c.Get(ctx, key); err == nil {
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			return value, nil
+		}
+	}
+
+	value, err := load(ctx)
+	if err != nil {
+		return value, err
+	}
+	if data, err := json.Marshal(value); err == nil {
+		_ = c.Set(ctx, key, data, ttl)
+	}
+	return value, nil
+}
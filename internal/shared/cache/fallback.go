@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Fallback is a Cache over primary (Redis) that uses secondary (memory) while primary fails, so
+// an outage keeps values cached per instance instead of disabling caching
+type Fallback struct {
+	primary   Cache
+	secondary Cache
+}
+
+// NewFallback creates a cache using secondary whenever primary returns an error
+func NewFallback(primary, secondary Cache) *Fallback {
+	return &Fallback{primary: primary, secondary: secondary}
+}
+
+// Get implements Cache
+func (c *Fallback) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.primary.Get(ctx, key)
+	if err == nil || errors.Is(err, ErrMiss) {
+		return value, err
+	}
+	return c.secondary.Get(ctx, key)
+}
+
+// Set implements Cache. A value stored in primary is dropped from secondary, so a later outage
+// can't bring back what secondary kept from an earlier one.
+func (c *Fallback) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.primary.Set(ctx, key, value, ttl); err != nil {
+		return c.secondary.Set(ctx, key, value, ttl)
+	}
+	return c.secondary.Delete(ctx, key)
+}
+
+// Delete implements Cache. Keys are dropped from both caches; the error is primary's, since its
+// copy may still be served once it is back (see database.RedisMonitor.OnRecover)
+func (c *Fallback) Delete(ctx context.Context, keys ...string) error {
+	_ = c.secondary.Delete(ctx, keys...)
+	return c.primary.Delete(ctx, keys...)
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryEntry is a value of the memory cache, kept in its recency list
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // Zero when the value never expires
+}
+
+// Memory is an in-process Cache holding at most maxEntries values; past that the least recently
+// used one is evicted. Expired values are dropped when read or evicted.
+type Memory struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	recency *list.List // Most recently used first
+}
+
+// NewMemory creates an in-memory LRU cache; maxEntries below 1 is treated as 1
+func NewMemory(maxEntries int) *Memory {
+	return &Memory{
+		maxEntries: max(maxEntries, 1),
+		entries:    make(map[string]*list.Element),
+		recency:    list.New(),
+	}
+}
+
+// Get implements Cache
+func (c *Memory) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, ErrMiss
+	}
+	entry := element.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.remove(element)
+		return nil, ErrMiss
+	}
+	c.recency.MoveToFront(element)
+	return bytes.Clone(entry.value), nil
+}
+
+// Set implements Cache
+func (c *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := &memoryEntry{key: key, value: bytes.Clone(value)}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.recency.MoveToFront(element)
+		return nil
+	}
+	c.entries[key] = c.recency.PushFront(entry)
+	for c.recency.Len() > c.maxEntries {
+		c.remove(c.recency.Back())
+	}
+	return nil
+}
+
+// Delete implements Cache
+func (c *Memory) Delete(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := c.entries[key]; ok {
+			c.remove(element)
+		}
+	}
+	return nil
+}
+
+// remove drops element; callers hold c.mu
+func (c *Memory) remove(element *list.Element) {
+	c.recency.Remove(element)
+	delete(c.entries, element.Value.(*memoryEntry).key)
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// NamespaceUserRoles holds users with their roles and effective permissions
+// (user.GetProfileWithRole); the role service clears it when a role changes
+const NamespaceUserRoles = "user-roles"
+
+// Namespace is a Cache whose keys carry a name and a generation, so Clear drops every key of the
+// namespace at once and stale values simply expire. A nil *Namespace never caches.
+type Namespace struct {
+	cache Cache
+	name  string
+}
+
+// NewNamespace groups keys of c under name; without a cache it returns nil
+func NewNamespace(c Cache, name string) *Namespace {
+	if c == nil {
+		return nil
+	}
+	return &Namespace{cache: c, name: name}
+}
+
+// Get implements Cache
+func (n *Namespace) Get(ctx context.Context, key string) ([]byte, error) {
+	if n == nil {
+		return nil, ErrMiss
+	}
+	full, err := n.key(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return n.cache.Get(ctx, full)
+}
+
+// Set implements Cache
+func (n *Namespace) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if n == nil {
+		return nil
+	}
+	full, err := n.key(ctx, key)
+	if err != nil {
+		return err
+	}
+	return n.cache.Set(ctx, full, value, ttl)
+}
+
+// Delete implements Cache
+func (n *Namespace) Delete(ctx context.Context, keys ...string) error {
+	if n == nil {
+		return nil
+	}
+	full := make([]string, len(keys))
+	for i, key := range keys {
+		var err error
+		if full[i], err = n.key(ctx, key); err != nil {
+			return err
+		}
+	}
+	return n.cache.Delete(ctx, full...)
+}
+
+// Clear drops every key of the namespace by starting a new generation
+func (n *Namespace) Clear(ctx context.Context) error {
+	if n == nil {
+		return nil
+	}
+	_, err := n.newGeneration(ctx)
+	return err
+}
+
+// key builds the key of the namespace's current generation
+func (n *Namespace) key(ctx context.Context, key string) (string, error) {
+	generation, err := n.cache.Get(ctx, n.generationKey())
+	if errors.Is(err, ErrMiss) {
+		// Never set, or evicted by the memory driver: older values must not become reachable again
+		generation, err = n.newGeneration(ctx)
+	}
+	if err != nil {
+		return "", err
+	}
+	return n.name + ":" + string(generation) + ":" + key, nil
+}
+
+// newGeneration stores and returns a new generation of the namespace
+func (n *Namespace) newGeneration(ctx context.Context) ([]byte, error) {
+	generation := []byte(strconv.FormatInt(time.Now().UnixNano(), 36))
+	if err := n.cache.Set(ctx, n.generationKey(), generation, 0); err != nil {
+		return nil, err
+	}
+	return generation, nil
+}
+
+// generationKey holds the current generation of the namespace
+func (n *Namespace) generationKey() string {
+	return n.name + ":generation"
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisPrefix keeps Cache keys apart from the other Redis keys (responses, permissions, OTPs)
+const redisPrefix = "cache:"
+
+// Redis is a Cache shared by every API instance
+type Redis struct {
+	redis *redis.Client
+}
+
+// NewRedis creates a Redis-backed cache
+func NewRedis(client *redis.Client) *Redis {
+	return &Redis{redis: client}
+}
+
+// Get implements Cache
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := c.redis.Get(ctx, redisPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrMiss
+	}
+	return data, err
+}
+
+// Set implements Cache
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.redis.Set(ctx, redisPrefix+key, value, ttl).Err()
+}
+
+// Delete implements Cache
+func (c *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = redisPrefix + key
+	}
+	return c.redis.Del(ctx, prefixed...).Err()
+}
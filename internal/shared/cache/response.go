@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go_boilerplate/internal/shared/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Namespaces of cached endpoints; services invalidate them after writes
+const (
+	NamespaceUsers = "users"
+	NamespaceRoles = "roles"
+)
+
+// Entry is a cached response body
+type Entry struct {
+	ContentType string
+	Headers     map[string]string // Response headers replayed on hits, e.g. pagination headers
+	Body        []byte
+}
+
+// ResponseCache stores GET responses in Redis, grouped by namespace (usually one per resource).
+// Each namespace has a generation counter that is part of every key, so invalidating a namespace
+// is a single INCR and stale entries simply expire. A nil *ResponseCache is valid and never caches.
+type ResponseCache struct {
+	redis *redis.Client
+	cfg   config.ResponseCacheConfig
+}
+
+// NewResponseCache creates a response cache
+func NewResponseCache(client *redis.Client, cfg config.ResponseCacheConfig) *ResponseCache {
+	return &ResponseCache{redis: client, cfg: cfg}
+}
+
+// Enabled reports whether responses are cached (RESPONSE_CACHE_ENABLED)
+func (c *ResponseCache) Enabled() bool {
+	return c != nil && c.redis != nil && c.cfg.Enabled
+}
+
+// DefaultTTL is used by routes that don't set their own TTL (RESPONSE_CACHE_TTL)
+func (c *ResponseCache) DefaultTTL() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return c.cfg.TTL
+}
+
+// Key builds the Redis key of a rendered cache key in the namespace's current generation
+// Build it before running the handler so a write that lands meanwhile leaves the result unreachable
+func (c *ResponseCache) Key(ctx context.Context, namespace, rendered string) (string, error) {
+	generation, err := c.redis.Get(ctx, generationKey(namespace)).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(rendered))
+	return fmt.Sprintf("response:%s:%d:%s", namespace, generation, hex.EncodeToString(sum[:])), nil
+}
+
+// Get returns the entry stored under key
+func (c *ResponseCache) Get(ctx context.Context, key string) (*Entry, bool) {
+	data, err := c.redis.Get(ctx, key).Bytes()
+	if err != nil {
+		return nil, false
+	}
+	head, body, ok := bytes.Cut(data, []byte("\n"))
+	if !ok {
+		return nil, false
+	}
+
+	// The first line is the content type followed by tab-separated "Name: value" headers
+	fields := strings.Split(string(head), "\t")
+	entry := &Entry{ContentType: fields[0], Body: body}
+	for _, field := range fields[1:] {
+		if name, value, ok := strings.Cut(field, ": "); ok {
+			if entry.Headers == nil {
+				entry.Headers = make(map[string]string)
+			}
+			entry.Headers[name] = value
+		}
+	}
+	return entry, true
+}
+
+// Set stores an entry under key for ttl
+func (c *ResponseCache) Set(ctx context.Context, key string, entry Entry, ttl time.Duration) error {
+	data := make([]byte, 0, len(entry.ContentType)+1+len(entry.Body))
+	data = append(data, entry.ContentType...)
+	for name, value := range entry.Headers {
+		data = append(data, '\t')
+		data = append(data, name+": "+value...)
+	}
+	data = append(data, '\n')
+	data = append(data, entry.Body...)
+	return c.redis.Set(ctx, key, data, ttl).Err()
+}
+
+// Invalidate drops every cached response of the given namespaces
+func (c *ResponseCache) Invalidate(ctx context.Context, namespaces ...string) error {
+	if !c.Enabled() {
+		return nil
+	}
+	for _, namespace := range namespaces {
+		if err := c.redis.Incr(ctx, generationKey(namespace)).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generationKey is the counter bumped when a namespace is invalidated
+func generationKey(namespace string) string {
+	return "response:generation:" + namespace
+}
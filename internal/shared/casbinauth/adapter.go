@@ -0,0 +1,117 @@
+package casbinauth
+
+import (
+	"fmt"
+
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+	"gorm.io/gorm"
+)
+
+// CasbinRule is a stored policy line (p, sub, obj, act, eft or g, user, role)
+type CasbinRule struct {
+	ID    uint   `gorm:"primaryKey;autoIncrement"`
+	Ptype string `gorm:"type:varchar(100);not null;index"`
+	V0    string `gorm:"type:varchar(255);not null;default:''"`
+	V1    string `gorm:"type:varchar(255);not null;default:''"`
+	V2    string `gorm:"type:varchar(255);not null;default:''"`
+	V3    string `gorm:"type:varchar(255);not null;default:''"`
+	V4    string `gorm:"type:varchar(255);not null;default:''"`
+	V5    string `gorm:"type:varchar(255);not null;default:''"`
+}
+
+// TableName specifies the table name for CasbinRule model
+func (CasbinRule) TableName() string {
+	return "m_casbin_rules"
+}
+
+// values returns the rule as a policy line without trailing empty fields
+func (r CasbinRule) values() []string {
+	values := []string{r.Ptype, r.V0, r.V1, r.V2, r.V3, r.V4, r.V5}
+	for len(values) > 1 && values[len(values)-1] == "" {
+		values = values[:len(values)-1]
+	}
+	return values
+}
+
+// newRule builds a stored rule from a policy line
+func newRule(ptype string, rule []string) CasbinRule {
+	fields := make([]string, 6)
+	copy(fields, rule)
+	return CasbinRule{Ptype: ptype, V0: fields[0], V1: fields[1], V2: fields[2], V3: fields[3], V4: fields[4], V5: fields[5]}
+}
+
+// Adapter stores Casbin policies in m_casbin_rules through GORM
+type Adapter struct {
+	db *gorm.DB
+}
+
+var _ persist.Adapter = (*Adapter)(nil)
+
+// NewAdapter creates a new GORM policy adapter
+func NewAdapter(db *gorm.DB) *Adapter {
+	return &Adapter{db: db}
+}
+
+// LoadPolicy loads all policy rules into the model
+func (a *Adapter) LoadPolicy(m model.Model) error {
+	var rules []CasbinRule
+	if err := a.db.Order("id").Find(&rules).Error; err != nil {
+		return err
+	}
+
+	for _, r := range rules {
+		if err := persist.LoadPolicyArray(r.values(), m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SavePolicy replaces every stored rule with the rules of the model
+func (a *Adapter) SavePolicy(m model.Model) error {
+	var rules []CasbinRule
+	for _, sec := range []string{"p", "g"} {
+		for ptype, assertion := range m[sec] {
+			for _, rule := range assertion.Policy {
+				rules = append(rules, newRule(ptype, rule))
+			}
+		}
+	}
+
+	return a.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&CasbinRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		return tx.Create(&rules).Error
+	})
+}
+
+// AddPolicy stores a single rule
+func (a *Adapter) AddPolicy(sec string, ptype string, rule []string) error {
+	r := newRule(ptype, rule)
+	return a.db.Create(&r).Error
+}
+
+// RemovePolicy deletes a single rule
+func (a *Adapter) RemovePolicy(sec string, ptype string, rule []string) error {
+	r := newRule(ptype, rule)
+	// A map keeps empty fields in the condition, unlike a struct
+	return a.db.Where(map[string]any{
+		"ptype": r.Ptype, "v0": r.V0, "v1": r.V1, "v2": r.V2, "v3": r.V3, "v4": r.V4, "v5": r.V5,
+	}).Delete(&CasbinRule{}).Error
+}
+
+// RemoveFilteredPolicy deletes the rules whose fields from fieldIndex on match fieldValues (empty values match anything)
+func (a *Adapter) RemoveFilteredPolicy(sec string, ptype string, fieldIndex int, fieldValues ...string) error {
+	query := a.db.Where("ptype = ?", ptype)
+	for i, value := range fieldValues {
+		if value != "" && fieldIndex+i < 6 {
+			query = query.Where(fmt.Sprintf("v%d = ?", fieldIndex+i), value)
+		}
+	}
+	return query.Delete(&CasbinRule{}).Error
+}
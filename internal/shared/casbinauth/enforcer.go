@@ -0,0 +1,148 @@
+package casbinauth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go_boilerplate/internal/shared/config"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+	"gorm.io/gorm"
+)
+
+// DefaultModel is RBAC with role inheritance, wildcard objects/actions and explicit denies
+// Requests are (subject, object, action) where a `resource.action` permission is split at the dot
+// and the subject is the user ID or one of the user's role slugs
+const DefaultModel = `
+[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act, eft
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow)) && !some(where (p.eft == deny))
+
+[matchers]
+m = g(r.sub, p.sub) && (p.obj == "*" || r.obj == p.obj) && (p.act == "*" || r.act == p.act)
+`
+
+// Grant is a permission given to (or denied from) a subject, used to seed an empty policy
+type Grant struct {
+	Subject    string // Role slug or user ID
+	Permission string // resource.action, resource.*, *.action or *
+	Deny       bool
+}
+
+// Enforcer authorizes permission checks against Casbin policies stored in m_casbin_rules
+// It implements middleware.Authorizer
+type Enforcer struct {
+	enforcer *casbin.SyncedEnforcer
+}
+
+// NewEnforcer loads the model (CASBIN_MODEL_PATH or DefaultModel) and the stored policy
+func NewEnforcer(db *gorm.DB, cfg config.RBACConfig) (*Enforcer, error) {
+	var (
+		m   model.Model
+		err error
+	)
+	if cfg.CasbinModelPath != "" {
+		m, err = model.NewModelFromFile(cfg.CasbinModelPath)
+	} else {
+		m, err = model.NewModelFromString(DefaultModel)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load casbin model: %w", err)
+	}
+
+	enforcer, err := casbin.NewSyncedEnforcer(m, NewAdapter(db))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create casbin enforcer: %w", err)
+	}
+
+	return &Enforcer{enforcer: enforcer}, nil
+}
+
+// Authorize reports whether any of the subjects is allowed the permission
+// Subjects are checked in order and a matching deny stops the check, so a deny on the user ID
+// (listed first) wins over grants of the user's roles
+func (e *Enforcer) Authorize(ctx context.Context, subjects []string, permission string) (bool, error) {
+	obj, act := splitPermission(permission)
+	for _, sub := range subjects {
+		allowed, explain, err := e.enforcer.EnforceEx(sub, obj, act)
+		if err != nil {
+			return false, err
+		}
+		if allowed {
+			return true, nil
+		}
+		if len(explain) > 0 {
+			return false, nil
+		}
+	}
+	return false, nil
+}
+
+// Reload re-reads the stored policy, e.g. after editing m_casbin_rules directly
+func (e *Enforcer) Reload() error {
+	return e.enforcer.LoadPolicy()
+}
+
+// SeedIfEmpty stores the grants and role inheritance links (child, parent) when no policy exists yet,
+// so switching backends starts from the permissions the native RBAC already granted
+func (e *Enforcer) SeedIfEmpty(grants []Grant, inherits [][2]string) (bool, error) {
+	policies, err := e.enforcer.GetPolicy()
+	if err != nil {
+		return false, err
+	}
+	groupings, err := e.enforcer.GetGroupingPolicy()
+	if err != nil {
+		return false, err
+	}
+	if len(policies) > 0 || len(groupings) > 0 {
+		return false, nil
+	}
+
+	var rules [][]string
+	for _, g := range grants {
+		obj, act := splitPermission(g.Permission)
+		eft := "allow"
+		if g.Deny {
+			eft = "deny"
+		}
+		rules = append(rules, []string{g.Subject, obj, act, eft})
+	}
+	if len(rules) > 0 {
+		if _, err := e.enforcer.AddPolicies(rules); err != nil {
+			return false, err
+		}
+	}
+
+	var links [][]string
+	for _, link := range inherits {
+		links = append(links, []string{link[0], link[1]})
+	}
+	if len(links) > 0 {
+		if _, err := e.enforcer.AddGroupingPolicies(links); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// splitPermission splits `resource.action` into object and action; the wildcard covers both
+func splitPermission(permission string) (string, string) {
+	if permission == "*" {
+		return "*", "*"
+	}
+	if i := strings.LastIndex(permission, "."); i >= 0 {
+		return permission[:i], permission[i+1:]
+	}
+	return permission, "*"
+}
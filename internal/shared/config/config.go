@@ -2,64 +2,387 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
+	"go_boilerplate/internal/shared/awssig"
+	"go_boilerplate/internal/shared/database/encryption"
+	"go_boilerplate/internal/shared/secrets"
+
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Server     ServerConfig
-	Database   DatabaseConfig
-	Redis      RedisConfig
-	JWT        JWTConfig
-	OAuth      OAuthConfig
-	Email      EmailConfig
-	Security   SecurityConfig
-	Logger     LoggerConfig
-	SuperAdmin SuperAdminConfig
+	Server      ServerConfig
+	TLS         TLSConfig
+	Proxy       ProxyConfig
+	Database    DatabaseConfig
+	Redis       RedisConfig
+	JWT         JWTConfig
+	OAuth       OAuthConfig
+	Email       EmailConfig
+	EmailQueue  EmailQueueConfig
+	Security    SecurityConfig
+	Logger      LoggerConfig
+	SuperAdmin  SuperAdminConfig
+	Notify      NotifyConfig
+	Abuse       AbuseConfig
+	Activity    ActivityConfig
+	Anomaly     AnomalyConfig
+	Trash       TrashConfig
+	Debug       DebugConfig
+	RBAC        RBACConfig
+	Pool        PoolConfig
+	Health      HealthConfig
+	Metrics     MetricsConfig
+	Tracing     TracingConfig
+	Encryption  EncryptionConfig
+	Concurrency ConcurrencyConfig
+	CORS        CORSConfig
+	Compression CompressionConfig
+	Audit       AuditConfig
+	BodyLog     BodyLogConfig
+	I18n        I18nConfig
+	Cache       ResponseCacheConfig
+	CacheStore  CacheStoreConfig
+	Sessions    SessionStoreConfig
+	Events      EventBusConfig
+	APIVersion  APIVersionConfig
+	Tenancy     TenancyConfig
+	Secrets     SecretsConfig
+	Features    FeaturesConfig
+	HotReload   HotReloadConfig
+	Sections    map[string]any // Module sections registered with RegisterSection; read them with Section[T]
+
+	report  Report            // How each setting was resolved; see Report
+	secrets *secrets.Resolver // Resolves vault://, aws-sm:// and gcp-sm:// references; see SecretResolver
+	hot     *hotState         // Settings applied by Reload; see Hot
 }
 
 // SecurityConfig holds security configuration
 type SecurityConfig struct {
 	EmailVerificationEnabled bool `mapstructure:"EMAIL_VERIFICATION_ENABLED"`
 	TwoFactorEnabled         bool `mapstructure:"TWO_FACTOR_ENABLED"`
+	SecurityTxt              SecurityTxtConfig
+	Headers                  SecurityHeadersConfig
+}
+
+// SecurityHeadersConfig holds the helmet-style response headers
+type SecurityHeadersConfig struct {
+	Enabled               bool          `mapstructure:"SECURITY_HEADERS_ENABLED"`
+	FrameOptions          string        `mapstructure:"SECURITY_FRAME_OPTIONS" validate:"omitempty,oneof=DENY SAMEORIGIN"` // X-Frame-Options (DENY or SAMEORIGIN)
+	ReferrerPolicy        string        `mapstructure:"SECURITY_REFERRER_POLICY"`                                          // Referrer-Policy
+	ContentSecurityPolicy string        `mapstructure:"SECURITY_CSP"`                                                      // Empty disables the header
+	HSTSMaxAge            time.Duration // SECURITY_HSTS_MAX_AGE; defaults to one year in production and 0 (disabled) elsewhere
+	HSTSIncludeSubdomains bool          `mapstructure:"SECURITY_HSTS_INCLUDE_SUBDOMAINS"`
+}
+
+// SecurityTxtConfig holds the fields served at /.well-known/security.txt (RFC 9116)
+type SecurityTxtConfig struct {
+	Contacts           []string  `mapstructure:"SECURITY_TXT_CONTACT" validate:"dive,uri"` // e.g. mailto:security@example.com (comma-separated)
+	Expires            time.Time // Parsed from SECURITY_TXT_EXPIRES (RFC 3339), defaults to one year from startup
+	Encryption         string    `mapstructure:"SECURITY_TXT_ENCRYPTION" validate:"omitempty,url"`
+	Acknowledgments    string    `mapstructure:"SECURITY_TXT_ACKNOWLEDGMENTS" validate:"omitempty,url"`
+	Policy             string    `mapstructure:"SECURITY_TXT_POLICY" validate:"omitempty,url"`
+	Hiring             string    `mapstructure:"SECURITY_TXT_HIRING" validate:"omitempty,url"`
+	Canonical          string    `mapstructure:"SECURITY_TXT_CANONICAL" validate:"omitempty,url"`
+	PreferredLanguages string    `mapstructure:"SECURITY_TXT_PREFERRED_LANGUAGES"`
+}
+
+// NotifyConfig holds admin notification configuration
+type NotifyConfig struct {
+	WebhookURL string `mapstructure:"NOTIFY_WEBHOOK_URL" validate:"omitempty,url"` // Slack-compatible incoming webhook
+	Email      string `mapstructure:"NOTIFY_EMAIL" validate:"omitempty,email"`     // Admin address for email notifications
+}
+
+// ActivityConfig holds user activity tracking configuration
+type ActivityConfig struct {
+	LastSeenFlushInterval time.Duration `mapstructure:"LAST_SEEN_FLUSH_INTERVAL" validate:"gt=0"` // How often Redis last_seen timestamps are written to the database
+}
+
+// AnomalyConfig holds auth metrics anomaly detection configuration
+type AnomalyConfig struct {
+	Enabled         bool          `mapstructure:"ANOMALY_DETECTION_ENABLED"`
+	Interval        time.Duration `mapstructure:"ANOMALY_CHECK_INTERVAL" validate:"gt=0"`   // Analysis interval and window size
+	BaselineWindows int           `mapstructure:"ANOMALY_BASELINE_WINDOWS" validate:"gt=0"` // Number of preceding windows forming the baseline
+	Threshold       float64       `mapstructure:"ANOMALY_THRESHOLD" validate:"gt=0"`        // Standard deviations above the baseline that trigger an alert
+	MinEvents       int64         `mapstructure:"ANOMALY_MIN_EVENTS"`                       // Windows with fewer events are never reported
+	AlertCooldown   time.Duration // Minimum time between alerts for the same metric (ANOMALY_ALERT_COOLDOWN)
+}
+
+// RBACConfig holds role/permission configuration
+type RBACConfig struct {
+	PermissionCacheTTL time.Duration // How long live permission checks may reuse a user's permission set (PERMISSION_CACHE_TTL)
+	ProfileCacheTTL    time.Duration // How long users with their roles are cached for auth and permission checks; 0 disables it (PROFILE_CACHE_TTL)
+	Backend            string        `mapstructure:"RBAC_BACKEND" validate:"oneof=native casbin"`          // native (token/role permissions) or casbin
+	CasbinModelPath    string        `mapstructure:"CASBIN_MODEL_PATH"`                                    // Custom Casbin model file; empty uses the built-in RBAC model
+	DefaultRoleSlug    string        `mapstructure:"DEFAULT_ROLE_SLUG" validate:"required,ne=super_admin"` // Role assigned on registration and when a create request names none
+}
+
+// ConcurrencyConfig holds in-flight request caps that shed excess load with 503
+type ConcurrencyConfig struct {
+	Enabled           bool          `mapstructure:"CONCURRENCY_LIMIT_ENABLED"`
+	MaxInFlight       int           `mapstructure:"CONCURRENCY_MAX_IN_FLIGHT" validate:"gte=0"`        // Cap across all routes (health checks excluded)
+	ReportMaxInFlight int           `mapstructure:"CONCURRENCY_REPORT_MAX_IN_FLIGHT" validate:"gte=0"` // Cap for expensive report-class routes (audit log, data exports)
+	QueueTimeout      time.Duration // How long a request over the cap waits for a slot (CONCURRENCY_QUEUE_TIMEOUT); 0 rejects at once
+	RetryAfter        time.Duration // Retry-After sent with the 503 (CONCURRENCY_RETRY_AFTER)
+}
+
+// CORSConfig holds cross-origin resource sharing configuration
+type CORSConfig struct {
+	AllowedOrigins   []string      `mapstructure:"CORS_ALLOWED_ORIGINS" validate:"dive,origin"` // Exact origins, wildcard subdomains (https://*.example.com) or * (defaults to * outside production)
+	AllowedMethods   []string      `mapstructure:"CORS_ALLOWED_METHODS"`
+	AllowedHeaders   []string      `mapstructure:"CORS_ALLOWED_HEADERS"`
+	ExposedHeaders   []string      `mapstructure:"CORS_EXPOSED_HEADERS"` // Response headers readable by browser scripts
+	AllowCredentials bool          `mapstructure:"CORS_ALLOW_CREDENTIALS"`
+	MaxAge           time.Duration `mapstructure:"CORS_MAX_AGE" validate:"gte=0"` // How long browsers may cache preflight responses
+}
+
+// CompressionConfig holds response compression and ETag configuration (applied per route group)
+type CompressionConfig struct {
+	Enabled bool   `mapstructure:"COMPRESSION_ENABLED"`                                   // gzip/brotli/deflate, negotiated via Accept-Encoding
+	Level   string `mapstructure:"COMPRESSION_LEVEL" validate:"oneof=speed default best"` // speed, default or best
+	ETag    bool   `mapstructure:"ETAG_ENABLED"`                                          // ETag on GET responses, 304 for a matching If-None-Match
+}
+
+// ResponseCacheConfig holds Redis response caching configuration for GET endpoints
+type ResponseCacheConfig struct {
+	Enabled bool          `mapstructure:"RESPONSE_CACHE_ENABLED"`
+	TTL     time.Duration `mapstructure:"RESPONSE_CACHE_TTL" validate:"gte=0"` // Default lifetime of cached responses; routes may set their own
+}
+
+// CacheStoreConfig selects the key/value cache services use through cache.Cache
+type CacheStoreConfig struct {
+	Driver           string `mapstructure:"CACHE_DRIVER" validate:"oneof=redis memory"` // redis (shared by instances) or memory (per-instance LRU)
+	MemoryMaxEntries int    `mapstructure:"CACHE_MEMORY_MAX_ENTRIES" validate:"gte=1"`  // Values kept by the memory driver before evicting the least recently used
+}
+
+// SessionStoreConfig selects where sessions (refresh tokens) are kept through session.Store
+type SessionStoreConfig struct {
+	Driver           string        `mapstructure:"SESSION_STORE" validate:"oneof=database redis"` // database (t_sessions rows) or redis (native TTL expiry)
+	CleanupInterval  time.Duration `mapstructure:"SESSION_CLEANUP_INTERVAL" validate:"gte=0"`     // How often expired t_sessions rows are deleted (0 disables it)
+	CleanupBatchSize int           `mapstructure:"SESSION_CLEANUP_BATCH_SIZE" validate:"gte=1"`   // Rows deleted per statement
+}
+
+// EventBusConfig selects the transport of the domain event bus (events.EventBus)
+type EventBusConfig struct {
+	Driver string `mapstructure:"EVENT_BUS_DRIVER" validate:"oneof=redis memory"` // redis (pub/sub across instances) or memory (this instance only)
+}
+
+// APIVersionConfig holds API version negotiation and deprecation configuration
+type APIVersionConfig struct {
+	Default         string               `mapstructure:"API_DEFAULT_VERSION" validate:"api_version"`                       // Version serving unversioned /api/... requests that don't ask for one
+	Deprecated      map[string]time.Time `mapstructure:"API_DEPRECATED_VERSIONS" validate:"dive,keys,api_version,endkeys"` // e.g. v1@2027-06-30 (the sunset date is optional)
+	DeprecationLink string               `mapstructure:"API_DEPRECATION_LINK" validate:"omitempty,url"`                    // Migration guide sent as Link rel="deprecation"
+}
+
+// TenancyConfig holds tenant resolution configuration
+type TenancyConfig struct {
+	Enabled    bool          `mapstructure:"TENANCY_ENABLED"`
+	Header     string        `mapstructure:"TENANT_HEADER"`      // Request header naming the tenant by ID or slug
+	BaseDomain string        `mapstructure:"TENANT_BASE_DOMAIN"` // e.g. example.com resolves acme.example.com to the acme tenant; empty disables subdomains
+	Required   bool          `mapstructure:"TENANT_REQUIRED"`    // Reject requests (health checks excluded) that name no tenant
+	CacheTTL   time.Duration // How long resolved tenants are reused before m_tenants is read again (TENANT_CACHE_TTL)
+}
+
+// I18nConfig holds locale negotiation configuration
+type I18nConfig struct {
+	DefaultLocale string `mapstructure:"DEFAULT_LOCALE" validate:"required"` // Used when neither ?lang= nor Accept-Language names a supported locale
+	QueryParam    string `mapstructure:"LOCALE_QUERY_PARAM"`                 // Query parameter that overrides Accept-Language; empty disables it
+}
+
+// AuditConfig holds audit logging configuration: mutating requests (middleware.Audit) and the
+// changes recorded by services (audit.Trail)
+type AuditConfig struct {
+	Enabled            bool                     `mapstructure:"AUDIT_ENABLED"`
+	RedactFields       []string                 `mapstructure:"AUDIT_REDACT_FIELDS"`                  // Body keys whose values are replaced (case-insensitive, any depth)
+	MaxBodySize        int                      `mapstructure:"AUDIT_MAX_BODY_SIZE" validate:"gte=0"` // Larger bodies are stored as a truncation marker
+	BufferSize         int                      `mapstructure:"AUDIT_BUFFER_SIZE" validate:"gt=0"`    // Events held in memory between flushes; excess events are dropped
+	FlushInterval      time.Duration            `mapstructure:"AUDIT_FLUSH_INTERVAL" validate:"gt=0"`
+	LogEnabled         bool                     `mapstructure:"AUDIT_LOG_ENABLED"`                                   // Record changes made by the user, role and auth services in t_audit_logs
+	LogRetention       time.Duration            `mapstructure:"AUDIT_LOG_RETENTION" validate:"gte=0"`                // How long audit log entries are kept (0 keeps them forever)
+	ResourceRetentions map[string]time.Duration `mapstructure:"AUDIT_LOG_RETENTION_RESOURCES" validate:"dive,gte=0"` // Per-resource overrides of AUDIT_LOG_RETENTION, e.g. session=720h (0 keeps the resource's entries)
+	ExportMaxRows      int                      `mapstructure:"AUDIT_LOG_EXPORT_MAX_ROWS" validate:"gt=0"`           // Rows returned by one audit log export at most
+}
+
+// BodyLogConfig holds request/response body logging configuration (troubleshooting aid, off by default)
+type BodyLogConfig struct {
+	Enabled      bool     `mapstructure:"BODY_LOG_ENABLED"`
+	SampleRate   float64  `mapstructure:"BODY_LOG_SAMPLE_RATE" validate:"min=0,max=1"` // Fraction of requests logged, 0 to 1
+	MaxBodySize  int      `mapstructure:"BODY_LOG_MAX_BODY_SIZE" validate:"gte=0"`     // Logged JSON bodies are cut at this many bytes; 0 logs them whole
+	RedactFields []string `mapstructure:"BODY_LOG_REDACT_FIELDS"`                      // Body keys whose values are replaced (case-insensitive, any depth)
+}
+
+// PoolConfig holds database connection pool monitoring and load shedding configuration
+type PoolConfig struct {
+	Enabled       bool          `mapstructure:"POOL_GUARD_ENABLED"`
+	Interval      time.Duration `mapstructure:"POOL_MONITOR_INTERVAL" validate:"gt=0"` // How often pool statistics are sampled
+	MaxAvgWait    time.Duration // Average connection wait above which the pool counts as saturated (POOL_MAX_AVG_WAIT)
+	AlertCooldown time.Duration // Minimum time between saturation alerts (POOL_ALERT_COOLDOWN)
+	ShedLoad      bool          `mapstructure:"POOL_SHED_ENABLED"` // Reject low-priority requests with 503 while saturated
+
+	StatsLogInterval time.Duration `mapstructure:"POOL_STATS_LOG_INTERVAL" validate:"gte=0"` // How often pool and query statistics are logged at info; 0 disables it
+}
+
+// HealthConfig holds readiness check configuration
+type HealthConfig struct {
+	Timeout   time.Duration `mapstructure:"HEALTH_CHECK_TIMEOUT" validate:"gt=0"` // Per-dependency timeout of /health/ready
+	CheckSMTP bool          `mapstructure:"HEALTH_CHECK_SMTP"`                    // Also connect to the SMTP server (reported, never makes the service unready)
+}
+
+// MetricsConfig holds Prometheus metrics endpoint configuration
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"METRICS_ENABLED"` // Serve /metrics
+	Token   string `mapstructure:"METRICS_TOKEN"`   // Bearer token scrapers must send; empty leaves /metrics open
+}
+
+// TracingConfig holds OpenTelemetry tracing configuration
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"TRACING_ENABLED"`                                          // Record spans and export them
+	Exporter    string  `mapstructure:"TRACING_EXPORTER" validate:"oneof=otlphttp otlpgrpc"`      // OTLP over HTTP (port 4318) or gRPC (port 4317)
+	Endpoint    string  `mapstructure:"TRACING_ENDPOINT"`                                         // host:port of the collector; empty uses OTEL_EXPORTER_OTLP_ENDPOINT or localhost
+	Insecure    bool    `mapstructure:"TRACING_INSECURE"`                                         // Plain HTTP/gRPC instead of TLS, e.g. for a local collector
+	SampleRatio float64 `mapstructure:"TRACING_SAMPLE_RATIO" validate:"min=0,max=1"`              // Fraction of new traces recorded; requests continue their caller's decision
+	ServiceName string  `mapstructure:"TRACING_SERVICE_NAME" validate:"required_if=Enabled true"` // service.name of the spans
+}
+
+// EncryptionConfig holds the keys of encrypted columns (encryption.EncryptedString, EncryptedJSON)
+type EncryptionConfig struct {
+	Keys []string `mapstructure:"ENCRYPTION_KEYS"` // id:base64 AES-256 keys, comma-separated; the first encrypts, the others only decrypt (empty stores plaintext)
+}
+
+// DebugConfig holds per-request debug mode and runtime debug endpoint configuration
+type DebugConfig struct {
+	Enabled          bool   `mapstructure:"REQUEST_DEBUG_ENABLED"`   // Allow super_admins to request debug info with X-Debug: true (defaults to off in production)
+	EndpointsEnabled bool   `mapstructure:"DEBUG_ENDPOINTS_ENABLED"` // Serve pprof profiles and expvar under /debug (defaults to off in production)
+	Token            string `mapstructure:"DEBUG_TOKEN"`             // Token accepted in X-Debug-Token instead of a super_admin access token; empty requires a super_admin
+}
+
+// TrashConfig holds soft-delete retention configuration: the purge job (retention.Purger) and modules
+// generated with --with-trash
+type TrashConfig struct {
+	Retention       time.Duration            `mapstructure:"TRASH_RETENTION" validate:"gte=0"`             // How long soft-deleted items stay restorable before being purged (0 disables purging)
+	TableRetentions map[string]time.Duration `mapstructure:"TRASH_RETENTION_TABLES" validate:"dive,gte=0"` // Per-table overrides of TRASH_RETENTION, e.g. m_users=2160h (0 keeps the table's rows)
+	PurgeInterval   time.Duration            `mapstructure:"TRASH_PURGE_INTERVAL" validate:"gt=0"`         // How often expired items are purged
+	BatchSize       int                      `mapstructure:"TRASH_PURGE_BATCH_SIZE" validate:"gt=0"`       // Rows deleted per statement, so a large purge doesn't hold long locks
+	DryRun          bool                     `mapstructure:"TRASH_PURGE_DRY_RUN"`                          // Only log how many rows each table would lose
+}
+
+// AbuseConfig holds abuse report configuration
+type AbuseConfig struct {
+	RateLimit int `mapstructure:"ABUSE_REPORT_RATE_LIMIT" validate:"gt=0"` // Max reports per IP per hour
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port string `mapstructure:"SERVER_PORT"`
-	Host string `mapstructure:"SERVER_HOST"`
-	Mode string `mapstructure:"SERVER_MODE"` // development, production, test
+	Port            string        `mapstructure:"SERVER_PORT" validate:"required,port"`
+	Host            string        `mapstructure:"SERVER_HOST"`
+	Mode            string        `mapstructure:"SERVER_MODE" validate:"oneof=development production test"` // development, production, test
+	RequestTimeout  time.Duration `mapstructure:"REQUEST_TIMEOUT" validate:"gte=0"`                         // Deadline of the request context; 0 disables it
+	BodyLimit       int           `mapstructure:"BODY_LIMIT" validate:"gt=0"`                               // Max request body in bytes for every route
+	PublicBodyLimit int           `mapstructure:"PUBLIC_BODY_LIMIT" validate:"gt=0"`                        // Max request body in bytes for unauthenticated endpoints (auth, abuse reports)
+	Envelope        bool          `mapstructure:"RESPONSE_ENVELOPE"`                                        // Wrap success responses in {code, success, message, data}; routes may override it
+}
+
+// ProxyConfig holds reverse proxy / load balancer configuration. c.IP() (and so per-IP rate limits,
+// sessions and audit events) reads Header only on requests coming from TrustedProxies; other
+// requests use the connection address.
+type ProxyConfig struct {
+	Header         string   `mapstructure:"PROXY_HEADER"`                            // Client IP header set by the proxy, e.g. X-Forwarded-For or X-Real-IP; empty ignores proxies
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES" validate:"dive,ip|cidr"` // Proxy addresses or CIDR ranges, e.g. 10.0.0.0/8
+}
+
+// TLSConfig holds HTTPS termination configuration: certificate files, or Let's Encrypt certificates
+// obtained on demand for whitelisted hosts (autocert)
+type TLSConfig struct {
+	Enabled          bool     `mapstructure:"TLS_ENABLED"`                                                      // Serve HTTPS on SERVER_PORT
+	CertFile         string   `mapstructure:"TLS_CERT_FILE" validate:"required_if=Enabled true Autocert false"` // PEM certificate chain (without autocert)
+	KeyFile          string   `mapstructure:"TLS_KEY_FILE" validate:"required_if=Enabled true Autocert false"`  // PEM private key (without autocert)
+	Autocert         bool     `mapstructure:"TLS_AUTOCERT"`                                                     // Obtain certificates from Let's Encrypt instead of files
+	AutocertHosts    []string `mapstructure:"TLS_AUTOCERT_HOSTS" validate:"required_if=Autocert true,dive,hostname_rfc1123"`
+	AutocertEmail    string   `mapstructure:"TLS_AUTOCERT_EMAIL" validate:"omitempty,email"` // Contact for expiry notices
+	AutocertCacheDir string   `mapstructure:"TLS_AUTOCERT_CACHE_DIR"`                        // Where certificates are kept across restarts
+	HTTPRedirect     bool     `mapstructure:"TLS_HTTP_REDIRECT"`                             // Redirect plain HTTP requests to HTTPS (and answer ACME challenges)
+	HTTPPort         string   `mapstructure:"TLS_HTTP_PORT" validate:"omitempty,port"`       // Port of the plain HTTP listener
 }
 
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
-	Host     string `mapstructure:"DB_HOST"`
-	Port     string `mapstructure:"DB_PORT"`
-	User     string `mapstructure:"DB_USER"`
-	Password string `mapstructure:"DB_PASSWORD"`
-	DBName   string `mapstructure:"DB_NAME"`
-	SSLMode  string `mapstructure:"DB_SSLMODE"`
+	Host           string   `mapstructure:"DB_HOST" validate:"required"`
+	Port           string   `mapstructure:"DB_PORT" validate:"required,port"`
+	User           string   `mapstructure:"DB_USER"`
+	Password       string   `mapstructure:"DB_PASSWORD"`
+	DBName         string   `mapstructure:"DB_NAME" validate:"required"`
+	SSLMode        string   `mapstructure:"DB_SSLMODE" validate:"oneof=disable allow prefer require verify-ca verify-full"`
+	Replicas       []string `mapstructure:"DB_READ_REPLICAS" validate:"dive,hostname_port|hostname_rfc1123"` // Read replica hosts (host or host:port); they share the primary's user, password, database and SSL mode
+	MigrationMode  string   `mapstructure:"MIGRATION_MODE" validate:"oneof=auto sql both"`                   // Schema management at startup: GORM AutoMigrate, SQL migrations, or both
+	MigrateOnStart bool     `mapstructure:"DB_MIGRATE_ON_START"`                                             // Apply the embedded SQL migrations before serving (sql and both modes)
+	Timeouts       QueryTimeoutConfig
+	Retry          ConnectRetryConfig
+	Connections    ConnectionPoolConfig
+	GORM           GORMConfig
+}
+
+// Schema management modes (MIGRATION_MODE)
+const (
+	MigrationModeAuto = "auto" // GORM AutoMigrate from the models
+	MigrationModeSQL  = "sql"  // Versioned SQL migrations only; startup fails while some are pending
+	MigrationModeBoth = "both" // SQL migrations, then AutoMigrate
+)
+
+// ConnectRetryConfig controls how long startup waits for the database to accept connections
+type ConnectRetryConfig struct {
+	Attempts   int           `mapstructure:"DB_CONNECT_ATTEMPTS" validate:"gte=1"`   // 1 gives up on the first failure
+	Backoff    time.Duration `mapstructure:"DB_CONNECT_BACKOFF" validate:"gt=0"`     // Delay before the second attempt; doubles after each failure, with jitter
+	MaxBackoff time.Duration `mapstructure:"DB_CONNECT_MAX_BACKOFF" validate:"gt=0"` // Upper bound of the delay
+}
+
+// ConnectionPoolConfig holds database/sql connection pool sizes and lifetimes
+type ConnectionPoolConfig struct {
+	MaxOpen     int           `mapstructure:"DB_MAX_OPEN_CONNS" validate:"gte=0"`     // 0 means unlimited
+	MaxIdle     int           `mapstructure:"DB_MAX_IDLE_CONNS" validate:"gte=0"`     // Kept open between bursts; capped at MaxOpen
+	MaxLifetime time.Duration `mapstructure:"DB_CONN_MAX_LIFETIME" validate:"gte=0"`  // Connections are recycled after this long; 0 keeps them forever
+	MaxIdleTime time.Duration `mapstructure:"DB_CONN_MAX_IDLE_TIME" validate:"gte=0"` // Idle connections are closed after this long; 0 keeps them
+}
+
+// GORMConfig holds GORM options
+type GORMConfig struct {
+	PrepareStmt   bool          `mapstructure:"DB_PREPARE_STMT"`                                      // Cache prepared statements per connection
+	LogLevel      string        `mapstructure:"DB_LOG_LEVEL" validate:"oneof=silent error warn info"` // info logs every query (default in development), silent logs nothing (default elsewhere)
+	SlowThreshold time.Duration `mapstructure:"DB_SLOW_QUERY_THRESHOLD" validate:"gte=0"`             // Queries slower than this are logged at warn with the request ID and caller; 0 disables it
+}
+
+// QueryTimeoutConfig holds per-operation-class query timeouts (0 disables the class)
+type QueryTimeoutConfig struct {
+	Read   time.Duration // SELECTs (DB_READ_TIMEOUT)
+	Write  time.Duration // INSERT/UPDATE/DELETE and raw statements (DB_WRITE_TIMEOUT)
+	Report time.Duration // Queries tagged with database.Report, e.g. data exports (DB_REPORT_TIMEOUT)
 }
 
 // RedisConfig holds Redis configuration
 type RedisConfig struct {
-	Host     string `mapstructure:"REDIS_HOST"`
-	Port     string `mapstructure:"REDIS_PORT"`
+	Enabled  bool   `mapstructure:"REDIS_ENABLED"` // When false, Redis-backed features (caching, activity, metrics) run without Redis
+	Host     string `mapstructure:"REDIS_HOST" validate:"required_if=Enabled true"`
+	Port     string `mapstructure:"REDIS_PORT" validate:"required_if=Enabled true,omitempty,port"`
 	Password string `mapstructure:"REDIS_PASSWORD"`
-	DB       int    `mapstructure:"REDIS_DB"`
+	DB       int    `mapstructure:"REDIS_DB" validate:"gte=0"`
+
+	CheckInterval time.Duration `mapstructure:"REDIS_CHECK_INTERVAL" validate:"gt=0"` // How often Redis is pinged to detect outages and reconnect
 }
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	Secret          string `mapstructure:"JWT_SECRET"`
-	AccessExpiry    time.Duration
-	RefreshExpiry   time.Duration
-	Issuer          string
+	Secret        string        `mapstructure:"JWT_SECRET" validate:"secret_in_production"`
+	AccessExpiry  time.Duration `mapstructure:"JWT_ACCESS_EXPIRY" validate:"gt=0"`
+	RefreshExpiry time.Duration `mapstructure:"JWT_REFRESH_EXPIRY" validate:"gt=0"`
+	Issuer        string
 }
 
 // OAuthConfig holds OAuth configuration
@@ -68,38 +391,83 @@ type OAuthConfig struct {
 	GitHub GitHubOAuthConfig
 }
 
+// Enabled reports whether any OAuth provider is enabled
+func (c *OAuthConfig) Enabled() bool {
+	return c.Google.Enabled || c.GitHub.Enabled
+}
+
+// SendsWelcomeEmail reports whether an enabled provider sends welcome emails to new users
+func (c *OAuthConfig) SendsWelcomeEmail() bool {
+	return (c.Google.Enabled && c.Google.SendWelcomeEmail) || (c.GitHub.Enabled && c.GitHub.SendWelcomeEmail)
+}
+
 // GoogleOAuthConfig holds Google OAuth configuration
 type GoogleOAuthConfig struct {
-	ClientID         string `mapstructure:"OAUTH_GOOGLE_CLIENT_ID"`
-	ClientSecret     string `mapstructure:"OAUTH_GOOGLE_CLIENT_SECRET"`
-	RedirectURL      string `mapstructure:"OAUTH_GOOGLE_REDIRECT_URL"`
+	ClientID         string `mapstructure:"OAUTH_GOOGLE_CLIENT_ID" validate:"required_if=Enabled true"`
+	ClientSecret     string `mapstructure:"OAUTH_GOOGLE_CLIENT_SECRET" validate:"required_if=Enabled true"`
+	RedirectURL      string `mapstructure:"OAUTH_GOOGLE_REDIRECT_URL" validate:"required_if=Enabled true,omitempty,url"`
 	Enabled          bool   `mapstructure:"OAUTH_GOOGLE_ENABLED"`
 	SendWelcomeEmail bool   `mapstructure:"OAUTH_GOOGLE_SEND_WELCOME_EMAIL"`
 }
 
 // GitHubOAuthConfig holds GitHub OAuth configuration
 type GitHubOAuthConfig struct {
-	ClientID         string `mapstructure:"OAUTH_GITHUB_CLIENT_ID"`
-	ClientSecret     string `mapstructure:"OAUTH_GITHUB_CLIENT_SECRET"`
-	RedirectURL      string `mapstructure:"OAUTH_GITHUB_REDIRECT_URL"`
+	ClientID         string `mapstructure:"OAUTH_GITHUB_CLIENT_ID" validate:"required_if=Enabled true"`
+	ClientSecret     string `mapstructure:"OAUTH_GITHUB_CLIENT_SECRET" validate:"required_if=Enabled true"`
+	RedirectURL      string `mapstructure:"OAUTH_GITHUB_REDIRECT_URL" validate:"required_if=Enabled true,omitempty,url"`
 	Enabled          bool   `mapstructure:"OAUTH_GITHUB_ENABLED"`
 	SendWelcomeEmail bool   `mapstructure:"OAUTH_GITHUB_SEND_WELCOME_EMAIL"`
 }
 
-// EmailConfig holds email configuration
+// EmailConfig holds email configuration; email verification, two-factor codes and password reset
+// emails need Enabled
 type EmailConfig struct {
-	SMTPHost     string `mapstructure:"SMTP_HOST"`
-	SMTPPort     int    `mapstructure:"SMTP_PORT"`
-	SMTPUser     string `mapstructure:"SMTP_USER"`
-	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
-	SMTPFrom     string `mapstructure:"SMTP_FROM"`
-	Enabled      bool   `mapstructure:"EMAIL_ENABLED"`
+	Provider      string `mapstructure:"EMAIL_PROVIDER" validate:"oneof=smtp ses sendgrid mailgun postmark log file"` // How email is delivered; log and file are for development
+	SMTPHost      string `mapstructure:"SMTP_HOST" validate:"required_if=Enabled true Provider smtp"`                 // Defaults to localhost:1025 (MailHog, Mailpit) in development
+	SMTPPort      int    `mapstructure:"SMTP_PORT" validate:"min=1,max=65535"`
+	SMTPUser      string `mapstructure:"SMTP_USER"`
+	SMTPPassword  string `mapstructure:"SMTP_PASSWORD"`
+	SMTPFrom      string `mapstructure:"SMTP_FROM" validate:"required_if=Enabled true"` // Sender address of every provider
+	APIKey        string `mapstructure:"EMAIL_API_KEY"`                                 // SendGrid, Mailgun or Postmark (server token) API key
+	APIURL        string `mapstructure:"EMAIL_API_URL" validate:"omitempty,url"`        // Overrides the provider's API base URL, e.g. https://api.eu.mailgun.net
+	MailgunDomain string `mapstructure:"EMAIL_MAILGUN_DOMAIN" validate:"required_if=Enabled true Provider mailgun"`
+	SESRegion     string `mapstructure:"EMAIL_SES_REGION" validate:"required_if=Enabled true Provider ses"` // Defaults to AWS_REGION; credentials come from the AWS_* variables
+	FileDir       string `mapstructure:"EMAIL_FILE_DIR" validate:"required_if=Enabled true Provider file"`  // Where the file provider writes messages
+	Sandbox       bool   `mapstructure:"EMAIL_SANDBOX"`                                                     // Use the provider's test mode: accepted but not delivered
+	Enabled       bool   `mapstructure:"EMAIL_ENABLED"`
 }
 
+// EmailQueueConfig holds the outgoing email queue (t_email_messages) and its send-queued-email worker
+type EmailQueueConfig struct {
+	PollInterval   time.Duration `mapstructure:"EMAIL_QUEUE_POLL_INTERVAL" validate:"gt=0"`    // How often each instance looks for due messages
+	BatchSize      int           `mapstructure:"EMAIL_QUEUE_BATCH_SIZE" validate:"gt=0"`       // Messages claimed per poll
+	MaxAttempts    int           `mapstructure:"EMAIL_QUEUE_MAX_ATTEMPTS" validate:"gt=0"`     // Delivery attempts before a message is marked dead
+	RetryBackoff   time.Duration `mapstructure:"EMAIL_QUEUE_RETRY_BACKOFF" validate:"gt=0"`    // Delay before the first retry, doubled after each failure (up to 1h)
+	Lease          time.Duration `mapstructure:"EMAIL_QUEUE_LEASE" validate:"gt=0"`            // How long a claimed message is reserved for its worker before another may retry it
+	Retention      time.Duration `mapstructure:"EMAIL_QUEUE_RETENTION" validate:"gte=0"`       // How long sent, dead and suppressed messages are kept (0 keeps them)
+	RecipientLimit int           `mapstructure:"EMAIL_QUEUE_RECIPIENT_LIMIT" validate:"gte=0"` // Emails queued per recipient per hour; more are suppressed (0 disables)
+	DedupWindow    time.Duration `mapstructure:"EMAIL_QUEUE_DEDUP_WINDOW" validate:"gte=0"`    // An email identical to one queued for the recipient within it is suppressed (0 disables)
+}
+
+// Log outputs (LOG_OUTPUT)
+const (
+	LogOutputStdout = "stdout"
+	LogOutputFile   = "file"
+	LogOutputBoth   = "both" // stdout and the file
+)
+
 // LoggerConfig holds logger configuration
 type LoggerConfig struct {
-	Level  string `mapstructure:"LOG_LEVEL"` // debug, info, warn, error
-	Format string `mapstructure:"LOG_FORMAT"` // json, text
+	Level            string        `mapstructure:"LOG_LEVEL" validate:"oneof=debug info warn error"`       // debug, info, warn, error
+	Format           string        `mapstructure:"LOG_FORMAT" validate:"oneof=json text"`                  // json, text
+	WideEvents       bool          `mapstructure:"LOG_WIDE_EVENTS"`                                        // emit one canonical event per request instead of the plain access log
+	AccessSampleRate float64       `mapstructure:"LOG_ACCESS_SAMPLE_RATE" validate:"min=0,max=1"`          // Fraction of 2xx requests the access log (HTTPLogger) records; others are always logged
+	Output           string        `mapstructure:"LOG_OUTPUT" validate:"oneof=stdout file both"`           // stdout, file (rotated) or both
+	FilePath         string        `mapstructure:"LOG_FILE_PATH" validate:"required_unless=Output stdout"` // Log file; rotated files are kept next to it with a timestamp in their name
+	FileMaxSize      int           `mapstructure:"LOG_FILE_MAX_SIZE" validate:"gt=0"`                      // Megabytes the file grows to before it is rotated
+	FileMaxAge       time.Duration `mapstructure:"LOG_FILE_MAX_AGE" validate:"gte=0"`                      // Rotated files older than this are deleted (rounded up to days; 0 keeps them)
+	FileMaxBackups   int           `mapstructure:"LOG_FILE_MAX_BACKUPS" validate:"gte=0"`                  // Rotated files kept at most (0 keeps them all, up to LOG_FILE_MAX_AGE)
+	FileCompress     bool          `mapstructure:"LOG_FILE_COMPRESS"`                                      // gzip rotated files
 }
 
 // SuperAdminConfig holds default SuperAdmin account configuration
@@ -109,41 +477,92 @@ type SuperAdminConfig struct {
 	Name     string `mapstructure:"SUPERADMIN_NAME"`
 }
 
-// LoadConfig loads configuration from environment variables
+// LoadConfig loads configuration from environment variables, falling back to config files
 func LoadConfig() (*Config, error) {
+	resetReport()
+
 	// Load .env file if exists
-	if err := godotenv.Load(); err != nil {
-		fmt.Println("⚠️  No .env file found, using system environment variables or defaults")
-	} else {
-		fmt.Println("✅ .env file loaded successfully")
+	report.EnvFile = godotenv.Load() == nil
+
+	// Load config.yaml and config.<mode>.yaml; environment variables override them
+	files, err := loadConfigFiles()
+	if err != nil {
+		return nil, err
 	}
+	report.Files = files
 
 	// Set defaults
 	setDefaults()
 
-	// Debug: Show what env vars we're reading
-	fmt.Println("📖 Reading environment variables...")
+	// Secret references (vault://, aws-sm://, gcp-sm://) in any setting below are resolved as it is read
+	secretsCacheTTL := getDurationEnv("SECRETS_CACHE_TTL", 5*time.Minute)
+	secretProblems = nil
+	secretResolver = secrets.NewResolver(secretsCacheTTL)
 
 	// Create config from environment variables
 	cfg := Config{
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "3000"),
-			Host: getEnv("SERVER_HOST", "localhost"),
-			Mode: getEnv("SERVER_MODE", "development"),
+			Port:            getEnv("SERVER_PORT", "3000"),
+			Host:            getEnv("SERVER_HOST", "localhost"),
+			Mode:            getEnv("SERVER_MODE", "development"),
+			RequestTimeout:  getDurationEnv("REQUEST_TIMEOUT", 30*time.Second),
+			BodyLimit:       parseInt(getEnv("BODY_LIMIT", "4194304")),
+			PublicBodyLimit: parseInt(getEnv("PUBLIC_BODY_LIMIT", "65536")),
+			Envelope:        getBoolEnv("RESPONSE_ENVELOPE", true),
+		},
+		TLS: TLSConfig{
+			Enabled:          getBoolEnv("TLS_ENABLED", false),
+			CertFile:         getEnv("TLS_CERT_FILE", ""),
+			KeyFile:          getEnv("TLS_KEY_FILE", ""),
+			Autocert:         getBoolEnv("TLS_AUTOCERT", false),
+			AutocertHosts:    parseList(strings.ToLower(getEnv("TLS_AUTOCERT_HOSTS", ""))),
+			AutocertEmail:    getEnv("TLS_AUTOCERT_EMAIL", ""),
+			AutocertCacheDir: getEnv("TLS_AUTOCERT_CACHE_DIR", "certs"),
+			HTTPRedirect:     getBoolEnv("TLS_HTTP_REDIRECT", true),
+			HTTPPort:         getEnv("TLS_HTTP_PORT", "80"),
+		},
+		Proxy: ProxyConfig{
+			Header:         getEnv("PROXY_HEADER", ""),
+			TrustedProxies: parseList(getEnv("TRUSTED_PROXIES", "")),
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "5432"),
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", "postgres"),
-			DBName:   getEnv("DB_NAME", "go_boilerplate"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			Host:           getEnv("DB_HOST", "localhost"),
+			Port:           getEnv("DB_PORT", "5432"),
+			User:           getEnv("DB_USER", "postgres"),
+			Password:       getEnv("DB_PASSWORD", "postgres"),
+			DBName:         getEnv("DB_NAME", "go_boilerplate"),
+			SSLMode:        getEnv("DB_SSLMODE", "disable"),
+			Replicas:       parseList(getEnv("DB_READ_REPLICAS", "")),
+			MigrateOnStart: getBoolEnv("DB_MIGRATE_ON_START", false),
+			Timeouts: QueryTimeoutConfig{
+				Read:   getDurationEnv("DB_READ_TIMEOUT", 5*time.Second),
+				Write:  getDurationEnv("DB_WRITE_TIMEOUT", 10*time.Second),
+				Report: getDurationEnv("DB_REPORT_TIMEOUT", time.Minute),
+			},
+			Retry: ConnectRetryConfig{
+				Attempts:   parseInt(getEnv("DB_CONNECT_ATTEMPTS", "5")),
+				Backoff:    getDurationEnv("DB_CONNECT_BACKOFF", time.Second),
+				MaxBackoff: getDurationEnv("DB_CONNECT_MAX_BACKOFF", 15*time.Second),
+			},
+			Connections: ConnectionPoolConfig{
+				MaxOpen:     parseInt(getEnv("DB_MAX_OPEN_CONNS", "100")),
+				MaxIdle:     parseInt(getEnv("DB_MAX_IDLE_CONNS", "10")),
+				MaxLifetime: getDurationEnv("DB_CONN_MAX_LIFETIME", time.Hour),
+				MaxIdleTime: getDurationEnv("DB_CONN_MAX_IDLE_TIME", 10*time.Minute),
+			},
+			GORM: GORMConfig{
+				PrepareStmt:   getBoolEnv("DB_PREPARE_STMT", false),
+				SlowThreshold: getDurationEnv("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+			},
 		},
 		Redis: RedisConfig{
+			Enabled:  getBoolEnv("REDIS_ENABLED", true),
 			Host:     getEnv("REDIS_HOST", "localhost"),
 			Port:     getEnv("REDIS_PORT", "6379"),
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       parseInt(getEnv("REDIS_DB", "0")),
+
+			CheckInterval: getDurationEnv("REDIS_CHECK_INTERVAL", 5*time.Second),
 		},
 		JWT: JWTConfig{
 			Secret: getEnv("JWT_SECRET", ""),
@@ -165,30 +584,210 @@ func LoadConfig() (*Config, error) {
 			},
 		},
 		Email: EmailConfig{
-			SMTPHost:     getEnv("SMTP_HOST", "smtp.gmail.com"),
-			SMTPPort:     parseInt(getEnv("SMTP_PORT", "587")),
-			SMTPUser:     getEnv("SMTP_USER", ""),
-			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
-			SMTPFrom:     getEnv("SMTP_FROM", ""),
-			Enabled:      getBoolEnv("EMAIL_ENABLED", false),
+			Provider:      getEnv("EMAIL_PROVIDER", "smtp"),
+			SMTPUser:      getEnv("SMTP_USER", ""),
+			SMTPPassword:  getEnv("SMTP_PASSWORD", ""),
+			SMTPFrom:      getEnv("SMTP_FROM", ""),
+			APIKey:        getEnv("EMAIL_API_KEY", ""),
+			APIURL:        getEnv("EMAIL_API_URL", ""),
+			MailgunDomain: getEnv("EMAIL_MAILGUN_DOMAIN", ""),
+			SESRegion:     getEnv("EMAIL_SES_REGION", awssig.RegionFromEnv()),
+			FileDir:       getEnv("EMAIL_FILE_DIR", "tmp/emails"),
+			Sandbox:       getBoolEnv("EMAIL_SANDBOX", false),
+			Enabled:       getBoolEnv("EMAIL_ENABLED", false),
+		},
+		EmailQueue: EmailQueueConfig{
+			PollInterval:   getDurationEnv("EMAIL_QUEUE_POLL_INTERVAL", 5*time.Second),
+			BatchSize:      parseInt(getEnv("EMAIL_QUEUE_BATCH_SIZE", "20")),
+			MaxAttempts:    parseInt(getEnv("EMAIL_QUEUE_MAX_ATTEMPTS", "5")),
+			RetryBackoff:   getDurationEnv("EMAIL_QUEUE_RETRY_BACKOFF", 30*time.Second),
+			Lease:          getDurationEnv("EMAIL_QUEUE_LEASE", 5*time.Minute),
+			Retention:      getDurationEnv("EMAIL_QUEUE_RETENTION", 7*24*time.Hour),
+			RecipientLimit: parseInt(getEnv("EMAIL_QUEUE_RECIPIENT_LIMIT", "20")),
+			DedupWindow:    getDurationEnv("EMAIL_QUEUE_DEDUP_WINDOW", 10*time.Minute),
 		},
 		Security: SecurityConfig{
 			EmailVerificationEnabled: getBoolEnv("EMAIL_VERIFICATION_ENABLED", false),
 			TwoFactorEnabled:         getBoolEnv("TWO_FACTOR_ENABLED", false),
+			SecurityTxt: SecurityTxtConfig{
+				Contacts:           parseList(getEnv("SECURITY_TXT_CONTACT", "")),
+				Encryption:         getEnv("SECURITY_TXT_ENCRYPTION", ""),
+				Acknowledgments:    getEnv("SECURITY_TXT_ACKNOWLEDGMENTS", ""),
+				Policy:             getEnv("SECURITY_TXT_POLICY", ""),
+				Hiring:             getEnv("SECURITY_TXT_HIRING", ""),
+				Canonical:          getEnv("SECURITY_TXT_CANONICAL", ""),
+				PreferredLanguages: getEnv("SECURITY_TXT_PREFERRED_LANGUAGES", "en"),
+			},
+			Headers: SecurityHeadersConfig{
+				Enabled:               getBoolEnv("SECURITY_HEADERS_ENABLED", true),
+				FrameOptions:          getEnv("SECURITY_FRAME_OPTIONS", "DENY"),
+				ReferrerPolicy:        getEnv("SECURITY_REFERRER_POLICY", "strict-origin-when-cross-origin"),
+				ContentSecurityPolicy: getEnv("SECURITY_CSP", "default-src 'none'; frame-ancestors 'none'"),
+				HSTSIncludeSubdomains: getBoolEnv("SECURITY_HSTS_INCLUDE_SUBDOMAINS", true),
+			},
 		},
 		Logger: LoggerConfig{
-			Level:  getEnv("LOG_LEVEL", "debug"),
-			Format: getEnv("LOG_FORMAT", "json"),
+			Level:            getEnv("LOG_LEVEL", "debug"),
+			Format:           getEnv("LOG_FORMAT", "json"),
+			WideEvents:       getBoolEnv("LOG_WIDE_EVENTS", true),
+			AccessSampleRate: parseFloat(getEnv("LOG_ACCESS_SAMPLE_RATE", "1")),
+			Output:           getEnv("LOG_OUTPUT", LogOutputStdout),
+			FilePath:         getEnv("LOG_FILE_PATH", "logs/app.log"),
+			FileMaxSize:      parseInt(getEnv("LOG_FILE_MAX_SIZE", "100")),
+			FileMaxAge:       getDurationEnv("LOG_FILE_MAX_AGE", 7*24*time.Hour),
+			FileMaxBackups:   parseInt(getEnv("LOG_FILE_MAX_BACKUPS", "10")),
+			FileCompress:     getBoolEnv("LOG_FILE_COMPRESS", true),
 		},
 		SuperAdmin: SuperAdminConfig{
 			Name:     getEnv("SUPERADMIN_NAME", "Super Admin"),
 			Email:    getEnv("SUPERADMIN_EMAIL", "superadmin@boilerplate.com"),
 			Password: getEnv("SUPERADMIN_PASSWORD", "SuperAdmin123!"),
 		},
+		Notify: NotifyConfig{
+			WebhookURL: getEnv("NOTIFY_WEBHOOK_URL", ""),
+			Email:      getEnv("NOTIFY_EMAIL", ""),
+		},
+		Abuse: AbuseConfig{
+			RateLimit: parseInt(getEnv("ABUSE_REPORT_RATE_LIMIT", "5")),
+		},
+		Activity: ActivityConfig{
+			LastSeenFlushInterval: getDurationEnv("LAST_SEEN_FLUSH_INTERVAL", time.Minute),
+		},
+		Anomaly: AnomalyConfig{
+			Enabled:         getBoolEnv("ANOMALY_DETECTION_ENABLED", true),
+			Interval:        getDurationEnv("ANOMALY_CHECK_INTERVAL", 5*time.Minute),
+			BaselineWindows: parseInt(getEnv("ANOMALY_BASELINE_WINDOWS", "12")),
+			Threshold:       parseFloat(getEnv("ANOMALY_THRESHOLD", "3")),
+			MinEvents:       int64(parseInt(getEnv("ANOMALY_MIN_EVENTS", "20"))),
+			AlertCooldown:   getDurationEnv("ANOMALY_ALERT_COOLDOWN", 30*time.Minute),
+		},
+		RBAC: RBACConfig{
+			PermissionCacheTTL: getDurationEnv("PERMISSION_CACHE_TTL", 30*time.Second),
+			ProfileCacheTTL:    getDurationEnv("PROFILE_CACHE_TTL", 5*time.Minute),
+			Backend:            getEnv("RBAC_BACKEND", "native"),
+			CasbinModelPath:    getEnv("CASBIN_MODEL_PATH", ""),
+			DefaultRoleSlug:    getEnv("DEFAULT_ROLE_SLUG", "user"),
+		},
+		Pool: PoolConfig{
+			Enabled:       getBoolEnv("POOL_GUARD_ENABLED", true),
+			Interval:      getDurationEnv("POOL_MONITOR_INTERVAL", 10*time.Second),
+			MaxAvgWait:    getDurationEnv("POOL_MAX_AVG_WAIT", 100*time.Millisecond),
+			AlertCooldown: getDurationEnv("POOL_ALERT_COOLDOWN", 15*time.Minute),
+			ShedLoad:      getBoolEnv("POOL_SHED_ENABLED", true),
+
+			StatsLogInterval: getDurationEnv("POOL_STATS_LOG_INTERVAL", 5*time.Minute),
+		},
+		Health: HealthConfig{
+			Timeout:   getDurationEnv("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+			CheckSMTP: getBoolEnv("HEALTH_CHECK_SMTP", false),
+		},
+		Metrics: MetricsConfig{
+			Enabled: getBoolEnv("METRICS_ENABLED", true),
+			Token:   getEnv("METRICS_TOKEN", ""),
+		},
+		Tracing: TracingConfig{
+			Enabled:     getBoolEnv("TRACING_ENABLED", false),
+			Exporter:    getEnv("TRACING_EXPORTER", "otlphttp"),
+			Endpoint:    getEnv("TRACING_ENDPOINT", ""),
+			Insecure:    getBoolEnv("TRACING_INSECURE", false),
+			SampleRatio: parseFloat(getEnv("TRACING_SAMPLE_RATIO", "1")),
+			ServiceName: getEnv("TRACING_SERVICE_NAME", "go-boilerplate"),
+		},
+		Encryption: EncryptionConfig{
+			Keys: parseList(getEnv("ENCRYPTION_KEYS", "")),
+		},
+		Concurrency: ConcurrencyConfig{
+			Enabled:           getBoolEnv("CONCURRENCY_LIMIT_ENABLED", true),
+			MaxInFlight:       parseInt(getEnv("CONCURRENCY_MAX_IN_FLIGHT", "512")),
+			ReportMaxInFlight: parseInt(getEnv("CONCURRENCY_REPORT_MAX_IN_FLIGHT", "8")),
+			QueueTimeout:      getDurationEnv("CONCURRENCY_QUEUE_TIMEOUT", 100*time.Millisecond),
+			RetryAfter:        getDurationEnv("CONCURRENCY_RETRY_AFTER", time.Second),
+		},
+		CORS: CORSConfig{
+			AllowedOrigins:   parseList(getEnv("CORS_ALLOWED_ORIGINS", "")),
+			AllowedMethods:   parseList(getEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS")),
+			AllowedHeaders:   parseList(getEnv("CORS_ALLOWED_HEADERS", "Origin,Content-Type,Accept,Authorization,X-Debug,X-Device-ID,X-Tenant-ID")),
+			ExposedHeaders:   parseList(getEnv("CORS_EXPOSED_HEADERS", "X-Request-ID,Retry-After,X-Total-Count,X-Total-Pages,X-Page,X-Per-Page,Link")),
+			AllowCredentials: getBoolEnv("CORS_ALLOW_CREDENTIALS", true),
+			MaxAge:           getDurationEnv("CORS_MAX_AGE", 24*time.Hour),
+		},
+		Compression: CompressionConfig{
+			Enabled: getBoolEnv("COMPRESSION_ENABLED", true),
+			Level:   getEnv("COMPRESSION_LEVEL", "default"),
+			ETag:    getBoolEnv("ETAG_ENABLED", true),
+		},
+		Audit: AuditConfig{
+			Enabled:       getBoolEnv("AUDIT_ENABLED", true),
+			RedactFields:  parseList(getEnv("AUDIT_REDACT_FIELDS", "password,current_password,new_password,confirm_password,token,access_token,refresh_token,secret,client_secret,otp,code")),
+			MaxBodySize:   parseInt(getEnv("AUDIT_MAX_BODY_SIZE", "8192")),
+			BufferSize:    parseInt(getEnv("AUDIT_BUFFER_SIZE", "5000")),
+			FlushInterval: getDurationEnv("AUDIT_FLUSH_INTERVAL", 5*time.Second),
+			LogEnabled:    getBoolEnv("AUDIT_LOG_ENABLED", true),
+			LogRetention:  getDurationEnv("AUDIT_LOG_RETENTION", 365*24*time.Hour),
+			ExportMaxRows: parseInt(getEnv("AUDIT_LOG_EXPORT_MAX_ROWS", "10000")),
+		},
+		BodyLog: BodyLogConfig{
+			Enabled:      getBoolEnv("BODY_LOG_ENABLED", false),
+			SampleRate:   parseFloat(getEnv("BODY_LOG_SAMPLE_RATE", "0.1")),
+			MaxBodySize:  parseInt(getEnv("BODY_LOG_MAX_BODY_SIZE", "4096")),
+			RedactFields: parseList(getEnv("BODY_LOG_REDACT_FIELDS", "password,current_password,new_password,confirm_password,token,access_token,refresh_token,secret,client_secret,otp,code")),
+		},
+		Cache: ResponseCacheConfig{
+			Enabled: getBoolEnv("RESPONSE_CACHE_ENABLED", true),
+			TTL:     getDurationEnv("RESPONSE_CACHE_TTL", 30*time.Second),
+		},
+		CacheStore: CacheStoreConfig{
+			Driver:           getEnv("CACHE_DRIVER", "redis"),
+			MemoryMaxEntries: parseInt(getEnv("CACHE_MEMORY_MAX_ENTRIES", "10000")),
+		},
+		Sessions: SessionStoreConfig{
+			Driver:           getEnv("SESSION_STORE", "database"),
+			CleanupInterval:  getDurationEnv("SESSION_CLEANUP_INTERVAL", time.Hour),
+			CleanupBatchSize: parseInt(getEnv("SESSION_CLEANUP_BATCH_SIZE", "1000")),
+		},
+		Events: EventBusConfig{
+			Driver: getEnv("EVENT_BUS_DRIVER", "redis"),
+		},
+		APIVersion: APIVersionConfig{
+			Default:         getEnv("API_DEFAULT_VERSION", "v1"),
+			DeprecationLink: getEnv("API_DEPRECATION_LINK", ""),
+		},
+		Tenancy: TenancyConfig{
+			Enabled:    getBoolEnv("TENANCY_ENABLED", false),
+			Header:     getEnv("TENANT_HEADER", "X-Tenant-ID"),
+			BaseDomain: strings.ToLower(strings.Trim(getEnv("TENANT_BASE_DOMAIN", ""), ".")),
+			Required:   getBoolEnv("TENANT_REQUIRED", false),
+			CacheTTL:   getDurationEnv("TENANT_CACHE_TTL", time.Minute),
+		},
+		Secrets: SecretsConfig{
+			CacheTTL:        secretsCacheTTL,
+			RefreshInterval: getDurationEnv("SECRETS_REFRESH_INTERVAL", 0),
+		},
+		Features: FeaturesConfig{
+			Enabled:  parseList(getEnv("FEATURE_FLAGS", "")),
+			CacheTTL: getDurationEnv("FEATURE_FLAGS_CACHE_TTL", 10*time.Second),
+		},
+		HotReload: HotReloadConfig{
+			Watch: getBoolEnv("CONFIG_WATCH", true),
+		},
+		I18n: I18nConfig{
+			DefaultLocale: getEnv("DEFAULT_LOCALE", "en"),
+			QueryParam:    getEnv("LOCALE_QUERY_PARAM", "lang"),
+		},
+		Debug: DebugConfig{
+			Enabled:          getBoolEnv("REQUEST_DEBUG_ENABLED", getEnv("SERVER_MODE", "development") != "production"),
+			EndpointsEnabled: getBoolEnv("DEBUG_ENDPOINTS_ENABLED", getEnv("SERVER_MODE", "development") != "production"),
+			Token:            getEnv("DEBUG_TOKEN", ""),
+		},
+		Trash: TrashConfig{
+			Retention:     getDurationEnv("TRASH_RETENTION", 30*24*time.Hour),
+			PurgeInterval: getDurationEnv("TRASH_PURGE_INTERVAL", time.Hour),
+			BatchSize:     parseInt(getEnv("TRASH_PURGE_BATCH_SIZE", "1000")),
+			DryRun:        getBoolEnv("TRASH_PURGE_DRY_RUN", false),
+		},
 	}
 
 	// Parse JWT expiry durations
-	var err error
 	cfg.JWT.AccessExpiry, err = time.ParseDuration(getEnv("JWT_ACCESS_EXPIRY", "1h"))
 	if err != nil {
 		cfg.JWT.AccessExpiry = 1 * time.Hour
@@ -201,54 +800,162 @@ func LoadConfig() (*Config, error) {
 
 	cfg.JWT.Issuer = "go_boilerplate"
 
-	// Debug: Print loaded config
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println("📋 Configuration Loaded:")
-	fmt.Printf("   Server Port: %s\n", cfg.Server.Port)
-	fmt.Printf("   Server Mode: %s\n", cfg.Server.Mode)
-	fmt.Printf("   Database: %s@%s:%s/%s\n", cfg.Database.User, cfg.Database.Host, cfg.Database.Port, cfg.Database.DBName)
-	fmt.Printf("   Redis: %s:%s (DB: %d)\n", cfg.Redis.Host, cfg.Redis.Port, cfg.Redis.DB)
-	fmt.Printf("   JWT Secret: %s\n", maskSecret(cfg.JWT.Secret))
-	fmt.Printf("   Log Level: %s\n", cfg.Logger.Level)
-	fmt.Printf("   Security: EmailVerify=%v, 2FA=%v\n", cfg.Security.EmailVerificationEnabled, cfg.Security.TwoFactorEnabled)
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	// Parse security.txt expiry (defaults to one year from now)
+	cfg.Security.SecurityTxt.Expires, err = time.Parse(time.RFC3339, getEnv("SECURITY_TXT_EXPIRES", ""))
+	if err != nil {
+		cfg.Security.SecurityTxt.Expires = time.Now().AddDate(1, 0, 0).UTC().Truncate(24 * time.Hour)
+	}
 
-	// Validate required fields
-	if err := validateConfig(&cfg); err != nil {
-		return nil, fmt.Errorf("config validation failed: %w", err)
+	// HSTS only makes sense behind HTTPS, so it is off by default outside production
+	hstsMaxAge := time.Duration(0)
+	if cfg.Server.IsProduction() {
+		hstsMaxAge = 365 * 24 * time.Hour
 	}
+	cfg.Security.Headers.HSTSMaxAge = getDurationEnv("SECURITY_HSTS_MAX_AGE", hstsMaxAge)
 
-	return &cfg, nil
-}
+	var problems []Problem
+	cfg.APIVersion.Deprecated, err = parseDeprecatedVersions(getEnv("API_DEPRECATED_VERSIONS", ""))
+	if err != nil {
+		problems = append(problems, Problem{Key: "API_DEPRECATED_VERSIONS", Message: err.Error()})
+	}
+	cfg.Trash.TableRetentions, err = parseTableDurations(getEnv("TRASH_RETENTION_TABLES", ""))
+	if err != nil {
+		problems = append(problems, Problem{Key: "TRASH_RETENTION_TABLES", Message: err.Error()})
+	}
+	cfg.Audit.ResourceRetentions, err = parseTableDurations(getEnv("AUDIT_LOG_RETENTION_RESOURCES", ""))
+	if err != nil {
+		problems = append(problems, Problem{Key: "AUDIT_LOG_RETENTION_RESOURCES", Message: err.Error()})
+	}
+	if _, err := encryption.ParseKeys(cfg.Encryption.Keys); err != nil {
+		problems = append(problems, Problem{Key: "ENCRYPTION_KEYS", Message: err.Error()})
+	}
+
+	// GORM logs every query in development and nothing elsewhere unless DB_LOG_LEVEL says otherwise
+	dbLogLevel := "silent"
+	if cfg.Server.IsDevelopment() {
+		dbLogLevel = "info"
+	}
+	cfg.Database.GORM.LogLevel = getEnv("DB_LOG_LEVEL", dbLogLevel)
+
+	// Development keeps the schema in sync with the models; elsewhere only SQL migrations touch it
+	migrationMode := MigrationModeSQL
+	if cfg.Server.IsDevelopment() {
+		migrationMode = MigrationModeAuto
+	}
+	cfg.Database.MigrationMode = getEnv("MIGRATION_MODE", migrationMode)
+
+	// Development sends to a local mail catcher (MailHog, Mailpit) unless SMTP_HOST/SMTP_PORT say otherwise
+	smtpHost, smtpPort := "smtp.gmail.com", "587"
+	if cfg.Server.IsDevelopment() {
+		smtpHost, smtpPort = "localhost", "1025"
+	}
+	cfg.Email.SMTPHost = getEnv("SMTP_HOST", smtpHost)
+	cfg.Email.SMTPPort = parseInt(getEnv("SMTP_PORT", smtpPort))
+
+	// Any origin is allowed outside production unless CORS_ALLOWED_ORIGINS narrows it
+	if len(cfg.CORS.AllowedOrigins) == 0 && !cfg.Server.IsProduction() {
+		cfg.CORS.AllowedOrigins = []string{"*"}
+	}
+	// Allowed origins are echoed back, so * with credentials lets any site make requests carrying
+	// the user's cookies and read the responses
+	if cfg.Server.IsProduction() && cfg.CORS.AllowCredentials && slices.Contains(cfg.CORS.AllowedOrigins, "*") {
+		problems = append(problems, Problem{Key: "CORS_ALLOWED_ORIGINS", Message: "* cannot be combined with CORS_ALLOW_CREDENTIALS in production; list the origins or set CORS_ALLOW_CREDENTIALS=false"})
+	}
+
+	// Validate the whole tree and module-registered sections, reporting every problem at once
+	validate := newConfigValidator(cfg.Server.Mode)
+	problems = append(problems, secretProblems...)
+	problems = append(problems, validateConfig(validate, &cfg)...)
+	var sectionProblems []Problem
+	cfg.Sections, sectionProblems = loadSections(validate)
+	problems = append(problems, sectionProblems...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	cfg.secrets = secretResolver
+
+	// In development, use a default secret if not set
+	if cfg.JWT.Secret == "" && cfg.Server.IsDevelopment() {
+		cfg.JWT.Secret = "development-secret-key-change-in-production"
+		report.Warnings = append(report.Warnings, "JWT_SECRET is not set; using the development default")
+	}
 
-// maskSecret masks JWT secret for display
-func maskSecret(secret string) string {
-	if secret == "" {
-		return "[NOT SET]"
+	// Email-dependent settings that are silently ignored while email is off
+	if !cfg.Email.Enabled {
+		if cfg.OAuth.SendsWelcomeEmail() {
+			report.Warnings = append(report.Warnings, "OAuth welcome emails are enabled but EMAIL_ENABLED is false; none will be sent")
+		}
+		if cfg.Notify.Email != "" {
+			report.Warnings = append(report.Warnings, "NOTIFY_EMAIL is set but EMAIL_ENABLED is false; notifications go to the log and webhook only")
+		}
+		if cfg.Health.CheckSMTP {
+			report.Warnings = append(report.Warnings, "HEALTH_CHECK_SMTP has no effect while EMAIL_ENABLED is false")
+		}
+	} else if (cfg.Email.Provider == "log" || cfg.Email.Provider == "file") && cfg.Server.IsProduction() {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("EMAIL_PROVIDER=%s does not deliver email; use it in development only", cfg.Email.Provider))
+	} else if cfg.Email.Provider != "smtp" {
+		if cfg.Health.CheckSMTP {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("HEALTH_CHECK_SMTP has no effect with EMAIL_PROVIDER=%s", cfg.Email.Provider))
+		}
+	} else if cfg.Email.Sandbox {
+		report.Warnings = append(report.Warnings, "EMAIL_SANDBOX has no effect with EMAIL_PROVIDER=smtp; point SMTP_HOST at a test server instead")
 	}
-	if len(secret) > 8 {
-		return secret[:4] + "****" + secret[len(secret)-4:]
+	if cfg.Database.MigrationMode == MigrationModeAuto && cfg.Database.MigrateOnStart {
+		report.Warnings = append(report.Warnings, "DB_MIGRATE_ON_START has no effect while MIGRATION_MODE is auto")
 	}
-	return "****"
+	if cfg.Database.MigrationMode == MigrationModeBoth {
+		report.Warnings = append(report.Warnings, "MIGRATION_MODE=both runs AutoMigrate on top of the SQL migrations; the schema may drift from db/migrations")
+	}
+	if !cfg.Redis.Enabled && cfg.Cache.Enabled {
+		report.Warnings = append(report.Warnings, "RESPONSE_CACHE_ENABLED has no effect while REDIS_ENABLED is false")
+	}
+	if !cfg.Redis.Enabled && cfg.CacheStore.Driver == "redis" {
+		report.Warnings = append(report.Warnings, "CACHE_DRIVER=redis falls back to the in-memory cache while REDIS_ENABLED is false")
+	}
+	if !cfg.Redis.Enabled && cfg.Sessions.Driver == "redis" {
+		report.Warnings = append(report.Warnings, "SESSION_STORE=redis falls back to the database while REDIS_ENABLED is false")
+	}
+	if !cfg.Redis.Enabled && cfg.Events.Driver == "redis" {
+		report.Warnings = append(report.Warnings, "EVENT_BUS_DRIVER=redis falls back to the in-process bus while REDIS_ENABLED is false")
+	}
+	if cfg.Metrics.Enabled && cfg.Metrics.Token == "" && cfg.Server.IsProduction() {
+		report.Warnings = append(report.Warnings, "METRICS_TOKEN is not set; /metrics is readable by anyone who can reach the API")
+	}
+	if cfg.Debug.EndpointsEnabled && cfg.Server.IsProduction() {
+		report.Warnings = append(report.Warnings, "DEBUG_ENDPOINTS_ENABLED is set in production; profiles of this instance can be collected under /debug")
+	}
+	if len(cfg.Encryption.Keys) == 0 && cfg.Server.IsProduction() {
+		report.Warnings = append(report.Warnings, "ENCRYPTION_KEYS is not set; OAuth tokens and other encrypted columns are stored in plaintext")
+	}
+
+	cfg.report = finishReport()
+	cfg.hot = newHotState(&cfg)
+	return &cfg, nil
 }
 
-// getEnv gets an environment variable or returns the default value
+// getEnv gets an environment variable or returns the default value, recording where it came from
 func getEnv(key, defaultValue string) string {
-	// Try os.Getenv first (from godotenv)
-	if value := os.Getenv(key); value != "" {
-		fmt.Printf("   ✅ %s = %s (from .env)\n", key, value)
-		return value
+	value, source := lookupSetting(key)
+	if value == "" {
+		value, source = defaultValue, SourceDefault
 	}
+	recordSetting(key, value, source)
+	return resolveSecret(key, value)
+}
 
-	// Fallback to viper
+// lookupSetting returns the value of key from the environment (including .env), the config
+// files or the viper defaults, in that order, or "" when none sets it
+func lookupSetting(key string) (string, Source) {
+	if value := os.Getenv(key); value != "" {
+		return value, SourceEnv
+	}
+	if value := fileValues[key]; value != "" {
+		return value, SourceFile
+	}
 	if value := viper.GetString(key); value != "" {
-		fmt.Printf("   ✅ %s = %s (from system)\n", key, value)
-		return value
+		return value, SourceDefault
 	}
-
-	// Use default
-	fmt.Printf("   ⚠️  %s not set, using default: %s\n", key, defaultValue)
-	return defaultValue
+	return "", SourceDefault
 }
 
 // parseInt parses a string to int
@@ -257,25 +964,81 @@ func parseInt(s string) int {
 	return i
 }
 
-// getBoolEnv parses a string to bool
-func getBoolEnv(key string, defaultValue bool) bool {
-	// Try os.Getenv first (from godotenv)
-	if value := os.Getenv(key); value != "" {
-	 parsed := parseBool(value)
-	 fmt.Printf("   ✅ %s = %v (from .env)\n", key, parsed)
-	 return parsed
+// parseFloat parses a string to float64
+func parseFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+// getDurationEnv gets a duration environment variable or returns the default value
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	d, err := time.ParseDuration(getEnv(key, defaultValue.String()))
+	if err != nil {
+		return defaultValue
 	}
+	return d
+}
 
-	// Fallback to viper
-	if value := viper.GetString(key); value != "" {
-	 parsed := parseBool(value)
-	 fmt.Printf("   ✅ %s = %v (from system)\n", key, parsed)
-	 return parsed
+// parseList parses a comma-separated string into a slice, skipping empty items
+func parseList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
+}
 
-	// Use default
-	fmt.Printf("   ⚠️  %s not set, using default: %v\n", key, defaultValue)
-	return defaultValue
+// apiVersionPattern matches API versions (v1, v2, ...)
+var apiVersionPattern = regexp.MustCompile(`^v[1-9][0-9]*$`)
+
+// parseDeprecatedVersions parses comma-separated version[@YYYY-MM-DD] entries into sunset dates by version
+// Versions deprecated without a date map to the zero time
+func parseDeprecatedVersions(s string) (map[string]time.Time, error) {
+	deprecated := map[string]time.Time{}
+	for _, item := range parseList(s) {
+		version, date, hasDate := strings.Cut(item, "@")
+		var sunset time.Time
+		if hasDate {
+			var err error
+			if sunset, err = time.Parse(time.DateOnly, date); err != nil {
+				return nil, fmt.Errorf("invalid sunset date in %q, want YYYY-MM-DD", item)
+			}
+		}
+		deprecated[version] = sunset
+	}
+	return deprecated, nil
+}
+
+// parseTableDurations parses comma-separated table=duration entries into durations by table
+func parseTableDurations(s string) (map[string]time.Duration, error) {
+	durations := map[string]time.Duration{}
+	for _, item := range parseList(s) {
+		table, value, ok := strings.Cut(item, "=")
+		if !ok || table == "" {
+			return nil, fmt.Errorf("invalid entry %q, want table=duration", item)
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration in %q: %v", item, err)
+		}
+		durations[table] = duration
+	}
+	return durations, nil
+}
+
+// getBoolEnv parses a string to bool
+func getBoolEnv(key string, defaultValue bool) bool {
+	value, source := lookupSetting(key)
+	parsed := defaultValue
+	if value != "" {
+		parsed = parseBool(resolveSecret(key, value))
+	} else {
+		source = SourceDefault
+	}
+	recordSetting(key, strconv.FormatBool(parsed), source)
+	return parsed
 }
 
 // parseBool parses a string to bool (accepts: true, false, 1, 0, yes, no)
@@ -326,6 +1089,14 @@ func bindEnvs() {
 
 	viper.BindEnv("LOG_LEVEL")
 	viper.BindEnv("LOG_FORMAT")
+	viper.BindEnv("LOG_WIDE_EVENTS")
+	viper.BindEnv("LOG_ACCESS_SAMPLE_RATE")
+	viper.BindEnv("LOG_OUTPUT")
+	viper.BindEnv("LOG_FILE_PATH")
+	viper.BindEnv("LOG_FILE_MAX_SIZE")
+	viper.BindEnv("LOG_FILE_MAX_AGE")
+	viper.BindEnv("LOG_FILE_MAX_BACKUPS")
+	viper.BindEnv("LOG_FILE_COMPRESS")
 }
 
 // setDefaults sets default configuration values
@@ -349,7 +1120,6 @@ func setDefaults() {
 	viper.SetDefault("JWT_REFRESH_EXPIRY", "24h")
 
 	// Email defaults
-	viper.SetDefault("SMTP_PORT", "587")
 
 	// Security defaults
 	viper.SetDefault("EMAIL_VERIFICATION_ENABLED", false)
@@ -360,29 +1130,6 @@ func setDefaults() {
 	viper.SetDefault("LOG_FORMAT", "json")
 }
 
-// validateConfig validates required configuration fields
-func validateConfig(cfg *Config) error {
-	if cfg.Server.Port == "" {
-		return fmt.Errorf("SERVER_PORT is required")
-	}
-	if cfg.Database.Host == "" {
-		return fmt.Errorf("DB_HOST is required")
-	}
-	if cfg.Database.DBName == "" {
-		return fmt.Errorf("DB_NAME is required")
-	}
-	// Only require JWT_SECRET in production
-	if cfg.Server.IsProduction() && (cfg.JWT.Secret == "" || cfg.JWT.Secret == "change-this-secret-in-production") {
-		return fmt.Errorf("JWT_SECRET must be set to a secure value in production")
-	}
-	// In development, use a default secret if not set
-	if cfg.JWT.Secret == "" && cfg.Server.IsDevelopment() {
-		cfg.JWT.Secret = "development-secret-key-change-in-production"
-		fmt.Println("WARNING: Using default JWT secret for development mode!")
-	}
-	return nil
-}
-
 // GetDSN returns the PostgreSQL Data Source Name
 func (c *DatabaseConfig) GetDSN() string {
 	return fmt.Sprintf(
@@ -391,6 +1138,23 @@ func (c *DatabaseConfig) GetDSN() string {
 	)
 }
 
+// GetReplicaDSNs returns the Data Source Names of the read replicas
+// Replicas listed without a port use DB_PORT
+func (c *DatabaseConfig) GetReplicaDSNs() []string {
+	dsns := make([]string, len(c.Replicas))
+	for i, replica := range c.Replicas {
+		host, port, err := net.SplitHostPort(replica)
+		if err != nil {
+			host, port = replica, c.Port
+		}
+		dsns[i] = fmt.Sprintf(
+			"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+			host, port, c.User, c.Password, c.DBName, c.SSLMode,
+		)
+	}
+	return dsns
+}
+
 // IsDevelopment returns true if server mode is development
 func (c *ServerConfig) IsDevelopment() bool {
 	return c.Mode == "development"
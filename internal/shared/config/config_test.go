@@ -0,0 +1,54 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+// corsProblem loads the configuration and returns the CORS_ALLOWED_ORIGINS problem, if any
+func corsProblem(t *testing.T) (Problem, bool) {
+	t.Helper()
+
+	_, err := LoadConfig()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		return Problem{}, false
+	}
+	for _, problem := range validationErr.Problems {
+		if problem.Key == "CORS_ALLOWED_ORIGINS" {
+			return problem, true
+		}
+	}
+	return Problem{}, false
+}
+
+func TestLoadConfigRejectsWildcardCredentialedCORSInProduction(t *testing.T) {
+	t.Setenv("CONFIG_DIR", t.TempDir())
+	t.Setenv("SERVER_MODE", "production")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com,*")
+
+	if _, ok := corsProblem(t); !ok {
+		t.Fatal("* with credentials was accepted in production")
+	}
+
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "false")
+	if problem, ok := corsProblem(t); ok {
+		t.Fatalf("* without credentials was rejected: %s", problem.Message)
+	}
+
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com")
+	if problem, ok := corsProblem(t); ok {
+		t.Fatalf("listed origins were rejected: %s", problem.Message)
+	}
+}
+
+func TestLoadConfigAllowsWildcardCORSInDevelopment(t *testing.T) {
+	t.Setenv("CONFIG_DIR", t.TempDir())
+	t.Setenv("SERVER_MODE", "development")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "*")
+
+	if problem, ok := corsProblem(t); ok {
+		t.Fatalf("* was rejected in development: %s", problem.Message)
+	}
+}
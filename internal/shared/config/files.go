@@ -0,0 +1,107 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// configFileName is the base name of config files; viper accepts .yaml, .yml, .toml and .json
+const configFileName = "config"
+
+// fileValues are settings read from config files, keyed by environment variable name.
+// Environment variables (and .env) take precedence over them.
+var fileValues = map[string]string{}
+
+// loadConfigFiles reads config.yaml and then config.<SERVER_MODE>.yaml (or .toml/.json) from
+// CONFIG_DIR (default: the working directory); both are optional and the mode file wins.
+// Nested keys are joined with underscores, so cors: {allowed_origins: [...]} sets
+// CORS_ALLOWED_ORIGINS, and lists become comma-separated values. It returns the files read.
+func loadConfigFiles() ([]string, error) {
+	dir := os.Getenv("CONFIG_DIR")
+	if dir == "" {
+		dir = "."
+	}
+
+	values := map[string]string{}
+	var files []string
+
+	base, err := readConfigFile(dir, configFileName)
+	if err != nil {
+		return nil, err
+	}
+	if base != nil {
+		files = append(files, base.ConfigFileUsed())
+		flattenSettings("", base.AllSettings(), values)
+	}
+
+	mode := os.Getenv("SERVER_MODE")
+	if mode == "" {
+		mode = values["SERVER_MODE"]
+	}
+	if mode == "" {
+		mode = "development"
+	}
+
+	overlay, err := readConfigFile(dir, configFileName+"."+mode)
+	if err != nil {
+		return nil, err
+	}
+	if overlay != nil {
+		files = append(files, overlay.ConfigFileUsed())
+		flattenSettings("", overlay.AllSettings(), values)
+	}
+
+	fileValues = values
+	return files, nil
+}
+
+// readConfigFile reads dir/name.<ext>, returning nil when no such file exists
+func readConfigFile(dir, name string) (*viper.Viper, error) {
+	v := viper.New()
+	v.SetConfigName(name)
+	v.AddConfigPath(dir)
+
+	if err := v.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("config file %s: %w", name, err)
+	}
+	return v, nil
+}
+
+// flattenSettings stores nested settings under their environment variable names
+func flattenSettings(prefix string, settings map[string]any, values map[string]string) {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name := strings.ToUpper(key)
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		switch value := settings[key].(type) {
+		case nil:
+		case map[string]any:
+			flattenSettings(name, value, values)
+		case []any:
+			items := make([]string, len(value))
+			for i, item := range value {
+				items[i] = fmt.Sprint(item)
+			}
+			values[name] = strings.Join(items, ",")
+		default:
+			values[name] = fmt.Sprint(value)
+		}
+	}
+}
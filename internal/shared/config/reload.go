@@ -0,0 +1,206 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// FeaturesConfig holds feature flags
+type FeaturesConfig struct {
+	Enabled  []string      `mapstructure:"FEATURE_FLAGS"`                            // Names of the enabled features
+	CacheTTL time.Duration `mapstructure:"FEATURE_FLAGS_CACHE_TTL" validate:"gte=0"` // How long each instance caches the Redis overrides
+}
+
+// HotReloadConfig holds runtime reload configuration; SIGHUP always reloads
+type HotReloadConfig struct {
+	Watch bool `mapstructure:"CONFIG_WATCH"` // Reload when a config file in CONFIG_DIR changes
+}
+
+// HotConfig holds the settings Reload applies to a running server. Every other setting keeps the
+// value read at startup; changing one requires a restart.
+type HotConfig struct {
+	LogLevel       string          // LOG_LEVEL
+	AbuseRateLimit int             // ABUSE_REPORT_RATE_LIMIT
+	CORSOrigins    []string        // CORS_ALLOWED_ORIGINS
+	Features       map[string]bool // FEATURE_FLAGS
+}
+
+// hotKeys are the settings making up HotConfig
+var hotKeys = []string{"LOG_LEVEL", "ABUSE_REPORT_RATE_LIMIT", "CORS_ALLOWED_ORIGINS", "FEATURE_FLAGS"}
+
+// Feature reports whether the named feature flag is enabled
+func (h HotConfig) Feature(name string) bool {
+	return h.Features[strings.ToLower(name)]
+}
+
+// ReloadHook is called with the previous and the new hot settings after a reload changed them
+type ReloadHook func(old, new HotConfig)
+
+// hotState is the live HotConfig and the hooks notified when it changes
+type hotState struct {
+	current atomic.Pointer[HotConfig]
+
+	mu     sync.Mutex // Serializes reloads and guards hooks
+	hooks  []ReloadHook
+	report Report // Report of the last reload, to tell changed settings apart
+}
+
+// newHotState snapshots the hot settings of cfg
+func newHotState(cfg *Config) *hotState {
+	state := &hotState{}
+	hot := cfg.hotConfig()
+	state.current.Store(&hot)
+	return state
+}
+
+// hotConfig collects the hot settings of a freshly loaded config
+func (c *Config) hotConfig() HotConfig {
+	features := make(map[string]bool, len(c.Features.Enabled))
+	for _, name := range c.Features.Enabled {
+		features[strings.ToLower(name)] = true
+	}
+	return HotConfig{
+		LogLevel:       c.Logger.Level,
+		AbuseRateLimit: c.Abuse.RateLimit,
+		CORSOrigins:    c.CORS.AllowedOrigins,
+		Features:       features,
+	}
+}
+
+// Hot returns the current hot settings; read these instead of the matching Config fields to
+// follow reloads
+func (c *Config) Hot() HotConfig {
+	return *c.hot.current.Load()
+}
+
+// OnReload registers a hook called after every reload that changes a hot setting
+func (c *Config) OnReload(hook ReloadHook) {
+	c.hot.mu.Lock()
+	defer c.hot.mu.Unlock()
+	c.hot.hooks = append(c.hot.hooks, hook)
+}
+
+// Reload loads the configuration again and applies the hot settings. It returns the hot settings
+// that changed and the other changed settings, which only take effect after a restart. Environment
+// variables set at startup (including .env) keep precedence, so reloads pick up config file edits.
+// Invalid configuration is rejected and the current settings stay in place.
+func (c *Config) Reload() (applied, restartRequired []string, err error) {
+	c.hot.mu.Lock()
+	defer c.hot.mu.Unlock()
+
+	next, err := LoadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	previous := c.hot.report
+	if previous.Settings == nil {
+		previous = c.report
+	}
+	for _, key := range changedSettings(previous, next.report) {
+		if slices.Contains(hotKeys, key) {
+			applied = append(applied, key)
+		} else {
+			restartRequired = append(restartRequired, key)
+		}
+	}
+	c.hot.report = next.report
+
+	if len(applied) == 0 {
+		return nil, restartRequired, nil
+	}
+
+	old := c.Hot()
+	hot := next.hotConfig()
+	c.hot.current.Store(&hot)
+	for _, hook := range c.hot.hooks {
+		hook(old, hot)
+	}
+	return applied, restartRequired, nil
+}
+
+// changedSettings returns the keys whose reported value differs between two reports
+func changedSettings(old, new Report) []string {
+	values := make(map[string]string, len(old.Settings))
+	for _, setting := range old.Settings {
+		values[setting.Key] = setting.Value
+	}
+
+	var changed []string
+	for _, setting := range new.Settings {
+		if value, ok := values[setting.Key]; ok && value != setting.Value {
+			changed = append(changed, setting.Key)
+		}
+	}
+	return changed
+}
+
+// watchDebounce coalesces the bursts of events editors produce when saving a file
+const watchDebounce = 500 * time.Millisecond
+
+// WatchFiles calls onChange whenever a config file in CONFIG_DIR is written, created, renamed or
+// removed, until ctx is done. The directory is watched rather than the files, so editors that
+// replace files on save and files created after startup are both noticed.
+func WatchFiles(ctx context.Context, onChange func()) error {
+	dir := os.Getenv("CONFIG_DIR")
+	if dir == "" {
+		dir = "."
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(dir); err != nil {
+		_ = watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if isConfigFile(event.Name) {
+					debounce = time.After(watchDebounce)
+				}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			case <-debounce:
+				debounce = nil
+				onChange()
+			}
+		}
+	}()
+	return nil
+}
+
+// isConfigFile reports whether path names config.<ext> or config.<mode>.<ext>
+func isConfigFile(path string) bool {
+	name := filepath.Base(path)
+	if !strings.HasPrefix(name, configFileName+".") {
+		return false
+	}
+	switch filepath.Ext(name) {
+	case ".yaml", ".yml", ".toml", ".json":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Source is where the value of a setting came from
+type Source string
+
+// Setting sources, in order of precedence
+const (
+	SourceEnv     Source = "env"     // Environment variable or .env
+	SourceFile    Source = "file"    // config.yaml or config.<mode>.yaml
+	SourceDefault Source = "default" // Built-in default
+)
+
+// Redacted replaces secret values in the config report
+const Redacted = "[REDACTED]"
+
+// secretKeyParts mark settings whose values are never reported
+var secretKeyParts = []string{"PASSWORD", "SECRET", "TOKEN", "PRIVATE_KEY", "API_KEY", "ENCRYPTION_KEY", "WEBHOOK_URL", "DSN"}
+
+// Setting is one resolved setting; Value is redacted for secrets
+type Setting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source Source `json:"source"`
+}
+
+// Report describes how the configuration was resolved, safe to log
+type Report struct {
+	EnvFile  bool      // .env was loaded
+	Files    []string  // Config files read, in order
+	Settings []Setting // Every setting read, sorted by key
+	Warnings []string  // Fallbacks worth surfacing, e.g. the development JWT secret
+}
+
+// report collects the settings read by the LoadConfig call in progress
+var report Report
+
+// settings holds the settings recorded by getEnv and getBoolEnv, keyed by name
+var settings = map[string]Setting{}
+
+// Report returns how the configuration was resolved, with secret values redacted
+func (c *Config) Report() Report {
+	return c.report
+}
+
+// BySource counts the settings taken from each source
+func (r Report) BySource() map[Source]int {
+	counts := map[Source]int{}
+	for _, setting := range r.Settings {
+		counts[setting.Source]++
+	}
+	return counts
+}
+
+// resetReport starts a new report
+func resetReport() {
+	report = Report{}
+	settings = map[string]Setting{}
+}
+
+// recordSetting records the resolved value of key, redacting secrets
+func recordSetting(key, value string, source Source) {
+	settings[key] = Setting{Key: key, Value: redactSetting(key, value), Source: source}
+}
+
+// finishReport returns the report with its settings sorted by key
+func finishReport() Report {
+	report.Settings = make([]Setting, 0, len(settings))
+	for _, setting := range settings {
+		report.Settings = append(report.Settings, setting)
+	}
+	sort.Slice(report.Settings, func(i, j int) bool { return report.Settings[i].Key < report.Settings[j].Key })
+	return report
+}
+
+// redactSetting hides secret values and the password of URLs with credentials; empty values stay
+// empty so unset secrets remain visible, and secret references are shown as written
+func redactSetting(key, value string) string {
+	if value == "" || isSecretReference(value) {
+		return value
+	}
+	for _, part := range secretKeyParts {
+		if strings.Contains(key, part) {
+			return Redacted
+		}
+	}
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		return u.Redacted()
+	}
+	return value
+}
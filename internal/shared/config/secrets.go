@@ -0,0 +1,46 @@
+package config
+
+import (
+	"context"
+	"time"
+
+	"go_boilerplate/internal/shared/secrets"
+)
+
+// SecretsConfig holds secret manager configuration. Any setting may be a reference such as
+// vault://secret/app#jwt_secret, aws-sm://prod/db#password or gcp-sm://project/jwt-secret,
+// resolved by LoadConfig.
+type SecretsConfig struct {
+	CacheTTL        time.Duration `mapstructure:"SECRETS_CACHE_TTL" validate:"gte=0"`        // How long a fetched secret is reused before the store is asked again
+	RefreshInterval time.Duration `mapstructure:"SECRETS_REFRESH_INTERVAL" validate:"gte=0"` // How often referenced secrets are re-fetched to detect rotation (0 disables it)
+}
+
+// secretResolver resolves secret references for the LoadConfig call in progress
+var secretResolver *secrets.Resolver
+
+// secretProblems collects references that could not be resolved; LoadConfig reports them
+var secretProblems []Problem
+
+// SecretResolver returns the resolver of the secret references in the configuration
+func (c *Config) SecretResolver() *secrets.Resolver {
+	return c.secrets
+}
+
+// resolveSecret returns the secret a reference points to, or value itself when it is not a reference
+func resolveSecret(key, value string) string {
+	if secretResolver == nil || !secretResolver.IsReference(value) {
+		return value
+	}
+
+	resolved, err := secretResolver.Resolve(context.Background(), value)
+	if err != nil {
+		secretProblems = append(secretProblems, Problem{Key: key, Message: err.Error()})
+		return ""
+	}
+	return resolved
+}
+
+// isSecretReference reports whether value is a secret reference, which is safe to report as written
+func isSecretReference(value string) bool {
+	return secretResolver != nil && secretResolver.IsReference(value)
+}
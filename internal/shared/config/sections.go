@@ -0,0 +1,195 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// SectionValidator is implemented by section structs that need checks beyond `validate` tags
+type SectionValidator interface {
+	Validate() error
+}
+
+// SectionField describes one environment variable of a registered section
+type SectionField struct {
+	Env     string `json:"env"`
+	Type    string `json:"type"`
+	Default string `json:"default,omitempty"`
+	Rules   string `json:"rules,omitempty"` // validate tag
+}
+
+// SectionInfo describes a registered section and its environment variables
+type SectionInfo struct {
+	Name   string         `json:"name"`
+	Fields []SectionField `json:"fields"`
+}
+
+var (
+	sectionsMu sync.RWMutex
+	sections   = map[string]reflect.Type{}
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// RegisterSection registers a module config struct under name; modules call it from init()
+//
+// Fields are bound from the environment through struct tags:
+//
+//	type StorageConfig struct {
+//		Bucket  string        `env:"STORAGE_BUCKET" validate:"required"`
+//		Timeout time.Duration `env:"STORAGE_TIMEOUT" default:"30s"`
+//	}
+//
+// Supported field types are string, bool, ints, floats, time.Duration and []string (comma-separated)
+func RegisterSection[T any](name string) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("config: section %q must be a struct, got %s", name, t))
+	}
+
+	sectionsMu.Lock()
+	defer sectionsMu.Unlock()
+
+	if _, exists := sections[name]; exists {
+		panic(fmt.Sprintf("config: section %q registered twice", name))
+	}
+	sections[name] = t
+}
+
+// Section returns the loaded value of a registered section
+func Section[T any](cfg *Config, name string) T {
+	value, ok := cfg.Sections[name].(T)
+	if !ok {
+		panic(fmt.Sprintf("config: section %q is not registered as %T", name, value))
+	}
+	return value
+}
+
+// Sections describes every registered section, sorted by name
+func Sections() []SectionInfo {
+	sectionsMu.RLock()
+	defer sectionsMu.RUnlock()
+
+	infos := make([]SectionInfo, 0, len(sections))
+	for name, t := range sections {
+		info := SectionInfo{Name: name, Fields: []SectionField{}}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if env := field.Tag.Get("env"); env != "" {
+				info.Fields = append(info.Fields, SectionField{
+					Env:     env,
+					Type:    field.Type.String(),
+					Default: field.Tag.Get("default"),
+					Rules:   field.Tag.Get("validate"),
+				})
+			}
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// loadSections binds, defaults and validates every registered section, returning the problems of
+// all of them
+func loadSections(validate *validator.Validate) (map[string]any, []Problem) {
+	sectionsMu.RLock()
+	defer sectionsMu.RUnlock()
+
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	loaded := make(map[string]any, len(sections))
+	var problems []Problem
+	for _, name := range names {
+		t := sections[name]
+		value := reflect.New(t).Elem()
+		bound := true
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			env := field.Tag.Get("env")
+			if env == "" || !field.IsExported() {
+				continue
+			}
+			if err := setField(value.Field(i), getEnv(env, field.Tag.Get("default"))); err != nil {
+				problems = append(problems, Problem{Key: env, Message: err.Error()})
+				bound = false
+			}
+		}
+		if !bound {
+			continue
+		}
+
+		if fieldProblems := problemsFrom(validate.Struct(value.Interface())); len(fieldProblems) > 0 {
+			problems = append(problems, fieldProblems...)
+			continue
+		}
+		if v, ok := value.Addr().Interface().(SectionValidator); ok {
+			if err := v.Validate(); err != nil {
+				problems = append(problems, Problem{Key: name, Message: err.Error()})
+				continue
+			}
+		}
+
+		loaded[name] = value.Interface()
+	}
+	return loaded, problems
+}
+
+// setField parses raw into field according to its type; empty values leave the zero value
+func setField(field reflect.Value, raw string) error {
+	if raw == "" {
+		return nil
+	}
+
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		field.SetBool(parseBool(raw))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", field.Type())
+		}
+		field.Set(reflect.ValueOf(parseList(raw)))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
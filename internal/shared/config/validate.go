@@ -0,0 +1,233 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Problem is one invalid setting found by LoadConfig
+type Problem struct {
+	Key     string // Environment variable name (or section name for section-level checks)
+	Message string
+}
+
+// ValidationError lists every invalid setting, so a bad deployment is fixed in one pass
+type ValidationError struct {
+	Problems []Problem
+}
+
+// Error lists the problems one per line
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString("config validation failed:")
+	for _, problem := range e.Problems {
+		fmt.Fprintf(&b, "\n  - %s: %s", problem.Key, problem.Message)
+	}
+	return b.String()
+}
+
+// placeholderSecrets are sample values from .env.example and the old built-in defaults
+var placeholderSecrets = []string{
+	"change-this-secret-in-production",
+	"your-super-secret-key-change-this-in-production",
+	"development-secret-key-change-in-production",
+}
+
+// IsPlaceholderSecret reports whether value is one of the sample secrets shipped with the project
+func IsPlaceholderSecret(value string) bool {
+	return slices.Contains(placeholderSecrets, value)
+}
+
+// newConfigValidator returns the validator for Config and module sections. Fields are reported by
+// their env or mapstructure tag. Besides the built-in rules it knows:
+//
+//	required_in_production   the field must be set when SERVER_MODE=production
+//	secret_in_production     in production, the field must be set to something other than a sample value
+//	port                     1-65535, for string and int fields
+//	api_version              v1, v2, ...
+//	origin                   a CORS origin, wildcard subdomain origin or *
+func newConfigValidator(mode string) *validator.Validate {
+	validate := validator.New()
+
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		if name := field.Tag.Get("env"); name != "" {
+			return name
+		}
+		if name := field.Tag.Get("mapstructure"); name != "" {
+			return name
+		}
+		return field.Name
+	})
+
+	production := mode == "production"
+	rules := map[string]validator.Func{
+		"required_in_production": func(fl validator.FieldLevel) bool {
+			return !production || !fl.Field().IsZero()
+		},
+		"secret_in_production": func(fl validator.FieldLevel) bool {
+			value := fl.Field().String()
+			return !production || (value != "" && !IsPlaceholderSecret(value))
+		},
+		// Replaces the built-in port rule, which only accepts unsigned ints; ports are strings here
+		"port": func(fl validator.FieldLevel) bool {
+			field := fl.Field()
+			port := field.String()
+			if field.CanInt() {
+				port = strconv.FormatInt(field.Int(), 10)
+			}
+			n, err := strconv.Atoi(port)
+			return err == nil && n >= 1 && n <= 65535
+		},
+		"api_version": func(fl validator.FieldLevel) bool {
+			return apiVersionPattern.MatchString(fl.Field().String())
+		},
+		"origin": func(fl validator.FieldLevel) bool {
+			return validOrigin(fl.Field().String())
+		},
+	}
+	for tag, fn := range rules {
+		// Tags are constant and unique, so registration cannot fail
+		_ = validate.RegisterValidation(tag, fn)
+	}
+
+	validate.RegisterStructValidation(func(sl validator.StructLevel) {
+		tenancy := sl.Current().Interface().(TenancyConfig)
+		if tenancy.Enabled && tenancy.Header == "" && tenancy.BaseDomain == "" {
+			sl.ReportError(tenancy.Header, "TENANT_HEADER", "Header", "tenant_source", "")
+		}
+	}, TenancyConfig{})
+
+	validate.RegisterStructValidation(func(sl validator.StructLevel) {
+		proxy := sl.Current().Interface().(ProxyConfig)
+		if proxy.Header != "" && len(proxy.TrustedProxies) == 0 {
+			sl.ReportError(proxy.TrustedProxies, "TRUSTED_PROXIES", "TrustedProxies", "proxy_source", "")
+		}
+	}, ProxyConfig{})
+
+	// API-based email providers authenticate with EMAIL_API_KEY
+	validate.RegisterStructValidation(func(sl validator.StructLevel) {
+		email := sl.Current().Interface().(EmailConfig)
+		switch email.Provider {
+		case "sendgrid", "mailgun", "postmark":
+			if email.Enabled && email.APIKey == "" {
+				sl.ReportError(email.APIKey, "EMAIL_API_KEY", "APIKey", "required_if", "")
+			}
+		}
+	}, EmailConfig{})
+
+	// Verification and two-factor codes are kept in Redis and delivered by email
+	validate.RegisterStructValidation(func(sl validator.StructLevel) {
+		cfg := sl.Current().Interface().(Config)
+		checks := []struct {
+			enabled bool
+			key     string
+			field   string
+		}{
+			{cfg.Security.EmailVerificationEnabled, "EMAIL_VERIFICATION_ENABLED", "EmailVerificationEnabled"},
+			{cfg.Security.TwoFactorEnabled, "TWO_FACTOR_ENABLED", "TwoFactorEnabled"},
+		}
+		for _, check := range checks {
+			if check.enabled && !cfg.Email.Enabled {
+				sl.ReportError(check.enabled, check.key, check.field, "requires_email", "")
+			}
+			if check.enabled && !cfg.Redis.Enabled {
+				sl.ReportError(check.enabled, check.key, check.field, "requires_redis", "")
+			}
+		}
+	}, Config{})
+
+	return validate
+}
+
+// validateConfig checks the validate tags of the whole Config tree
+func validateConfig(validate *validator.Validate, cfg *Config) []Problem {
+	return problemsFrom(validate.Struct(cfg))
+}
+
+// problemsFrom converts a validator error into problems
+func problemsFrom(err error) []Problem {
+	if err == nil {
+		return nil
+	}
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return []Problem{{Key: "config", Message: err.Error()}}
+	}
+
+	problems := make([]Problem, len(fieldErrors))
+	for i, fe := range fieldErrors {
+		problems[i] = Problem{Key: fe.Field(), Message: describeRule(fe)}
+	}
+	return problems
+}
+
+// describeRule explains a failed rule in words
+func describeRule(fe validator.FieldError) string {
+	param := fe.Param()
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_if":
+		return "is required when enabled"
+	case "required_in_production":
+		return "is required in production"
+	case "secret_in_production":
+		return "must be set to a secure value in production"
+	case "tenant_source":
+		return "TENANT_HEADER or TENANT_BASE_DOMAIN must be set when TENANCY_ENABLED is true"
+	case "proxy_source":
+		return "must list the proxies allowed to set PROXY_HEADER"
+	case "requires_email":
+		return "needs EMAIL_ENABLED=true"
+	case "requires_redis":
+		return "needs REDIS_ENABLED=true"
+	case "ip|cidr":
+		return fmt.Sprintf("%q must be an IP address or CIDR range", fmt.Sprint(fe.Value()))
+	case "port":
+		return "must be a port between 1 and 65535"
+	case "oneof":
+		return fmt.Sprintf("must be one of %s, got %q", strings.ReplaceAll(param, " ", ", "), fmt.Sprint(fe.Value()))
+	case "ne":
+		return fmt.Sprintf("must not be %s", param)
+	case "url", "http_url":
+		return "must be a URL"
+	case "uri":
+		return "must be a URI such as mailto:security@example.com or https://example.com/security"
+	case "hostname_port|hostname_rfc1123":
+		return fmt.Sprintf("%q must be a host name or host:port", fmt.Sprint(fe.Value()))
+	case "hostname_rfc1123":
+		return fmt.Sprintf("%q must be a host name", fmt.Sprint(fe.Value()))
+	case "email":
+		return "must be an email address"
+	case "api_version":
+		return fmt.Sprintf("must look like v1, got %q", fmt.Sprint(fe.Value()))
+	case "origin":
+		return fmt.Sprintf("%q must be *, an origin like https://app.example.com or https://*.example.com", fmt.Sprint(fe.Value()))
+	case "min", "gte":
+		return "must be at least " + param
+	case "max", "lte":
+		return "must be at most " + param
+	case "gt":
+		return "must be greater than " + param
+	case "lt":
+		return "must be less than " + param
+	default:
+		return fmt.Sprintf("failed the %s rule", fe.Tag())
+	}
+}
+
+// validOrigin reports whether origin is *, scheme://host[:port] or scheme://*.domain
+func validOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+	u, err := url.Parse(strings.Replace(origin, "://*.", "://wildcard.", 1))
+	return err == nil && u.Scheme != "" && u.Host != "" && (u.Path == "" || u.Path == "/") && u.RawQuery == "" && u.User == nil
+}
@@ -1,31 +1,70 @@
 package database
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
 	"time"
 
 	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/database/encryption"
+	"go_boilerplate/internal/shared/database/optimistic"
+	"go_boilerplate/internal/shared/database/pool"
+	"go_boilerplate/internal/shared/database/replica"
+	"go_boilerplate/internal/shared/observability"
 
+	"github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 )
 
-// InitDB initializes the database connection
-func InitDB(cfg *config.Config) (*gorm.DB, error) {
+// pingTimeout bounds each connection attempt, so an unreachable host doesn't stall startup
+const pingTimeout = 5 * time.Second
+
+// InitDB initializes the database connection, waiting for the database to come up when it
+// refuses connections at first
+func InitDB(cfg *config.Config, logger *logrus.Logger) (*gorm.DB, error) {
 	// Configure GORM
 	gormConfig := &gorm.Config{
-		Logger: logger.Default.LogMode(getLogLevel(cfg)),
+		Logger: gormlogger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), gormlogger.Config{
+			// Slow queries are logged by the observability plugin, without bound values
+			SlowThreshold: 0,
+			LogLevel:      getLogLevel(cfg.Database.GORM.LogLevel),
+			Colorful:      cfg.Server.IsDevelopment(),
+		}),
+		PrepareStmt: cfg.Database.GORM.PrepareStmt,
+		// The connection is tested below, with retries
+		DisableAutomaticPing: true,
 		// Disable foreign key constraints during development if needed
 		// DisableForeignKeyConstraintWhenMigrating: true,
 	}
 
+	// Encrypted columns read and write with ENCRYPTION_KEYS
+	keys, err := encryption.ParseKeys(cfg.Encryption.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ENCRYPTION_KEYS: %w", err)
+	}
+	encryption.SetKeyring(keys)
+
 	// Open connection
 	db, err := gorm.Open(postgres.Open(cfg.Database.GetDSN()), gormConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Record query metrics, slow queries and per-request query count and latency
+	if err := db.Use(observability.GormPlugin{SlowThreshold: cfg.Database.GORM.SlowThreshold, Logger: logger}); err != nil {
+		return nil, fmt.Errorf("failed to register observability plugin: %w", err)
+	}
+	if err := db.Use(optimistic.Plugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register optimistic locking plugin: %w", err)
+	}
+
 	// Get underlying SQL DB instance to configure connection pool
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -33,26 +72,136 @@ func InitDB(cfg *config.Config) (*gorm.DB, error) {
 	}
 
 	// Set connection pool settings
-	setConnectionPoolSettings(sqlDB)
+	setConnectionPoolSettings(sqlDB, cfg.Database.Connections)
+
+	if len(cfg.Database.Replicas) > 0 {
+		if err := registerReplicas(db, cfg); err != nil {
+			return nil, err
+		}
+	}
 
 	// Test connection
-	if err := sqlDB.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+	if err := waitForDB(sqlDB, cfg.Database, logger); err != nil {
+		return nil, err
 	}
 
 	return db, nil
 }
 
-// getLogLevel returns the appropriate GORM log level based on server mode
-func getLogLevel(cfg *config.Config) logger.LogLevel {
-	if cfg.Server.IsDevelopment() {
-		return logger.Info
+// waitForDB pings the database until it answers or DB_CONNECT_ATTEMPTS is used up. Postgres often
+// starts after the API (docker-compose, Kubernetes), so failed attempts are retried after
+// DB_CONNECT_BACKOFF, doubling up to DB_CONNECT_MAX_BACKOFF.
+func waitForDB(sqlDB *sql.DB, cfg config.DatabaseConfig, logger *logrus.Logger) error {
+	retry := cfg.Retry
+	backoff := min(retry.Backoff, retry.MaxBackoff)
+
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+		err := sqlDB.PingContext(ctx)
+		cancel()
+		if err == nil {
+			if attempt > 1 {
+				logger.Infof("Database %s:%s is ready after %d attempts", cfg.Host, cfg.Port, attempt)
+			}
+			return nil
+		}
+		if attempt >= retry.Attempts {
+			return fmt.Errorf("failed to ping database after %d attempts: %w", attempt, err)
+		}
+
+		delay := jitter(backoff)
+		logger.Warnf("Database %s:%s is not ready (attempt %d/%d): %v; retrying in %s",
+			cfg.Host, cfg.Port, attempt, retry.Attempts, err, delay.Round(time.Millisecond))
+		time.Sleep(delay)
+		backoff = min(backoff*2, retry.MaxBackoff)
+	}
+}
+
+// jitter picks a delay in [d/2, d] so instances started together don't retry in lockstep
+func jitter(d time.Duration) time.Duration {
+	return d/2 + rand.N(d/2+1)
+}
+
+// registerReplicas sends reads to the DB_READ_REPLICAS and keeps writes, transactions and SELECT ... FOR UPDATE
+// on the primary. Reads with a context marked by replica.WithPrimary, or run through replica.Primary, stay on
+// the primary too. Replicas get the same pool settings as the primary.
+func registerReplicas(db *gorm.DB, cfg *config.Config) error {
+	// The replica pools are opened here rather than by dbresolver, so Pools can report them
+	dialectors := make([]gorm.Dialector, len(cfg.Database.Replicas))
+	pools := make(replicaPools, len(cfg.Database.Replicas))
+	for i, dsn := range cfg.Database.GetReplicaDSNs() {
+		sqlDB, err := sql.Open("pgx", dsn)
+		if err != nil {
+			return fmt.Errorf("failed to open read replica %s: %w", cfg.Database.Replicas[i], err)
+		}
+		dialectors[i] = postgres.New(postgres.Config{Conn: sqlDB})
+		pools[i] = pool.Source{Name: cfg.Database.DBName + "@" + cfg.Database.Replicas[i], DB: sqlDB}
+	}
+
+	pool := cfg.Database.Connections
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas:          dialectors,
+		Policy:            dbresolver.RoundRobinPolicy(),
+		TraceResolverMode: cfg.Database.GORM.LogLevel == "info",
+	}).
+		SetMaxOpenConns(pool.MaxOpen).
+		SetMaxIdleConns(pool.MaxIdle).
+		SetConnMaxLifetime(pool.MaxLifetime).
+		SetConnMaxIdleTime(pool.MaxIdleTime)
+
+	if err := db.Use(resolver); err != nil {
+		return fmt.Errorf("failed to connect to read replicas: %w", err)
+	}
+	if err := db.Use(replica.Plugin{}); err != nil {
+		return fmt.Errorf("failed to register replica plugin: %w", err)
+	}
+	return db.Use(pools)
+}
+
+// replicaPools keeps the read replica pools on the gorm.DB, as a plugin, for Pools
+type replicaPools []pool.Source
+
+// Name implements gorm.Plugin
+func (replicaPools) Name() string {
+	return "database:replica_pools"
+}
+
+// Initialize implements gorm.Plugin
+func (replicaPools) Initialize(*gorm.DB) error {
+	return nil
+}
+
+// Pools returns the connection pools of db: the primary, named after DB_NAME, then each read
+// replica (DB_NAME@host). Tenant schema pools are not included.
+func Pools(db *gorm.DB, cfg *config.Config) ([]pool.Source, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database instance: %w", err)
+	}
+
+	sources := []pool.Source{{Name: cfg.Database.DBName, DB: sqlDB}}
+	if replicas, ok := db.Config.Plugins[replicaPools{}.Name()].(replicaPools); ok {
+		sources = append(sources, replicas...)
+	}
+	return sources, nil
+}
+
+// getLogLevel maps DB_LOG_LEVEL to a GORM log level
+func getLogLevel(level string) gormlogger.LogLevel {
+	switch level {
+	case "info":
+		return gormlogger.Info
+	case "warn":
+		return gormlogger.Warn
+	case "error":
+		return gormlogger.Error
+	default:
+		return gormlogger.Silent
 	}
-	return logger.Silent
 }
 
 // setConnectionPoolSettings configures the database connection pool
-func setConnectionPoolSettings(sqlDB any) {
+func setConnectionPoolSettings(sqlDB any, pool config.ConnectionPoolConfig) {
 	// Type assertion to access *sql.DB methods
 	type db interface {
 		SetMaxIdleConns(n int)
@@ -63,16 +212,16 @@ func setConnectionPoolSettings(sqlDB any) {
 
 	if db, ok := sqlDB.(db); ok {
 		// SetMaxIdleConns sets the maximum number of connections in the idle connection pool
-		db.SetMaxIdleConns(10)
+		db.SetMaxIdleConns(pool.MaxIdle)
 
 		// SetMaxOpenConns sets the maximum number of open connections to the database
-		db.SetMaxOpenConns(100)
+		db.SetMaxOpenConns(pool.MaxOpen)
 
 		// SetConnMaxLifetime sets the maximum amount of time a connection may be reused
-		db.SetConnMaxLifetime(1 * time.Hour)
+		db.SetConnMaxLifetime(pool.MaxLifetime)
 
 		// SetConnMaxIdleTime sets the maximum amount of time a connection may be idle
-		db.SetConnMaxIdleTime(10 * time.Minute)
+		db.SetConnMaxIdleTime(pool.MaxIdleTime)
 	}
 }
 
@@ -0,0 +1,137 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// prefix marks encrypted values, stored as enc:<key id>:<base64 nonce+ciphertext>. Values without
+// it are plaintext written before encryption was enabled; they are read as is.
+const prefix = "enc:"
+
+// keyIDPattern limits key IDs to short identifiers, so they can't contain the separator
+var keyIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,32}$`)
+
+var (
+	// ErrNoKeys is returned when reading an encrypted value while ENCRYPTION_KEYS is empty
+	ErrNoKeys = errors.New("no encryption keys configured")
+	// ErrUnknownKey is returned for values encrypted with a key that is no longer in ENCRYPTION_KEYS
+	ErrUnknownKey = errors.New("value encrypted with an unknown key")
+)
+
+// Keyring holds the AES-256-GCM keys of ENCRYPTION_KEYS. The first key encrypts new values; the
+// others only decrypt values written before the last rotation.
+type Keyring struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// ParseKeys builds a keyring from id:base64 entries (32-byte keys), primary first. No entries
+// give a nil keyring, which leaves values in plaintext.
+func ParseKeys(entries []string) (*Keyring, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	k := &Keyring{keys: make(map[string]cipher.AEAD, len(entries))}
+	for _, entry := range entries {
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("invalid key entry, want id:base64key with a 1-32 character [a-zA-Z0-9_-] id")
+		}
+		if _, exists := k.keys[id]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("key %q must be 32 bytes, base64-encoded (openssl rand -base64 32)", id)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		if k.primary == "" {
+			k.primary = id
+		}
+		k.keys[id] = aead
+	}
+	return k, nil
+}
+
+// Primary returns the ID of the key new values are encrypted with
+func (k *Keyring) Primary() string {
+	return k.primary
+}
+
+// Encrypt seals plaintext with the primary key; the key ID is authenticated along with it
+func (k *Keyring) Encrypt(plaintext []byte) (string, error) {
+	aead := k.keys[k.primary]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(k.primary))
+	return prefix + k.primary + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value written by Encrypt with whichever key sealed it; plaintext values are
+// returned as is
+func (k *Keyring) Decrypt(value string) ([]byte, error) {
+	rest, encrypted := strings.CutPrefix(value, prefix)
+	if !encrypted {
+		return []byte(value), nil
+	}
+	if k == nil {
+		return nil, ErrNoKeys
+	}
+
+	id, encoded, _ := strings.Cut(rest, ":")
+	aead, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, id)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("malformed encrypted value")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value with key %q: %w", id, err)
+	}
+	return plaintext, nil
+}
+
+// keyring is the process-wide keyring used by the column types
+var keyring atomic.Pointer[Keyring]
+
+// SetKeyring installs the keyring used by EncryptedString and EncryptedJSON; call it at startup,
+// before the database is used. A nil keyring stores new values in plaintext.
+func SetKeyring(k *Keyring) {
+	keyring.Store(k)
+}
+
+// Default returns the keyring installed with SetKeyring
+func Default() *Keyring {
+	return keyring.Load()
+}
+
+// seal encrypts plaintext with the installed keyring, or keeps it when none is installed
+func seal(plaintext []byte) (string, error) {
+	k := Default()
+	if k == nil {
+		return string(plaintext), nil
+	}
+	return k.Encrypt(plaintext)
+}
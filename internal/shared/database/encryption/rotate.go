@@ -0,0 +1,97 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// columnType is the interface implemented by the encrypted column types
+var columnType = reflect.TypeFor[column]()
+
+// Column is an encrypted column of a table
+type Column struct {
+	Table      string
+	PrimaryKey string
+	Name       string
+}
+
+// Columns lists the EncryptedString and EncryptedJSON columns of models
+func Columns(db *gorm.DB, models ...any) ([]Column, error) {
+	var columns []Column
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, fmt.Errorf("failed to parse %T: %w", model, err)
+		}
+		if stmt.Schema.PrioritizedPrimaryField == nil {
+			return nil, fmt.Errorf("%T has no primary key", model)
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && field.FieldType.Implements(columnType) {
+				columns = append(columns, Column{
+					Table:      stmt.Schema.Table,
+					PrimaryKey: stmt.Schema.PrioritizedPrimaryField.DBName,
+					Name:       field.DBName,
+				})
+			}
+		}
+	}
+	return columns, nil
+}
+
+// Rotate re-encrypts the values of col that are plaintext or sealed with an older key, batchSize
+// rows at a time, and returns how many were rewritten. A value changed concurrently is left to the
+// write that changed it, which already uses the primary key.
+func (k *Keyring) Rotate(ctx context.Context, db *gorm.DB, col Column, batchSize int) (int64, error) {
+	if k == nil {
+		return 0, ErrNoKeys
+	}
+
+	value := clause.Column{Name: col.Name}
+	stale := clause.Expr{
+		SQL:  "? <> '' AND ? NOT LIKE ?",
+		Vars: []any{value, value, prefix + k.primary + ":%"},
+	}
+
+	var rotated int64
+	for {
+		var rows []map[string]any
+		err := db.WithContext(ctx).Table(col.Table).
+			Select(col.PrimaryKey, col.Name).Where(stale).Limit(batchSize).Find(&rows).Error
+		if err != nil {
+			return rotated, fmt.Errorf("failed to read %s.%s: %w", col.Table, col.Name, err)
+		}
+
+		for _, row := range rows {
+			old, _ := row[col.Name].(string)
+			plaintext, err := k.Decrypt(old)
+			if err != nil {
+				return rotated, fmt.Errorf("%s %v: %w", col.Table, row[col.PrimaryKey], err)
+			}
+			sealed, err := k.Encrypt(plaintext)
+			if err != nil {
+				return rotated, err
+			}
+
+			result := db.WithContext(ctx).Table(col.Table).
+				Where(clause.Eq{Column: clause.Column{Name: col.PrimaryKey}, Value: row[col.PrimaryKey]}).
+				Where(clause.Eq{Column: value, Value: old}).
+				UpdateColumn(col.Name, sealed)
+			if result.Error != nil {
+				return rotated, fmt.Errorf("failed to update %s %v: %w", col.Table, row[col.PrimaryKey], result.Error)
+			}
+			rotated += result.RowsAffected
+		}
+
+		if len(rows) < batchSize {
+			return rotated, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return rotated, err
+		}
+	}
+}
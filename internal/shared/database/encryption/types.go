@@ -0,0 +1,114 @@
+package encryption
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// column is implemented by the encrypted column types; Columns uses it to find them in models
+type column interface {
+	encryptedColumn()
+}
+
+// EncryptedString is a string stored AES-GCM encrypted in a text column. It reads and marshals to
+// JSON as the plain string, so only the database sees the ciphertext. Empty strings are stored
+// empty.
+type EncryptedString string
+
+func (EncryptedString) encryptedColumn() {}
+
+// GormDataType implements schema.GormDataTypeInterface
+func (EncryptedString) GormDataType() string {
+	return "text"
+}
+
+// Value implements driver.Valuer
+func (s EncryptedString) Value() (driver.Value, error) {
+	if s == "" {
+		return "", nil
+	}
+	return seal([]byte(s))
+}
+
+// Scan implements sql.Scanner
+func (s *EncryptedString) Scan(src any) error {
+	value, err := scanText(src)
+	if err != nil {
+		return err
+	}
+	plaintext, err := Default().Decrypt(value)
+	if err != nil {
+		return err
+	}
+	*s = EncryptedString(plaintext)
+	return nil
+}
+
+// EncryptedJSON stores Data as AES-GCM encrypted JSON in a text column. It marshals to JSON as Data.
+// A NULL column reads as the zero Data.
+type EncryptedJSON[T any] struct {
+	Data T
+}
+
+func (EncryptedJSON[T]) encryptedColumn() {}
+
+// GormDataType implements schema.GormDataTypeInterface
+func (EncryptedJSON[T]) GormDataType() string {
+	return "text"
+}
+
+// Value implements driver.Valuer
+func (j EncryptedJSON[T]) Value() (driver.Value, error) {
+	plaintext, err := json.Marshal(j.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal encrypted JSON: %w", err)
+	}
+	return seal(plaintext)
+}
+
+// Scan implements sql.Scanner
+func (j *EncryptedJSON[T]) Scan(src any) error {
+	var zero T
+	j.Data = zero
+	if src == nil {
+		return nil
+	}
+
+	value, err := scanText(src)
+	if err != nil {
+		return err
+	}
+	plaintext, err := Default().Decrypt(value)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(plaintext, &j.Data); err != nil {
+		return fmt.Errorf("failed to unmarshal encrypted JSON: %w", err)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (j EncryptedJSON[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Data)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (j *EncryptedJSON[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &j.Data)
+}
+
+// scanText converts a text column value; NULL reads as ""
+func scanText(src any) (string, error) {
+	switch v := src.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	default:
+		return "", fmt.Errorf("unsupported type %T for an encrypted column", src)
+	}
+}
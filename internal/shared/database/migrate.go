@@ -0,0 +1,417 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"go_boilerplate/db/migrations"
+	"go_boilerplate/internal/shared/config"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	_ "github.com/jackc/pgx/v5/stdlib" // Registers the "pgx" database/sql driver
+	"github.com/sirupsen/logrus"
+)
+
+// Migration bookkeeping tables. golang-migrate only keeps the current version, so the time each
+// migration was applied is recorded next to it.
+const (
+	migrationsTable       = "schema_migrations"
+	migrationHistoryTable = "schema_migration_history"
+)
+
+// Migration is one versioned migration of the source
+type Migration struct {
+	Version   uint
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time // nil when applied before the history was recorded
+}
+
+// Step is one migration a command would run, for dry runs
+type Step struct {
+	Version uint
+	Name    string
+	Up      bool
+	SQL     string
+}
+
+// Migrator runs versioned SQL migrations and records when each was applied
+type Migrator struct {
+	m      *migrate.Migrate
+	source source.Driver
+	db     *sql.DB
+}
+
+// migrationSource opens the migrations in dir, or the ones embedded in the binary when dir is ""
+func migrationSource(dir string) (source.Driver, error) {
+	var fsys fs.FS = migrations.FS
+	if dir != "" {
+		fsys = os.DirFS(dir)
+	}
+	driver, err := iofs.New(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	return driver, nil
+}
+
+// NewMigrator returns a migrator running the migrations in dir (the embedded ones when dir is "")
+// on sqlDB. Closing the migrator closes sqlDB.
+func NewMigrator(sqlDB *sql.DB, dir string) (*Migrator, error) {
+	src, err := migrationSource(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := postgres.WithInstance(sqlDB, &postgres.Config{MigrationsTable: migrationsTable})
+	if err != nil {
+		src.Close()
+		return nil, fmt.Errorf("failed to create postgres driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "postgres", driver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration instance: %w", err)
+	}
+	return &Migrator{m: m, source: src, db: sqlDB}, nil
+}
+
+// Close releases the source and the database connection
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
+
+// Version returns the current version; ok is false when no migration was applied
+func (m *Migrator) Version() (version uint, dirty bool, ok bool, err error) {
+	version, dirty, err = m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, false, nil
+	}
+	if err != nil {
+		return 0, false, false, err
+	}
+	return version, dirty, true, nil
+}
+
+// Up applies every pending migration
+func (m *Migrator) Up() error {
+	return m.run(m.m.Up)
+}
+
+// Down reverts every applied migration
+func (m *Migrator) Down() error {
+	return m.run(m.m.Down)
+}
+
+// Steps applies n pending migrations, or reverts -n applied ones when n is negative
+func (m *Migrator) Steps(n int) error {
+	return m.run(func() error { return m.m.Steps(n) })
+}
+
+// Goto migrates up or down to version
+func (m *Migrator) Goto(version uint) error {
+	return m.run(func() error { return m.m.Migrate(version) })
+}
+
+// Force sets the version without running migrations, to recover from a dirty state
+func (m *Migrator) Force(version int) error {
+	return m.run(func() error { return m.m.Force(version) })
+}
+
+// run runs a migrate command and records the versions it applied or reverted. It returns
+// migrate.ErrNoChange when there was nothing to do.
+func (m *Migrator) run(command func() error) error {
+	before, _, hadVersion, err := m.Version()
+	if err != nil {
+		return err
+	}
+	runErr := command()
+	if errors.Is(runErr, migrate.ErrNoChange) {
+		return runErr
+	}
+	// A failed migration may still have applied the ones before it
+	if err := m.record(before, hadVersion); err != nil {
+		return errors.Join(runErr, fmt.Errorf("failed to record migration history: %w", err))
+	}
+	return runErr
+}
+
+// record removes reverted versions from the history and adds the ones applied since before
+func (m *Migrator) record(before uint, hadVersion bool) error {
+	after, _, ok, err := m.Version()
+	if err != nil {
+		return err
+	}
+	list, err := m.List()
+	if err != nil {
+		return err
+	}
+
+	if _, err := m.db.Exec(`CREATE TABLE IF NOT EXISTS ` + migrationHistoryTable + ` (
+		version    BIGINT PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`); err != nil {
+		return err
+	}
+	if !ok {
+		_, err := m.db.Exec(`DELETE FROM ` + migrationHistoryTable)
+		return err
+	}
+	if _, err := m.db.Exec(`DELETE FROM `+migrationHistoryTable+` WHERE version > $1`, after); err != nil {
+		return err
+	}
+	for _, migration := range list {
+		if migration.Version > after || (hadVersion && migration.Version <= before) {
+			continue
+		}
+		if _, err := m.db.Exec(`INSERT INTO `+migrationHistoryTable+` (version, name) VALUES ($1, $2)
+			ON CONFLICT (version) DO UPDATE SET name = EXCLUDED.name, applied_at = NOW()`,
+			migration.Version, migration.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns the migrations of the source with their applied status and time
+func (m *Migrator) List() ([]Migration, error) {
+	list, err := listMigrations(m.source)
+	if err != nil {
+		return nil, err
+	}
+	current, _, ok, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	appliedAt, err := m.history()
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		list[i].Applied = ok && list[i].Version <= current
+		if at, found := appliedAt[list[i].Version]; found && list[i].Applied {
+			list[i].AppliedAt = &at
+		}
+	}
+	return list, nil
+}
+
+// history returns the recorded application time of each version
+func (m *Migrator) history() (map[uint]time.Time, error) {
+	var exists bool
+	if err := m.db.QueryRow(`SELECT to_regclass($1) IS NOT NULL`, migrationHistoryTable).Scan(&exists); err != nil {
+		return nil, err
+	}
+	appliedAt := make(map[uint]time.Time)
+	if !exists {
+		return appliedAt, nil
+	}
+
+	rows, err := m.db.Query(`SELECT version, applied_at FROM ` + migrationHistoryTable)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		appliedAt[uint(version)] = at
+	}
+	return appliedAt, rows.Err()
+}
+
+// PlanUp returns the migrations Up (limit 0) or Steps(limit) would apply, in order
+func (m *Migrator) PlanUp(limit int) ([]Step, error) {
+	list, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []Step
+	for _, migration := range list {
+		if !migration.Applied && (limit == 0 || len(steps) < limit) {
+			steps = append(steps, Step{Version: migration.Version, Name: migration.Name, Up: true})
+		}
+	}
+	return m.readSteps(steps)
+}
+
+// PlanDown returns the migrations Down (limit 0) or Steps(-limit) would revert, in order
+func (m *Migrator) PlanDown(limit int) ([]Step, error) {
+	list, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []Step
+	for i := len(list) - 1; i >= 0; i-- {
+		if list[i].Applied && (limit == 0 || len(steps) < limit) {
+			steps = append(steps, Step{Version: list[i].Version, Name: list[i].Name})
+		}
+	}
+	return m.readSteps(steps)
+}
+
+// PlanGoto returns the migrations Goto(version) would run, in order
+func (m *Migrator) PlanGoto(version uint) ([]Step, error) {
+	list, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	var steps []Step
+	for _, migration := range list {
+		found = found || migration.Version == version
+		if !migration.Applied && migration.Version <= version {
+			steps = append(steps, Step{Version: migration.Version, Name: migration.Name, Up: true})
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("no migration with version %d", version)
+	}
+	for i := len(list) - 1; i >= 0; i-- {
+		if list[i].Applied && list[i].Version > version {
+			steps = append(steps, Step{Version: list[i].Version, Name: list[i].Name})
+		}
+	}
+	return m.readSteps(steps)
+}
+
+// readSteps loads the SQL of each step
+func (m *Migrator) readSteps(steps []Step) ([]Step, error) {
+	for i, step := range steps {
+		read := m.source.ReadDown
+		if step.Up {
+			read = m.source.ReadUp
+		}
+		body, _, err := read(step.Version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %d: %w", step.Version, err)
+		}
+		sqlText, err := io.ReadAll(body)
+		body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %d: %w", step.Version, err)
+		}
+		steps[i].SQL = string(sqlText)
+	}
+	return steps, nil
+}
+
+// ListMigrations returns the migrations in dir (the embedded ones when dir is ""), without their
+// applied status
+func ListMigrations(dir string) ([]Migration, error) {
+	src, err := migrationSource(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	return listMigrations(src)
+}
+
+// listMigrations walks the source in version order
+func listMigrations(src source.Driver) ([]Migration, error) {
+	var list []Migration
+	version, err := src.First()
+	for err == nil {
+		_, name, readErr := src.ReadUp(version)
+		if readErr != nil && !errors.Is(readErr, fs.ErrNotExist) {
+			return nil, readErr
+		}
+		list = append(list, Migration{Version: version, Name: name})
+		version, err = src.Next(version)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return list, nil
+}
+
+// openMigrator opens a migrator over the embedded migrations on its own connection, so closing it
+// leaves the application pool alone
+func openMigrator(cfg *config.Config) (*Migrator, error) {
+	sqlDB, err := sql.Open("pgx", cfg.Database.GetDSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migration connection: %w", err)
+	}
+
+	m, err := NewMigrator(sqlDB, "")
+	if err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// MigrateUp applies the pending embedded migrations (DB_MIGRATE_ON_START). The connection is
+// held for the duration of the run; golang-migrate takes an advisory lock, so instances starting
+// together apply each migration once.
+func MigrateUp(cfg *config.Config, logger *logrus.Logger) error {
+	m, err := openMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	logger.Info("Applying SQL migrations...")
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	version, dirty, _, err := m.Version()
+	if err != nil {
+		return fmt.Errorf("failed to read migration version: %w", err)
+	}
+	logger.WithFields(logrus.Fields{"version": version, "dirty": dirty}).Info("SQL migrations are up to date")
+	return nil
+}
+
+// CheckMigrations fails when an embedded migration is pending or the schema version is dirty, so
+// an instance doesn't serve against a schema older than its code (MIGRATION_MODE=sql)
+func CheckMigrations(cfg *config.Config) error {
+	m, err := openMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	version, dirty, _, err := m.Version()
+	if err != nil {
+		return fmt.Errorf("failed to read migration version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("migration %d is dirty; fix the schema and run cmd/migrate -force", version)
+	}
+
+	list, err := m.List()
+	if err != nil {
+		return err
+	}
+	var pending []string
+	for _, migration := range list {
+		if !migration.Applied {
+			pending = append(pending, fmt.Sprintf("%d_%s", migration.Version, migration.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%d pending migrations (%s); run cmd/migrate -up or set DB_MIGRATE_ON_START=true",
+			len(pending), strings.Join(pending, ", "))
+	}
+	return nil
+}
@@ -1,11 +1,13 @@
 package database
 
 import (
+	"context"
 	"fmt"
 
 	roleModule "go_boilerplate/internal/modules/role"
 	userModule "go_boilerplate/internal/modules/user"
 	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/database/replica"
 	"go_boilerplate/internal/shared/utils"
 
 	"github.com/google/uuid"
@@ -31,9 +33,9 @@ func DropAllTables(db *gorm.DB, logger *logrus.Logger) error {
 	logger.Warn("Dropping all tables...")
 
 	if err := db.Migrator().DropTable(
-		// Add all table names here
-		// "users",
-		// "refresh_tokens",
+	// Add all table names here
+	// "users",
+	// "refresh_tokens",
 	); err != nil {
 		return fmt.Errorf("failed to drop tables: %w", err)
 	}
@@ -78,6 +80,32 @@ func RenameTables(db *gorm.DB, logger *logrus.Logger) error {
 	return nil
 }
 
+// MigrateUserRoles moves the legacy single m_users.role_id column into the m_user_roles join table
+// AutoMigrate creates the join table but never drops columns, and the NOT NULL role_id would
+// reject new users, so development databases are converted here. Production uses the SQL migration.
+func MigrateUserRoles(db *gorm.DB, logger *logrus.Logger) error {
+	if !db.Migrator().HasColumn(&userModule.User{}, "role_id") {
+		return nil
+	}
+
+	logger.Info("Migrating m_users.role_id to m_user_roles...")
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`INSERT INTO m_user_roles (user_id, role_id)
+			SELECT id, role_id FROM m_users WHERE role_id IS NOT NULL
+			ON CONFLICT DO NOTHING`).Error; err != nil {
+			return fmt.Errorf("failed to copy user roles: %w", err)
+		}
+
+		if err := tx.Migrator().DropColumn(&userModule.User{}, "role_id"); err != nil {
+			return fmt.Errorf("failed to drop m_users.role_id: %w", err)
+		}
+
+		logger.Info("✓ User roles migrated to m_user_roles")
+		return nil
+	})
+}
+
 // CreateIndexes creates indexes for optimized queries
 func CreateIndexes(db *gorm.DB, logger *logrus.Logger) error {
 	logger.Info("Creating database indexes...")
@@ -91,29 +119,14 @@ func CreateIndexes(db *gorm.DB, logger *logrus.Logger) error {
 	return nil
 }
 
-// SeedDatabase seeds the database with initial data
-func SeedDatabase(db *gorm.DB, cfg *config.Config, logger *logrus.Logger) error {
-	logger.Info("Seeding database...")
-
-	// Add your seed data here
-	// Example: Create default admin user
-	// adminUser := &user.User{
-	// 	Name:  "Admin",
-	// 	Email: "admin@example.com",
-	// }
-	// if err := db.FirstOrCreate(adminUser, user.User{Email: adminUser.Email}).Error; err != nil {
-	// 	return fmt.Errorf("failed to seed admin user: %w", err)
-	// }
-
-	logger.Info("Database seeded successfully")
-	return nil
-}
-
 // SeedSuperAdmin creates a default SuperAdmin user if it doesn't exist
-// This should be called AFTER roles are seeded
-func SeedSuperAdmin(db *gorm.DB, cfg *config.Config, logger *logrus.Logger) error {
+// This should be called AFTER roles are seeded (the "superadmin" seeder runs after "roles")
+func SeedSuperAdmin(ctx context.Context, db *gorm.DB, cfg *config.Config, logger *logrus.Logger) error {
 	logger.Info("Checking for SuperAdmin user...")
 
+	// Read the roles just seeded from the primary; replicas may not have them yet
+	db = db.WithContext(replica.WithPrimary(ctx))
+
 	// Get SuperAdmin role
 	var superAdminRole roleModule.Role
 	if err := db.Where("slug = ?", "super_admin").First(&superAdminRole).Error; err != nil {
@@ -131,13 +144,17 @@ func SeedSuperAdmin(db *gorm.DB, cfg *config.Config, logger *logrus.Logger) erro
 
 		existingUser.Password = hashedPassword
 		existingUser.Name = cfg.SuperAdmin.Name
-		existingUser.RoleID = superAdminRole.ID
 		existingUser.IsVerified = true
 
 		if err := db.Save(&existingUser).Error; err != nil {
 			return fmt.Errorf("failed to update superadmin user: %w", err)
 		}
 
+		// Make sure the account keeps the super_admin role (other roles are left untouched)
+		if err := db.Model(&existingUser).Omit("Roles.*").Association("Roles").Append(&superAdminRole); err != nil {
+			return fmt.Errorf("failed to assign super_admin role: %w", err)
+		}
+
 		logger.Info("✓ SuperAdmin user updated successfully")
 		logger.Infof("  Email: %s", cfg.SuperAdmin.Email)
 		logger.Warn("  ⚠️  Please change the default password after first login!")
@@ -152,15 +169,15 @@ func SeedSuperAdmin(db *gorm.DB, cfg *config.Config, logger *logrus.Logger) erro
 
 	// Create SuperAdmin user
 	superAdminUser := &userModule.User{
-		ID:       uuid.New(),
-		Name:     cfg.SuperAdmin.Name,
-		Email:    cfg.SuperAdmin.Email,
-		Password: hashedPassword,
-		RoleID:   superAdminRole.ID,
+		ID:         uuid.New(),
+		Name:       cfg.SuperAdmin.Name,
+		Email:      cfg.SuperAdmin.Email,
+		Password:   hashedPassword,
+		Roles:      []roleModule.Role{superAdminRole},
 		IsVerified: true,
 	}
 
-	if err := db.Create(superAdminUser).Error; err != nil {
+	if err := db.Omit("Roles.*").Create(superAdminUser).Error; err != nil {
 		return fmt.Errorf("failed to create superadmin user: %w", err)
 	}
 
@@ -0,0 +1,118 @@
+package optimistic
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"go_boilerplate/internal/shared/apperror"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Column is the version column of optimistically locked tables; models opt in with a field
+//
+//	Version int `json:"version" gorm:"not null;default:1"`
+const Column = "version"
+
+const (
+	fieldName  = "Version"
+	skipKey    = "optimistic:skip"
+	checkedKey = "optimistic:checked"
+)
+
+// ErrConflict is the kind of every ConflictError, rendered as 409 version_conflict
+var ErrConflict = apperror.New(apperror.ErrConflict, "the record was modified by someone else; reload it and try again").WithCode("version_conflict")
+
+// ConflictError is returned by an update whose record no longer has the expected version: it was
+// updated (or deleted) since it was read
+type ConflictError struct {
+	Table   string
+	Version int // Version the update expected
+}
+
+// Error implements the error interface
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s: version %d is outdated: %s", e.Table, e.Version, ErrConflict.Message)
+}
+
+// Unwrap makes errors.Is(err, ErrConflict) match and lets handlers render it as a 409
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// Skip disables the version check and increment for updates run on the returned handle, e.g.
+// maintenance jobs that must not fail on concurrent edits
+func Skip(db *gorm.DB) *gorm.DB {
+	return db.Set(skipKey, true)
+}
+
+// Plugin checks and increments the version of optimistically locked records: updating a record
+// loaded with version N adds WHERE version = N and sets version = N+1, and an update matching no
+// row fails with a *ConflictError. Updates through a model without a loaded version (e.g.
+// db.Model(&User{}).Where(...).Update(...), used for last_seen_at and other bookkeeping columns)
+// are neither checked nor counted as a new version.
+type Plugin struct{}
+
+// Name implements gorm.Plugin
+func (Plugin) Name() string {
+	return "optimistic_lock"
+}
+
+// Initialize implements gorm.Plugin by registering callbacks around updates
+func (p Plugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+
+	if err := callbacks.Update().Before("gorm:update").Register("optimistic:before_update", p.before); err != nil {
+		return err
+	}
+	return callbacks.Update().After("gorm:update").Register("optimistic:after_update", p.after)
+}
+
+// before adds the version condition and bumps the version of the record being saved
+func (Plugin) before(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil || stmt.ReflectValue.Kind() != reflect.Struct {
+		return
+	}
+	if skip, _ := db.Get(skipKey); skip == true {
+		return
+	}
+	field := stmt.Schema.LookUpField(fieldName)
+	if field == nil || field.DBName != Column {
+		return
+	}
+	value, zero := field.ValueOf(stmt.Context, stmt.ReflectValue)
+	version, ok := value.(int)
+	if zero || !ok {
+		return
+	}
+
+	stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: Column}, Value: version},
+	}})
+	// Updates limited with Select must still write the new version
+	if len(stmt.Selects) > 0 && !slices.Contains(stmt.Selects, "*") && !slices.Contains(stmt.Selects, Column) {
+		stmt.Selects = append(stmt.Selects, Column)
+	}
+	stmt.SetColumn(fieldName, version+1, true)
+	db.InstanceSet(checkedKey, version)
+}
+
+// after turns an update that matched no row into a conflict. The in-memory version is restored
+// when the update didn't go through, so the record can be retried or reported as it was read.
+func (Plugin) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(checkedKey)
+	if !ok || db.DryRun || (db.Error == nil && db.Statement.RowsAffected > 0) {
+		return
+	}
+	version := value.(int)
+
+	if field := db.Statement.Schema.LookUpField(fieldName); field != nil && db.Statement.ReflectValue.CanAddr() {
+		_ = field.Set(db.Statement.Context, db.Statement.ReflectValue, version)
+	}
+	if db.Error == nil {
+		db.AddError(&ConflictError{Table: db.Statement.Table, Version: version})
+	}
+}
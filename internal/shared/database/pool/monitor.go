@@ -0,0 +1,140 @@
+package pool
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/notify"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Snapshot is the connection pool state observed during the last sampling interval
+type Snapshot struct {
+	OpenConnections int           `json:"open_connections"`
+	InUse           int           `json:"in_use"`
+	Idle            int           `json:"idle"`
+	MaxOpen         int           `json:"max_open"`
+	Waits           int64         `json:"waits"`     // Connection requests that had to queue during the interval
+	AvgWait         time.Duration `json:"avg_wait"`  // Mean time those requests spent queued
+	Saturated       bool          `json:"saturated"` // AvgWait exceeded the configured threshold
+}
+
+// Monitor samples sql.DB pool statistics, reports queue depth and wait times,
+// alerts administrators when callers queue for connections too long and exposes
+// a saturation signal used by the load shedding middleware
+type Monitor struct {
+	db        *sql.DB
+	cfg       config.PoolConfig
+	notifier  notify.Notifier
+	logger    *logrus.Logger
+	saturated atomic.Bool
+
+	mu        sync.Mutex
+	last      sql.DBStats
+	snapshot  Snapshot
+	lastAlert time.Time
+}
+
+// NewMonitor creates a new pool monitor
+func NewMonitor(db *sql.DB, cfg config.PoolConfig, notifier notify.Notifier, logger *logrus.Logger) *Monitor {
+	return &Monitor{
+		db:       db,
+		cfg:      cfg,
+		notifier: notifier,
+		logger:   logger,
+		last:     db.Stats(),
+	}
+}
+
+// Saturated reports whether the pool was saturated at the last sample
+func (m *Monitor) Saturated() bool {
+	return m.saturated.Load()
+}
+
+// Snapshot returns the pool state observed at the last sample
+func (m *Monitor) Snapshot() Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.snapshot
+}
+
+// Run samples the pool once; it is meant to be scheduled every cfg.Interval
+func (m *Monitor) Run(ctx context.Context) error {
+	stats := m.db.Stats()
+
+	m.mu.Lock()
+	waits := stats.WaitCount - m.last.WaitCount
+	waited := stats.WaitDuration - m.last.WaitDuration
+	m.last = stats
+
+	var avgWait time.Duration
+	if waits > 0 {
+		avgWait = waited / time.Duration(waits)
+	}
+
+	snapshot := Snapshot{
+		OpenConnections: stats.OpenConnections,
+		InUse:           stats.InUse,
+		Idle:            stats.Idle,
+		MaxOpen:         stats.MaxOpenConnections,
+		Waits:           waits,
+		AvgWait:         avgWait,
+		Saturated:       waits > 0 && avgWait > m.cfg.MaxAvgWait,
+	}
+	m.snapshot = snapshot
+	m.mu.Unlock()
+
+	wasSaturated := m.saturated.Swap(snapshot.Saturated)
+
+	entry := m.logger.WithFields(logrus.Fields{
+		"open":        snapshot.OpenConnections,
+		"in_use":      snapshot.InUse,
+		"idle":        snapshot.Idle,
+		"max_open":    snapshot.MaxOpen,
+		"waits":       snapshot.Waits,
+		"avg_wait_ms": float64(snapshot.AvgWait.Microseconds()) / 1000,
+	})
+
+	switch {
+	case snapshot.Saturated:
+		entry.Warn("Database connection pool saturated")
+		m.alert(snapshot)
+	case wasSaturated:
+		entry.Info("Database connection pool recovered")
+	default:
+		entry.Debug("Database connection pool sampled")
+	}
+
+	return nil
+}
+
+// alert notifies administrators, at most once per cooldown
+func (m *Monitor) alert(snapshot Snapshot) {
+	m.mu.Lock()
+	if time.Since(m.lastAlert) < m.cfg.AlertCooldown {
+		m.mu.Unlock()
+		return
+	}
+	m.lastAlert = time.Now()
+	m.mu.Unlock()
+
+	err := m.notifier.Notify(notify.Notification{
+		Title:   "Database connection pool saturated",
+		Message: fmt.Sprintf("%d requests waited %s on average for a database connection (threshold %s). Low-priority traffic is being shed.", snapshot.Waits, snapshot.AvgWait.Round(time.Millisecond), m.cfg.MaxAvgWait),
+		Level:   notify.LevelCritical,
+		Fields: map[string]string{
+			"in_use":   fmt.Sprintf("%d/%d", snapshot.InUse, snapshot.MaxOpen),
+			"waits":    fmt.Sprintf("%d", snapshot.Waits),
+			"avg_wait": snapshot.AvgWait.Round(time.Millisecond).String(),
+		},
+	})
+	if err != nil {
+		m.logger.Warnf("Failed to send pool saturation alert: %v", err)
+	}
+}
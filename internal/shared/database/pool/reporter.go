@@ -0,0 +1,85 @@
+package pool
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"go_boilerplate/internal/shared/observability"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Source is a database/sql connection pool and the name it is reported under
+type Source struct {
+	Name string // The database name for the primary, name@host for a read replica
+	DB   *sql.DB
+}
+
+// Reporter logs, at info, the statistics of each connection pool and of the statements run
+// since the previous report, to help size DB_MAX_OPEN_CONNS and DB_MAX_IDLE_CONNS under load.
+// Counters (waits, closed connections, queries) cover the interval; the rest is the current state.
+type Reporter struct {
+	sources []Source
+	logger  *logrus.Logger
+
+	mu          sync.Mutex
+	last        []sql.DBStats
+	lastQueries observability.QueryStats
+}
+
+// NewReporter creates a reporter for sources
+func NewReporter(sources []Source, logger *logrus.Logger) *Reporter {
+	last := make([]sql.DBStats, len(sources))
+	for i, source := range sources {
+		last[i] = source.DB.Stats()
+	}
+	return &Reporter{
+		sources:     sources,
+		logger:      logger,
+		last:        last,
+		lastQueries: observability.Queries(),
+	}
+}
+
+// Run logs one report; it is meant to be scheduled every POOL_STATS_LOG_INTERVAL
+func (r *Reporter) Run(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, source := range r.sources {
+		stats := source.DB.Stats()
+		last := r.last[i]
+		r.last[i] = stats
+
+		r.logger.WithFields(logrus.Fields{
+			"pool":             source.Name,
+			"open":             stats.OpenConnections,
+			"in_use":           stats.InUse,
+			"idle":             stats.Idle,
+			"max_open":         stats.MaxOpenConnections,
+			"waits":            stats.WaitCount - last.WaitCount,
+			"wait_ms":          (stats.WaitDuration - last.WaitDuration).Milliseconds(),
+			"max_idle_closed":  stats.MaxIdleClosed - last.MaxIdleClosed,
+			"idle_time_closed": stats.MaxIdleTimeClosed - last.MaxIdleTimeClosed,
+			"lifetime_closed":  stats.MaxLifetimeClosed - last.MaxLifetimeClosed,
+		}).Info("Database connection pool stats")
+	}
+
+	queries := observability.Queries()
+	last := r.lastQueries
+	r.lastQueries = queries
+
+	var avgMs float64
+	if n := queries.Queries - last.Queries; n > 0 {
+		avgMs = float64((queries.Duration - last.Duration).Microseconds()) / 1000 / float64(n)
+	}
+	r.logger.WithFields(logrus.Fields{
+		"queries": queries.Queries - last.Queries,
+		"errors":  queries.Errors - last.Errors,
+		"slow":    queries.Slow - last.Slow,
+		"avg_ms":  avgMs,
+	}).Info("Database query stats")
+
+	return nil
+}
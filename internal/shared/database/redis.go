@@ -2,31 +2,177 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/observability"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 )
 
-// InitRedis initializes the Redis client
-func InitRedis(cfg *config.Config, logger *logrus.Logger) (*redis.Client, error) {
+// ErrRedisUnavailable is returned for commands issued while the RedisMonitor marks Redis down, so
+// callers fall back at once instead of waiting for a connection timeout
+var ErrRedisUnavailable = errors.New("redis is unavailable")
+
+// probeCommands still reach Redis while it is marked down: PING checks it, the others are sent by
+// go-redis when it opens a connection
+var probeCommands = map[string]bool{"ping": true, "hello": true, "auth": true, "select": true, "client": true}
+
+// NewRedisClient creates the Redis client without connecting; go-redis dials on demand and
+// reconnects by itself after an outage
+func NewRedisClient(cfg *config.Config) *redis.Client {
 	rdb := redis.NewClient(&redis.Options{
 		Addr:     fmt.Sprintf("%s:%s", cfg.Redis.Host, cfg.Redis.Port),
 		Password: cfg.Redis.Password,
 		DB:       cfg.Redis.DB,
 	})
 
+	// Record command latency and cache hits on request wide events
+	rdb.AddHook(observability.RedisHook{})
+	return rdb
+}
+
+// InitRedis initializes the Redis client, failing when Redis doesn't answer
+func InitRedis(cfg *config.Config, logger *logrus.Logger) (*redis.Client, error) {
+	rdb := NewRedisClient(cfg)
+
 	// Test connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := rdb.Ping(ctx).Err(); err != nil {
+		_ = rdb.Close()
 		return nil, fmt.Errorf("failed to connect to redis: %w", err)
 	}
 
 	logger.Info("✓ Connected to Redis")
 	return rdb, nil
 }
+
+// RedisMonitor tracks whether Redis answers, so the API keeps serving through an outage. While
+// Redis is down every command but PING and the connection handshake fails at once with ErrRedisUnavailable, and the
+// Redis-backed features degrade as they do on any Redis error (caches fall back to memory or the
+// database, locks and the access token denylist are skipped). Check pings Redis and marks it up
+// again, running the OnRecover callbacks.
+type RedisMonitor struct {
+	client *redis.Client
+	logger *logrus.Logger
+	down   atomic.Bool
+
+	mu        sync.Mutex
+	onRecover []func(ctx context.Context)
+}
+
+// NewRedisMonitor starts monitoring client, which counts as available until a command or Check fails
+func NewRedisMonitor(client *redis.Client, logger *logrus.Logger) *RedisMonitor {
+	m := &RedisMonitor{client: client, logger: logger}
+	client.AddHook(m)
+	return m
+}
+
+// Available reports whether Redis answered the last command or check
+func (m *RedisMonitor) Available() bool {
+	return !m.down.Load()
+}
+
+// OnRecover registers fn to run whenever Redis becomes available again, e.g. to drop cached
+// values whose invalidation never reached Redis during the outage
+func (m *RedisMonitor) OnRecover(fn func(ctx context.Context)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onRecover = append(m.onRecover, fn)
+}
+
+// Check pings Redis and updates its availability, returning the ping error
+func (m *RedisMonitor) Check(ctx context.Context) error {
+	err := m.client.Ping(ctx).Err()
+	if err != nil {
+		m.markDown(err)
+		return err
+	}
+	if m.down.CompareAndSwap(true, false) {
+		m.logger.Info("✓ Redis is available again")
+		m.mu.Lock()
+		callbacks := m.onRecover
+		m.mu.Unlock()
+		for _, fn := range callbacks {
+			fn(ctx)
+		}
+	}
+	return nil
+}
+
+// Run checks Redis as a scheduled job; outages are logged when they start, not on every check
+func (m *RedisMonitor) Run(ctx context.Context) error {
+	_ = m.Check(ctx)
+	return nil
+}
+
+// markDown marks Redis unavailable, logging the outage once
+func (m *RedisMonitor) markDown(err error) {
+	if m.down.CompareAndSwap(false, true) {
+		m.logger.Warnf("Redis is unavailable, running degraded until it answers again (caches in memory, no locks or token denylist): %v", err)
+	}
+}
+
+// DialHook implements redis.Hook
+func (m *RedisMonitor) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements redis.Hook
+func (m *RedisMonitor) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if m.down.Load() && !probeCommands[cmd.Name()] {
+			cmd.SetErr(ErrRedisUnavailable)
+			return ErrRedisUnavailable
+		}
+		err := next(ctx, cmd)
+		m.observe(ctx, err)
+		return err
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook
+func (m *RedisMonitor) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if m.down.Load() && !onlyProbes(cmds) {
+			for _, cmd := range cmds {
+				cmd.SetErr(ErrRedisUnavailable)
+			}
+			return ErrRedisUnavailable
+		}
+		err := next(ctx, cmds)
+		m.observe(ctx, err)
+		return err
+	}
+}
+
+// onlyProbes reports whether cmds are all probe commands (the pipelined connection handshake)
+func onlyProbes(cmds []redis.Cmder) bool {
+	for _, cmd := range cmds {
+		if !probeCommands[cmd.Name()] {
+			return false
+		}
+	}
+	return true
+}
+
+// observe marks Redis down after a connection failure; errors caused by the caller's own
+// deadline or cancellation say nothing about Redis
+func (m *RedisMonitor) observe(ctx context.Context, err error) {
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, redis.ErrPoolTimeout) {
+		m.markDown(err)
+	}
+}
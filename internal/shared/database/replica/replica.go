@@ -0,0 +1,66 @@
+package replica
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+type primaryKey struct{}
+
+// WithPrimary marks ctx so every query run with it goes to the primary. Replicas apply writes with
+// a delay, so services use it to read back what they just wrote:
+//
+//	ctx = replica.WithPrimary(ctx)
+//	return s.GetProfileWithRole(ctx, userID)
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// UsesPrimary reports whether ctx was marked with WithPrimary
+func UsesPrimary(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
+
+// Primary sends the queries run on the returned handle to the primary
+//
+//	replica.Primary(r.db).Where(...).First(&row)
+func Primary(db *gorm.DB) *gorm.DB {
+	return db.Clauses(dbresolver.Write)
+}
+
+// Plugin routes reads whose context was marked with WithPrimary to the primary. It is registered
+// next to the dbresolver plugin, which sends every other SELECT to a replica.
+type Plugin struct{}
+
+// Name implements gorm.Plugin
+func (Plugin) Name() string {
+	return "replica:primary"
+}
+
+// Initialize implements gorm.Plugin by registering a callback on the processors dbresolver may
+// route to a replica
+func (p Plugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+
+	if err := callbacks.Query().Before("gorm:query").Register("replica:primary_query", p.route); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("replica:primary_row", p.route); err != nil {
+		return err
+	}
+	return callbacks.Raw().Before("gorm:raw").Register("replica:primary_raw", p.route)
+}
+
+// route switches the statement to the primary; dbresolver.Write re-runs the resolver itself, so
+// the order relative to the resolver callback doesn't matter
+func (Plugin) route(db *gorm.DB) {
+	if UsesPrimary(db.Statement.Context) {
+		dbresolver.Write.ModifyStatement(db.Statement)
+	}
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"go_boilerplate/internal/shared/apperror"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// pgUniqueViolationCode is the SQLSTATE of a unique constraint violation
+const pgUniqueViolationCode = "23505"
+
+// LookupError maps a missing record to the module error notFound, keeping the gorm error as its
+// cause; any other failure (connection, timeout, cancelled context) is wrapped as is, so it is
+// logged and answered as an internal error instead of a 404:
+//
+//	user, err := s.repo.FindByID(ctx, id)
+//	if err != nil {
+//		return nil, repository.LookupError(err, ErrUserNotFound, "user")
+//	}
+func LookupError(err error, notFound *apperror.Error, what string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return notFound.WithCause(err)
+	}
+	return fmt.Errorf("failed to find %s: %w", what, err)
+}
+
+// UniqueViolation returns the constraint (index) name when err is a unique constraint violation
+func UniqueViolation(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolationCode {
+		return pgErr.ConstraintName, true
+	}
+	return "", false
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go_boilerplate/internal/shared/apperror"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+var errThingNotFound = apperror.New(apperror.ErrNotFound, "thing not found").WithCode("thing_not_found")
+
+func TestLookupErrorMapsMissingRecord(t *testing.T) {
+	err := LookupError(gorm.ErrRecordNotFound, errThingNotFound, "thing")
+
+	if !errors.Is(err, errThingNotFound) {
+		t.Fatalf("errors.Is(%v, errThingNotFound) = false", err)
+	}
+	if !errors.Is(err, apperror.ErrNotFound) {
+		t.Fatalf("errors.Is(%v, apperror.ErrNotFound) = false", err)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("cause was dropped: %v", err)
+	}
+	if appErr, ok := apperror.As(err); !ok || appErr.Status != fiber.StatusNotFound || appErr.Code != "thing_not_found" {
+		t.Fatalf("apperror.As(%v) = %+v, %v", err, appErr, ok)
+	}
+}
+
+func TestLookupErrorKeepsOtherFailures(t *testing.T) {
+	for _, cause := range []error{
+		context.Canceled,
+		context.DeadlineExceeded,
+		fmt.Errorf("dial tcp: connection refused"),
+	} {
+		err := LookupError(cause, errThingNotFound, "thing")
+
+		if errors.Is(err, errThingNotFound) || errors.Is(err, apperror.ErrNotFound) {
+			t.Errorf("LookupError(%v) reports a missing thing", cause)
+		}
+		if !errors.Is(err, cause) {
+			t.Errorf("LookupError(%v) = %v, cause was dropped", cause, err)
+		}
+		if _, ok := apperror.As(err); ok {
+			t.Errorf("LookupError(%v) = %v, want an untyped (500) error", cause, err)
+		}
+	}
+}
+
+func TestUniqueViolation(t *testing.T) {
+	err := fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23505", ConstraintName: "idx_m_users_email"})
+	if constraint, ok := UniqueViolation(err); !ok || constraint != "idx_m_users_email" {
+		t.Fatalf("UniqueViolation() = %q, %v", constraint, ok)
+	}
+
+	if _, ok := UniqueViolation(&pgconn.PgError{Code: "23503"}); ok {
+		t.Fatal("foreign key violation reported as unique violation")
+	}
+	if _, ok := UniqueViolation(gorm.ErrRecordNotFound); ok {
+		t.Fatal("non-postgres error reported as unique violation")
+	}
+}
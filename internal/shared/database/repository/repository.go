@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+
+	"go_boilerplate/internal/shared/filter"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Scope narrows a query, as taken by gorm's Scopes (filter.Filter.Scope, tenant.Scope, ...)
+type Scope = func(*gorm.DB) *gorm.DB
+
+// Repository implements the data operations shared by every model T with a uuid "id" primary key
+// and a created_at column. Module repositories embed it, override what differs (e.g. omitting
+// associations on save) and add their own queries; services keep depending on the module's
+// repository interface, so they can still be tested with mocks.
+type Repository[T any] struct {
+	db *gorm.DB
+}
+
+// New creates a repository for T
+func New[T any](db *gorm.DB) Repository[T] {
+	return Repository[T]{db: db}
+}
+
+// Create inserts item
+func (r Repository[T]) Create(ctx context.Context, item *T) error {
+	return r.db.WithContext(ctx).Create(item).Error
+}
+
+// FindByID finds a record by ID; it returns gorm.ErrRecordNotFound when there is none
+func (r Repository[T]) FindByID(ctx context.Context, id uuid.UUID) (*T, error) {
+	return r.FindOne(ctx, "id = ?", id)
+}
+
+// FindOne finds the first record matching the condition, e.g. FindOne(ctx, "email = ?", email); it
+// returns gorm.ErrRecordNotFound when there is none
+func (r Repository[T]) FindOne(ctx context.Context, query any, args ...any) (*T, error) {
+	var item T
+	if err := r.db.WithContext(ctx).Where(query, args...).First(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// FindAll finds the records matching the filter, newest first, with the total count for pagination
+func (r Repository[T]) FindAll(ctx context.Context, offset, limit int, f filter.Filter) ([]T, int64, error) {
+	return r.FindPage(ctx, offset, limit, f.Scope())
+}
+
+// FindPage finds a page of the records matching scopes, newest first, with the total count. Scopes
+// apply to the count too, so preloads belong in a query of their own.
+func (r Repository[T]) FindPage(ctx context.Context, offset, limit int, scopes ...Scope) ([]T, int64, error) {
+	var items []T
+	var total int64
+	db := r.db.WithContext(ctx)
+
+	if err := db.Model(new(T)).Scopes(scopes...).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Scopes(scopes...).Offset(offset).Limit(limit).Order("created_at DESC").Find(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
+// Update saves every field of item
+func (r Repository[T]) Update(ctx context.Context, item *T) error {
+	return r.db.WithContext(ctx).Save(item).Error
+}
+
+// Delete deletes a record by ID; soft-deletable models are soft deleted
+func (r Repository[T]) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(new(T), "id = ?", id).Error
+}
+
+// ExistsByID checks if a record exists by ID
+func (r Repository[T]) ExistsByID(ctx context.Context, id uuid.UUID) (bool, error) {
+	return r.Exists(ctx, "id = ?", id)
+}
+
+// Exists checks if a record matches the condition, e.g. Exists(ctx, "slug = ?", slug)
+func (r Repository[T]) Exists(ctx context.Context, query any, args ...any) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(new(T)).Where(query, args...).Count(&count).Error
+	return count > 0, err
+}
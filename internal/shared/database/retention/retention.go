@@ -0,0 +1,165 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go_boilerplate/internal/shared/config"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Result is what a purge did to one table; in dry-run mode Rows counts the rows it would delete
+type Result struct {
+	Table     string
+	Retention time.Duration
+	Cutoff    time.Time
+	Rows      int64
+}
+
+// model is a registered soft-deletable model
+type model struct {
+	value      any
+	table      string
+	primaryKey string
+	deletedAt  string
+}
+
+// Purger permanently deletes soft-deleted rows of the models registered with it once they have
+// been deleted longer than their table's retention: TRASH_RETENTION, overridden per table by
+// TRASH_RETENTION_TABLES. Rows are deleted TRASH_PURGE_BATCH_SIZE at a time.
+type Purger struct {
+	db     *gorm.DB
+	cfg    config.TrashConfig
+	logger *logrus.Logger
+	models []model
+}
+
+// NewPurger creates a purger with no registered models
+func NewPurger(db *gorm.DB, cfg config.TrashConfig, logger *logrus.Logger) *Purger {
+	return &Purger{db: db, cfg: cfg, logger: logger}
+}
+
+// Register opts models into purging; each must have a gorm.DeletedAt field and a primary key
+func (p *Purger) Register(models ...any) error {
+	for _, value := range models {
+		stmt := &gorm.Statement{DB: p.db}
+		if err := stmt.Parse(value); err != nil {
+			return fmt.Errorf("failed to parse %T: %w", value, err)
+		}
+		if stmt.Schema.PrioritizedPrimaryField == nil {
+			return fmt.Errorf("%T has no primary key", value)
+		}
+
+		m := model{value: value, table: stmt.Schema.Table, primaryKey: stmt.Schema.PrioritizedPrimaryField.DBName}
+		for _, field := range stmt.Schema.Fields {
+			if field.FieldType == reflect.TypeFor[gorm.DeletedAt]() {
+				m.deletedAt = field.DBName
+			}
+		}
+		if m.deletedAt == "" {
+			return fmt.Errorf("%T has no gorm.DeletedAt field", value)
+		}
+		p.models = append(p.models, m)
+	}
+	return nil
+}
+
+// Retention returns how long soft-deleted rows of table are kept; 0 keeps them
+func (p *Purger) Retention(table string) time.Duration {
+	if retention, ok := p.cfg.TableRetentions[table]; ok {
+		return retention
+	}
+	return p.cfg.Retention
+}
+
+// Report counts the rows each table would lose, without deleting anything
+func (p *Purger) Report(ctx context.Context) ([]Result, error) {
+	return p.each(ctx, p.count)
+}
+
+// Purge deletes expired rows of every table
+func (p *Purger) Purge(ctx context.Context) ([]Result, error) {
+	return p.each(ctx, p.purge)
+}
+
+// Run purges expired rows, or only reports them with TRASH_PURGE_DRY_RUN; it is meant to be
+// scheduled every TRASH_PURGE_INTERVAL
+func (p *Purger) Run(ctx context.Context) error {
+	run, verb := p.Purge, "Purged"
+	if p.cfg.DryRun {
+		run, verb = p.Report, "Would purge"
+	}
+
+	results, err := run(ctx)
+	for _, result := range results {
+		if result.Rows == 0 && !p.cfg.DryRun {
+			continue
+		}
+		p.logger.WithFields(logrus.Fields{
+			"table":     result.Table,
+			"retention": result.Retention.String(),
+			"cutoff":    result.Cutoff.Format(time.RFC3339),
+			"rows":      result.Rows,
+		}).Infof("%s %d soft-deleted rows from %s", verb, result.Rows, result.Table)
+	}
+	return err
+}
+
+// each runs fn on every table with a retention; results are returned up to the first failure
+func (p *Purger) each(ctx context.Context, fn func(context.Context, model, time.Time) (int64, error)) ([]Result, error) {
+	var results []Result
+	for _, m := range p.models {
+		retention := p.Retention(m.table)
+		if retention <= 0 {
+			continue
+		}
+
+		result := Result{Table: m.table, Retention: retention, Cutoff: time.Now().Add(-retention)}
+		rows, err := fn(ctx, m, result.Cutoff)
+		result.Rows = rows
+		results = append(results, result)
+		if err != nil {
+			return results, fmt.Errorf("failed to purge %s: %w", m.table, err)
+		}
+	}
+	return results, nil
+}
+
+// expired matches rows of m soft-deleted before cutoff
+func (m model) expired(cutoff time.Time) clause.Expression {
+	return clause.Lt{Column: clause.Column{Name: m.deletedAt}, Value: cutoff}
+}
+
+// count counts rows of m soft-deleted before cutoff
+func (p *Purger) count(ctx context.Context, m model, cutoff time.Time) (int64, error) {
+	var rows int64
+	err := p.db.WithContext(ctx).Unscoped().Model(m.value).Where(m.expired(cutoff)).Count(&rows).Error
+	return rows, err
+}
+
+// purge deletes rows of m soft-deleted before cutoff in batches, until a batch comes back short
+func (p *Purger) purge(ctx context.Context, m model, cutoff time.Time) (int64, error) {
+	var purged int64
+	for {
+		batch := p.db.WithContext(ctx).Unscoped().Model(m.value).
+			Select(m.primaryKey).Where(m.expired(cutoff)).Limit(p.cfg.BatchSize)
+		result := p.db.WithContext(ctx).Unscoped().
+			Where(clause.Expr{SQL: "? IN (?)", Vars: []any{clause.Column{Name: m.primaryKey}, batch}}).
+			Delete(m.value)
+		purged += result.RowsAffected
+		if result.Error != nil {
+			return purged, result.Error
+		}
+		if result.RowsAffected < int64(p.cfg.BatchSize) {
+			return purged, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return purged, err
+		}
+	}
+}
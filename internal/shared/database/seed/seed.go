@@ -0,0 +1,109 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go_boilerplate/internal/shared/lock"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Seeder populates part of the database. Run must be idempotent: it runs on every API start and
+// every cmd/seed invocation, so it creates what is missing and leaves existing rows alone (or
+// brings them back to their seeded state).
+type Seeder struct {
+	Name string
+	Envs []string // SERVER_MODE values it runs in (development, production, test); empty runs in all
+	Run  func(ctx context.Context, db *gorm.DB) error
+}
+
+// RunsIn reports whether the seeder runs in env
+func (s Seeder) RunsIn(env string) bool {
+	return len(s.Envs) == 0 || slices.Contains(s.Envs, env)
+}
+
+// Lock timings: seeding holds the "seed" lock, kept alive while it runs, and instances starting
+// together wait for it in turn
+const (
+	lockName = "seed"
+	lockTTL  = time.Minute
+	lockWait = 2 * time.Minute
+)
+
+// Options select the seeders Run executes
+type Options struct {
+	Env    string       // Only seeders tagged with this environment run
+	Only   []string     // Names of the seeders to run; empty runs all of them
+	Locker *lock.Locker // Keeps instances starting together from seeding at the same time (nil doesn't lock)
+}
+
+// Run executes seeders in order, skipping those not tagged with opts.Env or not in opts.Only. It
+// stops at the first failure, since later seeders may depend on earlier ones (the superadmin on
+// the roles).
+func Run(ctx context.Context, db *gorm.DB, seeders []Seeder, opts Options, logger *logrus.Logger) error {
+	if err := Validate(seeders, opts); err != nil {
+		return err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, lockWait)
+	held, err := opts.Locker.Wait(waitCtx, lockName, lockTTL)
+	cancel()
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return err
+	case err != nil:
+		// Seeders are idempotent, so seeding unlocked beats not seeding while Redis is unavailable
+		logger.Warnf("Seeding without the seed lock: %v", err)
+	default:
+		defer held.Release(context.WithoutCancel(ctx))
+		defer held.KeepAlive(ctx, lockTTL)()
+	}
+
+	for _, seeder := range seeders {
+		if len(opts.Only) > 0 && !slices.Contains(opts.Only, seeder.Name) {
+			continue
+		}
+		if !seeder.RunsIn(opts.Env) {
+			// Seeders named with --only are expected to run, so their skipping is worth a warning
+			skipped := logger.Debugf
+			if len(opts.Only) > 0 {
+				skipped = logger.Warnf
+			}
+			skipped("Seeder %q skipped: runs in %s only", seeder.Name, strings.Join(seeder.Envs, ", "))
+			continue
+		}
+
+		start := time.Now()
+		if err := seeder.Run(ctx, db); err != nil {
+			return fmt.Errorf("seeder %q failed: %w", seeder.Name, err)
+		}
+		logger.WithField("latency", time.Since(start).String()).Infof("✓ Seeder %q done", seeder.Name)
+	}
+	return nil
+}
+
+// Validate checks that opts.Only names existing seeders
+func Validate(seeders []Seeder, opts Options) error {
+	names := Names(seeders)
+	for _, name := range opts.Only {
+		if !slices.Contains(names, name) {
+			return fmt.Errorf("unknown seeder %q (available: %s)", name, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
+// Names returns the names of seeders, in order
+func Names(seeders []Seeder) []string {
+	names := make([]string, len(seeders))
+	for i, seeder := range seeders {
+		names[i] = seeder.Name
+	}
+	return names
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	roleModule "go_boilerplate/internal/modules/role"
+	userModule "go_boilerplate/internal/modules/user"
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/database/seed"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// demoUsers is how many demo accounts the demo seeder creates
+const demoUsers = 5
+
+// DemoPassword is the password of the demo accounts (demo1@example.com, ...)
+const DemoPassword = "DemoUser123!"
+
+// Seeders returns the application seeders in the order they run, for the API startup and cmd/seed
+func Seeders(cfg *config.Config, logger *logrus.Logger) []seed.Seeder {
+	return []seed.Seeder{
+		{
+			Name: "roles",
+			Run: func(ctx context.Context, db *gorm.DB) error {
+				return roleModule.NewRoleService(roleModule.NewRoleRepository(db)).SeedInitialRoles(ctx)
+			},
+		},
+		{
+			Name: "superadmin",
+			Run: func(ctx context.Context, db *gorm.DB) error {
+				return SeedSuperAdmin(ctx, db, cfg, logger)
+			},
+		},
+		{
+			Name: "demo",
+			Envs: []string{"development"},
+			Run: func(ctx context.Context, db *gorm.DB) error {
+				return seedDemoUsers(ctx, db, cfg)
+			},
+		},
+	}
+}
+
+// seedDemoUsers creates verified demo accounts with the default role; accounts that exist (even
+// deleted ones) are left alone
+func seedDemoUsers(ctx context.Context, db *gorm.DB, cfg *config.Config) error {
+	db = db.WithContext(ctx)
+
+	var defaultRole roleModule.Role
+	if err := db.Where("slug = ?", cfg.RBAC.DefaultRoleSlug).First(&defaultRole).Error; err != nil {
+		return fmt.Errorf("default role %q not found: %w", cfg.RBAC.DefaultRoleSlug, err)
+	}
+
+	hashedPassword, err := utils.HashPassword(DemoPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	for i := 1; i <= demoUsers; i++ {
+		email := fmt.Sprintf("demo%d@example.com", i)
+
+		var count int64
+		if err := db.Unscoped().Model(&userModule.User{}).Where("email = ?", email).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		demoUser := &userModule.User{
+			ID:         uuid.New(),
+			Name:       fmt.Sprintf("Demo User %d", i),
+			Email:      email,
+			Password:   hashedPassword,
+			Roles:      []roleModule.Role{defaultRole},
+			IsVerified: true,
+		}
+		if err := db.Omit("Roles.*").Create(demoUser).Error; err != nil {
+			return fmt.Errorf("failed to create %s: %w", email, err)
+		}
+	}
+	return nil
+}
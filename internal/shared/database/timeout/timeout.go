@@ -0,0 +1,171 @@
+package timeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/observability"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// QueryClass groups queries that share a timeout
+type QueryClass string
+
+// Query classes
+const (
+	ReadQuery   QueryClass = "read"
+	WriteQuery  QueryClass = "write"
+	ReportQuery QueryClass = "report"
+)
+
+// ErrQueryTimeout is returned (wrapped) when a query exceeds the timeout of its class
+var ErrQueryTimeout = errors.New("query timeout exceeded")
+
+const (
+	queryClassKey       = "timeout:class"
+	queryCancelKey      = "timeout:cancel"
+	queryContextKey     = "timeout:context"
+	pgQueryCanceledCode = "57014" // query_canceled, raised by statement_timeout
+)
+
+// Report tags the queries run on the returned handle as reports (longer timeout)
+//
+//	timeout.Report(r.db).Where(...).Find(&rows)
+func Report(db *gorm.DB) *gorm.DB {
+	return db.Set(queryClassKey, ReportQuery)
+}
+
+// Plugin bounds every query with a context deadline chosen by its class, so a single
+// runaway query can't hold a pool connection indefinitely. Breaches are returned as
+// ErrQueryTimeout and counted on the wide event and the MetricDBQueryTimeouts counter.
+//
+// Row-style queries (Rows(), Raw().Scan()) are read after the callbacks finish and
+// only honour deadlines already present on the caller's context.
+type Plugin struct {
+	timeouts map[QueryClass]time.Duration
+	metrics  *observability.Counter
+}
+
+// NewPlugin creates a timeout plugin from the configured per-class timeouts
+func NewPlugin(cfg config.QueryTimeoutConfig, metrics *observability.Counter) *Plugin {
+	return &Plugin{
+		timeouts: map[QueryClass]time.Duration{
+			ReadQuery:   cfg.Read,
+			WriteQuery:  cfg.Write,
+			ReportQuery: cfg.Report,
+		},
+		metrics: metrics,
+	}
+}
+
+// Name implements gorm.Plugin
+func (p *Plugin) Name() string {
+	return "query_timeout"
+}
+
+// Initialize implements gorm.Plugin by registering before/after callbacks on every processor
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+
+	if err := callbacks.Create().Before("gorm:create").Register("timeout:before_create", p.before(WriteQuery)); err != nil {
+		return err
+	}
+	if err := callbacks.Create().After("gorm:create").Register("timeout:after_create", p.after); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("timeout:before_query", p.before(ReadQuery)); err != nil {
+		return err
+	}
+	if err := callbacks.Query().After("gorm:query").Register("timeout:after_query", p.after); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("timeout:before_update", p.before(WriteQuery)); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register("timeout:after_update", p.after); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("timeout:before_delete", p.before(WriteQuery)); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("gorm:delete").Register("timeout:after_delete", p.after); err != nil {
+		return err
+	}
+	if err := callbacks.Raw().Before("gorm:raw").Register("timeout:before_raw", p.before(WriteQuery)); err != nil {
+		return err
+	}
+	return callbacks.Raw().After("gorm:raw").Register("timeout:after_raw", p.after)
+}
+
+// before replaces the statement context with one bounded by the class timeout
+func (p *Plugin) before(defaultClass QueryClass) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		class := defaultClass
+		if value, ok := db.Get(queryClassKey); ok {
+			if tagged, ok := value.(QueryClass); ok {
+				class = tagged
+			}
+		}
+
+		timeout := p.timeouts[class]
+		if timeout <= 0 {
+			return
+		}
+
+		original := db.Statement.Context
+		if original == nil {
+			original = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(original, timeout)
+		db.Statement.Context = ctx
+		db.InstanceSet(queryClassKey, class)
+		db.InstanceSet(queryCancelKey, cancel)
+		db.InstanceSet(queryContextKey, original)
+	}
+}
+
+// after releases the deadline and turns timeouts into ErrQueryTimeout
+// The original context is restored because chained handles reuse their statement for later queries
+func (p *Plugin) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(queryCancelKey)
+	if !ok {
+		return
+	}
+	cancel, ok := value.(context.CancelFunc)
+	if !ok {
+		return
+	}
+	defer cancel()
+
+	if original, ok := db.InstanceGet(queryContextKey); ok {
+		db.Statement.Context = original.(context.Context)
+	}
+
+	if db.Error == nil || !isTimeout(db.Error) {
+		return
+	}
+
+	var class QueryClass
+	if value, ok := db.InstanceGet(queryClassKey); ok {
+		class, _ = value.(QueryClass)
+	}
+
+	observability.FromContext(db.Statement.Context).Incr("db.timeouts", 1)
+	observability.FromContext(db.Statement.Context).Set("db.timeout_class", string(class))
+	p.metrics.Incr(context.Background(), observability.MetricDBQueryTimeouts)
+	db.Error = fmt.Errorf("%w (%s query, limit %s): %w", ErrQueryTimeout, class, p.timeouts[class], db.Error)
+}
+
+// isTimeout reports whether err was caused by a deadline or a server-side statement timeout
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || pgconn.Timeout(err) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgQueryCanceledCode
+}
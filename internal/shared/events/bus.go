@@ -0,0 +1,87 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go_boilerplate/internal/shared/config"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// Drivers selectable with EVENT_BUS_DRIVER
+const (
+	DriverRedis  = "redis"
+	DriverMemory = "memory"
+)
+
+// ErrClosed is returned by Publish once the bus is closed
+var ErrClosed = errors.New("event bus is closed")
+
+// Event is a domain event, named "<entity>.<verb>" (user.created, role.assigned)
+type Event struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// Decode unmarshals the payload of the event into v
+func (e Event) Decode(v any) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// Handler reacts to an event; errors are logged, events are not redelivered
+type Handler func(ctx context.Context, event Event) error
+
+// EventBus lets modules publish domain events and others react to them without importing each
+// other. Delivery is at most once and unordered across subscriptions: handlers must tolerate
+// missed events (e.g. while an instance restarts) and do the durable work elsewhere.
+type EventBus interface {
+	// Publish sends an event with payload encoded as JSON to every matching subscription
+	Publish(ctx context.Context, name string, payload any) error
+	// Subscribe calls handler for events whose name matches pattern (glob: "user.*", "*") until
+	// unsubscribe is called
+	Subscribe(pattern string, handler Handler) (unsubscribe func())
+	// Close stops delivering events and waits for running handlers
+	Close() error
+}
+
+// New creates the event bus selected by EVENT_BUS_DRIVER; without a Redis client the redis driver
+// falls back to the in-process bus
+func New(cfg config.EventBusConfig, client *redis.Client, logger *logrus.Logger) EventBus {
+	if cfg.Driver == DriverRedis && client != nil {
+		return NewRedis(client, logger)
+	}
+	return NewMemory(logger)
+}
+
+// newEvent builds an event with a fresh ID
+func newEvent(name string, payload any) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{ID: uuid.NewString(), Name: name, Payload: data, OccurredAt: time.Now()}, nil
+}
+
+// deliver calls a handler, logging its error and recovering from panics so one handler can't take
+// down the process
+func deliver(ctx context.Context, logger *logrus.Logger, handler Handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("Event handler for %q panicked: %v", event.Name, r)
+		}
+	}()
+
+	if err := handler(ctx, event); err != nil {
+		logger.WithFields(logrus.Fields{
+			"event":    event.Name,
+			"event_id": event.ID,
+		}).Errorf("Event handler failed: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package events
+
+import (
+	"context"
+	"path"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// memorySubscription is a handler of the in-process bus
+type memorySubscription struct {
+	pattern string
+	handler Handler
+}
+
+// Memory is an in-process EventBus: events reach the subscriptions of this instance only. Each
+// handler runs in its own goroutine, so Publish never waits for them.
+type Memory struct {
+	logger *logrus.Logger
+
+	mu     sync.RWMutex
+	subs   map[uint64]memorySubscription
+	nextID uint64
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewMemory creates an in-process event bus
+func NewMemory(logger *logrus.Logger) *Memory {
+	return &Memory{logger: logger, subs: make(map[uint64]memorySubscription)}
+}
+
+// Publish implements EventBus
+func (b *Memory) Publish(ctx context.Context, name string, payload any) error {
+	event, err := newEvent(name, payload)
+	if err != nil {
+		return err
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return ErrClosed
+	}
+	// Handlers outlive the request that published the event, but keep its values (request ID)
+	ctx = context.WithoutCancel(ctx)
+	for _, sub := range b.subs {
+		if matched, _ := path.Match(sub.pattern, name); !matched {
+			continue
+		}
+		b.wg.Add(1)
+		go func(handler Handler) {
+			defer b.wg.Done()
+			deliver(ctx, b.logger, handler, event)
+		}(sub.handler)
+	}
+	return nil
+}
+
+// Subscribe implements EventBus
+func (b *Memory) Subscribe(pattern string, handler Handler) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = memorySubscription{pattern: pattern, handler: handler}
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subs, id)
+	}
+}
+
+// Close implements EventBus
+func (b *Memory) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+	b.wg.Wait()
+	return nil
+}
@@ -0,0 +1,101 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// channelPrefix namespaces event channels: an event is published on events:<name>
+const channelPrefix = "events:"
+
+// Redis is an EventBus over Redis pub/sub: events reach the subscriptions of every instance and
+// worker connected to the same Redis. Each subscription holds its own connection (resubscribed
+// after reconnects) and runs its handler for one event at a time, in publish order; events
+// published while no instance listens are lost.
+type Redis struct {
+	redis  *redis.Client
+	logger *logrus.Logger
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	subs   map[uint64]*redis.PubSub
+	nextID uint64
+	wg     sync.WaitGroup
+}
+
+// NewRedis creates an event bus over Redis pub/sub
+func NewRedis(client *redis.Client, logger *logrus.Logger) *Redis {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Redis{redis: client, logger: logger, ctx: ctx, cancel: cancel, subs: make(map[uint64]*redis.PubSub)}
+}
+
+// Publish implements EventBus
+func (b *Redis) Publish(ctx context.Context, name string, payload any) error {
+	if b.ctx.Err() != nil {
+		return ErrClosed
+	}
+	event, err := newEvent(name, payload)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return b.redis.Publish(ctx, channelPrefix+name, data).Err()
+}
+
+// Subscribe implements EventBus
+func (b *Redis) Subscribe(pattern string, handler Handler) func() {
+	pubsub := b.redis.PSubscribe(b.ctx, channelPrefix+pattern)
+
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = pubsub
+	b.mu.Unlock()
+
+	b.wg.Add(1)
+	go b.receive(pubsub, handler)
+
+	return func() {
+		b.mu.Lock()
+		delete(b.subs, id)
+		b.mu.Unlock()
+		_ = pubsub.Close()
+	}
+}
+
+// Close implements EventBus
+func (b *Redis) Close() error {
+	b.cancel()
+
+	b.mu.Lock()
+	for id, pubsub := range b.subs {
+		_ = pubsub.Close()
+		delete(b.subs, id)
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+	return nil
+}
+
+// receive runs handler for every event of a subscription until it is closed
+func (b *Redis) receive(pubsub *redis.PubSub, handler Handler) {
+	defer b.wg.Done()
+
+	for msg := range pubsub.Channel() {
+		var event Event
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			b.logger.WithField("channel", msg.Channel).Warnf("Ignoring malformed event: %v", err)
+			continue
+		}
+		deliver(b.ctx, b.logger, handler, event)
+	}
+}
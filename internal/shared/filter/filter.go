@@ -0,0 +1,237 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go_boilerplate/internal/shared/apperror"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Operator is a comparison applied to a filtered field
+type Operator string
+
+// Supported operators, used as ?filter[field][op]=value
+const (
+	OpEq   Operator = "eq"   // equal (default when the operator is omitted)
+	OpNe   Operator = "ne"   // not equal
+	OpLike Operator = "like" // case-insensitive substring match
+	OpGt   Operator = "gt"   // greater than
+	OpGte  Operator = "gte"  // greater than or equal
+	OpLt   Operator = "lt"   // less than
+	OpLte  Operator = "lte"  // less than or equal
+	OpIn   Operator = "in"   // one of a comma-separated list
+	OpNull Operator = "null" // IS NULL when true, IS NOT NULL when false
+)
+
+// Type is the value type of a filtered field, used to parse and validate query values
+type Type int
+
+// Supported field types
+const (
+	String Type = iota
+	Int
+	Bool
+	Time
+	UUID
+)
+
+// defaultOperators are the operators allowed for each type when a Field doesn't list its own
+var defaultOperators = map[Type][]Operator{
+	String: {OpEq, OpNe, OpLike, OpIn, OpNull},
+	Int:    {OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpIn, OpNull},
+	Bool:   {OpEq, OpNe, OpNull},
+	Time:   {OpEq, OpGt, OpGte, OpLt, OpLte, OpNull},
+	UUID:   {OpEq, OpNe, OpIn, OpNull},
+}
+
+// Field describes a filterable column
+type Field struct {
+	Column    string     // Database column; never taken from user input
+	Type      Type       // Value type
+	Operators []Operator // Allowed operators (defaults to all operators valid for Type)
+}
+
+// Fields maps query parameter names to filterable columns
+type Fields map[string]Field
+
+// Condition is a single parsed filter
+type Condition struct {
+	Field    string
+	Column   string
+	Operator Operator
+	Value    any
+}
+
+// Filter is a set of parsed conditions, combined with AND
+type Filter []Condition
+
+// ErrInvalidFilter is matched by every parse error (errors.Is), which answers 400 naming the rejected parameter
+var ErrInvalidFilter = apperror.New(apperror.ErrValidation, "invalid filter").WithCode("invalid_filter")
+
+// invalidFilter describes a parse error to the client
+func invalidFilter(format string, args ...any) error {
+	return ErrInvalidFilter.WithMessage("invalid filter: " + fmt.Sprintf(format, args...))
+}
+
+// paramPattern matches filter[field] and filter[field][op]
+var paramPattern = regexp.MustCompile(`^filter\[([a-zA-Z0-9_]+)\](?:\[([a-z]+)\])?$`)
+
+// FromQuery parses ?filter[...] query parameters against the allowed fields
+func FromQuery(c *fiber.Ctx, fields Fields) (Filter, error) {
+	var filter Filter
+	var parseErr error
+
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		if parseErr != nil {
+			return
+		}
+
+		matches := paramPattern.FindStringSubmatch(string(key))
+		if matches == nil {
+			return
+		}
+
+		condition, err := parseCondition(fields, matches[1], matches[2], string(value))
+		if err != nil {
+			parseErr = err
+			return
+		}
+		filter = append(filter, condition)
+	})
+
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	return filter, nil
+}
+
+// parseCondition validates a single field/operator/value triple
+func parseCondition(fields Fields, name, op, raw string) (Condition, error) {
+	field, ok := fields[name]
+	if !ok {
+		return Condition{}, invalidFilter("unknown field %q", name)
+	}
+
+	operator := OpEq
+	if op != "" {
+		operator = Operator(op)
+	}
+	if !field.allows(operator) {
+		return Condition{}, invalidFilter("operator %q is not supported for %q", operator, name)
+	}
+
+	condition := Condition{Field: name, Column: field.Column, Operator: operator}
+
+	switch operator {
+	case OpNull:
+		isNull, err := strconv.ParseBool(raw)
+		if err != nil {
+			return Condition{}, invalidFilter("%q expects true or false", name)
+		}
+		condition.Value = isNull
+	case OpIn:
+		var values []any
+		for _, item := range strings.Split(raw, ",") {
+			value, err := parseValue(field.Type, strings.TrimSpace(item))
+			if err != nil {
+				return Condition{}, invalidFilter("%q: %v", name, err)
+			}
+			values = append(values, value)
+		}
+		condition.Value = values
+	case OpLike:
+		condition.Value = "%" + escapeLike(raw) + "%"
+	default:
+		value, err := parseValue(field.Type, raw)
+		if err != nil {
+			return Condition{}, invalidFilter("%q: %v", name, err)
+		}
+		condition.Value = value
+	}
+
+	return condition, nil
+}
+
+// allows reports whether the operator may be used on the field
+func (f Field) allows(op Operator) bool {
+	operators := f.Operators
+	if operators == nil {
+		operators = defaultOperators[f.Type]
+	}
+	for _, allowed := range operators {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
+
+// parseValue converts a raw query value to the field's type
+func parseValue(t Type, raw string) (any, error) {
+	switch t {
+	case Int:
+		return strconv.ParseInt(raw, 10, 64)
+	case Bool:
+		return strconv.ParseBool(raw)
+	case Time:
+		if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+			return parsed, nil
+		}
+		parsed, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return nil, errors.New("expects an RFC 3339 timestamp or a YYYY-MM-DD date")
+		}
+		return parsed, nil
+	case UUID:
+		return uuid.Parse(raw)
+	default:
+		return raw, nil
+	}
+}
+
+// escapeLike escapes LIKE wildcards so user input is matched literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// Scope returns a GORM scope applying every condition
+// Columns come from the Fields whitelist and values are always bound as parameters
+func (f Filter) Scope() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, c := range f {
+			switch c.Operator {
+			case OpEq:
+				db = db.Where(c.Column+" = ?", c.Value)
+			case OpNe:
+				db = db.Where(c.Column+" <> ?", c.Value)
+			case OpLike:
+				db = db.Where(c.Column+" ILIKE ?", c.Value)
+			case OpGt:
+				db = db.Where(c.Column+" > ?", c.Value)
+			case OpGte:
+				db = db.Where(c.Column+" >= ?", c.Value)
+			case OpLt:
+				db = db.Where(c.Column+" < ?", c.Value)
+			case OpLte:
+				db = db.Where(c.Column+" <= ?", c.Value)
+			case OpIn:
+				db = db.Where(c.Column+" IN ?", c.Value)
+			case OpNull:
+				if c.Value.(bool) {
+					db = db.Where(c.Column + " IS NULL")
+				} else {
+					db = db.Where(c.Column + " IS NOT NULL")
+				}
+			}
+		}
+		return db
+	}
+}
@@ -0,0 +1,37 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+
+	"go_boilerplate/internal/shared/apperror"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestParseConditionErrorsAreTyped(t *testing.T) {
+	fields := Fields{
+		"name":       {Column: "name", Type: String},
+		"created_at": {Column: "created_at", Type: Time},
+	}
+
+	for _, tc := range []struct {
+		name, op, raw string
+		detail        string
+	}{
+		{"password", "", "x", `invalid filter: unknown field "password"`},
+		{"created_at", "like", "x", `invalid filter: operator "like" is not supported for "created_at"`},
+		{"created_at", "", "yesterday", `invalid filter: "created_at": expects an RFC 3339 timestamp or a YYYY-MM-DD date`},
+	} {
+		_, err := parseCondition(fields, tc.name, tc.op, tc.raw)
+
+		if !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("parseCondition(%q, %q) = %v, want ErrInvalidFilter", tc.name, tc.op, err)
+			continue
+		}
+		appErr, ok := apperror.As(err)
+		if !ok || appErr.Status != fiber.StatusBadRequest || appErr.Code != "invalid_filter" || appErr.Message != tc.detail {
+			t.Errorf("parseCondition(%q, %q) = %+v, want 400 invalid_filter %q", tc.name, tc.op, appErr, tc.detail)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go_boilerplate/internal/shared/apperror"
+	"go_boilerplate/internal/shared/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// overridesKey is the Redis hash holding one JSON-encoded Flag per flag name
+const overridesKey = "feature_flags"
+
+// ErrUnavailable is returned when overrides are changed without Redis
+var ErrUnavailable = apperror.New(apperror.ErrUnavailable, "Feature flag overrides need Redis").WithCode("feature_flags_unavailable")
+
+// ErrInvalidName is returned for names that are not lowercase letters, digits, '.', '_' or '-'
+var ErrInvalidName = apperror.New(apperror.ErrBadRequest, "Invalid feature flag name").WithCode("invalid_feature_flag")
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+// Flag is a runtime override of a feature flag, set by admins and stored in Redis. While it exists
+// it replaces the FEATURE_FLAGS setting of the flag on every instance.
+type Flag struct {
+	Name       string    `json:"name"`
+	Enabled    bool      `json:"enabled"`         // On for everyone
+	Percentage int       `json:"percentage"`      // On for this share (0-100) of users, bucketed by a stable hash of flag and user ID
+	Users      []string  `json:"users,omitempty"` // User IDs the flag is always on for
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// State is a flag as seen by the admin endpoint: its configured default and its override, if any
+type State struct {
+	Name     string `json:"name"`
+	Default  bool   `json:"default"` // Listed in FEATURE_FLAGS (config files or environment)
+	Override *Flag  `json:"override,omitempty"`
+}
+
+// Flags evaluates feature flags. Flags listed in FEATURE_FLAGS are on for everyone and follow config
+// reloads; an override stored in Redis takes precedence and can target users or a percentage of
+// them. Overrides are cached in-process for FEATURE_FLAGS_CACHE_TTL, so changes made on another
+// instance show up within that time. A nil *redis.Client disables overrides.
+type Flags struct {
+	cfg   *config.Config
+	redis *redis.Client
+
+	mu        sync.Mutex
+	overrides map[string]Flag
+	loadedAt  time.Time
+}
+
+// New creates the feature flag evaluator
+func New(cfg *config.Config, client *redis.Client) *Flags {
+	return &Flags{cfg: cfg, redis: client}
+}
+
+// ValidName reports whether name can be used as a flag name
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// IsEnabled reports whether the named flag is on for the request in ctx; user targeting uses the
+// user stored with WithUser (set by the JWT middleware)
+func (f *Flags) IsEnabled(ctx context.Context, name string) bool {
+	if f == nil {
+		return false
+	}
+	name = strings.ToLower(name)
+
+	override, ok := f.loadOverrides(ctx)[name]
+	if !ok {
+		return f.cfg.Hot().Feature(name)
+	}
+	if override.Enabled {
+		return true
+	}
+
+	userID, ok := UserFromContext(ctx)
+	if !ok {
+		return false
+	}
+	if slices.Contains(override.Users, userID) {
+		return true
+	}
+	return override.Percentage > 0 && bucket(name, userID) < override.Percentage
+}
+
+// List returns every flag that is configured or overridden, sorted by name
+func (f *Flags) List(ctx context.Context) ([]State, error) {
+	overrides, err := f.fetchOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	states := map[string]*State{}
+	for name, enabled := range f.cfg.Hot().Features {
+		states[name] = &State{Name: name, Default: enabled}
+	}
+	for name, override := range overrides {
+		state, ok := states[name]
+		if !ok {
+			state = &State{Name: name}
+			states[name] = state
+		}
+		state.Override = &override
+	}
+
+	list := make([]State, 0, len(states))
+	for _, state := range states {
+		list = append(list, *state)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// Set stores an override for flag.Name and returns the flag's new state
+func (f *Flags) Set(ctx context.Context, flag Flag) (*State, error) {
+	flag.Name = strings.ToLower(flag.Name)
+	if !ValidName(flag.Name) {
+		return nil, ErrInvalidName
+	}
+	if f.redis == nil {
+		return nil, ErrUnavailable
+	}
+
+	flag.UpdatedAt = time.Now().UTC()
+	data, err := json.Marshal(flag)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.redis.HSet(ctx, overridesKey, flag.Name, data).Err(); err != nil {
+		return nil, err
+	}
+	f.invalidate()
+	return &State{Name: flag.Name, Default: f.cfg.Hot().Feature(flag.Name), Override: &flag}, nil
+}
+
+// Delete removes the override of a flag, so FEATURE_FLAGS decides again. It reports whether there
+// was one.
+func (f *Flags) Delete(ctx context.Context, name string) (bool, error) {
+	if f.redis == nil {
+		return false, ErrUnavailable
+	}
+
+	removed, err := f.redis.HDel(ctx, overridesKey, strings.ToLower(name)).Result()
+	if err != nil {
+		return false, err
+	}
+	f.invalidate()
+	return removed > 0, nil
+}
+
+// loadOverrides returns the cached overrides, refreshing them when FEATURE_FLAGS_CACHE_TTL has passed.
+// Redis failures keep the previous overrides until the next refresh, so an outage neither flips
+// flags nor adds a Redis round trip to every check.
+func (f *Flags) loadOverrides(ctx context.Context) map[string]Flag {
+	if f.redis == nil {
+		return nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.loadedAt.IsZero() && time.Since(f.loadedAt) < f.cfg.Features.CacheTTL {
+		return f.overrides
+	}
+	if overrides, err := f.fetchOverrides(ctx); err == nil {
+		f.overrides = overrides
+	}
+	f.loadedAt = time.Now()
+	return f.overrides
+}
+
+// fetchOverrides reads every override from Redis
+func (f *Flags) fetchOverrides(ctx context.Context) (map[string]Flag, error) {
+	overrides := map[string]Flag{}
+	if f.redis == nil {
+		return overrides, nil
+	}
+
+	values, err := f.redis.HGetAll(ctx, overridesKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range values {
+		var flag Flag
+		if err := json.Unmarshal([]byte(value), &flag); err != nil {
+			continue
+		}
+		flag.Name = name
+		overrides[name] = flag
+	}
+	return overrides, nil
+}
+
+// invalidate drops the cached overrides after a change made through this instance
+func (f *Flags) invalidate() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.loadedAt = time.Time{}
+}
+
+// bucket maps a user to 0-99 for a flag; the flag name is part of the hash so each flag rolls out to
+// a different set of users
+func bucket(name, userID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name + ":" + userID))
+	return int(h.Sum32() % 100)
+}
+
+type userKey struct{}
+
+// WithUser stores the user flags are evaluated for in ctx
+func WithUser(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userKey{}, userID)
+}
+
+// UserFromContext returns the user stored by WithUser
+func UserFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	userID, ok := ctx.Value(userKey{}).(string)
+	return userID, ok && userID != ""
+}
@@ -0,0 +1,95 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go_boilerplate/internal/shared/config"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/redis/go-redis/v9"
+	"gopkg.in/gomail.v2"
+	"gorm.io/gorm"
+)
+
+// pgUndefinedTable is raised when schema_migrations doesn't exist (AutoMigrate-only databases)
+const pgUndefinedTable = "42P01"
+
+// Postgres pings the primary database
+func Postgres(db *gorm.DB) Check {
+	return Check{
+		Name:     "postgres",
+		Required: true,
+		Run: func(ctx context.Context) (string, error) {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return "", err
+			}
+			if err := sqlDB.PingContext(ctx); err != nil {
+				return "", err
+			}
+			stats := sqlDB.Stats()
+			return fmt.Sprintf("%d open connections, %d in use", stats.OpenConnections, stats.InUse), nil
+		},
+	}
+}
+
+// Migrations reports the schema version applied by cmd/migrate. A dirty version (a migration
+// that failed halfway) makes the service unready.
+func Migrations(db *gorm.DB) Check {
+	return Check{
+		Name:     "migrations",
+		Required: true,
+		Run: func(ctx context.Context) (string, error) {
+			var state struct {
+				Version int64
+				Dirty   bool
+			}
+			err := db.WithContext(ctx).Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&state).Error
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == pgUndefinedTable {
+				return "not managed by cmd/migrate", nil
+			}
+			if err != nil {
+				return "", err
+			}
+
+			detail := fmt.Sprintf("version %d", state.Version)
+			if state.Dirty {
+				return detail, fmt.Errorf("migration %d is dirty; fix the schema and run cmd/migrate -force", state.Version)
+			}
+			return detail, nil
+		},
+	}
+}
+
+// Redis pings Redis. It is optional: while it is down the API keeps serving degraded (caches in
+// memory, one-time codes unavailable) and reports "degraded" until the client reconnects.
+func Redis(client *redis.Client) Check {
+	return Check{
+		Name: "redis",
+		Run: func(ctx context.Context) (string, error) {
+			if client == nil {
+				return "", errors.New("not connected")
+			}
+			return "", client.Ping(ctx).Err()
+		},
+	}
+}
+
+// SMTP connects and authenticates to the SMTP server. It is optional: failed emails don't stop
+// the API from serving requests.
+func SMTP(cfg config.EmailConfig) Check {
+	return Check{
+		Name: "smtp",
+		Run: func(ctx context.Context) (string, error) {
+			// gomail takes no context; the checker stops waiting at the timeout and the dial ends on its own
+			conn, err := gomail.NewDialer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPassword).Dial()
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s:%d", cfg.SMTPHost, cfg.SMTPPort), conn.Close()
+		},
+	}
+}
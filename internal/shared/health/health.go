@@ -0,0 +1,145 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Report statuses
+const (
+	StatusOK          = "ok"          // Every check passed
+	StatusDegraded    = "degraded"    // An optional check failed; the service still takes traffic
+	StatusUnavailable = "unavailable" // A required check failed
+)
+
+// Check statuses
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Check probes one dependency. Run returns a short description of what it saw, e.g. a migration
+// version, or an error when the dependency is unusable.
+type Check struct {
+	Name     string
+	Required bool // The service isn't ready while a required check fails
+	Run      func(ctx context.Context) (string, error)
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Status   string `json:"status"`
+	Required bool   `json:"required"`
+	Latency  string `json:"latency"`
+	Detail   string `json:"detail,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Report is the readiness of the service and of each dependency
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Ready reports whether every required check passed
+func (r Report) Ready() bool {
+	return r.Status != StatusUnavailable
+}
+
+// Checker runs the registered checks concurrently, each bounded by the configured timeout
+type Checker struct {
+	checks  []Check
+	timeout time.Duration
+}
+
+// NewChecker creates a checker running checks with the given per-check timeout
+func NewChecker(timeout time.Duration, checks ...Check) *Checker {
+	return &Checker{checks: checks, timeout: timeout}
+}
+
+// Add registers another check
+func (h *Checker) Add(check Check) {
+	h.checks = append(h.checks, check)
+}
+
+// Run runs every check and summarizes them
+func (h *Checker) Run(ctx context.Context) Report {
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(h.checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := h.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.Name] = result
+			if result.Status == StatusDown {
+				if check.Required {
+					report.Status = StatusUnavailable
+				} else if report.Status == StatusOK {
+					report.Status = StatusDegraded
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return report
+}
+
+// run runs one check under the timeout; a check that doesn't return in time counts as down
+func (h *Checker) run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	type outcome struct {
+		detail string
+		err    error
+	}
+	done := make(chan outcome, 1)
+	start := time.Now()
+	go func() {
+		detail, err := check.Run(ctx)
+		done <- outcome{detail, err}
+	}()
+
+	result := Result{Status: StatusUp, Required: check.Required}
+	select {
+	case o := <-done:
+		result.Detail = o.detail
+		if o.err != nil {
+			result.Status = StatusDown
+			result.Error = o.err.Error()
+		}
+	case <-ctx.Done():
+		result.Status = StatusDown
+		result.Error = "no answer within " + h.timeout.String()
+	}
+	result.Latency = time.Since(start).Round(time.Millisecond).String()
+	return result
+}
+
+// Live answers liveness probes: the process is up and serving requests. It checks no
+// dependencies, so an outage elsewhere doesn't get the instance restarted.
+func (h *Checker) Live(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": StatusOK})
+}
+
+// Ready answers readiness probes with the report; 503 while a required check fails, so load
+// balancers stop routing to the instance until its dependencies are back
+func (h *Checker) Ready(c *fiber.Ctx) error {
+	report := h.Run(c.UserContext())
+
+	status := fiber.StatusOK
+	if !report.Ready() {
+		status = fiber.StatusServiceUnavailable
+	}
+	return c.Status(status).JSON(report)
+}
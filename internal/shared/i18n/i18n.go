@@ -0,0 +1,159 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// Messages are keyed by their English text, so untranslated messages fall back to English
+// and call sites keep readable strings. Catalogs live in locales/<tag>.json:
+//
+//	{"Users retrieved successfully": "Usuarios obtenidos correctamente"}
+
+//go:embed locales/*.json
+var localesFS embed.FS
+
+// Source is the language messages are written in; it needs no catalog
+const Source = "en"
+
+type contextKey struct{}
+
+var (
+	mu            sync.RWMutex
+	catalogs      = map[string]map[string]string{}
+	defaultLocale = Source
+	matcher       language.Matcher
+	supported     []string
+)
+
+func init() {
+	entries, err := localesFS.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: %v", err))
+	}
+	for _, entry := range entries {
+		data, err := localesFS.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: %v", err))
+		}
+		messages := map[string]string{}
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: %s: %v", entry.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(entry.Name(), ".json")] = messages
+	}
+	buildMatcher()
+}
+
+// buildMatcher prepares negotiation over the default locale, the source language and every catalog
+func buildMatcher() {
+	locales := map[string]bool{defaultLocale: true, Source: true}
+	for locale := range catalogs {
+		locales[locale] = true
+	}
+
+	supported = supported[:0]
+	for locale := range locales {
+		if locale != defaultLocale {
+			supported = append(supported, locale)
+		}
+	}
+	sort.Strings(supported)
+	// The first tag is the matcher's fallback
+	supported = append([]string{defaultLocale}, supported...)
+
+	tags := make([]language.Tag, len(supported))
+	for i, locale := range supported {
+		tags[i] = language.Make(locale)
+	}
+	matcher = language.NewMatcher(tags)
+}
+
+// SetDefault sets the locale used when a request names none we support (DEFAULT_LOCALE)
+func SetDefault(locale string) error {
+	if locale != Source {
+		if _, ok := catalogs[locale]; !ok {
+			return fmt.Errorf("i18n: no catalog for default locale %q", locale)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	defaultLocale = locale
+	buildMatcher()
+	return nil
+}
+
+// Default returns the default locale
+func Default() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return defaultLocale
+}
+
+// Supported returns every negotiable locale, the default first
+func Supported() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]string(nil), supported...)
+}
+
+// Negotiate picks the best supported locale for a list of preferences, e.g. a `?lang=` value
+// followed by the Accept-Language header; unparseable or empty preferences are skipped
+func Negotiate(preferences ...string) string {
+	var tags []language.Tag
+	for _, preference := range preferences {
+		if preference == "" {
+			continue
+		}
+		parsed, _, err := language.ParseAcceptLanguage(preference)
+		if err != nil {
+			continue
+		}
+		tags = append(tags, parsed...)
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+	if len(tags) == 0 {
+		return defaultLocale
+	}
+	_, index, _ := matcher.Match(tags...)
+	return supported[index]
+}
+
+// WithLocale stores the request locale in ctx
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext returns the locale stored by WithLocale, or the default locale
+func FromContext(ctx context.Context) string {
+	if ctx != nil {
+		if locale, ok := ctx.Value(contextKey{}).(string); ok {
+			return locale
+		}
+	}
+	return Default()
+}
+
+// Translate returns message in locale, falling back to the message itself
+func Translate(locale, message string) string {
+	if translated, ok := catalogs[locale][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// T translates message into the locale of ctx
+func T(ctx context.Context, message string) string {
+	return Translate(FromContext(ctx), message)
+}
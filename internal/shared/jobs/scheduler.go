@@ -0,0 +1,136 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go_boilerplate/internal/shared/lock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Job is a unit of background work run periodically by the Scheduler
+type Job struct {
+	Name      string
+	Interval  time.Duration
+	Run       func(ctx context.Context) error
+	RunOnStop bool // Run one final time during shutdown (e.g. to flush buffers)
+	Exclusive bool // Run on a single instance per interval, for jobs working on shared data (purges, Redis buffers)
+}
+
+// Scheduler runs registered jobs on fixed intervals until stopped
+type Scheduler struct {
+	jobs   []Job
+	logger *logrus.Logger
+	locker *lock.Locker
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScheduler creates a new job scheduler; locker keeps Exclusive jobs from running on several
+// instances at once (a nil locker runs them on every instance)
+func NewScheduler(logger *logrus.Logger, locker *lock.Locker) *Scheduler {
+	return &Scheduler{logger: logger, locker: locker}
+}
+
+// Add registers a job; it must be called before Start
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start launches every registered job in its own goroutine
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+		s.logger.Infof("✓ Job %q scheduled every %s", job.Name, job.Interval)
+	}
+}
+
+// Stop cancels all jobs, waits for running ones to finish and runs final passes
+func (s *Scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+
+	for _, job := range s.jobs {
+		if job.RunOnStop {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			s.run(ctx, job)
+			cancel()
+		}
+	}
+}
+
+// loop runs a job on every tick until the context is cancelled
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if job.Exclusive {
+				s.runExclusive(ctx, job)
+			} else {
+				s.run(ctx, job)
+			}
+		}
+	}
+}
+
+// runExclusive runs a job unless another instance ran it during the current interval. The lock
+// is left to expire rather than released, so instances ticking later in the interval skip it;
+// it lives a little less than the interval so the holder's next tick finds it free.
+func (s *Scheduler) runExclusive(ctx context.Context, job Job) {
+	ttl := job.Interval * 9 / 10
+	held, err := s.locker.Acquire(ctx, "job:"+job.Name, ttl)
+	if errors.Is(err, lock.ErrNotAcquired) {
+		s.logger.WithField("job", job.Name).Debug("Job skipped: running on another instance")
+		return
+	}
+	if err != nil {
+		// Running twice beats not running while Redis is unavailable
+		s.logger.WithField("job", job.Name).Warnf("Job lock unavailable, running anyway: %v", err)
+		s.run(ctx, job)
+		return
+	}
+
+	stop := held.KeepAlive(ctx, ttl)
+	defer stop()
+	s.run(ctx, job)
+}
+
+// run executes a job once, recovering from panics so one job can't take down the process
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Errorf("Job %q panicked: %v", job.Name, r)
+		}
+	}()
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		s.logger.WithFields(logrus.Fields{
+			"job":     job.Name,
+			"latency": time.Since(start).String(),
+		}).Errorf("Job failed: %v", err)
+		return
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"job":     job.Name,
+		"latency": time.Since(start).String(),
+	}).Debug("Job completed")
+}
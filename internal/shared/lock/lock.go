@@ -0,0 +1,186 @@
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix namespaces lock keys; the fencing counter of a lock lives next to it
+const keyPrefix = "lock:"
+
+// waitInterval is how often Wait retries a held lock
+const waitInterval = 200 * time.Millisecond
+
+var (
+	// ErrNotAcquired is returned by Acquire while another holder has the lock
+	ErrNotAcquired = errors.New("lock is held by another holder")
+	// ErrNotHeld is returned by Extend once the lock expired or was taken over
+	ErrNotHeld = errors.New("lock is no longer held")
+)
+
+// release deletes the lock only when it still holds the caller's token
+var release = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// extend renews the lock's expiry only when it still holds the caller's token
+var extend = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// Locker hands out named locks shared by every API instance through Redis (SET NX with an
+// expiry and a random token, so only the holder can extend or release a lock). Locks expire on
+// their own, so a crashed holder never blocks the others for longer than the ttl.
+// A nil *Locker grants every lock, which is right for a single instance without Redis.
+type Locker struct {
+	redis *redis.Client
+}
+
+// New creates a locker; without a Redis client it returns nil, which grants every lock
+func New(client *redis.Client) *Locker {
+	if client == nil {
+		return nil
+	}
+	return &Locker{redis: client}
+}
+
+// Lock is a held lock. Fence increases every time the lock is acquired, so storage written under
+// the lock can reject a holder whose lock expired meanwhile (fencing token).
+type Lock struct {
+	Fence int64
+
+	redis *redis.Client
+	key   string
+	token string
+}
+
+// Acquire takes the lock name for ttl, or fails with ErrNotAcquired while someone else holds it
+func (l *Locker) Acquire(ctx context.Context, name string, ttl time.Duration) (*Lock, error) {
+	if l == nil {
+		return &Lock{}, nil
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	key := keyPrefix + name
+	acquired, err := l.redis.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock %q: %w", name, err)
+	}
+	if !acquired {
+		return nil, ErrNotAcquired
+	}
+
+	fence, err := l.redis.Incr(ctx, key+":fence").Result()
+	if err != nil {
+		_ = release.Run(context.WithoutCancel(ctx), l.redis, []string{key}, token).Err()
+		return nil, fmt.Errorf("failed to acquire lock %q: %w", name, err)
+	}
+	return &Lock{Fence: fence, redis: l.redis, key: key, token: token}, nil
+}
+
+// Wait acquires the lock name for ttl, retrying while it is held until ctx is done
+func (l *Locker) Wait(ctx context.Context, name string, ttl time.Duration) (*Lock, error) {
+	for {
+		lock, err := l.Acquire(ctx, name, ttl)
+		if !errors.Is(err, ErrNotAcquired) {
+			return lock, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for lock %q: %w", name, ctx.Err())
+		case <-time.After(waitInterval):
+		}
+	}
+}
+
+// Run calls fn while holding the lock name, extending it until fn returns and releasing it
+// afterwards. It reports false without calling fn when the lock is held elsewhere.
+func (l *Locker) Run(ctx context.Context, name string, ttl time.Duration, fn func(ctx context.Context) error) (bool, error) {
+	lock, err := l.Acquire(ctx, name, ttl)
+	if errors.Is(err, ErrNotAcquired) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer lock.Release(context.WithoutCancel(ctx))
+
+	stop := lock.KeepAlive(ctx, ttl)
+	defer stop()
+	return true, fn(ctx)
+}
+
+// Extend renews the lock for ttl; it fails with ErrNotHeld once the lock expired
+func (k *Lock) Extend(ctx context.Context, ttl time.Duration) error {
+	if k.redis == nil {
+		return nil
+	}
+	extended, err := extend.Run(ctx, k.redis, []string{k.key}, k.token, ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if extended == 0 {
+		return ErrNotHeld
+	}
+	return nil
+}
+
+// KeepAlive extends the lock to ttl every ttl/3 until the returned stop is called or ctx is done,
+// so work that outlasts ttl keeps the lock
+func (k *Lock) KeepAlive(ctx context.Context, ttl time.Duration) (stop func()) {
+	if k.redis == nil {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := k.Extend(ctx, ttl); errors.Is(err, ErrNotHeld) {
+					return
+				}
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// Release frees the lock; releasing a lock that expired or was taken over does nothing
+func (k *Lock) Release(ctx context.Context) error {
+	if k.redis == nil {
+		return nil
+	}
+	return release.Run(ctx, k.redis, []string{k.key}, k.token).Err()
+}
+
+// newToken returns a random lock token identifying one holder
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// ActivityRecorder records that an authenticated user was active
+type ActivityRecorder interface {
+	Touch(userID string)
+}
+
+// TrackActivity records the authenticated user's activity after each request
+// It must be registered globally: route-level JWT middleware has populated the
+// user context by the time the downstream handlers return
+func TrackActivity(recorder ActivityRecorder) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+
+		if userID, ok := GetUserIDFromContext(c); ok {
+			recorder.Touch(userID)
+		}
+
+		return err
+	}
+}
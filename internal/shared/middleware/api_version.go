@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go_boilerplate/internal/shared/apiversion"
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIVersion negotiates the API version of /api requests. Versioned paths (/api/v2/users) are
+// served as-is; unversioned ones (/api/users) are routed to the version named by Accept-Version
+// or a vendor media type in Accept (application/vnd.go-boilerplate.v2+json), else API_DEFAULT_VERSION.
+// Responses carry API-Version, plus Deprecation, Sunset and Link headers for deprecated versions.
+// Register it globally before other middleware that matches on paths.
+func APIVersion(cfg config.APIVersionConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		path := c.Path()
+		if !strings.HasPrefix(path, apiversion.Prefix) {
+			return c.Next()
+		}
+
+		version, versioned := apiversion.FromPath(path)
+		if versioned && !apiversion.Registered(version) {
+			return c.Next() // Unknown version: let routing answer 404
+		}
+
+		if !versioned {
+			c.Vary(fiber.HeaderAccept, apiversion.RequestHeader)
+
+			version = requestedVersion(c, cfg.Default)
+			if !apiversion.Registered(version) {
+				message := fmt.Sprintf("Unsupported API version %q", version)
+				return utils.ProblemResponse(c, fiber.StatusNotAcceptable, message, nil, map[string]any{"supported": apiversion.Versions()})
+			}
+			c.Path(apiversion.Prefix + version + path[len(apiversion.Prefix)-1:])
+		}
+
+		c.Set(apiversion.ResponseHeader, version)
+		if sunset, deprecated := cfg.Deprecated[version]; deprecated {
+			c.Set("Deprecation", "true")
+			if !sunset.IsZero() {
+				c.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			}
+			if cfg.DeprecationLink != "" {
+				c.Append(fiber.HeaderLink, fmt.Sprintf(`<%s>; rel="deprecation"`, cfg.DeprecationLink))
+			}
+		}
+
+		return c.Next()
+	}
+}
+
+// requestedVersion reads the version an unversioned request asks for, falling back to fallback
+func requestedVersion(c *fiber.Ctx, fallback string) string {
+	if version := strings.TrimSpace(c.Get(apiversion.RequestHeader)); version != "" {
+		return version
+	}
+	if version, ok := apiversion.FromAccept(c.Get(fiber.HeaderAccept)); ok {
+		return version
+	}
+	return fallback
+}
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/tenant"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// redactedValue replaces the value of sensitive body fields in audit entries
+const redactedValue = "[REDACTED]"
+
+// AuditEntry is a mutating request captured by Audit
+type AuditEntry struct {
+	Method     string
+	Path       string
+	Route      string     // Route pattern, e.g. /api/v1/users/:id
+	ActorID    string     // Authenticated user ID; empty for anonymous requests
+	TenantID   *uuid.UUID // Resolved by ResolveTenant; nil when tenancy is disabled or no tenant was named
+	Status     int
+	Body       string // JSON body with sensitive fields redacted; empty for non-JSON bodies
+	IPAddress  string
+	UserAgent  string
+	RequestID  string
+	DurationMs int64
+	OccurredAt time.Time
+}
+
+// AuditRecorder stores audit entries; Record must not block the request
+type AuditRecorder interface {
+	Record(entry AuditEntry)
+}
+
+// Audit records every POST/PUT/PATCH/DELETE request with its actor, final status and redacted body
+// The actor is read after the route's JWTAuth has run, so it must be registered before the routes
+func Audit(recorder AuditRecorder, cfg config.AuditConfig) fiber.Handler {
+	if !cfg.Enabled {
+		return passthrough
+	}
+
+	redact := redactSet(cfg.RedactFields)
+
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		default:
+			return c.Next()
+		}
+
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		actorID, _ := GetUserIDFromContext(c)
+		recorder.Record(AuditEntry{
+			Method:     c.Method(),
+			Path:       c.Path(),
+			Route:      c.Route().Path,
+			ActorID:    actorID,
+			TenantID:   tenant.IDFromContext(c.UserContext()),
+			Status:     status,
+			Body:       auditBody(c, redact, cfg.MaxBodySize),
+			IPAddress:  c.IP(),
+			UserAgent:  string(c.Request().Header.UserAgent()),
+			RequestID:  c.GetRespHeader(fiber.HeaderXRequestID),
+			DurationMs: time.Since(start).Milliseconds(),
+			OccurredAt: start,
+		})
+
+		return err
+	}
+}
+
+// auditBody returns the JSON request body with sensitive fields redacted, or a marker when it is too large
+func auditBody(c *fiber.Ctx, redact map[string]bool, maxSize int) string {
+	body := c.Body()
+	if len(body) == 0 || !strings.HasPrefix(string(c.Request().Header.ContentType()), fiber.MIMEApplicationJSON) {
+		return ""
+	}
+
+	var payload any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+
+	encoded, err := json.Marshal(redactFields(payload, redact))
+	if err != nil {
+		return ""
+	}
+	if maxSize > 0 && len(encoded) > maxSize {
+		marker, _ := json.Marshal(map[string]any{"_truncated": true, "size": len(encoded)})
+		return string(marker)
+	}
+	return string(encoded)
+}
+
+// RedactJSON encodes value as JSON with the values of sensitive keys (AUDIT_REDACT_FIELDS) replaced
+// at any depth, as in audit entries
+func RedactJSON(value any, fields []string) ([]byte, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload any
+	if err := json.Unmarshal(encoded, &payload); err != nil {
+		return nil, err
+	}
+	return json.Marshal(redactFields(payload, redactSet(fields)))
+}
+
+// redactSet returns the lower-cased field names looked up by redactFields
+func redactSet(fields []string) map[string]bool {
+	redact := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		redact[strings.ToLower(field)] = true
+	}
+	return redact
+}
+
+// redactFields replaces the values of sensitive keys at any depth
+func redactFields(value any, redact map[string]bool) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, nested := range v {
+			if redact[strings.ToLower(key)] {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redactFields(nested, redact)
+		}
+	case []any:
+		for i, nested := range v {
+			v[i] = redactFields(nested, redact)
+		}
+	}
+	return value
+}
@@ -1,129 +1,140 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"strings"
+	"time"
 
+	"go_boilerplate/internal/shared/apperror"
 	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/flags"
+	"go_boilerplate/internal/shared/observability"
+	"go_boilerplate/internal/shared/utils"
 
-	jwtware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// userLocalKey is the c.Locals key holding the authenticated user's *utils.JWTClaims
+const userLocalKey = "user"
+
+// claimsContextKey is the c.UserContext() key holding the same claims, for code that only has ctx
+type claimsContextKey struct{}
+
+// errMalformedJWT is returned when the Authorization header doesn't carry a bearer token
+var errMalformedJWT = apperror.New(apperror.ErrBadRequest, "Missing or malformed JWT").WithCode("malformed_token")
+
+// ErrInsufficientPermissions is answered when the user lacks a required role or permission
+var ErrInsufficientPermissions = apperror.New(apperror.ErrForbidden, "Insufficient permissions").WithCode("insufficient_permissions")
+
 // JWTAuth returns a JWT authentication middleware
 func JWTAuth(cfg *config.Config) fiber.Handler {
-	// Using Fiber's contrib JWT middleware
-	return jwtware.New(jwtware.Config{
-		SigningKey:   jwtware.SigningKey{Key: []byte(cfg.JWT.Secret)},
-		ErrorHandler: jwtError,
-	})
-}
+	jwtManager := newJWTManager(cfg)
 
-// jwtError handles JWT errors
-func jwtError(c *fiber.Ctx, err error) error {
-	if err.Error() == "Missing or malformed JWT" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "Missing or malformed JWT",
-		})
+	return func(c *fiber.Ctx) error {
+		wideEvent(c).StartTimer("middleware.jwt")
+		err := authenticate(c, jwtManager, c.Get(fiber.HeaderAuthorization))
+		wideEvent(c).StopTimer("middleware.jwt")
+		if err != nil {
+			return jwtError(c, err)
+		}
+		return c.Next()
 	}
-
-	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-		"success": false,
-		"error":   "Invalid or expired JWT",
-	})
 }
 
 // OptionalAuth is a middleware that checks for JWT but doesn't require it
 // If JWT is present and valid, it sets the user context
-// If JWT is missing, it continues without setting user context
+// If JWT is missing, it continues without setting user context; a malformed, invalid or expired JWT is rejected
 func OptionalAuth(cfg *config.Config) fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		authHeader := c.Get("Authorization")
+	jwtManager := newJWTManager(cfg)
 
-		// No authorization header, continue without auth
-		if authHeader == "" {
+	return func(c *fiber.Ctx) error {
+		header := c.Get(fiber.HeaderAuthorization)
+		if header == "" {
 			return c.Next()
 		}
 
-		// Check if header starts with "Bearer "
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"success": false,
-				"error":   "Invalid authorization header format",
-			})
+		wideEvent(c).StartTimer("middleware.optional_auth")
+		err := authenticate(c, jwtManager, header)
+		wideEvent(c).StopTimer("middleware.optional_auth")
+		if err != nil {
+			return jwtError(c, err)
 		}
+		return c.Next()
+	}
+}
 
-		// Extract token
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+// newJWTManager creates the manager used to validate access tokens
+func newJWTManager(cfg *config.Config) *utils.JWTManager {
+	return utils.NewJWTManager(cfg.JWT.Secret, cfg.JWT.AccessExpiry, cfg.JWT.RefreshExpiry, cfg.JWT.Issuer)
+}
 
-		// Create JWT middleware instance to validate
-		jwtMiddleware := jwtware.New(jwtware.Config{
-			SigningKey: jwtware.SigningKey{Key: []byte(cfg.JWT.Secret)},
-			ContextKey: "jwt",
-		})
+// authenticate validates the bearer token of an Authorization header (signature, expiry, not-before,
+// denylist) and stores its claims for the helpers below
+func authenticate(c *fiber.Ctx, jwtManager *utils.JWTManager, header string) error {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		recordAuthDecision(c, "jwt", nil, false, errMalformedJWT.Error())
+		return errMalformedJWT
+	}
 
-		// Create a fake context to test the token
-		app := fiber.New()
-		app.Use(jwtMiddleware)
+	claims, err := jwtManager.ValidateToken(token)
+	if err != nil {
+		recordAuthDecision(c, "jwt", nil, false, err.Error())
+		return err
+	}
+	if revoked(c.UserContext(), token) {
+		recordAuthDecision(c, "jwt", nil, false, errRevokedJWT.Error())
+		return errRevokedJWT
+	}
 
-		// Try to parse and validate the token
-		parser := jwt.NewParser(jwt.WithoutClaimsValidation())
+	c.Locals(userLocalKey, claims)
+	ctx := WithClaims(c.UserContext(), claims)
+	c.SetUserContext(flags.WithUser(ctx, claims.UserID.String())) // Feature flag targeting
+	recordAuthDecision(c, "jwt", nil, true, "")
+	return nil
+}
 
-		token, err := parser.Parse(tokenString, func(t *jwt.Token) (any, error) {
-			return []byte(cfg.JWT.Secret), nil
-		})
+// jwtError handles JWT errors
+func jwtError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, errMalformedJWT) {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "Missing or malformed JWT", errMalformedJWT)
+	}
 
-		if err != nil || !token.Valid {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"success": false,
-				"error":   "Invalid or expired JWT",
-			})
-		}
+	invalid := apperror.Wrap(apperror.ErrUnauthorized, "Invalid or expired JWT", err).WithCode("invalid_token")
+	return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Invalid or expired JWT", invalid)
+}
 
-		// Token is valid, store it in context
-		c.Locals("jwt", token)
-		c.Locals("user", token.Claims.(jwt.MapClaims))
+// getClaims returns the claims stored by JWTAuth or OptionalAuth
+func getClaims(c *fiber.Ctx) (*utils.JWTClaims, bool) {
+	claims, ok := c.Locals(userLocalKey).(*utils.JWTClaims)
+	return claims, ok && claims != nil
+}
 
-		return c.Next()
-	}
+// WithClaims stores the claims of the authenticated user in ctx; authenticate sets them on
+// c.UserContext() for services, which read them with ClaimsFromContext
+func WithClaims(ctx context.Context, claims *utils.JWTClaims) context.Context {
+	return context.WithValue(ctx, claimsContextKey{}, claims)
 }
 
-// getClaims extracts JWT claims from context handling both *jwt.Token and jwt.MapClaims
-func getClaims(c *fiber.Ctx) (jwt.MapClaims, bool) {
-	user := c.Locals("user")
-	if user == nil {
+// ClaimsFromContext returns the claims of the user authenticated for the request in ctx
+func ClaimsFromContext(ctx context.Context) (*utils.JWTClaims, bool) {
+	if ctx == nil {
 		return nil, false
 	}
-
-	// Case 1: stored as *jwt.Token (standard behavior of gofiber/contrib/jwt)
-	if token, ok := user.(*jwt.Token); ok {
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			return claims, true
-		}
-	}
-
-	// Case 2: stored directly as jwt.MapClaims (e.g. from OptionalAuth or custom override)
-	if claims, ok := user.(jwt.MapClaims); ok {
-		return claims, true
-	}
-
-	return nil, false
+	claims, ok := ctx.Value(claimsContextKey{}).(*utils.JWTClaims)
+	return claims, ok && claims != nil
 }
 
 // GetUserIDFromContext extracts user ID from JWT context
 func GetUserIDFromContext(c *fiber.Ctx) (string, bool) {
 	claims, ok := getClaims(c)
-	if !ok {
-		return "", false
-	}
-
-	userID, ok := claims["user_id"].(string)
-	if !ok {
+	if !ok || claims.UserID == uuid.Nil {
 		return "", false
 	}
 
-	return userID, true
+	return claims.UserID.String(), true
 }
 
 // GetEmailFromContext extracts email from JWT context
@@ -133,12 +144,7 @@ func GetEmailFromContext(c *fiber.Ctx) (string, bool) {
 		return "", false
 	}
 
-	email, ok := claims["email"].(string)
-	if !ok {
-		return "", false
-	}
-
-	return email, true
+	return claims.Email, claims.Email != ""
 }
 
 // RequireRole checks if the authenticated user has one of the required roles
@@ -146,33 +152,25 @@ func RequireRole(cfg *config.Config, roles ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		claims, ok := getClaims(c)
 		if !ok {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"success": false,
-				"error":   "Unauthorized",
-			})
+			return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Unauthorized", nil)
 		}
 
-		// Extract role_slug from claims
-		userRoleSlug, ok := claims["role_slug"].(string)
+		// Extract roles from claims
+		userRoles, ok := rolesFromClaims(claims)
 		if !ok {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"success": false,
-				"error":   "Role information not found in token",
-			})
+			return utils.ErrorResponse(c, fiber.StatusForbidden, "Role information not found in token", nil)
 		}
 
 		// Check if user has any of the required roles
-		for _, role := range roles {
-			if userRoleSlug == role {
-				return c.Next()
-			}
+		if containsAny(userRoles, roles) {
+			recordAuthDecision(c, "role", roles, true, "")
+			return c.Next()
 		}
 
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"success":       false,
-			"error":         "Insufficient permissions",
+		recordAuthDecision(c, "role", roles, false, "missing role")
+		return utils.ProblemResponse(c, fiber.StatusForbidden, "Insufficient permissions", ErrInsufficientPermissions, map[string]any{
 			"required_roles": roles,
-			"user_role":      userRoleSlug,
+			"user_roles":     userRoles,
 		})
 	}
 }
@@ -182,60 +180,123 @@ func RequirePermission(cfg *config.Config, permission string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		claims, ok := getClaims(c)
 		if !ok {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"success": false,
-				"error":   "Unauthorized",
-			})
+			return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Unauthorized", nil)
 		}
 
-		// Get permissions from claims
-		permissionsInterface, ok := claims["permissions"].([]interface{})
-		if !ok {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"success": false,
-				"error":   "Permissions not found in token",
-			})
+		if a, ok := currentAuthorizer(); ok {
+			return requireFromAuthorizer(c, a, "permission", permission)
 		}
 
-		// Convert to string slice
-		permissions := make([]string, len(permissionsInterface))
-		for i, p := range permissionsInterface {
-			if str, ok := p.(string); ok {
-				permissions[i] = str
-			}
+		// Get permissions from claims
+		permissions := claims.Permissions
+		if permissions == nil {
+			return utils.ErrorResponse(c, fiber.StatusForbidden, "Permissions not found in token", nil)
 		}
 
 		// Check for wildcard permission
 		for _, p := range permissions {
 			if p == "*" {
+				recordAuthDecision(c, "permission", []string{permission}, true, "wildcard")
 				return c.Next()
 			}
 		}
 
 		// Check specific permission
+		// Grants are matched exactly: role profiles expand patterns such as roles.* to catalog names
+		// when roles are saved, so tokens never need pattern matching here
 		for _, p := range permissions {
 			if p == permission {
+				recordAuthDecision(c, "permission", []string{permission}, true, "")
 				return c.Next()
 			}
 		}
 
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"success":   false,
-			"error":     "Insufficient permissions",
-			"required":  permission,
-		})
+		recordAuthDecision(c, "permission", []string{permission}, false, "missing permission")
+
+		return utils.ProblemResponse(c, fiber.StatusForbidden, "Insufficient permissions", ErrInsufficientPermissions, map[string]any{"required": permission})
+	}
+}
+
+// PermissionResolver loads the current effective permissions of a user
+type PermissionResolver interface {
+	UserPermissions(ctx context.Context, userID string) ([]string, error)
+}
+
+// RequirePermissionLive checks a permission against the user's current roles instead of the token claims,
+// so revoking a permission takes effect immediately rather than when the access token is refreshed
+// Must be used after JWTAuth
+func RequirePermissionLive(resolver PermissionResolver, permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := GetUserIDFromContext(c)
+		if !ok {
+			return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Unauthorized", nil)
+		}
+
+		// An external authorizer reads its own policy store, so it is already live
+		if a, ok := currentAuthorizer(); ok {
+			return requireFromAuthorizer(c, a, "permission_live", permission)
+		}
+
+		start := time.Now()
+		permissions, err := resolver.UserPermissions(c.UserContext(), userID)
+		wideEvent(c).AddDuration("middleware.permission_live", time.Since(start))
+		if err != nil {
+			recordAuthDecision(c, "permission_live", []string{permission}, false, err.Error())
+			return utils.ErrorResponse(c, fiber.StatusForbidden, "Unable to verify permissions", nil)
+		}
+
+		for _, p := range permissions {
+			if p == "*" || p == permission {
+				recordAuthDecision(c, "permission_live", []string{permission}, true, "")
+				return c.Next()
+			}
+		}
+
+		recordAuthDecision(c, "permission_live", []string{permission}, false, "missing permission")
+		return utils.ProblemResponse(c, fiber.StatusForbidden, "Insufficient permissions", ErrInsufficientPermissions, map[string]any{"required": permission})
 	}
 }
 
-// GetRoleSlugFromContext extracts role slug from JWT context
-func GetRoleSlugFromContext(c *fiber.Ctx) (string, bool) {
+// GetRolesFromContext extracts role slugs from JWT context
+func GetRolesFromContext(c *fiber.Ctx) ([]string, bool) {
 	claims, ok := getClaims(c)
 	if !ok {
-		return "", false
+		return nil, false
 	}
 
-	roleSlug, ok := claims["role_slug"].(string)
-	return roleSlug, ok
+	return rolesFromClaims(claims)
+}
+
+// HasAnyRole reports whether the authenticated user has any of the given roles
+func HasAnyRole(c *fiber.Ctx, roles ...string) bool {
+	userRoles, ok := GetRolesFromContext(c)
+	return ok && containsAny(userRoles, roles)
+}
+
+// rolesFromClaims reads the roles claim
+// Tokens issued before users could hold several roles carry a single role_slug instead
+func rolesFromClaims(claims *utils.JWTClaims) ([]string, bool) {
+	if claims.Roles != nil {
+		return claims.Roles, true
+	}
+
+	if claims.RoleSlug != "" {
+		return []string{claims.RoleSlug}, true
+	}
+
+	return nil, false
+}
+
+// containsAny reports whether any of wanted is in have
+func containsAny(have, wanted []string) bool {
+	for _, h := range have {
+		for _, w := range wanted {
+			if h == w {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // GetPermissionsFromContext extracts permissions from JWT context
@@ -245,17 +306,15 @@ func GetPermissionsFromContext(c *fiber.Ctx) ([]string, bool) {
 		return nil, false
 	}
 
-	permissionsInterface, ok := claims["permissions"].([]interface{})
-	if !ok {
-		return nil, false
-	}
-
-	permissions := make([]string, len(permissionsInterface))
-	for i, p := range permissionsInterface {
-		if str, ok := p.(string); ok {
-			permissions[i] = str
-		}
-	}
+	return claims.Permissions, claims.Permissions != nil
+}
 
-	return permissions, true
+// recordAuthDecision adds an authentication/authorization outcome to the debug trace of X-Debug requests
+func recordAuthDecision(c *fiber.Ctx, check string, required []string, granted bool, reason string) {
+	wideEvent(c).RecordAuthDecision(observability.DebugAuthDecision{
+		Check:    check,
+		Required: required,
+		Granted:  granted,
+		Reason:   reason,
+	})
 }
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/flags"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// withTestClaims stands in for JWTAuth, storing claims with the given permissions
+func withTestClaims(permissions []string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(userLocalKey, &utils.JWTClaims{UserID: uuid.New(), Permissions: permissions})
+		return c.Next()
+	}
+}
+
+type staticPermissionResolver []string
+
+func (r staticPermissionResolver) UserPermissions(context.Context, string) ([]string, error) {
+	return r, nil
+}
+
+// permissionGrantCases are matched exactly: patterns are expanded when roles are saved, so a
+// pattern left in a token must not grant anything beyond its own name
+var permissionGrantCases = []struct {
+	name    string
+	granted []string
+	allowed bool
+}{
+	{"exact", []string{"users.read", "roles.read"}, true},
+	{"wildcard", []string{"*"}, true},
+	{"missing", []string{"users.read"}, false},
+	{"resource pattern", []string{"roles.*"}, false},
+	{"action pattern", []string{"*.read"}, false},
+	{"none", []string{}, false},
+}
+
+func checkPermissionStatus(t *testing.T, app *fiber.App, allowed bool) {
+	t.Helper()
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	want := fiber.StatusForbidden
+	if allowed {
+		want = fiber.StatusNoContent
+	}
+	if resp.StatusCode != want {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, want)
+	}
+}
+
+func TestRequirePermissionMatchesExactly(t *testing.T) {
+	for _, tc := range permissionGrantCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", withTestClaims(tc.granted), RequirePermission(&config.Config{}, "roles.read"), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusNoContent)
+			})
+			checkPermissionStatus(t, app, tc.allowed)
+		})
+	}
+}
+
+func TestRequirePermissionLiveMatchesExactly(t *testing.T) {
+	for _, tc := range permissionGrantCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", withTestClaims(nil), RequirePermissionLive(staticPermissionResolver(tc.granted), "roles.read"), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusNoContent)
+			})
+			checkPermissionStatus(t, app, tc.allowed)
+		})
+	}
+}
+
+func TestHasPermissionMatchesExactly(t *testing.T) {
+	for _, tc := range permissionGrantCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", withTestClaims(tc.granted), func(c *fiber.Ctx) error {
+				if !HasPermission(c, "roles.read") {
+					return c.SendStatus(fiber.StatusForbidden)
+				}
+				return c.SendStatus(fiber.StatusNoContent)
+			})
+			checkPermissionStatus(t, app, tc.allowed)
+		})
+	}
+}
+
+func TestJWTAuthStoresClaimsInUserContext(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.JWT = config.JWTConfig{Secret: "auth-context-test", AccessExpiry: time.Minute, RefreshExpiry: time.Hour, Issuer: "test"}
+	userID := uuid.New()
+	token, err := newJWTManager(cfg).GenerateAccessToken(userID, "user@example.com", []string{"user"}, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New()
+	app.Get("/", JWTAuth(cfg), func(c *fiber.Ctx) error {
+		// Services only get c.UserContext(); the feature flag user must not be the source of identity
+		claims, ok := ClaimsFromContext(c.UserContext())
+		if !ok || claims.UserID != userID {
+			return c.SendStatus(fiber.StatusForbidden)
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != fiber.StatusNoContent {
+		t.Fatalf("status = %d, want the claims in c.UserContext()", resp.StatusCode)
+	}
+
+	if _, ok := ClaimsFromContext(flags.WithUser(context.Background(), userID.String())); ok {
+		t.Fatal("ClaimsFromContext accepted a feature flag user as the authenticated user")
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BodyLimit rejects request bodies larger than maxBytes with 413 for a route group
+// It can only tighten the global BODY_LIMIT, which Fiber enforces before any handler runs
+func BodyLimit(maxBytes int) fiber.Handler {
+	if maxBytes <= 0 {
+		return passthrough
+	}
+
+	return func(c *fiber.Ctx) error {
+		if c.Request().Header.ContentLength() > maxBytes || len(c.Body()) > maxBytes {
+			return utils.ProblemResponse(c, fiber.StatusRequestEntityTooLarge, "Request body too large", nil, map[string]any{"max_bytes": maxBytes})
+		}
+		return c.Next()
+	}
+}
+
+// isJSONRequest reports whether the request declares a JSON body (application/json or a +json type)
+func isJSONRequest(c *fiber.Ctx) bool {
+	return isJSONContentType(string(c.Request().Header.ContentType()))
+}
+
+// isJSONContentType reports whether contentType is application/json or a +json type (e.g. application/problem+json)
+func isJSONContentType(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	contentType = strings.TrimSpace(contentType)
+	return contentType == fiber.MIMEApplicationJSON || strings.HasSuffix(contentType, "+json")
+}
+
+// unsupportedMediaType answers 415 for a body that isn't JSON
+func unsupportedMediaType(c *fiber.Ctx) error {
+	message := "Content-Type must be " + fiber.MIMEApplicationJSON
+	if received := string(c.Request().Header.ContentType()); received != "" {
+		message += " (received " + strconv.Quote(received) + ")"
+	}
+	return utils.ErrorResponse(c, fiber.StatusUnsupportedMediaType, message, nil)
+}
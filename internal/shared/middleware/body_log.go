@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+
+	"go_boilerplate/internal/shared/config"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// BodyLogger logs the request and response bodies of a sample of requests, for troubleshooting in
+// staging. JSON bodies have the values of BODY_LOG_REDACT_FIELDS replaced (any depth) and are cut at
+// BODY_LOG_MAX_BODY_SIZE bytes; other bodies are only described by content type and size, since
+// they can't be redacted reliably. Paths starting with one of the exempt prefixes are never logged.
+func BodyLogger(logger *logrus.Logger, cfg config.BodyLogConfig, exempt ...string) fiber.Handler {
+	if !cfg.Enabled || cfg.SampleRate <= 0 {
+		return passthrough
+	}
+
+	redact := redactSet(cfg.RedactFields)
+
+	return func(c *fiber.Ctx) error {
+		if hasAnyPrefix(c.Path(), exempt) || (cfg.SampleRate < 1 && rand.Float64() >= cfg.SampleRate) {
+			return c.Next()
+		}
+
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		logger.WithFields(logrus.Fields{
+			"method":        c.Method(),
+			"path":          c.Path(),
+			"status":        status,
+			"request_id":    c.GetRespHeader(fiber.HeaderXRequestID),
+			"request_body":  loggedRequestBody(c, redact, cfg.MaxBodySize),
+			"response_body": loggedResponseBody(c, redact, cfg.MaxBodySize),
+		}).Info("Request and response bodies")
+
+		return err
+	}
+}
+
+// loggedRequestBody returns the request body as it is logged
+func loggedRequestBody(c *fiber.Ctx, redact map[string]bool, maxSize int) string {
+	return loggedBody(c.Body(), string(c.Request().Header.ContentType()), redact, maxSize)
+}
+
+// loggedResponseBody returns the response body as it is logged; streamed and compressed bodies are only described
+func loggedResponseBody(c *fiber.Ctx, redact map[string]bool, maxSize int) string {
+	contentType := string(c.Response().Header.ContentType())
+	if c.Response().IsBodyStream() {
+		return fmt.Sprintf("[streamed %s]", contentType)
+	}
+	if encoding := c.GetRespHeader(fiber.HeaderContentEncoding); encoding != "" {
+		return fmt.Sprintf("[%s %s, %d bytes]", encoding, contentType, len(c.Response().Body()))
+	}
+	return loggedBody(c.Response().Body(), contentType, redact, maxSize)
+}
+
+// loggedBody redacts and truncates a JSON body, or describes any other body by content type and size
+func loggedBody(body []byte, contentType string, redact map[string]bool, maxSize int) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if !isJSONContentType(contentType) {
+		return fmt.Sprintf("[%s, %d bytes]", contentType, len(body))
+	}
+
+	var payload any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return fmt.Sprintf("[invalid JSON, %d bytes]", len(body))
+	}
+
+	encoded, err := json.Marshal(redactFields(payload, redact))
+	if err != nil {
+		return ""
+	}
+	if maxSize > 0 && len(encoded) > maxSize {
+		return fmt.Sprintf("%s...[truncated, %d bytes]", encoded[:maxSize], len(encoded))
+	}
+	return string(encoded)
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"go_boilerplate/internal/shared/cache"
+	"go_boilerplate/internal/shared/i18n"
+	"go_boilerplate/internal/shared/tenant"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CacheStatusHeader reports whether a response was served from the response cache (HIT or MISS)
+const CacheStatusHeader = "X-Cache"
+
+// DefaultCacheKey caches per path, query, tenant, user and locale, so users never see each other's
+// responses, nor tenants each other's
+const DefaultCacheKey = "{path}?{query}|{tenant}|{user}|{locale}"
+
+// CacheRule configures caching of one route
+type CacheRule struct {
+	Namespace string        // Invalidated with cache.ResponseCache.Invalidate after writes
+	TTL       time.Duration // 0 uses RESPONSE_CACHE_TTL
+	// Key template; placeholders are {path}, {query} (sorted), {tenant}, {user} and {locale}.
+	// Drop {user} only for responses that don't depend on who asks, and {tenant} only for ones
+	// that are the same in every tenant. Defaults to DefaultCacheKey.
+	Key string
+}
+
+// Cache serves successful GET responses of a route from Redis until they expire or the rule's
+// namespace is invalidated. Register it on the route after authorization middleware, so hits are
+// only served to requests allowed to see them, and after Compress/ETag so they see plain bodies.
+// X-Debug requests and Redis failures bypass the cache.
+func Cache(responses *cache.ResponseCache, rule CacheRule) fiber.Handler {
+	if !responses.Enabled() {
+		return passthrough
+	}
+
+	ttl := rule.TTL
+	if ttl <= 0 {
+		ttl = responses.DefaultTTL()
+	}
+	template := rule.Key
+	if template == "" {
+		template = DefaultCacheKey
+	}
+
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet || isDebugRequest(c) {
+			return c.Next()
+		}
+
+		ctx := c.UserContext()
+		key, err := responses.Key(ctx, rule.Namespace, renderCacheKey(c, template))
+		if err != nil {
+			return c.Next()
+		}
+
+		if entry, ok := responses.Get(ctx, key); ok {
+			wideEvent(c).Set("cache", "hit")
+			c.Set(CacheStatusHeader, "HIT")
+			c.Set(fiber.HeaderContentType, entry.ContentType)
+			for name, value := range entry.Headers {
+				c.Set(name, value)
+			}
+			return c.Status(fiber.StatusOK).Send(entry.Body)
+		}
+
+		c.Set(CacheStatusHeader, "MISS")
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		if c.Response().StatusCode() == fiber.StatusOK {
+			entry := cache.Entry{
+				ContentType: string(c.Response().Header.ContentType()),
+				Headers:     cachedHeaders(c),
+				Body:        append([]byte(nil), c.Response().Body()...),
+			}
+			_ = responses.Set(ctx, key, entry, ttl)
+		}
+		return nil
+	}
+}
+
+// cachedHeaders returns the pagination headers of the response (set when the envelope is disabled)
+func cachedHeaders(c *fiber.Ctx) map[string]string {
+	var headers map[string]string
+	for _, name := range utils.PaginationHeaders {
+		if value := c.GetRespHeader(name); value != "" {
+			if headers == nil {
+				headers = make(map[string]string)
+			}
+			headers[name] = value
+		}
+	}
+	return headers
+}
+
+// renderCacheKey fills the placeholders of a cache key template
+func renderCacheKey(c *fiber.Ctx, template string) string {
+	userID, _ := GetUserIDFromContext(c)
+	var tenantID string
+	if id := tenant.IDFromContext(c.UserContext()); id != nil {
+		tenantID = id.String()
+	}
+	return strings.NewReplacer(
+		"{path}", c.Path(),
+		"{query}", canonicalQuery(c),
+		"{tenant}", tenantID,
+		"{user}", userID,
+		"{locale}", i18n.FromContext(c.UserContext()),
+	).Replace(template)
+}
+
+// canonicalQuery sorts query parameters by name so equivalent URLs share a cache entry
+func canonicalQuery(c *fiber.Ctx) string {
+	values := url.Values{}
+	c.Request().URI().QueryArgs().VisitAll(func(key, value []byte) {
+		values.Add(string(key), string(value))
+	})
+	return values.Encode()
+}
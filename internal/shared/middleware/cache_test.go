@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"go_boilerplate/internal/shared/tenant"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// renderTestCacheKey renders template for a GET /roles?page=2&limit=10 request in the given tenant
+func renderTestCacheKey(t *testing.T, template string, requestTenant *tenant.Tenant, userID uuid.UUID) string {
+	t.Helper()
+
+	var key string
+	app := fiber.New()
+	app.Get("/roles", func(c *fiber.Ctx) error {
+		if requestTenant != nil {
+			c.SetUserContext(tenant.WithTenant(c.UserContext(), requestTenant))
+		}
+		c.Locals(userLocalKey, &utils.JWTClaims{UserID: userID})
+		key = renderCacheKey(c, template)
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/roles?page=2&limit=10", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return key
+}
+
+func TestRenderCacheKeySeparatesTenants(t *testing.T) {
+	acme := &tenant.Tenant{ID: uuid.New(), Slug: "acme"}
+	globex := &tenant.Tenant{ID: uuid.New(), Slug: "globex"}
+	userID := uuid.New()
+
+	for _, template := range []string{DefaultCacheKey, "{path}?{query}|{tenant}|{locale}"} {
+		acmeKey := renderTestCacheKey(t, template, acme, userID)
+		globexKey := renderTestCacheKey(t, template, globex, userID)
+		noTenantKey := renderTestCacheKey(t, template, nil, userID)
+
+		if acmeKey == globexKey || acmeKey == noTenantKey {
+			t.Errorf("%q: tenants share a cache key: %q, %q, %q", template, acmeKey, globexKey, noTenantKey)
+		}
+	}
+
+	want := "/roles?limit=10&page=2|" + acme.ID.String() + "|" + userID.String() + "|"
+	if got := renderTestCacheKey(t, "{path}?{query}|{tenant}|{user}|", acme, userID); got != want {
+		t.Fatalf("key = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"strings"
+
+	"go_boilerplate/internal/shared/config"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+	"github.com/gofiber/fiber/v2/middleware/etag"
+)
+
+// Compress compresses responses of a route group with brotli, gzip or deflate, as negotiated by
+// Accept-Encoding. Small bodies and non-text content types (e.g. export archives) are sent as-is.
+// X-Debug requests are skipped so Debug can still rewrite the JSON body.
+func Compress(cfg *config.Config) fiber.Handler {
+	if !cfg.Compression.Enabled {
+		return passthrough
+	}
+
+	level := compress.LevelDefault
+	switch cfg.Compression.Level {
+	case "speed":
+		level = compress.LevelBestSpeed
+	case "best":
+		level = compress.LevelBestCompression
+	}
+
+	return compress.New(compress.Config{
+		Next:  isDebugRequest,
+		Level: level,
+	})
+}
+
+// ETag adds an ETag to successful GET/HEAD responses of a route group and answers a matching
+// If-None-Match with 304 Not Modified. Register it after Compress on the same group so the tag
+// is computed from the uncompressed body.
+func ETag(cfg *config.Config) fiber.Handler {
+	if !cfg.Compression.ETag {
+		return passthrough
+	}
+
+	return etag.New(etag.Config{
+		Next: func(c *fiber.Ctx) bool {
+			method := c.Method()
+			return (method != fiber.MethodGet && method != fiber.MethodHead) || isDebugRequest(c)
+		},
+	})
+}
+
+// isDebugRequest reports whether the request asks for debug info, whose body changes per response
+func isDebugRequest(c *fiber.Ctx) bool {
+	return strings.EqualFold(c.Get(DebugHeader), "true")
+}
+
+// passthrough is used in place of a disabled middleware
+func passthrough(c *fiber.Ctx) error {
+	return c.Next()
+}
@@ -1,41 +1,78 @@
 package middleware
 
 import (
+	"strconv"
+	"strings"
+
 	"go_boilerplate/internal/shared/config"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// CORS returns a CORS middleware
+// CORS returns a CORS middleware driven by CORSConfig
+// Allowed origins are echoed back (never `*`) so credentialed requests work; other origins get no
+// CORS headers and the browser blocks the response. Entries may be exact origins, wildcard
+// subdomains such as https://*.example.com, or `*` for any origin. Origins follow config reloads
 func CORS(cfg *config.Config) fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		// Allow all origins in development
-		if cfg.Server.IsDevelopment() {
-			c.Set("Access-Control-Allow-Origin", "*")
-		} else {
-			// In production, you should specify allowed origins
-			origin := c.Get("Origin")
-			// You can add your own logic here to validate origin
-			c.Set("Access-Control-Allow-Origin", origin)
-		}
+	methods := strings.Join(cfg.CORS.AllowedMethods, ",")
+	headers := strings.Join(cfg.CORS.AllowedHeaders, ",")
+	exposed := strings.Join(cfg.CORS.ExposedHeaders, ",")
+	maxAge := strconv.Itoa(int(cfg.CORS.MaxAge.Seconds()))
 
-		// Allow methods
-		c.Set("Access-Control-Allow-Methods", "GET,POST,PUT,DELETE,OPTIONS")
+	return func(c *fiber.Ctx) error {
+		origin := c.Get(fiber.HeaderOrigin)
+		preflight := c.Method() == fiber.MethodOptions && c.Get(fiber.HeaderAccessControlRequestMethod) != ""
 
-		// Allow headers
-		c.Set("Access-Control-Allow-Headers", "Origin,Content-Type,Accept,Authorization")
+		// Responses differ per Origin, so shared caches must key on it
+		c.Vary(fiber.HeaderOrigin)
 
-		// Allow credentials
-		c.Set("Access-Control-Allow-Credentials", "true")
+		if origin == "" || !originAllowed(origin, cfg.Hot().CORSOrigins) {
+			if preflight {
+				return c.SendStatus(fiber.StatusNoContent)
+			}
+			return c.Next()
+		}
 
-		// Max age
-		c.Set("Access-Control-Max-Age", "86400")
+		c.Set(fiber.HeaderAccessControlAllowOrigin, origin)
+		if cfg.CORS.AllowCredentials {
+			c.Set(fiber.HeaderAccessControlAllowCredentials, "true")
+		}
 
-		// Handle preflight requests
-		if c.Method() == "OPTIONS" {
+		if preflight {
+			c.Set(fiber.HeaderAccessControlAllowMethods, methods)
+			if headers != "" {
+				c.Set(fiber.HeaderAccessControlAllowHeaders, headers)
+			} else if requested := c.Get(fiber.HeaderAccessControlRequestHeaders); requested != "" {
+				c.Set(fiber.HeaderAccessControlAllowHeaders, requested)
+			}
+			if cfg.CORS.MaxAge > 0 {
+				c.Set(fiber.HeaderAccessControlMaxAge, maxAge)
+			}
 			return c.SendStatus(fiber.StatusNoContent)
 		}
 
+		if exposed != "" {
+			c.Set(fiber.HeaderAccessControlExposeHeaders, exposed)
+		}
 		return c.Next()
 	}
 }
+
+// originAllowed matches origin against exact entries, `*` and wildcard subdomain entries
+// (https://*.example.com matches https://api.example.com but not https://example.com)
+func originAllowed(origin string, allowed []string) bool {
+	origin = strings.ToLower(origin)
+	for _, entry := range allowed {
+		entry = strings.ToLower(entry)
+		switch {
+		case entry == "*" || entry == origin:
+			return true
+		case strings.Contains(entry, "://*."):
+			scheme, host, _ := strings.Cut(entry, "*")
+			if strings.HasPrefix(origin, scheme) && strings.HasSuffix(origin, host) && len(origin) > len(scheme)+len(host) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"encoding/json"
+	"strings"
+
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/observability"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DebugHeader is the request header that asks for debug information in the response
+const DebugHeader = "X-Debug"
+
+// Debug attaches timing breakdowns, executed SQL, cache operations and authorization
+// decisions to the response under meta.debug when a super_admin sends X-Debug: true.
+// It must be registered after WideEvent so it can reuse the request's event.
+func Debug(cfg *config.Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !cfg.Debug.Enabled || !strings.EqualFold(c.Get(DebugHeader), "true") {
+			return c.Next()
+		}
+
+		// Wide events may be disabled; debug mode still needs an event to collect into
+		event := wideEvent(c)
+		if event == nil {
+			event = observability.NewEvent()
+			c.SetUserContext(observability.WithEvent(c.UserContext(), event))
+		}
+		event.EnableDebug()
+
+		err := c.Next()
+
+		// Roles are only known once route-level auth has run, so the check happens afterwards
+		if err != nil || !HasAnyRole(c, "super_admin") {
+			return err
+		}
+
+		attachDebugInfo(c, event.DebugInfo())
+		return nil
+	}
+}
+
+// attachDebugInfo merges debug info into the meta object of a JSON response body
+func attachDebugInfo(c *fiber.Ctx, info map[string]any) {
+	if !isJSONContentType(string(c.Response().Header.ContentType())) {
+		return
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(c.Response().Body(), &body); err != nil {
+		return
+	}
+
+	meta := map[string]any{}
+	if raw, ok := body["meta"]; ok {
+		if err := json.Unmarshal(raw, &meta); err != nil {
+			return
+		}
+	}
+	meta["debug"] = info
+
+	encodedMeta, err := json.Marshal(meta)
+	if err != nil {
+		return
+	}
+	body["meta"] = encodedMeta
+
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return
+	}
+	c.Response().SetBodyRaw(encoded)
+	c.Set(DebugHeader, "true")
+}
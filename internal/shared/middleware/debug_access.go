@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	"go_boilerplate/internal/shared/apperror"
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DebugTokenHeader is the request header carrying DEBUG_TOKEN to the /debug endpoints
+const DebugTokenHeader = "X-Debug-Token"
+
+// errInvalidDebugToken is returned when X-Debug-Token doesn't match DEBUG_TOKEN
+var errInvalidDebugToken = apperror.New(apperror.ErrUnauthorized, "Invalid debug token").WithCode("invalid_debug_token")
+
+// RequireDebugAccess guards the runtime debug endpoints (pprof, expvar): a request carrying
+// DEBUG_TOKEN in X-Debug-Token is let through, any other needs the access token of a super_admin.
+// A wrong debug token is rejected rather than falling back to the Authorization header.
+func RequireDebugAccess(cfg *config.Config) fiber.Handler {
+	jwtManager := newJWTManager(cfg)
+	superAdmin := RequireRole(cfg, "super_admin")
+	expected := []byte(cfg.Debug.Token)
+
+	return func(c *fiber.Ctx) error {
+		if token := c.Get(DebugTokenHeader); token != "" {
+			if len(expected) == 0 || subtle.ConstantTimeCompare([]byte(token), expected) != 1 {
+				recordAuthDecision(c, "debug_token", nil, false, errInvalidDebugToken.Error())
+				return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Invalid debug token", errInvalidDebugToken)
+			}
+			recordAuthDecision(c, "debug_token", nil, true, "")
+			return c.Next()
+		}
+
+		if err := authenticate(c, jwtManager, c.Get(fiber.HeaderAuthorization)); err != nil {
+			return jwtError(c, err)
+		}
+		return superAdmin(c)
+	}
+}
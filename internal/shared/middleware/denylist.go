@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// errRevokedJWT is returned for access tokens revoked before they expired (e.g. on logout)
+var errRevokedJWT = errors.New("token has been revoked")
+
+// TokenDenylist tells revoked access tokens apart (e.g. session.Denylist)
+type TokenDenylist interface {
+	Denied(ctx context.Context, token string) (bool, error)
+}
+
+type denylistHolder struct{ TokenDenylist }
+
+var denylist atomic.Pointer[denylistHolder]
+
+// UseDenylist makes JWTAuth and OptionalAuth refuse access tokens found in d; nil accepts every
+// valid token again
+func UseDenylist(d TokenDenylist) {
+	if d == nil {
+		denylist.Store(nil)
+		return
+	}
+	denylist.Store(&denylistHolder{d})
+}
+
+// revoked reports whether token is on the configured denylist. Lookup failures accept the token:
+// access tokens are short-lived, and a Redis outage must not sign every user out.
+func revoked(ctx context.Context, token string) bool {
+	holder := denylist.Load()
+	if holder == nil {
+		return false
+	}
+	denied, err := holder.Denied(ctx, token)
+	return err == nil && denied
+}
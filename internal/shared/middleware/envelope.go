@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ResponseEnvelope sets whether utils.SuccessResponse wraps responses in APIResponse. Registered
+// globally with RESPONSE_ENVELOPE; a route or group registering it again overrides the global
+// setting, e.g. middleware.ResponseEnvelope(false) for endpoints consumed as plain REST. Without
+// the envelope, list pagination is sent as X-Total-Count, X-Total-Pages, X-Page, X-Per-Page and
+// Link headers. Error responses are problem+json either way.
+func ResponseEnvelope(enabled bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		utils.SetEnvelope(c, enabled)
+		return c.Next()
+	}
+}
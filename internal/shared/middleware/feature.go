@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"go_boilerplate/internal/shared/flags"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireFeature answers 404 while the named feature flag is off for the request, so routes behind a
+// flag look absent until it is turned on. Register it after JWTAuth or OptionalAuth for per-user and
+// percentage targeting; flags follow config reloads and admin overrides.
+func RequireFeature(features *flags.Flags, name string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !features.IsEnabled(c.UserContext(), name) {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "Resource not found", nil)
+		}
+		return c.Next()
+	}
+}
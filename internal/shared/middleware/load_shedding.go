@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"go_boilerplate/internal/shared/apperror"
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errOverloaded is answered to requests shed by ShedLoad and ConcurrencyLimit
+var errOverloaded = apperror.New(apperror.ErrUnavailable, "Service is under heavy load, please retry shortly").WithCode("overloaded")
+
+// SaturationSignal reports whether a shared resource (e.g. the database pool) is overloaded
+type SaturationSignal interface {
+	Saturated() bool
+}
+
+// ShedLoad rejects requests with 503 while signal reports saturation, except for paths
+// starting with one of the exempt prefixes (health checks and latency-critical auth endpoints)
+func ShedLoad(signal SaturationSignal, retryAfter time.Duration, exempt ...string) fiber.Handler {
+	retryAfterSeconds := strconv.Itoa(max(1, int(retryAfter.Seconds())))
+
+	return func(c *fiber.Ctx) error {
+		if !signal.Saturated() {
+			return c.Next()
+		}
+
+		if hasAnyPrefix(c.Path(), exempt) {
+			return c.Next()
+		}
+
+		return shed(c, "pool_saturated", retryAfterSeconds)
+	}
+}
+
+// ConcurrencyLimit caps the requests in flight through the handlers it guards at limit. Register it
+// globally for an overall cap, or on a route group (or route) for a separate, tighter cap on expensive
+// endpoints. A request over the cap waits up to CONCURRENCY_QUEUE_TIMEOUT for a slot, then gets 503
+// with Retry-After. Paths starting with one of the exempt prefixes are never limited.
+func ConcurrencyLimit(cfg config.ConcurrencyConfig, limit int, exempt ...string) fiber.Handler {
+	if !cfg.Enabled || limit <= 0 {
+		return passthrough
+	}
+
+	slots := make(chan struct{}, limit)
+	retryAfterSeconds := strconv.Itoa(max(1, int(cfg.RetryAfter.Seconds())))
+
+	return func(c *fiber.Ctx) error {
+		if hasAnyPrefix(c.Path(), exempt) {
+			return c.Next()
+		}
+
+		select {
+		case slots <- struct{}{}:
+		default:
+			if !waitForSlot(c, slots, cfg.QueueTimeout) {
+				return shed(c, "concurrency_limit", retryAfterSeconds)
+			}
+		}
+		defer func() { <-slots }()
+
+		return c.Next()
+	}
+}
+
+// waitForSlot blocks until a slot frees up or timeout passes
+func waitForSlot(c *fiber.Ctx, slots chan struct{}, timeout time.Duration) bool {
+	if timeout <= 0 {
+		return false
+	}
+
+	start := time.Now()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	defer func() { wideEvent(c).AddDuration("middleware.concurrency_wait", time.Since(start)) }()
+
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// shed rejects a request because the service is overloaded
+func shed(c *fiber.Ctx, reason, retryAfterSeconds string) error {
+	wideEvent(c).Set("load_shed", true)
+	wideEvent(c).Set("load_shed_reason", reason)
+	c.Set(fiber.HeaderRetryAfter, retryAfterSeconds)
+	return utils.ErrorResponse(c, fiber.StatusServiceUnavailable, "Service is under heavy load, please retry shortly", errOverloaded)
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/i18n"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Locale negotiates the response language from the `?lang=` query parameter, then the
+// Accept-Language header, falling back to DEFAULT_LOCALE. The result is stored in the request
+// context (read it with i18n.FromContext(c.UserContext())) and echoed in Content-Language.
+func Locale(cfg config.I18nConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var override string
+		if cfg.QueryParam != "" {
+			override = c.Query(cfg.QueryParam)
+		}
+
+		locale := i18n.Negotiate(override, c.Get(fiber.HeaderAcceptLanguage))
+		c.SetUserContext(i18n.WithLocale(c.UserContext(), locale))
+		c.Set(fiber.HeaderContentLanguage, locale)
+		c.Vary(fiber.HeaderAcceptLanguage)
+
+		return c.Next()
+	}
+}
@@ -1,14 +1,19 @@
 package middleware
 
 import (
+	"math/rand/v2"
 	"time"
 
+	"go_boilerplate/internal/shared/config"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 )
 
 // HTTPLogger is a middleware that logs HTTP requests
-func HTTPLogger(logger *logrus.Logger) fiber.Handler {
+// Successful (2xx) requests are sampled at LOG_ACCESS_SAMPLE_RATE; redirects, errors and failed
+// requests are always logged
+func HTTPLogger(logger *logrus.Logger, cfg config.LoggerConfig) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Start timer
 		start := time.Now()
@@ -19,22 +24,38 @@ func HTTPLogger(logger *logrus.Logger) fiber.Handler {
 		// Calculate latency
 		latency := time.Since(start)
 
+		// Get status; an error is answered by the error handler after this middleware
+		status := responseStatus(c, err)
+		success := err == nil && status >= 200 && status < 300
+		if success && cfg.AccessSampleRate < 1 && rand.Float64() >= cfg.AccessSampleRate {
+			return err
+		}
+
 		// Get request details
-		method := c.Method()
-		path := c.Path()
-		status := c.Response().StatusCode()
-		ip := c.IP()
-		userAgent := c.Get("User-Agent")
+		fields := logrus.Fields{
+			"request_id":    c.GetRespHeader(fiber.HeaderXRequestID),
+			"method":        c.Method(),
+			"path":          c.Path(),
+			"status":        status,
+			"latency":       latency.String(),
+			"response_size": len(c.Response().Body()),
+			"ip":            c.IP(),
+			"user_agent":    c.Get("User-Agent"),
+		}
+		if userID, ok := GetUserIDFromContext(c); ok {
+			fields["user_id"] = userID
+		}
+		if roles, ok := GetRolesFromContext(c); ok {
+			fields["roles"] = roles
+		}
+		// Logged lines stand for 1/rate requests each
+		if success && cfg.AccessSampleRate < 1 {
+			fields["sample_rate"] = cfg.AccessSampleRate
+		}
 
 		// Create log entry
-		entry := logger.WithFields(logrus.Fields{
-			"method":     method,
-			"path":       path,
-			"status":     status,
-			"latency":    latency.String(),
-			"ip":         ip,
-			"user_agent": userAgent,
-		})
+		// WithContext lets observability.TraceHook add the trace and span IDs (TRACING_ENABLED)
+		entry := logger.WithContext(c.UserContext()).WithFields(fields)
 
 		// Log based on status code
 		if err != nil {
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"go_boilerplate/internal/shared/apperror"
+	"go_boilerplate/internal/shared/observability"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// unmatchedRoute labels requests no route matched, so scanners probing random paths can't add series
+const unmatchedRoute = "unmatched"
+
+// Metrics records the count and duration of every request in the Prometheus metrics, labelled with
+// the method, the route pattern and the status; paths starting with a skip prefix are not recorded
+func Metrics(skip ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if hasAnyPrefix(c.Path(), skip) {
+			return c.Next()
+		}
+
+		start := time.Now()
+		err := c.Next()
+
+		// Label values outlive the request, Fiber's strings don't
+		observability.ObserveRequest(strings.Clone(c.Method()), routePattern(c, err), responseStatus(c, err), time.Since(start))
+		return err
+	}
+}
+
+// responseStatus returns the status of the response to c: errors are rendered by the app's
+// ErrorHandler after the middleware returns, with the same statuses
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	if appErr, ok := apperror.As(err); ok {
+		return appErr.Status
+	}
+	return fiber.StatusInternalServerError
+}
+
+// routePattern returns the route that served c, or unmatchedRoute for Fiber's own 404 (where the
+// route is the prefix of the last middleware that ran)
+func routePattern(c *fiber.Ctx, err error) string {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) && fiberErr.Code == fiber.StatusNotFound {
+		return unmatchedRoute
+	}
+	return c.Route().Path
+}
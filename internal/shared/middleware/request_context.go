@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"strings"
+
+	"go_boilerplate/internal/shared/observability"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestContext stores the request ID, method and path in the request's user context, so logs
+// written without the fiber.Ctx (e.g. slow query logs) name the request. Register it after the
+// request ID middleware.
+func RequestContext() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Fiber reuses the buffers behind these strings once the request ends
+		c.SetUserContext(observability.WithRequest(c.UserContext(), observability.Request{
+			ID:     strings.Clone(c.GetRespHeader(fiber.HeaderXRequestID)),
+			Method: strings.Clone(c.Method()),
+			Path:   strings.Clone(c.Path()),
+		}))
+		return c.Next()
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"go_boilerplate/internal/shared/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SecurityHeaders sets helmet-style response headers: X-Content-Type-Options, X-Frame-Options,
+// Referrer-Policy, Strict-Transport-Security (when HSTSMaxAge > 0) and Content-Security-Policy
+// Paths starting with a cspExempt prefix (e.g. the Swagger UI, which needs inline scripts) get no CSP
+func SecurityHeaders(cfg config.SecurityHeadersConfig, cspExempt ...string) fiber.Handler {
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.FormatInt(int64(cfg.HSTSMaxAge.Seconds()), 10)
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+
+	return func(c *fiber.Ctx) error {
+		if !cfg.Enabled {
+			return c.Next()
+		}
+
+		c.Set("X-Content-Type-Options", "nosniff")
+		if cfg.FrameOptions != "" {
+			c.Set("X-Frame-Options", cfg.FrameOptions)
+		}
+		if cfg.ReferrerPolicy != "" {
+			c.Set("Referrer-Policy", cfg.ReferrerPolicy)
+		}
+		if hsts != "" {
+			c.Set("Strict-Transport-Security", hsts)
+		}
+		if cfg.ContentSecurityPolicy != "" && !hasAnyPrefix(c.Path(), cspExempt) {
+			c.Set("Content-Security-Policy", cfg.ContentSecurityPolicy)
+		}
+
+		return c.Next()
+	}
+}
+
+// hasAnyPrefix reports whether path starts with any of prefixes
+func hasAnyPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
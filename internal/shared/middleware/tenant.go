@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"errors"
+	"net"
+	"strings"
+
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/tenant"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ResolveTenant resolves the request tenant and stores it in the request context (read it with
+// tenant.FromContext(c.UserContext()); tenant-aware repositories apply tenant.Scope). The tenant
+// is named, by ID or slug, by the tenant_id claim of a valid bearer token, the TENANT_HEADER
+// header or a subdomain of TENANT_BASE_DOMAIN, in that order. A token bound to one tenant can't
+// be used against another (403). Paths starting with one of the exempt prefixes are skipped.
+func ResolveTenant(cfg *config.Config, resolver tenant.Resolver, exempt ...string) fiber.Handler {
+	if !cfg.Tenancy.Enabled {
+		return passthrough
+	}
+
+	jwtManager := newJWTManager(cfg)
+
+	return func(c *fiber.Ctx) error {
+		if hasAnyPrefix(c.Path(), exempt) {
+			return c.Next()
+		}
+
+		wideEvent(c).StartTimer("middleware.tenant")
+		t, status, message, err := resolveRequestTenant(c, cfg.Tenancy, jwtManager, resolver)
+		wideEvent(c).StopTimer("middleware.tenant")
+		if status != 0 {
+			return utils.ErrorResponse(c, status, message, err)
+		}
+
+		if t != nil {
+			c.SetUserContext(tenant.WithTenant(c.UserContext(), t))
+			wideEvent(c).Set("tenant", t.Slug)
+		}
+		return c.Next()
+	}
+}
+
+// resolveRequestTenant returns the request tenant (nil when none is named and none is required),
+// or the status and message to reject the request with
+func resolveRequestTenant(c *fiber.Ctx, cfg config.TenancyConfig, jwtManager *utils.JWTManager, resolver tenant.Resolver) (*tenant.Tenant, int, string, error) {
+	requested := ""
+	if cfg.Header != "" {
+		requested = strings.TrimSpace(c.Get(cfg.Header))
+	}
+	if requested == "" {
+		requested = tenantSubdomain(c.Hostname(), cfg.BaseDomain)
+	}
+
+	// Invalid tokens are ignored here; the route's JWTAuth rejects them
+	claimed := ""
+	if _, token, ok := strings.Cut(c.Get(fiber.HeaderAuthorization), " "); ok && token != "" {
+		if claims, err := jwtManager.ValidateToken(token); err == nil {
+			claimed = claims.TenantID
+		}
+	}
+
+	if claimed == "" && requested == "" {
+		if cfg.Required {
+			return nil, fiber.StatusBadRequest, "Tenant is required", errors.New("no tenant named by header, subdomain or token")
+		}
+		return nil, 0, "", nil
+	}
+
+	key := claimed
+	if key == "" {
+		key = requested
+	}
+	t, status, message, err := lookupTenant(c, resolver, key)
+	if status != 0 {
+		return nil, status, message, err
+	}
+
+	if claimed != "" && requested != "" {
+		other, status, message, err := lookupTenant(c, resolver, requested)
+		if status != 0 {
+			return nil, status, message, err
+		}
+		if other.ID != t.ID {
+			return nil, fiber.StatusForbidden, "Token is not valid for this tenant", errors.New("token tenant does not match the requested tenant")
+		}
+	}
+
+	return t, 0, "", nil
+}
+
+// lookupTenant resolves key, mapping unknown tenants to 404 and lookup failures to 500
+func lookupTenant(c *fiber.Ctx, resolver tenant.Resolver, key string) (*tenant.Tenant, int, string, error) {
+	t, err := resolver.Resolve(c.UserContext(), key)
+	if errors.Is(err, tenant.ErrNotFound) {
+		return nil, fiber.StatusNotFound, "Tenant not found", err
+	}
+	if err != nil {
+		return nil, fiber.StatusInternalServerError, "Failed to resolve tenant", err
+	}
+	return t, 0, "", nil
+}
+
+// tenantSubdomain returns the first label of host when it is a direct subdomain of baseDomain
+// (acme.example.com -> acme), or "" otherwise
+func tenantSubdomain(host, baseDomain string) string {
+	if baseDomain == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	label, ok := strings.CutSuffix(strings.ToLower(host), "."+baseDomain)
+	if !ok || label == "" || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
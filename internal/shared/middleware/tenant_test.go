@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/tenant"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// staticTenantResolver resolves the tenants it holds by ID or slug
+type staticTenantResolver []*tenant.Tenant
+
+func (r staticTenantResolver) Resolve(_ context.Context, key string) (*tenant.Tenant, error) {
+	for _, t := range r {
+		if key == t.ID.String() || key == t.Slug {
+			return t, nil
+		}
+	}
+	return nil, tenant.ErrNotFound
+}
+
+func TestResolveTenantRejectsTokenForAnotherTenant(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.JWT = config.JWTConfig{Secret: "tenant-test", AccessExpiry: time.Minute, RefreshExpiry: time.Hour, Issuer: "test"}
+	cfg.Tenancy = config.TenancyConfig{Enabled: true, Header: "X-Tenant-ID"}
+
+	acme := &tenant.Tenant{ID: uuid.New(), Slug: "acme"}
+	globex := &tenant.Tenant{ID: uuid.New(), Slug: "globex"}
+	token, err := newJWTManager(cfg).GenerateAccessToken(uuid.New(), "user@example.com", []string{"user"}, nil, acme.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New()
+	app.Use(ResolveTenant(cfg, staticTenantResolver{acme, globex}))
+	app.Get("/", func(c *fiber.Ctx) error {
+		if t, ok := tenant.FromContext(c.UserContext()); !ok || t.ID != acme.ID {
+			return c.SendStatus(fiber.StatusInternalServerError)
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	for _, tc := range []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"same tenant by slug", "acme", fiber.StatusNoContent},
+		{"same tenant by ID", acme.ID.String(), fiber.StatusNoContent},
+		{"no header", "", fiber.StatusNoContent},
+		{"other tenant", "globex", fiber.StatusForbidden},
+		{"other tenant by ID", globex.ID.String(), fiber.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+			if tc.header != "" {
+				req.Header.Set("X-Tenant-ID", tc.header)
+			}
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tc.want {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go_boilerplate/internal/shared/apperror"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errRequestTimedOut is answered when the request deadline expired before the handler succeeded
+var errRequestTimedOut = apperror.New(apperror.ErrUnavailable, "Request timed out").WithCode("request_timeout")
+
+// Timeout gives the request context (c.UserContext()) a deadline so services and repositories
+// that run queries with db.WithContext(ctx) are cancelled instead of hanging on slow queries.
+// Register it globally or on a route group; nested groups may set a shorter deadline.
+//
+// Handlers run to completion (Fiber can't interrupt them), so a response that still succeeded
+// is kept; failures caused by the expired deadline are replaced with 503 Service Unavailable.
+func Timeout(d time.Duration) fiber.Handler {
+	if d <= 0 {
+		return passthrough
+	}
+
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(c.UserContext(), d)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return err
+		}
+		if err == nil && c.Response().StatusCode() < fiber.StatusBadRequest {
+			return nil
+		}
+
+		wideEvent(c).Set("timed_out", true)
+		c.Response().ResetBody()
+		return utils.ErrorResponse(c, fiber.StatusServiceUnavailable, "Request timed out", errRequestTimedOut)
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"go_boilerplate/internal/shared/observability"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing starts the server span of every request, continuing the trace of a caller that sent a
+// traceparent header, and stores it in c.UserContext() so database, Redis, outbound HTTP and service
+// spans become its children. The span is named "<method> <route pattern>" once the route is known.
+// Paths starting with a skip prefix are not traced.
+func Tracing(skip ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if hasAnyPrefix(c.Path(), skip) {
+			return c.Next()
+		}
+
+		// Fiber's strings are only valid during the request, spans are exported after it
+		method := strings.Clone(c.Method())
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), requestHeaderCarrier{c})
+		ctx, span := observability.Tracer().Start(ctx, method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(method),
+				semconv.URLPath(strings.Clone(c.Path())),
+				semconv.ClientAddress(strings.Clone(c.IP())),
+				semconv.UserAgentOriginal(strings.Clone(c.Get(fiber.HeaderUserAgent))),
+			),
+		)
+		defer span.End()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		route := routePattern(c, err)
+		status := responseStatus(c, err)
+		span.SetName(method + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route), semconv.HTTPResponseStatusCode(status))
+		if err != nil {
+			span.RecordError(err)
+		}
+		// Client errors are the caller's; only server errors fail the span
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		return err
+	}
+}
+
+// requestHeaderCarrier reads propagation headers (traceparent, baggage) from the request
+type requestHeaderCarrier struct {
+	c *fiber.Ctx
+}
+
+// Get implements propagation.TextMapCarrier
+func (h requestHeaderCarrier) Get(key string) string {
+	return h.c.Get(key)
+}
+
+// Set implements propagation.TextMapCarrier; request headers are only read
+func (requestHeaderCarrier) Set(string, string) {}
+
+// Keys implements propagation.TextMapCarrier
+func (h requestHeaderCarrier) Keys() []string {
+	var keys []string
+	h.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
@@ -1,36 +1,55 @@
 package middleware
 
 import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"go_boilerplate/internal/shared/apperror"
+	"go_boilerplate/internal/shared/i18n"
 	"go_boilerplate/internal/shared/utils"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 // BodyValidator validates request body against a struct
+// Bodies must be JSON; other content types (form, XML, none) are rejected with 415
+// v is only used as a template: each request is parsed into a fresh instance of its type,
+// so fields omitted from one request never carry over from another and concurrent requests
+// never share a DTO. v must be a pointer to a struct, e.g. &dto.CreateUserRequest{}
 func BodyValidator(v any) fiber.Handler {
+	templateType := reflect.TypeOf(v)
+	if templateType == nil || templateType.Kind() != reflect.Pointer || templateType.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("middleware: BodyValidator needs a pointer to a struct, got %T", v))
+	}
+	bodyType := templateType.Elem()
+
+	// One validator per route: it is safe for concurrent use and caches the DTO's rules
+	validator := utils.NewValidator()
+
 	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		v := reflect.New(bodyType).Interface()
+
+		if !isJSONRequest(c) {
+			return unsupportedMediaType(c)
+		}
+
 		// Parse body
 		if err := c.BodyParser(v); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"success": false,
-				"error":   "Failed to parse request body",
-			})
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "Failed to parse request body", nil)
 		}
 
 		// Validate struct
-		validator := utils.NewValidator()
 		if err := validator.ValidateStruct(v); err != nil {
-			errors := utils.GetValidationErrors(err)
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"success": false,
-				"error":   "Validation failed",
-				"details": errors,
-			})
+			fieldErrors := validator.ValidationErrors(err, i18n.FromContext(c.UserContext()))
+			return utils.ProblemResponse(c, fiber.StatusBadRequest, "Validation failed", apperror.ErrValidation, map[string]any{"errors": fieldErrors})
 		}
 
 		// Store validated body in context for later use
 		c.Locals("validatedBody", v)
 
+		wideEvent(c).AddDuration("middleware.validator", time.Since(start))
 		return c.Next()
 	}
 }
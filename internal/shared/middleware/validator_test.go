@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type validatorTestBody struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email,omitempty" validate:"omitempty,email"`
+	Seq   int    `json:"seq"`
+}
+
+// newValidatorTestApp echoes the body BodyValidator stored for the request
+func newValidatorTestApp() *fiber.App {
+	app := fiber.New()
+	app.Post("/items", BodyValidator(&validatorTestBody{}), func(c *fiber.Ctx) error {
+		body := c.Locals("validatedBody").(*validatorTestBody)
+		return c.JSON(fiber.Map{"body": body, "ptr": fmt.Sprintf("%p", body)})
+	})
+	return app
+}
+
+type validatorTestResponse struct {
+	Body validatorTestBody `json:"body"`
+	Ptr  string            `json:"ptr"`
+}
+
+func postValidatorTestBody(app *fiber.App, payload string) (int, validatorTestResponse, error) {
+	req := httptest.NewRequest(fiber.MethodPost, "/items", strings.NewReader(payload))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		return 0, validatorTestResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	var decoded validatorTestResponse
+	if resp.StatusCode == fiber.StatusOK {
+		raw, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return 0, validatorTestResponse{}, err
+		}
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			return 0, validatorTestResponse{}, err
+		}
+	}
+	return resp.StatusCode, decoded, nil
+}
+
+// TestBodyValidatorConcurrentRequests runs requests in parallel (go test -race): each must get back
+// exactly the body it sent, never a DTO shared with or left over from another request
+func TestBodyValidatorConcurrentRequests(t *testing.T) {
+	app := newValidatorTestApp()
+
+	const requests = 64
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(seq int) {
+			defer wg.Done()
+
+			// Every request sends unique values; every other one omits email, which must not
+			// inherit another request's value
+			sent := validatorTestBody{Name: fmt.Sprintf("user-%d", seq), Seq: seq}
+			if seq%2 == 0 {
+				sent.Email = fmt.Sprintf("user-%d@example.com", seq)
+			}
+			payload, err := json.Marshal(sent)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			status, resp, err := postValidatorTestBody(app, string(payload))
+			if err != nil {
+				errs <- err
+				return
+			}
+			if status != fiber.StatusOK || resp.Body != sent {
+				errs <- fmt.Errorf("request %d: status %d, body %+v, sent %+v", seq, status, resp.Body, sent)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+// TestBodyValidatorFreshInstance checks sequential requests get distinct DTOs, without the fields
+// of the previous body
+func TestBodyValidatorFreshInstance(t *testing.T) {
+	app := newValidatorTestApp()
+
+	_, first, err := postValidatorTestBody(app, `{"name":"first","email":"first@example.com","seq":1}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, second, err := postValidatorTestBody(app, `{"name":"second"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if second.Body != (validatorTestBody{Name: "second"}) {
+		t.Fatalf("second body = %+v, fields leaked from the first request", second.Body)
+	}
+	if first.Ptr == second.Ptr {
+		t.Fatalf("both requests were bound to the DTO at %s", first.Ptr)
+	}
+}
+
+func TestBodyValidatorRejectsInvalidBody(t *testing.T) {
+	app := newValidatorTestApp()
+
+	status, _, err := postValidatorTestBody(app, `{"email":"not-an-email"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != fiber.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", status, fiber.StatusBadRequest)
+	}
+}
+
+func TestBodyValidatorPanicsOnNonStructPointer(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("BodyValidator accepted a struct value")
+		}
+	}()
+	BodyValidator(validatorTestBody{})
+}
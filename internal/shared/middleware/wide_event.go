@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"errors"
+
+	"go_boilerplate/internal/shared/observability"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// WideEvent emits a single canonical structured event per request
+// The event is attached to the request's user context so middleware, repositories
+// (via the GORM plugin), Redis and the shared HTTP client can add facts to it
+func WideEvent(logger *logrus.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		event := observability.NewEvent()
+		c.SetUserContext(observability.WithEvent(c.UserContext(), event))
+
+		// Process request
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+			event.Set("error", err.Error())
+		}
+
+		event.Set("request_id", c.GetRespHeader(fiber.HeaderXRequestID))
+		event.Set("method", c.Method())
+		event.Set("route", c.Route().Path)
+		event.Set("path", c.Path())
+		event.Set("status", status)
+		event.Set("ip", c.IP())
+		event.Set("user_agent", c.Get("User-Agent"))
+
+		for key, value := range observability.TraceFields(c.UserContext()) {
+			event.Set(key, value)
+		}
+		if userID, ok := GetUserIDFromContext(c); ok {
+			event.Set("user_id", userID)
+		}
+		if roles, ok := GetRolesFromContext(c); ok {
+			event.Set("roles", roles)
+		}
+
+		// Log based on status code
+		level := logrus.InfoLevel
+		if err != nil || status >= 500 {
+			level = logrus.ErrorLevel
+		} else if status >= 400 {
+			level = logrus.WarnLevel
+		}
+		event.Emit(logger, level, "request")
+
+		return err
+	}
+}
+
+// wideEvent returns the wide event of the current request, or nil when wide events are disabled
+func wideEvent(c *fiber.Ctx) *observability.Event {
+	return observability.FromContext(c.UserContext())
+}
@@ -0,0 +1,161 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Notification levels
+const (
+	LevelInfo     = "info"
+	LevelWarning  = "warning"
+	LevelCritical = "critical"
+)
+
+// Notification represents a message routed to administrators
+type Notification struct {
+	Title   string
+	Message string
+	Level   string
+	Fields  map[string]string
+}
+
+// Notifier delivers notifications to administrators
+type Notifier interface {
+	Notify(n Notification) error
+}
+
+// New creates the default notifier from configuration
+// Notifications are always logged, and also posted to the webhook if NOTIFY_WEBHOOK_URL is set
+func New(cfg *config.Config, logger *logrus.Logger) Notifier {
+	notifiers := []Notifier{NewLogNotifier(logger)}
+
+	if cfg.Notify.WebhookURL != "" {
+		notifiers = append(notifiers, NewWebhookNotifier(cfg.Notify.WebhookURL))
+	}
+
+	return Multi(notifiers...)
+}
+
+// logNotifier writes notifications to the application log
+type logNotifier struct {
+	logger *logrus.Logger
+}
+
+// NewLogNotifier creates a notifier that writes to the logger
+func NewLogNotifier(logger *logrus.Logger) Notifier {
+	return &logNotifier{logger: logger}
+}
+
+// Notify logs the notification at a level matching its severity
+func (n *logNotifier) Notify(notification Notification) error {
+	fields := logrus.Fields{"notification": notification.Title}
+	for k, v := range notification.Fields {
+		fields[k] = v
+	}
+
+	entry := n.logger.WithFields(fields)
+	switch notification.Level {
+	case LevelCritical:
+		entry.Error(notification.Message)
+	case LevelWarning:
+		entry.Warn(notification.Message)
+	default:
+		entry.Info(notification.Message)
+	}
+
+	return nil
+}
+
+// webhookNotifier posts notifications to a Slack-compatible incoming webhook
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier that posts JSON to the given webhook URL
+func NewWebhookNotifier(url string) Notifier {
+	return &webhookNotifier{
+		url:    url,
+		client: utils.NewHTTPClient(10 * time.Second),
+	}
+}
+
+// Notify posts the notification to the webhook
+// The payload carries a Slack-style "text" field plus structured fields for generic receivers
+func (n *webhookNotifier) Notify(notification Notification) error {
+	payload := map[string]any{
+		"text":    formatText(notification),
+		"title":   notification.Title,
+		"message": notification.Message,
+		"level":   notification.Level,
+		"fields":  notification.Fields,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// formatText renders a notification as plain text with one line per field
+func formatText(n Notification) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s*\n%s", n.Title, n.Message)
+
+	keys := make([]string, 0, len(n.Fields))
+	for k := range n.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintf(&b, "\n• %s: %s", k, n.Fields[k])
+	}
+
+	return b.String()
+}
+
+// multiNotifier fans a notification out to several notifiers
+type multiNotifier struct {
+	notifiers []Notifier
+}
+
+// Multi combines several notifiers into one; every notifier is attempted
+func Multi(notifiers ...Notifier) Notifier {
+	return &multiNotifier{notifiers: notifiers}
+}
+
+// Notify sends the notification to every notifier and joins their errors
+func (m *multiNotifier) Notify(notification Notification) error {
+	var errs []error
+	for _, n := range m.notifiers {
+		if err := n.Notify(notification); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
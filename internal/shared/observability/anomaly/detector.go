@@ -0,0 +1,151 @@
+package anomaly
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/notify"
+	"go_boilerplate/internal/shared/observability"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// Metric is a counter watched for anomalous spikes
+type Metric struct {
+	Name  string // Counter name, e.g. observability.MetricLoginFailures
+	Label string // Human readable name used in alerts
+}
+
+// AuthMetrics are the auth counters watched by default
+var AuthMetrics = []Metric{
+	{Name: observability.MetricLoginFailures, Label: "Login failures"},
+	{Name: observability.MetricRegistrations, Label: "Registrations"},
+	{Name: observability.MetricTokenRefreshes, Label: "Token refreshes"},
+}
+
+// Detector compares the latest window of each metric against a baseline built
+// from the preceding windows and raises an alert when it deviates too far
+type Detector struct {
+	counter  *observability.Counter
+	redis    *redis.Client
+	notifier notify.Notifier
+	metrics  []Metric
+	cfg      config.AnomalyConfig
+	logger   *logrus.Logger
+}
+
+// NewDetector creates a new anomaly detector
+func NewDetector(redisClient *redis.Client, notifier notify.Notifier, metrics []Metric, cfg config.AnomalyConfig, logger *logrus.Logger) *Detector {
+	return &Detector{
+		counter:  observability.NewCounter(redisClient),
+		redis:    redisClient,
+		notifier: notifier,
+		metrics:  metrics,
+		cfg:      cfg,
+		logger:   logger,
+	}
+}
+
+// Run analyses every metric once; it is meant to be scheduled every cfg.Interval
+func (d *Detector) Run(ctx context.Context) error {
+	if d.redis == nil {
+		return nil
+	}
+
+	// Align windows to whole minutes, matching the counter's bucket size
+	window := d.cfg.Interval.Truncate(time.Minute)
+	if window < time.Minute {
+		window = time.Minute
+	}
+	end := time.Now().Truncate(time.Minute)
+
+	for _, metric := range d.metrics {
+		if err := d.check(ctx, metric, window, end); err != nil {
+			d.logger.Warnf("Anomaly check failed for %s: %v", metric.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// check evaluates a single metric
+func (d *Detector) check(ctx context.Context, metric Metric, window time.Duration, end time.Time) error {
+	current, err := d.counter.Sum(ctx, metric.Name, end.Add(-window), end)
+	if err != nil {
+		return err
+	}
+
+	// Too few events to be meaningful (also avoids alerting on a cold start)
+	if current < d.cfg.MinEvents {
+		return nil
+	}
+
+	baseline := make([]float64, 0, d.cfg.BaselineWindows)
+	for i := 1; i <= d.cfg.BaselineWindows; i++ {
+		windowEnd := end.Add(-time.Duration(i) * window)
+		count, err := d.counter.Sum(ctx, metric.Name, windowEnd.Add(-window), windowEnd)
+		if err != nil {
+			return err
+		}
+		baseline = append(baseline, float64(count))
+	}
+
+	mean, stddev := meanStdDev(baseline)
+
+	// Event counts are roughly Poisson, so never trust a spread below sqrt(mean);
+	// this keeps a perfectly flat baseline from alerting on tiny fluctuations
+	spread := math.Max(stddev, math.Max(math.Sqrt(mean), 1))
+	score := (float64(current) - mean) / spread
+	if score < d.cfg.Threshold {
+		return nil
+	}
+
+	// Only one alert per metric per cooldown, across all instances
+	acquired, err := d.redis.SetNX(ctx, "metrics:alerted:"+metric.Name, end.Unix(), d.cfg.AlertCooldown).Result()
+	if err != nil || !acquired {
+		return err
+	}
+
+	level := notify.LevelWarning
+	if score >= 2*d.cfg.Threshold {
+		level = notify.LevelCritical
+	}
+
+	return d.notifier.Notify(notify.Notification{
+		Title:   fmt.Sprintf("Anomaly detected: %s", metric.Label),
+		Message: fmt.Sprintf("%s reached %d in the last %s, against a baseline of %.1f ± %.1f.", metric.Label, current, window, mean, stddev),
+		Level:   level,
+		Fields: map[string]string{
+			"metric":          metric.Name,
+			"current":         fmt.Sprintf("%d", current),
+			"baseline_mean":   fmt.Sprintf("%.2f", mean),
+			"baseline_stddev": fmt.Sprintf("%.2f", stddev),
+			"deviation":       fmt.Sprintf("%.1f", score),
+			"window":          window.String(),
+		},
+	})
+}
+
+// meanStdDev returns the mean and population standard deviation of values
+func meanStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
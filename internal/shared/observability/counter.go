@@ -0,0 +1,82 @@
+package observability
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Auth metric names recorded by the auth module
+const (
+	MetricLoginFailures  = "auth.login_failures"
+	MetricRegistrations  = "auth.registrations"
+	MetricTokenRefreshes = "auth.token_refreshes"
+)
+
+// Database metric names recorded by the database package
+const (
+	MetricDBQueryTimeouts = "db.query_timeouts"
+)
+
+// counterRetention is how long per-minute buckets are kept; it bounds the longest usable baseline
+const counterRetention = 48 * time.Hour
+
+// Counter is a Redis-backed event counter bucketed per minute, shared across instances
+type Counter struct {
+	redis *redis.Client
+}
+
+// NewCounter creates a new counter; it is a no-op when Redis is unavailable
+func NewCounter(redisClient *redis.Client) *Counter {
+	return &Counter{redis: redisClient}
+}
+
+// Incr records one occurrence of the named event now
+func (c *Counter) Incr(ctx context.Context, name string) {
+	if c == nil || c.redis == nil {
+		return
+	}
+
+	key := bucketKey(name, time.Now())
+	pipe := c.redis.TxPipeline()
+	pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, counterRetention)
+	_, _ = pipe.Exec(ctx)
+}
+
+// Sum returns the number of events recorded in [from, to), at minute granularity
+func (c *Counter) Sum(ctx context.Context, name string, from, to time.Time) (int64, error) {
+	if c == nil || c.redis == nil {
+		return 0, nil
+	}
+
+	var keys []string
+	for t := from.Truncate(time.Minute); t.Before(to); t = t.Add(time.Minute) {
+		keys = append(keys, bucketKey(name, t))
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	values, err := c.redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			n, _ := strconv.ParseInt(s, 10, 64)
+			total += n
+		}
+	}
+
+	return total, nil
+}
+
+// bucketKey returns the Redis key of the minute bucket containing t
+func bucketKey(name string, t time.Time) string {
+	return "metrics:" + name + ":" + strconv.FormatInt(t.Unix()/60, 10)
+}
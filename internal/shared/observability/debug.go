@@ -0,0 +1,145 @@
+package observability
+
+import (
+	"strings"
+	"time"
+)
+
+// DebugQuery is a SQL statement executed while serving a debug request
+// Statements are recorded with placeholders, never with bound values
+type DebugQuery struct {
+	SQL        string  `json:"sql"`
+	DurationMs float64 `json:"duration_ms"`
+	Rows       int64   `json:"rows"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// DebugCacheOp is a Redis command executed while serving a debug request
+type DebugCacheOp struct {
+	Command    string  `json:"command"`
+	Key        string  `json:"key,omitempty"` // Key namespace only (e.g. "blacklist:*"), never the full key
+	Result     string  `json:"result"`        // hit, miss, ok or error
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// DebugAuthDecision is an authentication or authorization check evaluated for the request
+type DebugAuthDecision struct {
+	Check    string   `json:"check"` // jwt, role or permission
+	Required []string `json:"required,omitempty"`
+	Granted  bool     `json:"granted"`
+	Reason   string   `json:"reason,omitempty"`
+}
+
+// debugTrace holds the per-statement details only collected for debug requests
+type debugTrace struct {
+	queries   []DebugQuery
+	cache     []DebugCacheOp
+	decisions []DebugAuthDecision
+}
+
+// EnableDebug starts collecting statement-level details (SQL, cache operations, auth decisions)
+func (e *Event) EnableDebug() {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	if e.debug == nil {
+		e.debug = &debugTrace{}
+	}
+	e.mu.Unlock()
+}
+
+// Debugging reports whether statement-level details are being collected
+func (e *Event) Debugging() bool {
+	if e == nil {
+		return false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.debug != nil
+}
+
+// RecordQuery adds an executed SQL statement to the debug trace
+func (e *Event) RecordQuery(q DebugQuery) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	if e.debug != nil {
+		e.debug.queries = append(e.debug.queries, q)
+	}
+	e.mu.Unlock()
+}
+
+// RecordCacheOp adds a Redis command to the debug trace
+func (e *Event) RecordCacheOp(op DebugCacheOp) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	if e.debug != nil {
+		e.debug.cache = append(e.debug.cache, op)
+	}
+	e.mu.Unlock()
+}
+
+// RecordAuthDecision adds an authentication/authorization outcome to the debug trace
+func (e *Event) RecordAuthDecision(d DebugAuthDecision) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	if e.debug != nil {
+		e.debug.decisions = append(e.debug.decisions, d)
+	}
+	e.mu.Unlock()
+}
+
+// DebugInfo returns the timing breakdown, counters and debug trace collected so far
+func (e *Event) DebugInfo() map[string]any {
+	if e == nil {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	timings := make(map[string]float64, len(e.timings))
+	for k, v := range e.timings {
+		timings[k] = durationMillis(v)
+	}
+	counts := make(map[string]int64, len(e.counts))
+	for k, v := range e.counts {
+		counts[k] = v
+	}
+
+	info := map[string]any{
+		"duration_ms": durationMillis(time.Since(e.start)),
+		"timings_ms":  timings,
+		"counters":    counts,
+	}
+	if e.debug != nil {
+		info["queries"] = nonNil(e.debug.queries)
+		info["cache"] = nonNil(e.debug.cache)
+		info["authorization"] = nonNil(e.debug.decisions)
+	}
+	return info
+}
+
+// keyNamespace reduces a Redis key to its namespace so tokens or emails embedded in keys aren't exposed
+func keyNamespace(key string) string {
+	if key == "" {
+		return ""
+	}
+	if i := strings.Index(key, ":"); i >= 0 {
+		return key[:i] + ":*"
+	}
+	return "*"
+}
+
+// nonNil makes empty traces serialize as [] instead of null
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -0,0 +1,143 @@
+package observability
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// eventContextKey is the context key under which the request's wide event is stored
+type eventContextKey struct{}
+
+// Event is a canonical "wide" event aggregating every fact about a single request
+// (route, user, latency breakdown, cache hits, errors) so it can be emitted as one
+// structured log line instead of many scattered ones.
+//
+// All methods are safe for concurrent use and are no-ops on a nil *Event, so
+// instrumentation hooks never need to check whether an event is present.
+type Event struct {
+	mu      sync.Mutex
+	start   time.Time
+	fields  map[string]any
+	counts  map[string]int64
+	timings map[string]time.Duration
+	timers  map[string]time.Time
+	debug   *debugTrace // Only set for X-Debug requests (see EnableDebug)
+}
+
+// NewEvent creates a new wide event starting now
+func NewEvent() *Event {
+	return &Event{
+		start:   time.Now(),
+		fields:  make(map[string]any),
+		counts:  make(map[string]int64),
+		timings: make(map[string]time.Duration),
+		timers:  make(map[string]time.Time),
+	}
+}
+
+// WithEvent returns a copy of ctx carrying the event
+func WithEvent(ctx context.Context, e *Event) context.Context {
+	return context.WithValue(ctx, eventContextKey{}, e)
+}
+
+// FromContext returns the event stored in ctx, or nil if there is none
+func FromContext(ctx context.Context) *Event {
+	if ctx == nil {
+		return nil
+	}
+	e, _ := ctx.Value(eventContextKey{}).(*Event)
+	return e
+}
+
+// Set records a single fact about the request
+func (e *Event) Set(key string, value any) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	e.fields[key] = value
+	e.mu.Unlock()
+}
+
+// Incr adds n to a counter (e.g. "db.queries", "cache.hits")
+func (e *Event) Incr(key string, n int64) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	e.counts[key] += n
+	e.mu.Unlock()
+}
+
+// AddDuration adds d to a latency bucket (e.g. "db", "external", "middleware.jwt")
+func (e *Event) AddDuration(key string, d time.Duration) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	e.timings[key] += d
+	e.mu.Unlock()
+}
+
+// StartTimer starts a named timer, for spans whose start and end live in different callbacks
+func (e *Event) StartTimer(key string) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	e.timers[key] = time.Now()
+	e.mu.Unlock()
+}
+
+// StopTimer stops a named timer and adds its elapsed time to the latency bucket of the same name
+func (e *Event) StopTimer(key string) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	if started, ok := e.timers[key]; ok {
+		e.timings[key] += time.Since(started)
+		delete(e.timers, key)
+	}
+	e.mu.Unlock()
+}
+
+// Fields flattens the event into structured log fields
+// Latencies are reported in milliseconds under "duration_ms" and "<bucket>_ms"
+func (e *Event) Fields() logrus.Fields {
+	if e == nil {
+		return logrus.Fields{}
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	fields := make(logrus.Fields, len(e.fields)+len(e.counts)+len(e.timings)+1)
+	for k, v := range e.fields {
+		fields[k] = v
+	}
+	for k, v := range e.counts {
+		fields[k] = v
+	}
+	for k, v := range e.timings {
+		fields[k+"_ms"] = durationMillis(v)
+	}
+	fields["duration_ms"] = durationMillis(time.Since(e.start))
+
+	return fields
+}
+
+// Emit writes the event as a single structured log line
+func (e *Event) Emit(logger *logrus.Logger, level logrus.Level, msg string) {
+	if e == nil {
+		return
+	}
+	logger.WithFields(e.Fields()).Log(level, msg)
+}
+
+// durationMillis converts a duration to fractional milliseconds
+func durationMillis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
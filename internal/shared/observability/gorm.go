@@ -0,0 +1,237 @@
+package observability
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// Statement settings holding the query start time and its span
+const (
+	gormStartKey = "observability:start"
+	gormSpanKey  = "observability:span"
+)
+
+// observabilityPackage prefixes the function names of this package in stack frames
+const observabilityPackage = "go_boilerplate/internal/shared/observability."
+
+// QueryStats are totals of the statements recorded by GormPlugin since the process started
+type QueryStats struct {
+	Queries  int64
+	Errors   int64         // Failed statements, not found excluded
+	Slow     int64         // Statements slower than SlowThreshold
+	Duration time.Duration // Time spent in statements
+}
+
+// queryTotals backs Queries
+var queryTotals struct {
+	queries, errors, slow, nanos atomic.Int64
+}
+
+// Queries returns the statement totals recorded by GormPlugin, e.g. for the periodic pool
+// statistics log line
+func Queries() QueryStats {
+	return QueryStats{
+		Queries:  queryTotals.queries.Load(),
+		Errors:   queryTotals.errors.Load(),
+		Slow:     queryTotals.slow.Load(),
+		Duration: time.Duration(queryTotals.nanos.Load()),
+	}
+}
+
+// GormPlugin records database statements: their duration per table and operation on Registry,
+// a warning for statements slower than SlowThreshold (with the request and the calling code),
+// their count and latency on the wide event carried by the statement context, and a span (SQL with
+// placeholders only) under the span of that context. Queries must be run with db.WithContext(ctx)
+// to be attributed to a request.
+type GormPlugin struct {
+	SlowThreshold time.Duration // 0 disables slow query logging
+	Logger        *logrus.Logger
+}
+
+// Name implements gorm.Plugin
+func (GormPlugin) Name() string {
+	return "observability"
+}
+
+// Initialize implements gorm.Plugin by registering before/after callbacks on every processor
+func (p GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+
+	if err := callbacks.Create().Before("gorm:create").Register("observability:before_create", p.before("create")); err != nil {
+		return err
+	}
+	if err := callbacks.Create().After("gorm:create").Register("observability:after_create", p.after("create")); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("observability:before_query", p.before("query")); err != nil {
+		return err
+	}
+	if err := callbacks.Query().After("gorm:query").Register("observability:after_query", p.after("query")); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("observability:before_update", p.before("update")); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register("observability:after_update", p.after("update")); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("observability:before_delete", p.before("delete")); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("gorm:delete").Register("observability:after_delete", p.after("delete")); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("observability:before_row", p.before("row")); err != nil {
+		return err
+	}
+	if err := callbacks.Row().After("gorm:row").Register("observability:after_row", p.after("row")); err != nil {
+		return err
+	}
+	if err := callbacks.Raw().Before("gorm:raw").Register("observability:before_raw", p.before("raw")); err != nil {
+		return err
+	}
+	return callbacks.Raw().After("gorm:raw").Register("observability:after_raw", p.after("raw"))
+}
+
+// before stores the query start time on the statement and starts its span
+func (GormPlugin) before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		db.InstanceSet(gormStartKey, time.Now())
+		_, span := Tracer().Start(db.Statement.Context, operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemNamePostgreSQL, semconv.DBOperationName(operation)),
+		)
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+// after records a statement of the given operation
+func (p GormPlugin) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(gormStartKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		elapsed := time.Since(start)
+		failed := spanError(db.Error, gorm.ErrRecordNotFound)
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "other" // Raw SQL
+		}
+		endStatementSpan(db, operation, table, failed)
+		dbQueryDuration.WithLabelValues(table, operation).Observe(elapsed.Seconds())
+		queryTotals.queries.Add(1)
+		queryTotals.nanos.Add(int64(elapsed))
+		if failed {
+			dbQueryErrors.WithLabelValues(table, operation).Inc()
+			queryTotals.errors.Add(1)
+		}
+		if p.SlowThreshold > 0 && elapsed >= p.SlowThreshold {
+			dbSlowQueries.WithLabelValues(table, operation).Inc()
+			queryTotals.slow.Add(1)
+			p.logSlow(db, table, operation, elapsed)
+		}
+
+		event := FromContext(db.Statement.Context)
+		if event == nil {
+			return
+		}
+		event.Incr("db.queries", 1)
+		event.AddDuration("db", elapsed)
+		if failed {
+			event.Incr("db.errors", 1)
+		}
+
+		if event.Debugging() {
+			query := DebugQuery{
+				SQL:        db.Statement.SQL.String(), // Placeholders only; bound values are never recorded
+				DurationMs: durationMillis(elapsed),
+				Rows:       db.Statement.RowsAffected,
+			}
+			if db.Error != nil {
+				query.Error = db.Error.Error()
+			}
+			event.RecordQuery(query)
+		}
+	}
+}
+
+// endStatementSpan ends the span started by before, named "<operation> <table>"
+func endStatementSpan(db *gorm.DB, operation, table string, failed bool) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok || !span.IsRecording() {
+		return
+	}
+
+	span.SetName(operation + " " + table)
+	span.SetAttributes(
+		semconv.DBCollectionName(table),
+		semconv.DBQueryText(db.Statement.SQL.String()), // Placeholders only; bound values are never recorded
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if failed {
+		EndSpan(span, db.Error)
+		return
+	}
+	span.End()
+}
+
+// logSlow warns about a statement slower than SlowThreshold
+func (p GormPlugin) logSlow(db *gorm.DB, table, operation string, elapsed time.Duration) {
+	if p.Logger == nil {
+		return
+	}
+
+	fields := logrus.Fields{
+		"table":       table,
+		"operation":   operation,
+		"duration_ms": durationMillis(elapsed),
+		"rows":        db.Statement.RowsAffected,
+		"sql":         db.Statement.SQL.String(), // Placeholders only; bound values may hold personal data
+		"caller":      caller(),
+	}
+	if request, ok := RequestFromContext(db.Statement.Context); ok {
+		fields["request_id"] = request.ID
+		fields["method"] = request.Method
+		fields["path"] = request.Path
+	}
+	if db.Error != nil {
+		fields["error"] = db.Error.Error()
+	}
+	p.Logger.WithContext(db.Statement.Context).WithFields(fields).Warn("Slow query")
+}
+
+// caller returns file:line of the code that ran the statement, skipping GORM, its plugins and
+// this package
+func caller() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.Contains(frame.File, "/gorm.io/") && !strings.HasPrefix(frame.Function, observabilityPackage) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package observability
+
+import (
+	"crypto/subtle"
+	"strconv"
+	"time"
+
+	"go_boilerplate/internal/shared/buildinfo"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds the metrics served on /metrics; packages register their collectors on it
+var Registry = prometheus.NewRegistry()
+
+// Database statement metrics recorded by GormPlugin
+var (
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Duration of database statements by table and operation.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"table", "operation"})
+	dbQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "Database statements that failed (not found excluded) by table and operation.",
+	}, []string{"table", "operation"})
+	dbSlowQueries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_slow_queries_total",
+		Help: "Database statements slower than DB_SLOW_QUERY_THRESHOLD by table and operation.",
+	}, []string{"table", "operation"})
+)
+
+// HTTP metrics recorded by middleware.Metrics; route is the route pattern (/api/v1/users/:id), so
+// the label stays bounded
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Duration of HTTP requests by method, route and status.",
+		Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"method", "route", "status"})
+)
+
+// Authentication methods and login outcomes of the auth metrics; OAuth logins use the provider name
+const (
+	AuthMethodPassword = "password"
+
+	LoginSucceeded = "success"
+	LoginFailed    = "failure"
+)
+
+// Business metrics recorded by the auth and oauth modules
+var (
+	authRegistrations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_registrations_total",
+		Help: "Users registered by method (password, or the OAuth provider).",
+	}, []string{"method"})
+	authLogins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_logins_total",
+		Help: "Completed logins and rejected credentials or 2FA codes by method and outcome.",
+	}, []string{"method", "outcome"})
+)
+
+// buildInfo is always 1; its labels identify the running build, so dashboards can split series and
+// mark deploys by version
+var buildInfo = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "app_build_info",
+	Help: "Build of the running binary (always 1), labelled with version, commit, build_time and go_version.",
+	ConstLabels: prometheus.Labels{
+		"version":    buildinfo.Get().Version,
+		"commit":     buildinfo.Get().Commit,
+		"build_time": buildinfo.Get().BuildTime,
+		"go_version": buildinfo.Get().GoVersion,
+	},
+})
+
+func init() {
+	buildInfo.Set(1)
+	Registry.MustRegister(
+		buildInfo,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		dbQueryDuration,
+		dbQueryErrors,
+		dbSlowQueries,
+		httpRequests,
+		httpRequestDuration,
+		authRegistrations,
+		authLogins,
+	)
+}
+
+// ObserveRequest records a served HTTP request
+func ObserveRequest(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpRequestDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// RecordRegistration counts a registered user; method is AuthMethodPassword or the OAuth provider
+func RecordRegistration(method string) {
+	authRegistrations.WithLabelValues(method).Inc()
+}
+
+// RecordLogin counts a login with outcome LoginSucceeded (tokens issued) or LoginFailed (wrong
+// credentials or 2FA code); method is AuthMethodPassword or the OAuth provider
+func RecordLogin(method, outcome string) {
+	authLogins.WithLabelValues(method, outcome).Inc()
+}
+
+// MetricsHandler serves the registry in the Prometheus text format. With a token, scrapers must
+// send it as "Authorization: Bearer <token>".
+func MetricsHandler(token string) fiber.Handler {
+	serve := adaptor.HTTPHandler(promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+	expected := []byte("Bearer " + token)
+
+	return func(c *fiber.Ctx) error {
+		if token != "" && subtle.ConstantTimeCompare([]byte(c.Get(fiber.HeaderAuthorization)), expected) != 1 {
+			return c.SendStatus(fiber.StatusUnauthorized)
+		}
+		return serve(c)
+	}
+}
@@ -0,0 +1,118 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RedisHook records Redis command latency and cache hits/misses on the wide event
+// carried by the command context, and a span per command or pipeline under the span of that context
+type RedisHook struct{}
+
+// DialHook implements redis.Hook
+func (RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+// ProcessHook implements redis.Hook
+func (RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) (err error) {
+		ctx, span := startRedisSpan(ctx, strings.ToLower(cmd.Name()))
+		defer func() { endRedisSpan(span, err) }()
+
+		event := FromContext(ctx)
+		if event == nil {
+			return next(ctx, cmd)
+		}
+
+		start := time.Now()
+		err = next(ctx, cmd)
+		elapsed := time.Since(start)
+		event.AddDuration("cache", elapsed)
+		event.Incr("cache.commands", 1)
+
+		// Read commands count towards the hit ratio; a redis.Nil reply is a miss
+		result := "ok"
+		switch strings.ToLower(cmd.Name()) {
+		case "get", "getdel", "hget", "mget", "exists":
+			if errors.Is(err, redis.Nil) {
+				event.Incr("cache.misses", 1)
+				result = "miss"
+			} else if err == nil {
+				event.Incr("cache.hits", 1)
+				result = "hit"
+			}
+		}
+
+		if err != nil && !errors.Is(err, redis.Nil) {
+			event.Incr("cache.errors", 1)
+			result = "error"
+		}
+
+		if event.Debugging() {
+			event.RecordCacheOp(DebugCacheOp{
+				Command:    strings.ToLower(cmd.Name()),
+				Key:        keyNamespace(commandKey(cmd)),
+				Result:     result,
+				DurationMs: durationMillis(elapsed),
+			})
+		}
+
+		return err
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook
+func (RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) (err error) {
+		ctx, span := startRedisSpan(ctx, "pipeline", attribute.Int("db.operation.batch.size", len(cmds)))
+		defer func() { endRedisSpan(span, err) }()
+
+		event := FromContext(ctx)
+		if event == nil {
+			return next(ctx, cmds)
+		}
+
+		start := time.Now()
+		err = next(ctx, cmds)
+		event.AddDuration("cache", time.Since(start))
+		event.Incr("cache.commands", int64(len(cmds)))
+
+		return err
+	}
+}
+
+// startRedisSpan starts the client span of a command
+func startRedisSpan(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, semconv.DBSystemNameRedis, semconv.DBOperationName(operation))
+	return Tracer().Start(ctx, operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// endRedisSpan ends the span of a command; a redis.Nil reply (missing key) is not an error
+func endRedisSpan(span trace.Span, err error) {
+	if spanError(err, redis.Nil) {
+		EndSpan(span, err)
+		return
+	}
+	span.End()
+}
+
+// commandKey returns the first key argument of a command, if any
+func commandKey(cmd redis.Cmder) string {
+	args := cmd.Args()
+	if len(args) < 2 {
+		return ""
+	}
+	key, _ := args[1].(string)
+	return key
+}
@@ -0,0 +1,28 @@
+package observability
+
+import "context"
+
+// requestContextKey is the context key under which the request description is stored
+type requestContextKey struct{}
+
+// Request identifies the HTTP request a context belongs to, so code without the fiber.Ctx
+// (database hooks, services) can tag its logs with it
+type Request struct {
+	ID     string
+	Method string
+	Path   string
+}
+
+// WithRequest returns a copy of ctx carrying the request description
+func WithRequest(ctx context.Context, r Request) context.Context {
+	return context.WithValue(ctx, requestContextKey{}, r)
+}
+
+// RequestFromContext returns the request description stored in ctx
+func RequestFromContext(ctx context.Context) (Request, bool) {
+	if ctx == nil {
+		return Request{}, false
+	}
+	r, ok := ctx.Value(requestContextKey{}).(Request)
+	return r, ok
+}
@@ -0,0 +1,148 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go_boilerplate/internal/shared/buildinfo"
+	"go_boilerplate/internal/shared/config"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the spans recorded by this codebase
+const tracerName = "go_boilerplate"
+
+// Exporters selectable with TRACING_EXPORTER
+const (
+	ExporterOTLPHTTP = "otlphttp"
+	ExporterOTLPGRPC = "otlpgrpc"
+)
+
+// InitTracing installs the global OpenTelemetry tracer provider exporting to the OTLP collector of
+// TRACING_ENDPOINT, and the W3C trace context propagator; export failures are logged as warnings.
+// The returned function flushes pending spans on shutdown. Until it is called, and while
+// TRACING_ENABLED is off, spans are no-ops.
+func InitTracing(ctx context.Context, cfg *config.Config, logger *logrus.Logger) (func(context.Context) error, error) {
+	exporter, err := newExporter(ctx, cfg.Tracing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", cfg.Tracing.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(cfg.Tracing.ServiceName),
+		semconv.ServiceVersion(buildinfo.Get().Version),
+		semconv.DeploymentEnvironmentName(cfg.Server.Mode),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.Tracing.SampleRatio))),
+	)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warnf("Tracing: %v", err)
+	}))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// newExporter creates the OTLP exporter of TRACING_EXPORTER; without TRACING_ENDPOINT the
+// exporters read OTEL_EXPORTER_OTLP_ENDPOINT, then default to localhost
+func newExporter(ctx context.Context, cfg config.TracingConfig) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case ExporterOTLPGRPC:
+		var opts []otlptracegrpc.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	default:
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	}
+}
+
+// Tracer returns the tracer of this codebase from the global provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// StartSpan starts an internal span as a child of the span in ctx, e.g. around a service method:
+//
+//	ctx, span := observability.StartSpan(ctx, "auth.Login")
+//	defer span.End()
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan records err on the span, if any, and ends it
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceFields returns the trace_id and span_id of the span in ctx as log fields, or nil without one
+func TraceFields(ctx context.Context) logrus.Fields {
+	if ctx == nil {
+		return nil
+	}
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return nil
+	}
+	return logrus.Fields{
+		"trace_id": spanContext.TraceID().String(),
+		"span_id":  spanContext.SpanID().String(),
+	}
+}
+
+// TraceHook adds the trace_id and span_id of the current span to log entries created with
+// logger.WithContext(ctx), so logs can be joined with their trace
+type TraceHook struct{}
+
+// Levels implements logrus.Hook
+func (TraceHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook
+func (TraceHook) Fire(entry *logrus.Entry) error {
+	for key, value := range TraceFields(entry.Context) {
+		entry.Data[key] = value
+	}
+	return nil
+}
+
+// spanError reports whether err should mark a span failed; a missing row or cache key is a result,
+// not a failure
+func spanError(err, notFound error) bool {
+	return err != nil && !errors.Is(err, notFound)
+}
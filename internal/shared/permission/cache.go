@@ -0,0 +1,91 @@
+package permission
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// cacheGenerationKey is bumped whenever a role changes, invalidating every cached permission set at once
+const cacheGenerationKey = "permissions:generation"
+
+// Cache keeps each user's effective permission set in Redis for a short time so
+// permission checks can read live data without hitting the database on every request.
+// A nil *Cache is valid and never caches.
+type Cache struct {
+	redis *redis.Client
+	ttl   time.Duration
+}
+
+// NewCache creates a permission cache with the given entry lifetime; without a Redis client it
+// returns nil, which never caches
+func NewCache(client *redis.Client, ttl time.Duration) *Cache {
+	if client == nil {
+		return nil
+	}
+	return &Cache{redis: client, ttl: ttl}
+}
+
+// Get returns the cached permissions of a user, calling load on a miss
+// Redis failures fall back to load so an outage never grants or denies by itself
+func (c *Cache) Get(ctx context.Context, userID string, load func() ([]string, error)) ([]string, error) {
+	if c == nil || c.ttl <= 0 {
+		return load()
+	}
+
+	key, err := c.key(ctx, userID)
+	if err != nil {
+		return load()
+	}
+
+	if cached, err := c.redis.Get(ctx, key).Bytes(); err == nil {
+		var permissions []string
+		if err := json.Unmarshal(cached, &permissions); err == nil {
+			return permissions, nil
+		}
+	}
+
+	permissions, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(permissions); err == nil {
+		_ = c.redis.Set(ctx, key, data, c.ttl).Err()
+	}
+	return permissions, nil
+}
+
+// InvalidateUser drops the cached permissions of a single user (e.g. after a role assignment)
+func (c *Cache) InvalidateUser(ctx context.Context, userID string) error {
+	if c == nil {
+		return nil
+	}
+
+	key, err := c.key(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return c.redis.Del(ctx, key).Err()
+}
+
+// InvalidateAll drops every cached permission set (e.g. after a role's permissions change)
+func (c *Cache) InvalidateAll(ctx context.Context) error {
+	if c == nil {
+		return nil
+	}
+	return c.redis.Incr(ctx, cacheGenerationKey).Err()
+}
+
+// key builds the cache key of a user for the current generation
+func (c *Cache) key(ctx context.Context, userID string) (string, error) {
+	generation, err := c.redis.Get(ctx, cacheGenerationKey).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return "", err
+	}
+	return fmt.Sprintf("permissions:%d:%s", generation, userID), nil
+}
@@ -0,0 +1,132 @@
+package permission
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Wildcard grants every permission
+const Wildcard = "*"
+
+// Permission describes a single `resource.action` permission exposed by a module
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+var (
+	mu      sync.RWMutex
+	catalog = map[string]Permission{}
+)
+
+// Register adds permissions to the catalog; modules call it from init()
+func Register(perms ...Permission) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, p := range perms {
+		catalog[p.Name] = p
+	}
+}
+
+// All returns every registered permission sorted by name
+func All() []Permission {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	perms := make([]Permission, 0, len(catalog))
+	for _, p := range catalog {
+		perms = append(perms, p)
+	}
+	sort.Slice(perms, func(i, j int) bool { return perms[i].Name < perms[j].Name })
+	return perms
+}
+
+// Exists reports whether name is a registered permission (or the wildcard)
+func Exists(name string) bool {
+	if name == Wildcard {
+		return true
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	_, ok := catalog[name]
+	return ok
+}
+
+// Match reports whether name is covered by pattern
+// Patterns are an exact name, the wildcard, `resource.*` or `*.action`
+func Match(pattern, name string) bool {
+	switch {
+	case pattern == Wildcard || pattern == name:
+		return true
+	case strings.HasSuffix(pattern, ".*"):
+		return strings.HasPrefix(name, strings.TrimSuffix(pattern, "*"))
+	case strings.HasPrefix(pattern, "*."):
+		return strings.HasSuffix(name, strings.TrimPrefix(pattern, "*"))
+	}
+	return false
+}
+
+// Expand resolves patterns to the sorted set of catalog permissions they cover
+// The wildcard is kept as-is so roles granted `*` also receive permissions registered later
+func Expand(patterns ...string) []string {
+	set := map[string]struct{}{}
+	for _, pattern := range patterns {
+		if pattern == Wildcard {
+			set[Wildcard] = struct{}{}
+			continue
+		}
+		for _, p := range All() {
+			if Match(pattern, p.Name) {
+				set[p.Name] = struct{}{}
+			}
+		}
+	}
+
+	expanded := make([]string, 0, len(set))
+	for name := range set {
+		expanded = append(expanded, name)
+	}
+	sort.Strings(expanded)
+	return expanded
+}
+
+// Resolve merges granted patterns with denied ones; a deny always wins, even over a wildcard grant
+// Patterns covering a denied permission are expanded to the catalog permissions they still grant
+func Resolve(granted, denied []string) []string {
+	isDenied := func(name string) bool {
+		for _, d := range denied {
+			if Match(d, name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	seen := map[string]bool{}
+	resolved := []string{}
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			resolved = append(resolved, name)
+		}
+	}
+
+	for _, g := range granted {
+		if len(denied) == 0 || !strings.Contains(g, "*") {
+			if !isDenied(g) {
+				add(g)
+			}
+			continue
+		}
+		for _, p := range All() {
+			if Match(g, p.Name) && !isDenied(p.Name) {
+				add(p.Name)
+			}
+		}
+	}
+	return resolved
+}
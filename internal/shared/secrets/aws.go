@@ -0,0 +1,117 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"go_boilerplate/internal/shared/awssig"
+)
+
+// AWSSecretsManager reads AWS Secrets Manager secrets: aws-sm://<name or ARN>#<key>. Credentials come
+// from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN; the region from AWS_REGION
+// (or AWS_DEFAULT_REGION, or the ARN). AWS_ENDPOINT_URL_SECRETS_MANAGER overrides the endpoint.
+type AWSSecretsManager struct {
+	Region          string
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// NewAWSSecretsManager creates an AWS Secrets Manager provider from the standard AWS environment variables
+func NewAWSSecretsManager() *AWSSecretsManager {
+	endpoint := os.Getenv("AWS_ENDPOINT_URL_SECRETS_MANAGER")
+	if endpoint == "" {
+		endpoint = os.Getenv("AWS_ENDPOINT_URL")
+	}
+	credentials := awssig.FromEnv()
+	return &AWSSecretsManager{
+		Region:          awssig.RegionFromEnv(),
+		Endpoint:        endpoint,
+		AccessKeyID:     credentials.AccessKeyID,
+		SecretAccessKey: credentials.SecretAccessKey,
+		SessionToken:    credentials.SessionToken,
+	}
+}
+
+// Scheme returns aws-sm
+func (a *AWSSecretsManager) Scheme() string {
+	return "aws-sm"
+}
+
+// Fetch calls GetSecretValue and returns SecretString (or the decoded SecretBinary)
+func (a *AWSSecretsManager) Fetch(ctx context.Context, path string) ([]byte, error) {
+	if a.AccessKeyID == "" || a.SecretAccessKey == "" {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required")
+	}
+
+	region := a.Region
+	if strings.HasPrefix(path, "arn:") {
+		// arn:aws:secretsmanager:<region>:<account>:secret:<name>
+		if parts := strings.Split(path, ":"); len(parts) > 3 && parts[3] != "" {
+			region = parts[3]
+		}
+	}
+	if region == "" {
+		return nil, fmt.Errorf("AWS_REGION is required")
+	}
+
+	endpoint := a.Endpoint
+	if endpoint == "" {
+		endpoint = "https://secretsmanager." + region + ".amazonaws.com"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secrets manager endpoint: %w", err)
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	payload, err := json.Marshal(map[string]string{"SecretId": path})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	a.sign(req, payload, region, time.Now().UTC())
+
+	body, err := doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		SecretString *string `json:"SecretString"`
+		SecretBinary string  `json:"SecretBinary"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("decode secrets manager response: %w", err)
+	}
+	if resp.SecretString != nil {
+		return []byte(*resp.SecretString), nil
+	}
+	return base64.StdEncoding.DecodeString(resp.SecretBinary)
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to req
+func (a *AWSSecretsManager) sign(req *http.Request, payload []byte, region string, now time.Time) {
+	credentials := awssig.Credentials{
+		AccessKeyID:     a.AccessKeyID,
+		SecretAccessKey: a.SecretAccessKey,
+		SessionToken:    a.SessionToken,
+	}
+	credentials.Sign(req, payload, "secretsmanager", region, now)
+}
@@ -0,0 +1,100 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// gcpMetadataTokenURL serves access tokens for the attached service account on GCP compute
+const gcpMetadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// GCPSecretManager reads GCP Secret Manager secrets: gcp-sm://<project>/<secret>[/<version>]#<key>;
+// the version defaults to latest. The token comes from GCP_ACCESS_TOKEN, or else from the metadata
+// server of the instance the app runs on.
+type GCPSecretManager struct {
+	Endpoint    string
+	AccessToken string
+}
+
+// NewGCPSecretManager creates a GCP Secret Manager provider from the environment
+func NewGCPSecretManager() *GCPSecretManager {
+	return &GCPSecretManager{
+		Endpoint:    "https://secretmanager.googleapis.com",
+		AccessToken: os.Getenv("GCP_ACCESS_TOKEN"),
+	}
+}
+
+// Scheme returns gcp-sm
+func (g *GCPSecretManager) Scheme() string {
+	return "gcp-sm"
+}
+
+// Fetch reads the payload of a secret version
+func (g *GCPSecretManager) Fetch(ctx context.Context, path string) ([]byte, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("expected gcp-sm://<project>/<secret>[/<version>]")
+	}
+	version := "latest"
+	if len(parts) == 3 && parts[2] != "" {
+		version = parts[2]
+	}
+
+	token, err := g.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/v1/projects/%s/secrets/%s/versions/%s:access", g.Endpoint, parts[0], parts[1], version)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	body, err := doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("decode secret manager response: %w", err)
+	}
+	return base64.StdEncoding.DecodeString(resp.Payload.Data)
+}
+
+// token returns GCP_ACCESS_TOKEN or a token of the instance's service account
+func (g *GCPSecretManager) token(ctx context.Context) (string, error) {
+	if g.AccessToken != "" {
+		return g.AccessToken, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcpMetadataTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	body, err := doRequest(req)
+	if err != nil {
+		return "", fmt.Errorf("GCP_ACCESS_TOKEN is not set and the metadata server is unavailable: %w", err)
+	}
+
+	var resp struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || resp.AccessToken == "" {
+		return "", fmt.Errorf("metadata server returned no access token")
+	}
+	return resp.AccessToken, nil
+}
@@ -0,0 +1,218 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Provider fetches secrets of one reference scheme from an external secret store
+type Provider interface {
+	// Scheme is the reference scheme handled by the provider, e.g. vault for vault://...
+	Scheme() string
+	// Fetch returns the raw secret at path; JSON objects may be picked apart with #key
+	Fetch(ctx context.Context, path string) ([]byte, error)
+}
+
+// Ref is a parsed secret reference: <scheme>://<path>[#<key>]
+type Ref struct {
+	Scheme string
+	Path   string
+	Key    string // Field of a JSON secret; empty uses the whole secret
+}
+
+// String returns the reference as written
+func (r Ref) String() string {
+	if r.Key == "" {
+		return r.Scheme + "://" + r.Path
+	}
+	return r.Scheme + "://" + r.Path + "#" + r.Key
+}
+
+// fetchTimeout bounds a single request to a secret store
+const fetchTimeout = 10 * time.Second
+
+// httpClient is shared by the built-in providers
+var httpClient = &http.Client{Timeout: fetchTimeout}
+
+// cachedSecret is a fetched secret and when it was fetched
+type cachedSecret struct {
+	payload   []byte
+	fetchedAt time.Time
+}
+
+// Resolver resolves secret references through the registered providers. Fetched secrets are
+// cached per path (one fetch serves every #key of a secret) for the TTL; a TTL of 0 caches them
+// until Refresh.
+type Resolver struct {
+	ttl       time.Duration
+	providers map[string]Provider
+
+	mu    sync.Mutex
+	cache map[string]cachedSecret // By scheme://path
+}
+
+// registered holds providers added with Register
+var (
+	registeredMu sync.Mutex
+	registered   []Provider
+)
+
+// Register adds a provider to every resolver created afterwards, including the one LoadConfig uses;
+// call it from init(). A provider replaces a built-in one with the same scheme.
+func Register(p Provider) {
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+	registered = append(registered, p)
+}
+
+// NewResolver creates a resolver with the built-in providers (vault, aws-sm, gcp-sm), the registered
+// ones and extra ones, later ones replacing earlier ones with the same scheme
+func NewResolver(ttl time.Duration, extra ...Provider) *Resolver {
+	r := &Resolver{
+		ttl:       ttl,
+		providers: map[string]Provider{},
+		cache:     map[string]cachedSecret{},
+	}
+
+	registeredMu.Lock()
+	providers := append([]Provider{NewVault(), NewAWSSecretsManager(), NewGCPSecretManager()}, registered...)
+	registeredMu.Unlock()
+
+	for _, p := range append(providers, extra...) {
+		r.providers[p.Scheme()] = p
+	}
+	return r
+}
+
+// IsReference reports whether value is a reference to a registered provider's scheme
+func (r *Resolver) IsReference(value string) bool {
+	scheme, _, ok := strings.Cut(value, "://")
+	if !ok {
+		return false
+	}
+	_, ok = r.providers[scheme]
+	return ok
+}
+
+// Parse splits a secret reference into scheme, path and key
+func Parse(value string) (Ref, error) {
+	scheme, rest, ok := strings.Cut(value, "://")
+	if !ok || scheme == "" {
+		return Ref{}, fmt.Errorf("secrets: %q is not a <scheme>://<path> reference", value)
+	}
+	path, key := rest, ""
+	if i := strings.LastIndex(rest, "#"); i >= 0 {
+		path, key = rest[:i], rest[i+1:]
+	}
+	if path == "" {
+		return Ref{}, fmt.Errorf("secrets: %q has no path", value)
+	}
+	return Ref{Scheme: scheme, Path: path, Key: key}, nil
+}
+
+// Resolve returns the secret a reference points to
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	ref, err := Parse(value)
+	if err != nil {
+		return "", err
+	}
+	provider, ok := r.providers[ref.Scheme]
+	if !ok {
+		return "", fmt.Errorf("secrets: no provider for %s://", ref.Scheme)
+	}
+
+	cacheKey := ref.Scheme + "://" + ref.Path
+	r.mu.Lock()
+	cached, hit := r.cache[cacheKey]
+	r.mu.Unlock()
+
+	if !hit || (r.ttl > 0 && time.Since(cached.fetchedAt) > r.ttl) {
+		payload, err := provider.Fetch(ctx, ref.Path)
+		if err != nil {
+			return "", fmt.Errorf("secrets: %s: %w", ref, err)
+		}
+		cached = cachedSecret{payload: payload, fetchedAt: time.Now()}
+		r.mu.Lock()
+		r.cache[cacheKey] = cached
+		r.mu.Unlock()
+	}
+
+	secret, err := pickKey(cached.payload, ref.Key)
+	if err != nil {
+		return "", fmt.Errorf("secrets: %s: %w", ref, err)
+	}
+	return secret, nil
+}
+
+// Refresh fetches every cached secret again and returns the references (without #key) whose value
+// changed. Values already handed out are not updated; the caller decides how to apply a rotation.
+func (r *Resolver) Refresh(ctx context.Context) ([]string, error) {
+	r.mu.Lock()
+	keys := make([]string, 0, len(r.cache))
+	for key := range r.cache {
+		keys = append(keys, key)
+	}
+	r.mu.Unlock()
+
+	var changed []string
+	for _, key := range keys {
+		ref, _ := Parse(key)
+		payload, err := r.providers[ref.Scheme].Fetch(ctx, ref.Path)
+		if err != nil {
+			return changed, fmt.Errorf("secrets: %s: %w", key, err)
+		}
+
+		r.mu.Lock()
+		if !bytes.Equal(r.cache[key].payload, payload) {
+			changed = append(changed, key)
+		}
+		r.cache[key] = cachedSecret{payload: payload, fetchedAt: time.Now()}
+		r.mu.Unlock()
+	}
+	return changed, nil
+}
+
+// pickKey returns the whole payload, or the named field when it is a JSON object
+func pickKey(payload []byte, key string) (string, error) {
+	if key == "" {
+		return string(payload), nil
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return "", fmt.Errorf("#%s needs a JSON object secret", key)
+	}
+	value, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("secret has no %q field", key)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+// doRequest sends req and returns the body of a 2xx response
+func doRequest(req *http.Request) ([]byte, error) {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s returned %d: %s", req.URL.Host, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
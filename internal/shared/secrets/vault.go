@@ -0,0 +1,71 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Vault reads HashiCorp Vault KV v2 secrets: vault://<mount>/<path>#<key>, e.g. vault://secret/app/db#password.
+// The server and token come from VAULT_ADDR, VAULT_TOKEN and the optional VAULT_NAMESPACE.
+type Vault struct {
+	Addr      string
+	Token     string
+	Namespace string
+}
+
+// NewVault creates a Vault provider from the standard Vault environment variables
+func NewVault() *Vault {
+	return &Vault{
+		Addr:      os.Getenv("VAULT_ADDR"),
+		Token:     os.Getenv("VAULT_TOKEN"),
+		Namespace: os.Getenv("VAULT_NAMESPACE"),
+	}
+}
+
+// Scheme returns vault
+func (v *Vault) Scheme() string {
+	return "vault"
+}
+
+// Fetch reads the data of a KV v2 secret as a JSON object
+func (v *Vault) Fetch(ctx context.Context, path string) ([]byte, error) {
+	if v.Addr == "" || v.Token == "" {
+		return nil, fmt.Errorf("VAULT_ADDR and VAULT_TOKEN are required")
+	}
+	mount, secretPath, ok := strings.Cut(path, "/")
+	if !ok || secretPath == "" {
+		return nil, fmt.Errorf("expected vault://<mount>/<path>")
+	}
+
+	url := strings.TrimRight(v.Addr, "/") + "/v1/" + mount + "/data/" + secretPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", v.Token)
+	if v.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+
+	body, err := doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data struct {
+			Data json.RawMessage `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("decode vault response: %w", err)
+	}
+	if len(resp.Data.Data) == 0 || string(resp.Data.Data) == "null" {
+		return nil, fmt.Errorf("vault secret has no data")
+	}
+	return resp.Data.Data, nil
+}
@@ -0,0 +1,161 @@
+package serializer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go_boilerplate/internal/shared/apperror"
+	"go_boilerplate/internal/shared/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrInvalidInclude is matched by every include parse error (errors.Is), which answers 400 naming the relation
+var ErrInvalidInclude = apperror.New(apperror.ErrValidation, "invalid include").WithCode("invalid_include")
+
+// alwaysIncluded are fields kept in every sparse response, so clients can still identify resources
+var alwaysIncluded = []string{"id"}
+
+// Options are the sparse fieldset (?fields=id,name,email) and the related resources to embed
+// (?include=roles) requested by a client
+type Options struct {
+	Fields  []string        // Top-level response fields to keep; empty keeps all of them
+	Include map[string]bool // Requested relations; always a subset of the endpoint's includable names
+}
+
+// FromQuery parses ?fields= and ?include= (comma-separated) against the relations the endpoint can embed.
+// Unknown fields are ignored; unknown relations return an error wrapping ErrInvalidInclude.
+func FromQuery(c *fiber.Ctx, includable ...string) (Options, error) {
+	opts := Options{Fields: splitList(c.Query("fields"))}
+
+	for _, name := range splitList(c.Query("include")) {
+		if !contains(includable, name) {
+			if len(includable) == 0 {
+				return Options{}, ErrInvalidInclude.WithMessage(fmt.Sprintf("invalid include: %q (this endpoint has no includable relations)", name))
+			}
+			return Options{}, ErrInvalidInclude.WithMessage(fmt.Sprintf("invalid include: %q (supported: %s)", name, strings.Join(includable, ", ")))
+		}
+		if opts.Include == nil {
+			opts.Include = make(map[string]bool)
+		}
+		opts.Include[name] = true
+	}
+
+	return opts, nil
+}
+
+// Includes reports whether the relation was requested
+func (o Options) Includes(name string) bool {
+	return o.Include[name]
+}
+
+// Apply trims v to the requested fields. With a collection name (e.g. "users"), v is a list response
+// and every element of v[collection] is trimmed instead, keeping the rest (meta) untouched. Requested
+// relations and id are always kept. v is returned as is when no fields were requested; trimmed lists
+// remain utils.Paginated when v is.
+func (o Options) Apply(v any, collection string) (any, error) {
+	if len(o.Fields) == 0 {
+		return v, nil
+	}
+
+	keep := make(map[string]bool, len(o.Fields)+len(o.Include)+len(alwaysIncluded))
+	for _, field := range o.Fields {
+		keep[field] = true
+	}
+	for name := range o.Include {
+		keep[name] = true
+	}
+	for _, field := range alwaysIncluded {
+		keep[field] = true
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if collection == "" {
+		var item map[string]json.RawMessage
+		if err := json.Unmarshal(encoded, &item); err != nil {
+			return nil, err
+		}
+		return pick(item, keep), nil
+	}
+
+	var list map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &list); err != nil {
+		return nil, err
+	}
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(list[collection], &items); err != nil {
+		return nil, err
+	}
+
+	trimmed := make([]map[string]json.RawMessage, len(items))
+	for i, item := range items {
+		trimmed[i] = pick(item, keep)
+	}
+	if list[collection], err = json.Marshal(trimmed); err != nil {
+		return nil, err
+	}
+	if paginated, ok := v.(utils.Paginated); ok {
+		return page{body: list, items: list[collection], meta: paginated.PageMeta()}, nil
+	}
+	return list, nil
+}
+
+// page is a trimmed list response that stays utils.Paginated, so it can be sent without the envelope
+type page struct {
+	body  map[string]json.RawMessage
+	items json.RawMessage
+	meta  utils.PaginationMeta
+}
+
+// MarshalJSON encodes the trimmed list response
+func (p page) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.body)
+}
+
+// PageItems returns the trimmed items (utils.Paginated)
+func (p page) PageItems() any {
+	return p.items
+}
+
+// PageMeta returns the pagination metadata of the source response (utils.Paginated)
+func (p page) PageMeta() utils.PaginationMeta {
+	return p.meta
+}
+
+// pick returns the members of item whose names are in keep
+func pick(item map[string]json.RawMessage, keep map[string]bool) map[string]json.RawMessage {
+	picked := make(map[string]json.RawMessage, len(keep))
+	for name, value := range item {
+		if keep[name] {
+			picked[name] = value
+		}
+	}
+	return picked
+}
+
+// splitList splits a comma-separated query value, dropping blanks and duplicates
+func splitList(raw string) []string {
+	var values []string
+	for _, value := range strings.Split(raw, ",") {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value != "" && !contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// contains reports whether values contains value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"go_boilerplate/internal/shared/config"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// Listen serves app on SERVER_PORT: plain HTTP, or HTTPS with the configured certificate files or
+// autocert certificates. With TLS_HTTP_REDIRECT a second listener on TLS_HTTP_PORT redirects HTTP
+// requests to HTTPS and answers ACME http-01 challenges; it is closed when the app shuts down.
+// Listen blocks until the app stops.
+func Listen(app *fiber.App, cfg *config.Config, logger *logrus.Logger) error {
+	addr := ":" + cfg.Server.Port
+	if !cfg.TLS.Enabled {
+		return app.Listen(addr)
+	}
+
+	var manager *autocert.Manager
+	if cfg.TLS.Autocert {
+		manager = &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.TLS.AutocertHosts...),
+			Cache:      autocert.DirCache(cfg.TLS.AutocertCacheDir),
+			Email:      cfg.TLS.AutocertEmail,
+		}
+	}
+
+	if cfg.TLS.HTTPRedirect {
+		redirect := &http.Server{
+			Addr:              ":" + cfg.TLS.HTTPPort,
+			Handler:           redirectHandler(cfg.Server.Port),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		if manager != nil {
+			redirect.Handler = manager.HTTPHandler(redirect.Handler)
+		}
+		app.Hooks().OnShutdown(redirect.Close)
+
+		go func() {
+			logger.Infof("Redirecting HTTP on %s to HTTPS", redirect.Addr)
+			if err := redirect.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Errorf("HTTP redirect listener failed: %v", err)
+			}
+		}()
+	}
+
+	if manager == nil {
+		return app.ListenTLS(addr, cfg.TLS.CertFile, cfg.TLS.KeyFile)
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	tlsConfig := manager.TLSConfig()
+	tlsConfig.MinVersion = tls.VersionTLS12
+	return app.Listener(tls.NewListener(ln, tlsConfig))
+}
+
+// redirectHandler redirects every request to the same host and path over HTTPS
+func redirectHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+
+		// Only GET and HEAD are safe to follow automatically; other methods keep their body with 308
+		status := http.StatusMovedPermanently
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			status = http.StatusPermanentRedirect
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), status)
+	})
+}
+
+// Scheme returns https when TLS is enabled, http otherwise
+func Scheme(cfg *config.Config) string {
+	if cfg.TLS.Enabled {
+		return "https"
+	}
+	return "http"
+}
@@ -0,0 +1,63 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	authdto "go_boilerplate/internal/modules/auth/dto"
+	"go_boilerplate/internal/shared/config"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Cleanup deletes t_sessions rows whose refresh token expired, blocked ones included, which the
+// database store never reads again. Rows are deleted SESSION_CLEANUP_BATCH_SIZE at a time, so a
+// large backlog doesn't hold long locks. The redis store needs no cleanup.
+type Cleanup struct {
+	db     *gorm.DB
+	cfg    config.SessionStoreConfig
+	logger *logrus.Logger
+}
+
+// NewCleanup creates the expired session cleanup
+func NewCleanup(db *gorm.DB, cfg config.SessionStoreConfig, logger *logrus.Logger) *Cleanup {
+	return &Cleanup{db: db, cfg: cfg, logger: logger}
+}
+
+// Run deletes expired sessions; it is meant to be scheduled every SESSION_CLEANUP_INTERVAL
+func (c *Cleanup) Run(ctx context.Context) error {
+	start := time.Now()
+	deleted, err := c.DeleteExpired(ctx, start)
+	if deleted > 0 || err != nil {
+		c.logger.WithFields(logrus.Fields{
+			"rows":    deleted,
+			"latency": time.Since(start).String(),
+		}).Infof("Deleted %d expired sessions", deleted)
+	}
+	return err
+}
+
+// DeleteExpired deletes sessions that expired before cutoff in batches, until a batch comes back
+// short, and returns how many it deleted
+func (c *Cleanup) DeleteExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	var deleted int64
+	for {
+		batch := c.db.WithContext(ctx).Model(&authdto.Session{}).
+			Select("id").Where("expires_at < ?", cutoff).Limit(c.cfg.CleanupBatchSize)
+		result := c.db.WithContext(ctx).
+			Where(clause.Expr{SQL: "id IN (?)", Vars: []any{batch}}).
+			Delete(&authdto.Session{})
+		deleted += result.RowsAffected
+		if result.Error != nil {
+			return deleted, result.Error
+		}
+		if result.RowsAffected < int64(c.cfg.CleanupBatchSize) {
+			return deleted, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return deleted, err
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	authdto "go_boilerplate/internal/modules/auth/dto"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DatabaseStore keeps sessions as t_sessions rows; expired rows stay until they are deleted
+type DatabaseStore struct {
+	db *gorm.DB
+}
+
+// NewDatabaseStore creates a session store over t_sessions
+func NewDatabaseStore(db *gorm.DB) *DatabaseStore {
+	return &DatabaseStore{db: db}
+}
+
+// Create implements Store
+func (s *DatabaseStore) Create(ctx context.Context, session *authdto.Session) error {
+	return s.db.WithContext(ctx).Create(session).Error
+}
+
+// FindActive implements Store
+func (s *DatabaseStore) FindActive(ctx context.Context, token string) (*authdto.Session, error) {
+	var session authdto.Session
+	err := s.db.WithContext(ctx).Where("token = ? AND expires_at > ? AND is_blocked = ?", token, time.Now(), false).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Delete implements Store
+func (s *DatabaseStore) Delete(ctx context.Context, token string) error {
+	return s.db.WithContext(ctx).Where("token = ?", token).Delete(&authdto.Session{}).Error
+}
+
+// ListByUser implements Store
+func (s *DatabaseStore) ListByUser(ctx context.Context, userID uuid.UUID) ([]authdto.Session, error) {
+	var sessions []authdto.Session
+	err := s.db.WithContext(ctx).Where("user_id = ?", userID).Order("last_active desc").Find(&sessions).Error
+	return sessions, err
+}
+
+// DeleteForUser implements Store
+func (s *DatabaseStore) DeleteForUser(ctx context.Context, userID, sessionID uuid.UUID) error {
+	return s.db.WithContext(ctx).Where("id = ? AND user_id = ?", sessionID, userID).Delete(&authdto.Session{}).Error
+}
+
+// BlockForUser implements Store
+func (s *DatabaseStore) BlockForUser(ctx context.Context, userID, sessionID uuid.UUID) error {
+	return s.db.WithContext(ctx).Model(&authdto.Session{}).Where("id = ? AND user_id = ?", sessionID, userID).Update("is_blocked", true).Error
+}
@@ -0,0 +1,47 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// denylistPrefix namespaces denied access tokens, stored as denylist:<sha256 of the token>
+const denylistPrefix = "denylist:"
+
+// Denylist revokes access tokens before they expire (e.g. on logout). Entries expire with the token
+// they deny, so the list only holds tokens that would otherwise still be accepted.
+// A nil *Denylist denies nothing, which is the case without Redis.
+type Denylist struct {
+	redis *redis.Client
+}
+
+// NewDenylist creates an access-token denylist; without a Redis client it returns nil
+func NewDenylist(client *redis.Client) *Denylist {
+	if client == nil {
+		return nil
+	}
+	return &Denylist{redis: client}
+}
+
+// Deny refuses token until expiresAt, when the token expires anyway
+func (d *Denylist) Deny(ctx context.Context, token string, expiresAt time.Time) error {
+	if d == nil {
+		return nil
+	}
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return d.redis.Set(ctx, denylistPrefix+hashToken(token), 1, ttl).Err()
+}
+
+// Denied reports whether token was denied
+func (d *Denylist) Denied(ctx context.Context, token string) (bool, error) {
+	if d == nil {
+		return false, nil
+	}
+	n, err := d.redis.Exists(ctx, denylistPrefix+hashToken(token)).Result()
+	return n > 0, err
+}
@@ -0,0 +1,216 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	authdto "go_boilerplate/internal/modules/auth/dto"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix namespaces session keys: session:<id> holds the session, session:token:<sha256> maps a
+// refresh token to it and session:user:<user ID> indexes a user's sessions by expiry
+const keyPrefix = "session:"
+
+// redisRecord is a session as stored in Redis: the refresh token itself is never stored, only its hash
+type redisRecord struct {
+	authdto.Session
+	TokenHash string `json:"token_hash"`
+}
+
+// RedisStore keeps sessions in Redis with native expiry, so expired refresh tokens disappear on
+// their own instead of piling up as rows. Sessions returned by ListByUser carry no token.
+type RedisStore struct {
+	redis *redis.Client
+}
+
+// NewRedisStore creates a Redis-backed session store
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{redis: client}
+}
+
+// Create implements Store
+func (s *RedisStore) Create(ctx context.Context, session *authdto.Session) error {
+	ttl := time.Until(session.ExpiresAt)
+	if ttl <= 0 {
+		return fmt.Errorf("session already expired at %s", session.ExpiresAt)
+	}
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = time.Now()
+	}
+
+	record := redisRecord{Session: *session, TokenHash: hashToken(session.Token)}
+	record.Token = ""
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	id := session.ID.String()
+	userKey := userKey(session.UserID)
+	pipe := s.redis.TxPipeline()
+	pipe.Set(ctx, recordKey(id), data, ttl)
+	pipe.Set(ctx, tokenKey(record.TokenHash), id, ttl)
+	pipe.ZAdd(ctx, userKey, redis.Z{Score: float64(session.ExpiresAt.Unix()), Member: id})
+	// Every session lasts JWT_REFRESH_EXPIRY, so the newest one outlives the others in the index
+	pipe.Expire(ctx, userKey, ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// FindActive implements Store
+func (s *RedisStore) FindActive(ctx context.Context, token string) (*authdto.Session, error) {
+	id, err := s.redis.Get(ctx, tokenKey(hashToken(token))).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || record.IsBlocked || !record.ExpiresAt.After(time.Now()) {
+		return nil, ErrNotFound
+	}
+	session := record.Session
+	session.Token = token
+	return &session, nil
+}
+
+// Delete implements Store
+func (s *RedisStore) Delete(ctx context.Context, token string) error {
+	hash := hashToken(token)
+	id, err := s.redis.Get(ctx, tokenKey(hash)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	record, err := s.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return s.redis.Del(ctx, tokenKey(hash)).Err()
+	}
+	return s.remove(ctx, record)
+}
+
+// ListByUser implements Store
+func (s *RedisStore) ListByUser(ctx context.Context, userID uuid.UUID) ([]authdto.Session, error) {
+	key := userKey(userID)
+	if err := s.redis.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(time.Now().Unix(), 10)).Err(); err != nil {
+		return nil, err
+	}
+	ids, err := s.redis.ZRange(ctx, key, 0, -1).Result()
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = recordKey(id)
+	}
+	values, err := s.redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]authdto.Session, 0, len(values))
+	for _, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue // Expired since the index was pruned
+		}
+		var record redisRecord
+		if err := json.Unmarshal([]byte(data), &record); err != nil {
+			return nil, fmt.Errorf("failed to decode session: %w", err)
+		}
+		sessions = append(sessions, record.Session)
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].LastActive.After(sessions[j].LastActive)
+	})
+	return sessions, nil
+}
+
+// DeleteForUser implements Store
+func (s *RedisStore) DeleteForUser(ctx context.Context, userID, sessionID uuid.UUID) error {
+	record, err := s.load(ctx, sessionID.String())
+	if err != nil || record == nil || record.UserID != userID {
+		return err
+	}
+	return s.remove(ctx, record)
+}
+
+// BlockForUser implements Store
+func (s *RedisStore) BlockForUser(ctx context.Context, userID, sessionID uuid.UUID) error {
+	record, err := s.load(ctx, sessionID.String())
+	if err != nil || record == nil || record.UserID != userID {
+		return err
+	}
+
+	record.IsBlocked = true
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.redis.SetArgs(ctx, recordKey(sessionID.String()), data, redis.SetArgs{KeepTTL: true, Mode: "XX"}).Err()
+}
+
+// load returns the stored session with the given ID, or nil once it expired
+func (s *RedisStore) load(ctx context.Context, id string) (*redisRecord, error) {
+	data, err := s.redis.Get(ctx, recordKey(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var record redisRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to decode session: %w", err)
+	}
+	return &record, nil
+}
+
+// remove deletes a session together with its token mapping and index entry
+func (s *RedisStore) remove(ctx context.Context, record *redisRecord) error {
+	id := record.ID.String()
+	pipe := s.redis.TxPipeline()
+	pipe.Del(ctx, recordKey(id), tokenKey(record.TokenHash))
+	pipe.ZRem(ctx, userKey(record.UserID), id)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// recordKey holds the session with the given ID
+func recordKey(id string) string {
+	return keyPrefix + id
+}
+
+// tokenKey maps a refresh token hash to its session ID
+func tokenKey(hash string) string {
+	return keyPrefix + "token:" + hash
+}
+
+// userKey indexes the session IDs of a user, scored by expiry
+func userKey(userID uuid.UUID) string {
+	return keyPrefix + "user:" + userID.String()
+}
@@ -0,0 +1,55 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	authdto "go_boilerplate/internal/modules/auth/dto"
+	"go_boilerplate/internal/shared/config"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// Drivers selectable with SESSION_STORE
+const (
+	DriverDatabase = "database"
+	DriverRedis    = "redis"
+)
+
+// ErrNotFound is returned by FindActive when the refresh token has no usable session
+var ErrNotFound = errors.New("session not found, expired, or blocked")
+
+// Store keeps sessions: refresh tokens with the device metadata they were issued to
+type Store interface {
+	// Create stores a new session; ExpiresAt bounds how long its refresh token is accepted
+	Create(ctx context.Context, session *authdto.Session) error
+	// FindActive returns the unexpired, unblocked session of a refresh token, or ErrNotFound
+	FindActive(ctx context.Context, token string) (*authdto.Session, error)
+	// Delete removes the session of a refresh token; unknown tokens are ignored
+	Delete(ctx context.Context, token string) error
+	// ListByUser returns the sessions of a user, most recently active first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]authdto.Session, error)
+	// DeleteForUser removes a session of a user by ID
+	DeleteForUser(ctx context.Context, userID, sessionID uuid.UUID) error
+	// BlockForUser blocks a session of a user by ID, so its refresh token is refused until it expires
+	BlockForUser(ctx context.Context, userID, sessionID uuid.UUID) error
+}
+
+// NewStore creates the session store selected by SESSION_STORE; without a Redis client the redis
+// driver falls back to the database
+func NewStore(cfg config.SessionStoreConfig, db *gorm.DB, client *redis.Client) Store {
+	if cfg.Driver == DriverRedis && client != nil {
+		return NewRedisStore(client)
+	}
+	return NewDatabaseStore(db)
+}
+
+// hashToken identifies a token in Redis keys without storing the token itself
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
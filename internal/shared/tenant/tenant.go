@@ -0,0 +1,87 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Column is the column tenant-aware tables use to reference m_tenants
+const Column = "tenant_id"
+
+// ErrNotFound is returned by a Resolver for unknown or inactive tenants
+var ErrNotFound = errors.New("tenant not found")
+
+// Tenant is the tenant a request was resolved to
+type Tenant struct {
+	ID       uuid.UUID
+	Slug     string
+	Name     string
+	Settings map[string]any // Per-tenant configuration
+}
+
+// Resolver loads an active tenant by ID or slug
+type Resolver interface {
+	Resolve(ctx context.Context, key string) (*Tenant, error)
+}
+
+type contextKey struct{}
+
+// WithTenant stores the request tenant in ctx
+func WithTenant(ctx context.Context, t *Tenant) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the tenant stored by WithTenant
+func FromContext(ctx context.Context) (*Tenant, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	t, ok := ctx.Value(contextKey{}).(*Tenant)
+	return t, ok && t != nil
+}
+
+// IDFromContext returns the ID of the request tenant, nil when there is none
+func IDFromContext(ctx context.Context) *uuid.UUID {
+	if t, ok := FromContext(ctx); ok {
+		return &t.ID
+	}
+	return nil
+}
+
+// ClaimFromContext returns the tenant_id claim for tokens issued in ctx: the ID of the request
+// tenant, or "" when there is none
+func ClaimFromContext(ctx context.Context) string {
+	if t, ok := FromContext(ctx); ok {
+		return t.ID.String()
+	}
+	return ""
+}
+
+// MatchesClaim reports whether a tenant_id claim (an ID or slug) names the tenant in ctx
+// An empty claim matches only a context without tenant.
+func MatchesClaim(ctx context.Context, claim string) bool {
+	t, ok := FromContext(ctx)
+	if !ok {
+		return claim == ""
+	}
+	return claim == t.ID.String() || claim == t.Slug
+}
+
+// Scope restricts a query on a tenant-aware table to the tenant in ctx
+// Queries without a tenant (tenancy disabled, background jobs) are left unchanged. Only the audit
+// tables are tenant-aware so far; the others are shared by every tenant.
+//
+//	db.WithContext(ctx).Scopes(tenant.Scope(ctx)).Find(&events)
+func Scope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		t, ok := FromContext(ctx)
+		if !ok {
+			return db
+		}
+		return db.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: Column}, Value: t.ID})
+	}
+}
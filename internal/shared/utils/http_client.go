@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"net/http"
+	"time"
+
+	"go_boilerplate/internal/shared/observability"
+)
+
+// NewHTTPClient creates an HTTP client for outbound calls to external services
+// Calls made with a request context are recorded on that request's wide event
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &observability.Transport{Base: http.DefaultTransport},
+	}
+}
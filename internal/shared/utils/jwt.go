@@ -12,17 +12,19 @@ import (
 type JWTClaims struct {
 	UserID      uuid.UUID `json:"user_id"`
 	Email       string    `json:"email"`
-	RoleSlug    string    `json:"role_slug"`
-	Permissions []string  `json:"permissions"`
+	Roles       []string  `json:"roles"`               // Slugs of every role assigned to the user
+	Permissions []string  `json:"permissions"`         // Permissions merged across all roles
+	RoleSlug    string    `json:"role_slug,omitempty"` // Single role of tokens issued before users could hold several; never issued
+	TenantID    string    `json:"tenant_id,omitempty"` // Tenant the token is bound to (ID or slug); empty for tokens valid in any tenant
 	jwt.RegisteredClaims
 }
 
 // JWTManager handles JWT token generation and validation
 type JWTManager struct {
-	secret           string
-	accessExpiry     time.Duration
-	refreshExpiry    time.Duration
-	issuer           string
+	secret        string
+	accessExpiry  time.Duration
+	refreshExpiry time.Duration
+	issuer        string
 }
 
 // NewJWTManager creates a new JWT manager
@@ -36,12 +38,14 @@ func NewJWTManager(secret string, accessExpiry, refreshExpiry time.Duration, iss
 }
 
 // GenerateToken generates a JWT token with custom claims
-func (j *JWTManager) GenerateToken(userID uuid.UUID, email, roleSlug string, permissions []string, expiry time.Duration) (string, error) {
+// tenantID binds the token to a tenant; pass "" for a token valid in any tenant.
+func (j *JWTManager) GenerateToken(userID uuid.UUID, email string, roles, permissions []string, tenantID string, expiry time.Duration) (string, error) {
 	claims := JWTClaims{
 		UserID:      userID,
 		Email:       email,
-		RoleSlug:    roleSlug,
+		Roles:       roles,
 		Permissions: permissions,
+		TenantID:    tenantID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -60,23 +64,23 @@ func (j *JWTManager) GenerateToken(userID uuid.UUID, email, roleSlug string, per
 }
 
 // GenerateAccessToken generates an access token
-func (j *JWTManager) GenerateAccessToken(userID uuid.UUID, email, roleSlug string, permissions []string) (string, error) {
-	return j.GenerateToken(userID, email, roleSlug, permissions, j.accessExpiry)
+func (j *JWTManager) GenerateAccessToken(userID uuid.UUID, email string, roles, permissions []string, tenantID string) (string, error) {
+	return j.GenerateToken(userID, email, roles, permissions, tenantID, j.accessExpiry)
 }
 
 // GenerateRefreshToken generates a refresh token
-func (j *JWTManager) GenerateRefreshToken(userID uuid.UUID, email, roleSlug string, permissions []string) (string, error) {
-	return j.GenerateToken(userID, email, roleSlug, permissions, j.refreshExpiry)
+func (j *JWTManager) GenerateRefreshToken(userID uuid.UUID, email string, roles, permissions []string, tenantID string) (string, error) {
+	return j.GenerateToken(userID, email, roles, permissions, tenantID, j.refreshExpiry)
 }
 
 // GenerateTokenPair generates both access and refresh tokens
-func (j *JWTManager) GenerateTokenPair(userID uuid.UUID, email, roleSlug string, permissions []string) (accessToken, refreshToken string, err error) {
-	accessToken, err = j.GenerateAccessToken(userID, email, roleSlug, permissions)
+func (j *JWTManager) GenerateTokenPair(userID uuid.UUID, email string, roles, permissions []string, tenantID string) (accessToken, refreshToken string, err error) {
+	accessToken, err = j.GenerateAccessToken(userID, email, roles, permissions, tenantID)
 	if err != nil {
 		return "", "", err
 	}
 
-	refreshToken, err = j.GenerateRefreshToken(userID, email, roleSlug, permissions)
+	refreshToken, err = j.GenerateRefreshToken(userID, email, roles, permissions, tenantID)
 	if err != nil {
 		return "", "", err
 	}
@@ -124,13 +128,13 @@ func (j *JWTManager) ExtractEmail(tokenString string) (string, error) {
 	return claims.Email, nil
 }
 
-// ExtractRoleSlug extracts role slug from token string
-func (j *JWTManager) ExtractRoleSlug(tokenString string) (string, error) {
+// ExtractRoles extracts role slugs from token string
+func (j *JWTManager) ExtractRoles(tokenString string) ([]string, error) {
 	claims, err := j.ValidateToken(tokenString)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return claims.RoleSlug, nil
+	return claims.Roles, nil
 }
 
 // ExtractPermissions extracts permissions from token string
@@ -141,4 +145,3 @@ func (j *JWTManager) ExtractPermissions(tokenString string) ([]string, error) {
 	}
 	return claims.Permissions, nil
 }
-
@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrUnknownLogModule is returned when changing the level of a module that has no logger
+var ErrUnknownLogModule = errors.New("unknown log module")
+
+// ModuleLevel is the level of a module logger; Overridden is false while it follows the root level
+type ModuleLevel struct {
+	Name       string
+	Level      logrus.Level
+	Overridden bool
+}
+
+// LogLevels hands out one logger per module, writing like the root logger with a "module" field,
+// so log levels can be changed at runtime for the whole process or for a single module
+type LogLevels struct {
+	root *logrus.Logger
+
+	mu         sync.Mutex
+	modules    map[string]*logrus.Logger
+	overridden map[string]bool
+}
+
+// NewLogLevels creates the module logger registry of root
+func NewLogLevels(root *logrus.Logger) *LogLevels {
+	return &LogLevels{root: root, modules: make(map[string]*logrus.Logger), overridden: make(map[string]bool)}
+}
+
+// Root returns the root logger
+func (l *LogLevels) Root() *logrus.Logger {
+	return l.root
+}
+
+// Module returns the logger of a module, created at the root level on first use. It shares the
+// output, formatter and hooks the root logger has at that point.
+func (l *LogLevels) Module(name string) *logrus.Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if logger, ok := l.modules[name]; ok {
+		return logger
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(l.root.Out)
+	logger.SetFormatter(l.root.Formatter)
+	logger.SetReportCaller(l.root.ReportCaller)
+	logger.SetLevel(l.root.GetLevel())
+	logger.ExitFunc = l.root.ExitFunc
+	for _, hooks := range l.root.Hooks {
+		for _, hook := range hooks {
+			logger.Hooks.Add(hook)
+		}
+	}
+	logger.AddHook(moduleHook(name))
+
+	l.modules[name] = logger
+	return logger
+}
+
+// SetLevel sets the level of the root logger and of every module, dropping module overrides
+func (l *LogLevels) SetLevel(level logrus.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.root.SetLevel(level)
+	for name, logger := range l.modules {
+		logger.SetLevel(level)
+		delete(l.overridden, name)
+	}
+}
+
+// SetModuleLevel overrides the level of one module until the next SetLevel
+func (l *LogLevels) SetModuleLevel(name string, level logrus.Level) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	logger, ok := l.modules[name]
+	if !ok {
+		return ErrUnknownLogModule
+	}
+	logger.SetLevel(level)
+	l.overridden[name] = true
+	return nil
+}
+
+// Modules returns the level of every module logger, sorted by name
+func (l *LogLevels) Modules() []ModuleLevel {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	modules := make([]ModuleLevel, 0, len(l.modules))
+	for name, logger := range l.modules {
+		modules = append(modules, ModuleLevel{Name: name, Level: logger.GetLevel(), Overridden: l.overridden[name]})
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Name < modules[j].Name })
+	return modules
+}
+
+// moduleHook adds the module field to the entries of a module logger
+type moduleHook string
+
+// Levels implements logrus.Hook
+func (moduleHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook
+func (h moduleHook) Fire(entry *logrus.Entry) error {
+	if _, ok := entry.Data["module"]; !ok {
+		entry.Data["module"] = string(h)
+	}
+	return nil
+}
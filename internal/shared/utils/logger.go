@@ -1,11 +1,15 @@
 package utils
 
 import (
+	"io"
 	"os"
+	"time"
 
 	"go_boilerplate/internal/shared/config"
+	"go_boilerplate/internal/shared/observability"
 
 	"github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // InitLogger initializes the logger with the given configuration
@@ -13,18 +17,7 @@ func InitLogger(cfg *config.Config) *logrus.Logger {
 	logger := logrus.New()
 
 	// Set log level
-	switch cfg.Logger.Level {
-	case "debug":
-		logger.SetLevel(logrus.DebugLevel)
-	case "info":
-		logger.SetLevel(logrus.InfoLevel)
-	case "warn":
-		logger.SetLevel(logrus.WarnLevel)
-	case "error":
-		logger.SetLevel(logrus.ErrorLevel)
-	default:
-		logger.SetLevel(logrus.InfoLevel)
-	}
+	SetLogLevel(logger, cfg.Logger.Level)
 
 	// Set log format
 	if cfg.Logger.Format == "json" {
@@ -38,12 +31,62 @@ func InitLogger(cfg *config.Config) *logrus.Logger {
 		})
 	}
 
-	// Set output to stdout
-	logger.SetOutput(os.Stdout)
+	// Set output to stdout, the rotated log file or both (LOG_OUTPUT)
+	logger.SetOutput(logOutput(cfg.Logger))
+
+	// Entries created with logger.WithContext(ctx) carry the trace_id and span_id of ctx
+	logger.AddHook(observability.TraceHook{})
 
 	return logger
 }
 
+// logOutput returns the writer of LOG_OUTPUT
+// The log file is opened on the first write and rotated once it reaches LOG_FILE_MAX_SIZE
+func logOutput(cfg config.LoggerConfig) io.Writer {
+	if cfg.Output == config.LogOutputStdout || cfg.Output == "" {
+		return os.Stdout
+	}
+
+	file := &lumberjack.Logger{
+		Filename:   cfg.FilePath,
+		MaxSize:    cfg.FileMaxSize,
+		MaxAge:     int((cfg.FileMaxAge + 24*time.Hour - 1) / (24 * time.Hour)),
+		MaxBackups: cfg.FileMaxBackups,
+		Compress:   cfg.FileCompress,
+	}
+	if cfg.Output == config.LogOutputBoth {
+		return io.MultiWriter(os.Stdout, file)
+	}
+	return file
+}
+
+// SetLogLevel applies a LOG_LEVEL value (debug, info, warn, error), falling back to info
+func SetLogLevel(logger *logrus.Logger, level string) {
+	logger.SetLevel(ParseLogLevel(level))
+}
+
+// ParseLogLevel parses a LOG_LEVEL value (debug, info, warn, error), falling back to info
+func ParseLogLevel(level string) logrus.Level {
+	switch level {
+	case "debug":
+		return logrus.DebugLevel
+	case "warn":
+		return logrus.WarnLevel
+	case "error":
+		return logrus.ErrorLevel
+	default:
+		return logrus.InfoLevel
+	}
+}
+
+// LogLevelName formats a level as a LOG_LEVEL value (warn rather than logrus' warning)
+func LogLevelName(level logrus.Level) string {
+	if level == logrus.WarnLevel {
+		return "warn"
+	}
+	return level.String()
+}
+
 // WithFields creates a logger entry with fields
 func WithFields(logger *logrus.Logger, fields logrus.Fields) *logrus.Entry {
 	return logger.WithFields(fields)
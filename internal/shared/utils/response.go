@@ -1,64 +1,224 @@
 package utils
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go_boilerplate/internal/shared/apperror"
+	"go_boilerplate/internal/shared/i18n"
+	"go_boilerplate/internal/shared/observability"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MIMEApplicationProblemJSON is the content type of error responses (RFC 7807)
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// Pagination headers sent instead of meta when the response envelope is disabled (along with Link)
+const (
+	HeaderTotalCount = "X-Total-Count"
+	HeaderTotalPages = "X-Total-Pages"
+	HeaderPage       = "X-Page"
+	HeaderPerPage    = "X-Per-Page"
+)
+
+// PaginationHeaders are the response headers describing a page of a list
+var PaginationHeaders = []string{HeaderTotalCount, HeaderTotalPages, HeaderPage, HeaderPerPage, fiber.HeaderLink}
+
+// envelopeKey is the fiber.Ctx local overriding whether success responses are wrapped in APIResponse
+const envelopeKey = "responseEnvelope"
 
 // APIResponse represents a standardized API response
 type APIResponse struct {
-	Code    int         `json:"code"`
-	Success bool        `json:"success"`
-	Message string      `json:"message,omitempty"`
-	Data    any         `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
+	Code    int    `json:"code"`
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+	Data    any    `json:"data,omitempty"`
 }
 
-// SuccessResponse sends a successful response
+// ProblemDetails is the RFC 7807 body of every error response
+type ProblemDetails struct {
+	Type      string `json:"type"`                 // Always about:blank; the code identifies the problem
+	Title     string `json:"title"`                // HTTP status text
+	Status    int    `json:"status"`               // HTTP status
+	Detail    string `json:"detail,omitempty"`     // Human-readable explanation, translated into the request locale
+	Instance  string `json:"instance,omitempty"`   // Request path
+	Code      string `json:"code"`                 // Stable machine-readable error code (see apperror)
+	RequestID string `json:"request_id,omitempty"` // X-Request-ID of the failed request
+
+	Extensions map[string]any `json:"-"` // Extra members, e.g. "errors" for per-field validation errors
+}
+
+// MarshalJSON adds the extension members next to the standard ones
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	type problem ProblemDetails
+	encoded, err := json.Marshal(problem(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return encoded, err
+	}
+
+	extensions, err := json.Marshal(p.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(encoded[:len(encoded)-1], ','), extensions[1:]...), nil
+}
+
+// Paginated is implemented by list responses. Without the envelope, SuccessResponse sends the
+// items as the body and the pagination metadata as headers.
+type Paginated interface {
+	PageItems() any
+	PageMeta() PaginationMeta
+}
+
+// SetEnvelope sets whether success responses of the request are wrapped in APIResponse
+// (see middleware.ResponseEnvelope); responses are wrapped unless it is set to false
+func SetEnvelope(c *fiber.Ctx, enabled bool) {
+	c.Locals(envelopeKey, enabled)
+}
+
+// EnvelopeEnabled reports whether success responses of the request are wrapped in APIResponse
+func EnvelopeEnabled(c *fiber.Ctx) bool {
+	enabled, ok := c.Locals(envelopeKey).(bool)
+	return !ok || enabled
+}
+
+// SuccessResponse sends a successful response; message is translated into the request locale.
+// Without the envelope the bare data is sent (see rawResponse) and message is dropped.
 func SuccessResponse(c *fiber.Ctx, statusCode int, data any, message string) error {
+	if !EnvelopeEnabled(c) {
+		return rawResponse(c, statusCode, data)
+	}
 	return c.Status(statusCode).JSON(APIResponse{
 		Code:    statusCode,
 		Success: true,
-		Message: message,
+		Message: i18n.T(c.UserContext(), message),
 		Data:    data,
 	})
 }
 
-// ErrorResponse sends an error response
+// ErrorResponse sends an application/problem+json error response. When err is (or wraps) an
+// *apperror.Error, its status, code and message take precedence over statusCode and message.
+// Other errors are never described to clients (they may carry SQL or driver text); the cause
+// stays on the wide event.
 func ErrorResponse(c *fiber.Ctx, statusCode int, message string, err error) error {
-	errorMsg := message
+	return ProblemResponse(c, statusCode, message, err, nil)
+}
+
+// ProblemResponse is ErrorResponse with extension members added to the body
+func ProblemResponse(c *fiber.Ctx, statusCode int, message string, err error, extensions map[string]any) error {
+	problem := ProblemDetails{
+		Type:      "about:blank",
+		Status:    statusCode,
+		Instance:  c.Path(),
+		Code:      apperror.CodeForStatus(statusCode),
+		RequestID: c.GetRespHeader(fiber.HeaderXRequestID),
+
+		Extensions: extensions,
+	}
+
+	detail := i18n.T(c.UserContext(), message)
+	if appErr, ok := apperror.As(err); ok {
+		problem.Status = appErr.Status
+		problem.Code = appErr.Code
+		if appErr.Message != "" {
+			detail = i18n.T(c.UserContext(), appErr.Message)
+		}
+	}
+	problem.Detail = detail
+	problem.Title = http.StatusText(problem.Status)
+
+	// Surface the untranslated error on the request's wide event
+	logged := message
 	if err != nil {
-		errorMsg = message + ": " + err.Error()
+		logged = message + ": " + err.Error()
 	}
+	event := observability.FromContext(c.UserContext())
+	event.Set("error", logged)
+	event.Set("error_code", problem.Code)
 
-	return c.Status(statusCode).JSON(APIResponse{
-		Code:    statusCode,
-		Success: false,
-		Error:   errorMsg,
-	})
+	c.Status(problem.Status)
+	return c.JSON(problem, MIMEApplicationProblemJSON)
 }
 
 // PagedResponse represents a paginated response
 type PagedResponse struct {
-	Code    int              `json:"code"`
-	Success bool             `json:"success"`
-	Data    any              `json:"data"`
-	Message string           `json:"message,omitempty"`
-	Meta    *PaginationMeta  `json:"meta,omitempty"`
+	Code    int             `json:"code"`
+	Success bool            `json:"success"`
+	Data    any             `json:"data"`
+	Message string          `json:"message,omitempty"`
+	Meta    *PaginationMeta `json:"meta,omitempty"`
 }
 
 // PaginationMeta contains pagination metadata
 type PaginationMeta struct {
-	Page      int `json:"page"`
-	Limit     int `json:"limit"`
-	Total     int `json:"total"`
+	Page       int `json:"page"`
+	Limit      int `json:"limit"`
+	Total      int `json:"total"`
 	TotalPages int `json:"total_pages"`
 }
 
 // SuccessPagedResponse sends a successful paginated response
 func SuccessPagedResponse(c *fiber.Ctx, statusCode int, data any, message string, meta *PaginationMeta) error {
+	if !EnvelopeEnabled(c) {
+		if meta != nil {
+			setPaginationHeaders(c, *meta)
+		}
+		return rawResponse(c, statusCode, data)
+	}
 	return c.Status(statusCode).JSON(PagedResponse{
 		Code:    statusCode,
 		Success: true,
 		Data:    data,
-		Message: message,
+		Message: i18n.T(c.UserContext(), message),
 		Meta:    meta,
 	})
 }
+
+// rawResponse sends data without the envelope: a Paginated list sends its items with pagination
+// headers, and nil data answers 204 No Content in place of 200
+func rawResponse(c *fiber.Ctx, statusCode int, data any) error {
+	if page, ok := data.(Paginated); ok {
+		setPaginationHeaders(c, page.PageMeta())
+		data = page.PageItems()
+	}
+	if data == nil {
+		if statusCode == fiber.StatusOK {
+			statusCode = fiber.StatusNoContent
+		}
+		return c.SendStatus(statusCode)
+	}
+	return c.Status(statusCode).JSON(data)
+}
+
+// setPaginationHeaders sets the pagination headers and a Link header (RFC 8288) with first, prev,
+// next and last page URLs
+func setPaginationHeaders(c *fiber.Ctx, meta PaginationMeta) {
+	c.Set(HeaderTotalCount, strconv.Itoa(meta.Total))
+	c.Set(HeaderTotalPages, strconv.Itoa(meta.TotalPages))
+	c.Set(HeaderPage, strconv.Itoa(meta.Page))
+	c.Set(HeaderPerPage, strconv.Itoa(meta.Limit))
+
+	links := []string{pageLink(c, 1, "first")}
+	if meta.Page > 1 {
+		links = append(links, pageLink(c, meta.Page-1, "prev"))
+	}
+	if meta.Page < meta.TotalPages {
+		links = append(links, pageLink(c, meta.Page+1, "next"))
+	}
+	if meta.TotalPages > 0 {
+		links = append(links, pageLink(c, meta.TotalPages, "last"))
+	}
+	c.Set(fiber.HeaderLink, strings.Join(links, ", "))
+}
+
+// pageLink returns a Link header entry for the request URL with its page query parameter replaced
+func pageLink(c *fiber.Ctx, page int, rel string) string {
+	query, _ := url.ParseQuery(string(c.Request().URI().QueryString()))
+	query.Set("page", strconv.Itoa(page))
+	return fmt.Sprintf(`<%s%s?%s>; rel="%s"`, c.BaseURL(), c.Path(), query.Encode(), rel)
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go_boilerplate/internal/shared/apperror"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func problemFor(t *testing.T, statusCode int, message string, err error) (int, ProblemDetails) {
+	t.Helper()
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return ErrorResponse(c, statusCode, message, err)
+	})
+
+	resp, testErr := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil), -1)
+	if testErr != nil {
+		t.Fatal(testErr)
+	}
+	defer resp.Body.Close()
+
+	raw, readErr := io.ReadAll(resp.Body)
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+	var problem ProblemDetails
+	if err := json.Unmarshal(raw, &problem); err != nil {
+		t.Fatalf("decode %s: %v", raw, err)
+	}
+	return resp.StatusCode, problem
+}
+
+func TestErrorResponseHidesUntypedErrors(t *testing.T) {
+	cause := errors.New(`ERROR: duplicate key value violates unique constraint "idx_m_users_email" (SQLSTATE 23505)`)
+
+	for _, status := range []int{fiber.StatusBadRequest, fiber.StatusNotFound, fiber.StatusInternalServerError} {
+		code, problem := problemFor(t, status, "Failed to update user", fmt.Errorf("save: %w", cause))
+
+		if code != status {
+			t.Errorf("status = %d, want %d", code, status)
+		}
+		if problem.Detail != "Failed to update user" {
+			t.Errorf("%d detail = %q, want only the message", status, problem.Detail)
+		}
+		if strings.Contains(problem.Detail, "SQLSTATE") {
+			t.Errorf("%d detail leaks the cause: %q", status, problem.Detail)
+		}
+	}
+}
+
+func TestErrorResponseUsesAppErrors(t *testing.T) {
+	notFound := apperror.New(apperror.ErrNotFound, "user not found").WithCode("user_not_found")
+
+	code, problem := problemFor(t, fiber.StatusInternalServerError, "Failed to retrieve user", notFound.WithCause(errors.New("record not found")))
+
+	if code != fiber.StatusNotFound || problem.Code != "user_not_found" || problem.Detail != "user not found" {
+		t.Fatalf("got %d %q %q, want 404 user_not_found \"user not found\"", code, problem.Code, problem.Detail)
+	}
+}
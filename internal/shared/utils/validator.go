@@ -1,21 +1,180 @@
 package utils
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
 	"strings"
+	"sync"
+	"unicode"
 
+	"go_boilerplate/internal/shared/i18n"
+	"go_boilerplate/internal/shared/permission"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+	estranslations "github.com/go-playground/validator/v10/translations/es"
 )
 
 // Validator wraps the go-playground/validator
 type Validator struct {
-	validate *validator.Validate
+	validate    *validator.Validate
+	translators map[string]ut.Translator // go-playground messages by i18n locale, for rules without our own message
+}
+
+// validatorLocale is a locale of go-playground's built-in validation messages
+type validatorLocale struct {
+	locale   func() locales.Translator
+	register func(*validator.Validate, ut.Translator) error
+}
+
+// validatorLocales are the i18n locales with go-playground validation messages; others fall back to English
+var validatorLocales = map[string]validatorLocale{
+	"en": {en.New, entranslations.RegisterDefaultTranslations},
+	"es": {es.New, estranslations.RegisterDefaultTranslations},
+}
+
+// usernamePattern allows 3-30 lowercase letters, digits and underscores, starting with a letter
+var usernamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{2,29}$`)
+
+// reservedUsernames are handles that could be confused with the system or staff
+var reservedUsernames = map[string]bool{
+	"admin": true, "administrator": true, "root": true, "system": true, "support": true,
+	"help": true, "security": true, "abuse": true, "staff": true, "moderator": true,
+	"superadmin": true, "super_admin": true, "owner": true, "api": true, "auth": true,
+	"login": true, "logout": true, "register": true, "me": true, "settings": true,
+	"www": true, "mail": true, "null": true, "undefined": true,
+}
+
+// slugPattern allows lowercase letters and digits in words separated by single hyphens or underscores
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:[-_][a-z0-9]+)*$`)
+
+// htmlPattern matches the start of an HTML tag, comment or doctype
+var htmlPattern = regexp.MustCompile(`<[a-zA-Z!/?]`)
+
+// MinStrongPasswordLength is the shortest password accepted by the strong_password rule
+const MinStrongPasswordLength = 8
+
+// Rule is a custom validation tag, usable in DTO struct tags once registered
+type Rule struct {
+	Tag     string         // Tag used in validate struct tags, e.g. sku
+	Func    validator.Func // Reports whether the field is valid
+	Message string         // Error message following the field name, e.g. "must be a valid SKU"
+}
+
+var (
+	rulesMu sync.RWMutex
+	rules   = map[string]Rule{}
+)
+
+// Built-in domain rules
+func init() {
+	RegisterRule(
+		Rule{Tag: "username", Func: func(fl validator.FieldLevel) bool {
+			return IsValidUsername(fl.Field().String())
+		}, Message: "must be 3-30 lowercase letters, digits or underscores, start with a letter, and not be reserved"},
+		Rule{Tag: "permission", Func: func(fl validator.FieldLevel) bool {
+			return permission.Exists(fl.Field().String())
+		}},
+		Rule{Tag: "strong_password", Func: func(fl validator.FieldLevel) bool {
+			return IsStrongPassword(fl.Field().String())
+		}, Message: fmt.Sprintf("must be at least %d characters with an uppercase letter, a lowercase letter, a digit and a symbol", MinStrongPasswordLength)},
+		Rule{Tag: "slug", Func: func(fl validator.FieldLevel) bool {
+			return slugPattern.MatchString(fl.Field().String())
+		}, Message: "must be lowercase letters and digits, separated by single hyphens or underscores"},
+		Rule{Tag: "no_html", Func: func(fl validator.FieldLevel) bool {
+			return fl.Field().Kind() != reflect.String || !htmlPattern.MatchString(fl.Field().String())
+		}, Message: "must not contain HTML"},
+	)
+}
+
+// RegisterRule adds custom validation rules to every validator created afterwards; modules call it
+// from init(). Registering a tag again replaces it.
+func RegisterRule(custom ...Rule) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+
+	for _, rule := range custom {
+		rules[rule.Tag] = rule
+	}
 }
 
-// NewValidator creates a new validator instance
+// registeredRule returns the custom rule registered for tag
+func registeredRule(tag string) (Rule, bool) {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+
+	rule, ok := rules[tag]
+	return rule, ok
+}
+
+// NewValidator creates a new validator instance with the registered custom rules.
+// phone is an alias of the built-in e164 rule (+14155552671).
 func NewValidator() *Validator {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(jsonFieldName)
+	validate.RegisterAlias("phone", "e164")
+
+	rulesMu.RLock()
+	for _, rule := range rules {
+		if err := validate.RegisterValidation(rule.Tag, rule.Func); err != nil {
+			panic(fmt.Sprintf("utils: invalid validation rule %q: %v", rule.Tag, err))
+		}
+	}
+	rulesMu.RUnlock()
+
+	fallback := en.New()
+	uni := ut.New(fallback, fallback)
+	translators := make(map[string]ut.Translator, len(validatorLocales))
+	for name, vl := range validatorLocales {
+		l := vl.locale()
+		_ = uni.AddTranslator(l, true)
+		translator, _ := uni.GetTranslator(l.Locale())
+		if err := vl.register(validate, translator); err != nil {
+			panic(fmt.Sprintf("utils: validation messages for %q: %v", name, err))
+		}
+		translators[name] = translator
+	}
+
 	return &Validator{
-		validate: validator.New(),
+		validate:    validate,
+		translators: translators,
+	}
+}
+
+// IsStrongPassword reports whether password has at least MinStrongPasswordLength characters,
+// including an uppercase letter, a lowercase letter, a digit and a symbol
+func IsStrongPassword(password string) bool {
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
 	}
+	return len([]rune(password)) >= MinStrongPasswordLength && upper && lower && digit && symbol
+}
+
+// NormalizeUsername lowercases and trims a username handle
+func NormalizeUsername(handle string) string {
+	return strings.ToLower(strings.TrimSpace(handle))
+}
+
+// IsValidUsername reports whether a handle is well-formed and not reserved
+func IsValidUsername(handle string) bool {
+	handle = NormalizeUsername(handle)
+	return usernamePattern.MatchString(handle) && !reservedUsernames[handle]
 }
 
 // ValidateStruct validates a struct
@@ -23,41 +182,130 @@ func (v *Validator) ValidateStruct(s any) error {
 	return v.validate.Struct(s)
 }
 
-// GetValidationErrors returns formatted validation errors
-func GetValidationErrors(err error) []string {
-	var errors []string
+// FieldError is a single failed validation rule, addressed by the field's JSON path
+type FieldError struct {
+	Field   string `json:"field"`           // JSON path, e.g. name or notifications.email or permissions[2]
+	Rule    string `json:"rule"`            // Validation tag that failed, e.g. required or max
+	Param   string `json:"param,omitempty"` // Rule parameter, e.g. 50 for max=50
+	Message string `json:"message"`
+}
 
-	if validationErrors, ok := err.(validator.ValidationErrors); ok {
-		for _, e := range validationErrors {
-			errors = append(errors, formatValidationError(e))
-		}
-	} else {
-		errors = append(errors, err.Error())
+// ValidationErrors returns one FieldError per failed rule, with messages in locale (see
+// formatValidationError); errors that aren't validation errors (e.g. a non-struct value) are
+// returned as a single entry without a field
+func (v *Validator) ValidationErrors(err error, locale string) []FieldError {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []FieldError{{Message: err.Error()}}
+	}
+
+	fieldErrors := make([]FieldError, 0, len(validationErrors))
+	for _, e := range validationErrors {
+		field := fieldPath(e)
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   field,
+			Rule:    e.Tag(),
+			Param:   e.Param(),
+			Message: v.formatValidationError(e, field, locale),
+		})
+	}
+	return fieldErrors
+}
+
+// fieldPath returns the JSON path of the failed field: its namespace without the struct name
+func fieldPath(e validator.FieldError) string {
+	_, path, found := strings.Cut(e.Namespace(), ".")
+	if !found {
+		return e.Field()
+	}
+	return path
+}
+
+// formatValidationError formats a single validation error in locale. Our messages are templates
+// translated through the i18n catalogs (keyed by the English template, e.g. "{field} is required");
+// custom rules translate their Message; other rules use go-playground's messages for the locale.
+func (v *Validator) formatValidationError(e validator.FieldError, field, locale string) string {
+	if template, param := validationTemplate(e); template != "" {
+		return strings.NewReplacer("{field}", field, "{param}", param).Replace(i18n.Translate(locale, template))
 	}
 
-	return errors
+	if rule, ok := registeredRule(e.Tag()); ok && rule.Message != "" {
+		return field + " " + i18n.Translate(locale, rule.Message)
+	}
+
+	translator, ok := v.translators[locale]
+	if !ok {
+		translator = v.translators[i18n.Source]
+	}
+	if message := e.Translate(translator); message != e.Error() {
+		// go-playground names the field by its JSON key; use the full path
+		return strings.Replace(message, e.Field(), field, 1)
+	}
+
+	return strings.NewReplacer("{field}", field, "{param}", e.Tag()).Replace(i18n.Translate(locale, "{field} failed on {param} validation"))
 }
 
-// formatValidationError formats a single validation error
-func formatValidationError(e validator.FieldError) string {
-	field := e.Field()
-	tag := e.Tag()
+// validationTemplate returns our English message template for the failed rule and the value of its
+// {param} placeholder, or "" for rules without one
+func validationTemplate(e validator.FieldError) (string, string) {
 	param := e.Param()
 
-	switch tag {
+	switch e.Tag() {
 	case "required":
-		return field + " is required"
+		return "{field} is required", param
 	case "email":
-		return field + " must be a valid email"
+		return "{field} must be a valid email", param
 	case "min":
-		return field + " must be at least " + param + " characters"
+		return "{field} must be at least {param}" + sizeUnit(e.Kind()), param
 	case "max":
-		return field + " must be at most " + param + " characters"
+		return "{field} must be at most {param}" + sizeUnit(e.Kind()), param
 	case "len":
-		return field + " must be " + param + " characters"
+		return "{field} must be {param}" + sizeUnit(e.Kind()), param
+	case "oneof":
+		return "{field} must be one of: {param}", strings.ReplaceAll(param, " ", ", ")
+	case "url":
+		return "{field} must be a valid URL", param
+	case "alphanum":
+		return "{field} must contain only letters and digits", param
+	case "bcp47_language_tag":
+		return "{field} must be a language tag such as en or pt-BR", param
+	case "required_without":
+		return "{field} is required when {param} is not provided", ToSnakeCase(param)
+	case "excluded_with":
+		return "{field} cannot be combined with {param}", ToSnakeCase(param)
+	case "permission":
+		value, _ := e.Value().(string)
+		return "{field} has unknown permission \"{param}\" (see GET /api/v1/permissions)", value
+	case "phone", "e164":
+		return "{field} must be a phone number in E.164 format, e.g. +14155552671", param
+	case "uuid4":
+		return "{field} must be a version 4 UUID", param
+	}
+	return "", ""
+}
+
+// sizeUnit names what min/max/len count for a field of kind k
+func sizeUnit(k reflect.Kind) string {
+	switch k {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " items"
 	default:
-		return field + " failed on " + tag + " validation"
+		return ""
+	}
+}
+
+// jsonFieldName names struct fields in validation errors after their JSON key (snake_case fallback)
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return ToSnakeCase(field.Name)
 	}
+	return name
 }
 
 // ToSnakeCase converts a string to snake_case